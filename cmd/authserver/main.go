@@ -8,7 +8,6 @@ import (
 	"syscall"
 
 	"github.com/ardanlabs/conf"
-	"github.com/ory/fosite/storage"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"go.opentelemetry.io/otel"
@@ -19,6 +18,7 @@ import (
 
 	"github.com/tbd54566975/ssi-service/config"
 	"github.com/tbd54566975/ssi-service/pkg/authorizationserver"
+	"github.com/tbd54566975/ssi-service/pkg/service"
 )
 
 func main() {
@@ -51,15 +51,27 @@ func run() error {
 	shutdown := make(chan os.Signal, 1)
 	signal.Notify(shutdown, os.Interrupt, syscall.SIGTERM)
 
-	// This is the example storage that contains:
-	// * an OAuth2 Client with id "my-client" and secrets "foobar" and "foobaz" capable of all oauth2 and open id connect grant and response types.
-	// * a User for the resource owner password credentials grant type with username "peter" and password "secret".
-	//
-	// You will most likely replace this with your own logic once you set up a real world application.
-	store := storage.NewMemoryStore()
+	// The services of the SSI Service issue the credentials requested from the credential endpoint.
+	ssiConfig, err := config.LoadConfig(cfg.ServicesConfigPath)
+	if err != nil {
+		return errors.Wrap(err, "loading services config")
+	}
+	ssi, err := service.InstantiateSSIService(ssiConfig.Services)
+	if err != nil {
+		return errors.Wrap(err, "instantiating services")
+	}
+
+	// Clients, grants and sessions are kept in the same storage as the services, so they survive restarts and can be
+	// shared by replicas. Expired grants are deleted in the background.
+	store, err := authorizationserver.NewStorage(ssi.GetStorage())
+	if err != nil {
+		return errors.Wrap(err, "creating authserver storage")
+	}
+	cleanupCtx, stopCleanup := context.WithCancel(context.Background())
+	defer stopCleanup()
+	go store.DeleteExpiredEvery(cleanupCtx, cfg.GrantCleanupInterval)
 
-	var err error
-	srv, err := authorizationserver.NewServer(shutdown, &cfg, store)
+	srv, err := authorizationserver.NewServer(shutdown, &cfg, store, ssi)
 	if err != nil {
 		logrus.WithError(err).Fatal("cannot create authserver")
 		os.Exit(1)
@@ -93,6 +93,8 @@ type ServicesConfig struct {
 	ManifestConfig        ManifestServiceConfig     `toml:"manifest,omitempty"`
 	PresentationConfig    PresentationServiceConfig `toml:"presentation,omitempty"`
 	WebhookConfig         WebhookServiceConfig      `toml:"webhook,omitempty"`
+	TrustConfig           TrustServiceConfig        `toml:"trust,omitempty"`
+	WalletConfig          WalletServiceConfig       `toml:"wallet,omitempty"`
 }
 
 // BaseServiceConfig represents configurable properties for a specific component of the SSI Service
@@ -131,6 +133,26 @@ type DIDServiceConfig struct {
 	UniversalResolverURL     string   `toml:"universal_resolver_url"`
 	UniversalResolverMethods []string `toml:"universal_resolver_methods"`
 	IONResolverURL           string   `toml:"ion_resolver_url"`
+
+	// Caching of DID resolutions, which is disabled when unset.
+	ResolutionCache *DIDResolutionCacheConfig `toml:"resolution_cache,omitempty"`
+}
+
+// DIDResolutionCacheConfig configures the cache of DID resolutions, which is invalidated as DIDs managed by the service
+// change.
+type DIDResolutionCacheConfig struct {
+	// Where resolutions are cached, either "memory", the default, or "storage" for the service's storage.
+	Store string `toml:"store"`
+	// How many resolutions the "memory" store keeps, evicting the least recently used ones beyond it. Defaults to
+	// 10000 when zero.
+	MaxEntries int `toml:"max_entries"`
+	// How long resolutions are cached for, unless their method has a TTL of its own.
+	TTL time.Duration `toml:"ttl"`
+	// TTLs of resolutions by method, such as a short one for did:ion or a long one for did:key. Resolutions of methods
+	// with a TTL of zero aren't cached.
+	MethodTTLs map[string]time.Duration `toml:"method_ttls"`
+	// How long DIDs that weren't found are cached for. They aren't cached when zero.
+	NotFoundTTL time.Duration `toml:"not_found_ttl"`
 }
 
 func (d *DIDServiceConfig) IsEmpty() bool {
@@ -166,6 +188,10 @@ func (c *CredentialServiceConfig) IsEmpty() bool {
 
 type ManifestServiceConfig struct {
 	*BaseServiceConfig
+
+	// When true, each credential submitted with an application must be issued by an issuer the trust registry
+	// trusts for the credential's schema.
+	RequireTrustedIssuers bool `toml:"require_trusted_issuers"`
 }
 
 func (m *ManifestServiceConfig) IsEmpty() bool {
@@ -178,6 +204,9 @@ func (m *ManifestServiceConfig) IsEmpty() bool {
 type PresentationServiceConfig struct {
 	*BaseServiceConfig
 	ExpirationDuration time.Duration `toml:"expiration_duration" conf:"default:30m"`
+
+	// When true, every submission must reference the presentation request it answers.
+	RequirePresentationRequest bool `toml:"require_presentation_request"`
 }
 
 func (p *PresentationServiceConfig) IsEmpty() bool {
@@ -199,6 +228,31 @@ func (p *WebhookServiceConfig) IsEmpty() bool {
 	return reflect.DeepEqual(p, &WebhookServiceConfig{})
 }
 
+type TrustServiceConfig struct {
+	*BaseServiceConfig
+}
+
+func (t *TrustServiceConfig) IsEmpty() bool {
+	if t == nil {
+		return true
+	}
+	return reflect.DeepEqual(t, &TrustServiceConfig{})
+}
+
+type WalletServiceConfig struct {
+	*BaseServiceConfig
+
+	// How long to wait for a verifier to answer when submitting a presentation on behalf of a holder. Defaults to 10s.
+	SubmissionTimeout string `toml:"submission_timeout"`
+}
+
+func (w *WalletServiceConfig) IsEmpty() bool {
+	if w == nil {
+		return true
+	}
+	return reflect.DeepEqual(w, &WalletServiceConfig{})
+}
+
 // LoadConfig attempts to load a TOML config file from the given path, and coerce it into our object model.
 // Before loading, defaults are applied on certain properties, which are overwritten if specified in the TOML file.
 func LoadConfig(path string) (*SSIServiceConfig, error) {
@@ -278,8 +332,8 @@ func loadDefaultServicesConfig(config *SSIServiceConfig) {
 		},
 		DIDConfig: DIDServiceConfig{
 			BaseServiceConfig:      &BaseServiceConfig{Name: "did"},
-			Methods:                []string{"key", "web"},
-			LocalResolutionMethods: []string{"key", "peer", "web", "pkh"},
+			Methods:                []string{"key", "web", "jwk", "peer"},
+			LocalResolutionMethods: []string{"key", "peer", "web", "pkh", "jwk"},
 		},
 		SchemaConfig: SchemaServiceConfig{
 			BaseServiceConfig: &BaseServiceConfig{Name: "schema"},
@@ -291,7 +345,7 @@ func loadDefaultServicesConfig(config *SSIServiceConfig) {
 			BaseServiceConfig: &BaseServiceConfig{Name: "manifest"},
 		},
 		PresentationConfig: PresentationServiceConfig{
-			BaseServiceConfig: &BaseServiceConfig{Name: "presentation"},
+			BaseServiceConfig: &BaseServiceConfig{Name: "presentation", ServiceEndpoint: DefaultServiceEndpoint},
 		},
 		IssuanceServiceConfig: IssuanceServiceConfig{
 			BaseServiceConfig: &BaseServiceConfig{Name: "issuance"},
@@ -300,6 +354,13 @@ func loadDefaultServicesConfig(config *SSIServiceConfig) {
 			BaseServiceConfig: &BaseServiceConfig{Name: "webhook"},
 			WebhookTimeout:    "10s",
 		},
+		TrustConfig: TrustServiceConfig{
+			BaseServiceConfig: &BaseServiceConfig{Name: "trust"},
+		},
+		WalletConfig: WalletServiceConfig{
+			BaseServiceConfig: &BaseServiceConfig{Name: "wallet"},
+			SubmissionTimeout: "10s",
+		},
 	}
 
 	config.Services = servicesConfig
@@ -315,6 +376,9 @@ func loadTOMLConfig(path string, config *SSIServiceConfig) error {
 	if config.Services.CredentialConfig.BaseServiceConfig.ServiceEndpoint == "" {
 		config.Services.CredentialConfig.BaseServiceConfig.ServiceEndpoint = config.Services.ServiceEndpoint
 	}
+	if config.Services.PresentationConfig.BaseServiceConfig.ServiceEndpoint == "" {
+		config.Services.PresentationConfig.BaseServiceConfig.ServiceEndpoint = config.Services.ServiceEndpoint
+	}
 
 	return nil
 }
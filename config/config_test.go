@@ -2,6 +2,7 @@ package config
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -17,4 +18,10 @@ func TestConfig(t *testing.T) {
 	assert.False(t, config.Server.APIHost == "")
 
 	assert.NotEmpty(t, config.Services.StorageProvider)
+
+	resolutionCache := config.Services.DIDConfig.ResolutionCache
+	assert.NotNil(t, resolutionCache)
+	assert.Equal(t, 10000, resolutionCache.MaxEntries)
+	assert.Equal(t, 5*time.Minute, resolutionCache.TTL)
+	assert.Equal(t, time.Minute, resolutionCache.MethodTTLs["ion"])
 }
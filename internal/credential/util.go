@@ -0,0 +1,37 @@
+package credential
+
+import (
+	"github.com/pkg/errors"
+)
+
+// IssuerID returns the id of the issuer of a credential, which is either the id itself or an object with an id.
+func IssuerID(issuer any) (string, error) {
+	switch i := issuer.(type) {
+	case string:
+		return i, nil
+	case map[string]any:
+		if id, ok := i["id"].(string); ok {
+			return id, nil
+		}
+	}
+	return "", errors.Errorf("could not determine issuer id from: %v", issuer)
+}
+
+// Types returns the types of a credential, which are either a single type or a set of them.
+func Types(t any) []string {
+	switch types := t.(type) {
+	case string:
+		return []string{types}
+	case []string:
+		return types
+	case []any:
+		out := make([]string, 0, len(types))
+		for _, v := range types {
+			if s, ok := v.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
@@ -3,10 +3,13 @@ package did
 import (
 	"context"
 	"crypto"
+	"crypto/ecdsa"
+	"strings"
 
 	didsdk "github.com/TBD54566975/ssi-sdk/did"
 	"github.com/TBD54566975/ssi-sdk/did/resolution"
 	"github.com/TBD54566975/ssi-sdk/util"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
 	"github.com/pkg/errors"
 
 	"github.com/tbd54566975/ssi-service/internal/keyaccess"
@@ -21,7 +24,7 @@ func ResolveKeyForDID(ctx context.Context, resolver resolution.Resolver, did, ki
 	}
 
 	// next, get the verification information (key) from the did document
-	pubKey, err = didsdk.GetKeyFromVerificationMethod(resolved.Document, kid)
+	pubKey, err = KeyFromVerificationMethod(resolved.Document, kid)
 	if err != nil {
 		err = errors.Wrapf(err, "getting verification information from DID Document: %s", did)
 		return nil, err
@@ -38,8 +41,17 @@ func VerifyTokenFromDID(ctx context.Context, resolver resolution.Resolver, did,
 		return errors.Wrapf(err, "resolving DID: %s", did)
 	}
 
+	// the signer's key of tokens signed with recoverable signatures is recovered from the signature, since the
+	// verification method may only identify the signer, as blockchain accounts do
+	if keyaccess.IsRecoverableJWT(token) {
+		if err = verifyRecoverableToken(resolved.Document, kid, token); err != nil {
+			return util.LoggingErrorMsg(err, "could not verify the token's recoverable signature")
+		}
+		return nil
+	}
+
 	// get the verification information from the DID document
-	pubKey, err := didsdk.GetKeyFromVerificationMethod(resolved.Document, kid)
+	pubKey, err := KeyFromVerificationMethod(resolved.Document, kid)
 	if err != nil {
 		return errors.Wrapf(err, "getting verification information from the DID document: %s", did)
 	}
@@ -53,3 +65,114 @@ func VerifyTokenFromDID(ctx context.Context, resolver resolution.Resolver, did,
 	}
 	return nil
 }
+
+// verifyRecoverableToken verifies a token signed with ES256K-R against the verification method identified by kid.
+// The key recovered from the signature must be the verification method's key or, for a blockchain account, the key
+// of the account's address.
+func verifyRecoverableToken(doc didsdk.Document, kid string, token keyaccess.JWT) error {
+	signer, err := keyaccess.RecoverJWTSigner(token)
+	if err != nil {
+		return err
+	}
+	var verificationMethod *didsdk.VerificationMethod
+	for i, method := range doc.VerificationMethod {
+		if method.ID == kid || method.ID == "#"+kid || method.ID == doc.ID+"#"+kid || method.ID == doc.ID+kid || doc.ID+method.ID == kid {
+			verificationMethod = &doc.VerificationMethod[i]
+			break
+		}
+	}
+	if verificationMethod == nil {
+		return errors.Errorf("did<%s> has no verification methods with kid: %s", doc.ID, kid)
+	}
+
+	if verificationMethod.BlockchainAccountID != "" {
+		namespace, _, address, err := ParseCAIP10AccountID(verificationMethod.BlockchainAccountID)
+		if err != nil {
+			return err
+		}
+		if namespace != EIP155Namespace {
+			return errors.Errorf("recoverable signatures are not supported for %s accounts", namespace)
+		}
+		if !strings.EqualFold(ethereumAddress(signer), address) {
+			return errors.Errorf("token was not signed by account: %s", verificationMethod.BlockchainAccountID)
+		}
+		return nil
+	}
+
+	pubKey, err := KeyFromVerificationMethod(doc, kid)
+	if err != nil {
+		return errors.Wrapf(err, "getting verification information from the DID document: %s", doc.ID)
+	}
+	if !isSECP256k1Key(signer, pubKey) {
+		return errors.Errorf("token was not signed by the key of kid: %s", kid)
+	}
+	return nil
+}
+
+// KeyFromVerificationMethod returns the public key of the verification method identified by kid. The kid may also be
+// the absolute id of a verification method whose id is relative in the document, as keys of imported DIDs sign with.
+func KeyFromVerificationMethod(doc didsdk.Document, kid string) (crypto.PublicKey, error) {
+	pubKey, err := didsdk.GetKeyFromVerificationMethod(doc, kid)
+	if err == nil {
+		return pubKey, nil
+	}
+	if relativeID := strings.TrimPrefix(kid, doc.ID); relativeID != kid && strings.HasPrefix(relativeID, "#") {
+		for _, method := range doc.VerificationMethod {
+			if method.ID == relativeID {
+				return didsdk.GetKeyFromVerificationMethod(doc, relativeID)
+			}
+		}
+	}
+	return nil, err
+}
+
+// isSECP256k1Key is whether the public key, of any of the types the sdk represents secp256k1 keys with, is key.
+func isSECP256k1Key(key *secp256k1.PublicKey, pubKey crypto.PublicKey) bool {
+	switch k := pubKey.(type) {
+	case secp256k1.PublicKey:
+		return key.IsEqual(&k)
+	case *secp256k1.PublicKey:
+		return key.IsEqual(k)
+	case ecdsa.PublicKey:
+		return key.ToECDSA().Equal(&k)
+	case *ecdsa.PublicKey:
+		return key.ToECDSA().Equal(k)
+	}
+	return false
+}
+
+// AssertionMethodIDs returns the ids of the verification methods referenced by the assertion methods of the document,
+// in order. References relative to the document are expanded to the full id of the verification method.
+func AssertionMethodIDs(doc didsdk.Document) []string {
+	ids := make([]string, 0, len(doc.AssertionMethod))
+	for _, entry := range doc.AssertionMethod {
+		ids = append(ids, verificationMethodSetIDs(doc.ID, entry)...)
+	}
+	return ids
+}
+
+func verificationMethodSetIDs(docID string, entry didsdk.VerificationMethodSet) []string {
+	var ids []string
+	switch e := entry.(type) {
+	case string:
+		ids = append(ids, e)
+	case didsdk.VerificationMethod:
+		ids = append(ids, e.ID)
+	case map[string]any:
+		if id, ok := e["id"].(string); ok {
+			ids = append(ids, id)
+		}
+	case []string:
+		ids = append(ids, e...)
+	case []any:
+		for _, item := range e {
+			ids = append(ids, verificationMethodSetIDs(docID, item)...)
+		}
+	}
+	for i, id := range ids {
+		if strings.HasPrefix(id, "#") {
+			ids[i] = docID + id
+		}
+	}
+	return ids
+}
@@ -0,0 +1,166 @@
+package did
+
+import (
+	"context"
+	b64 "encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	didsdk "github.com/TBD54566975/ssi-sdk/did"
+	"github.com/TBD54566975/ssi-sdk/did/peer"
+	"github.com/TBD54566975/ssi-sdk/did/resolution"
+	"github.com/goccy/go-json"
+	"github.com/pkg/errors"
+)
+
+const peerNumAlgo2Prefix = peer.DIDPeerPrefix + ":2"
+
+// peerServiceBlock is a service of a numalgo 2 did:peer, with the abbreviated keys of
+// https://identity.foundation/peer-did-method-spec/#method-2-multiple-inception-key-without-doc
+type peerServiceBlock struct {
+	ID              string   `json:"id,omitempty"`
+	ServiceType     string   `json:"t"`
+	ServiceEndpoint string   `json:"s"`
+	RoutingKeys     []string `json:"r,omitempty"`
+	Accept          []string `json:"a,omitempty"`
+}
+
+// peerResolver resolves did:peer DIDs. Unlike the sdk's resolver, numalgo 2 DIDs are resolved with every purpose of
+// the spec, including assertion, and their keys are listed as verification methods which the verification
+// relationships reference. Other numalgos are resolved by the sdk.
+type peerResolver struct{}
+
+var _ resolution.Resolver = (*peerResolver)(nil)
+
+func (peerResolver) Resolve(ctx context.Context, id string, opts ...resolution.ResolutionOption) (*resolution.ResolutionResult, error) {
+	if !strings.HasPrefix(id, peerNumAlgo2Prefix+".") {
+		return new(peer.Resolver).Resolve(ctx, id, opts...)
+	}
+	doc, err := ExpandPeerDIDNumAlgo2(id)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not resolve did:peer DID: %s", id)
+	}
+	return &resolution.ResolutionResult{Document: *doc}, nil
+}
+
+func (peerResolver) Methods() []didsdk.Method {
+	return []didsdk.Method{didsdk.PeerMethod}
+}
+
+// ExpandPeerDIDNumAlgo2 builds the document of a numalgo 2 did:peer. Each key gets a verification method, whose id is
+// the DID followed by the multibase encoding of the key as the fragment, referenced by the verification relationships
+// of its purposes. Services without an id get #service, #service-1 and so on.
+func ExpandPeerDIDNumAlgo2(id string) (*didsdk.Document, error) {
+	if !strings.HasPrefix(id, peerNumAlgo2Prefix+".") {
+		return nil, fmt.Errorf("not a numalgo 2 did:peer DID: %s", id)
+	}
+	doc := didsdk.Document{
+		Context: didsdk.KnownDIDContext,
+		ID:      id,
+	}
+	for _, entry := range strings.Split(strings.TrimPrefix(id, peerNumAlgo2Prefix+"."), ".") {
+		if entry == "" {
+			return nil, errors.New("empty did:peer entry")
+		}
+		purpose, value := peer.PurposeType(entry[0]), entry[1:]
+		if purpose == peer.PurposeCapabilityServiceCode {
+			service, err := decodePeerService(value)
+			if err != nil {
+				return nil, err
+			}
+			if service.ID == "" {
+				service.ID = "#service"
+				if len(doc.Services) > 0 {
+					service.ID += "-" + strconv.Itoa(len(doc.Services))
+				}
+			}
+			doc.Services = append(doc.Services, *service)
+			continue
+		}
+
+		vmID := id + "#" + value
+		var relationship *[]didsdk.VerificationMethodSet
+		switch purpose {
+		case peer.PurposeAssertionCode:
+			relationship = &doc.AssertionMethod
+		case peer.PurposeEncryptionCode:
+			relationship = &doc.KeyAgreement
+		case peer.PurposeVerificationCode:
+			relationship = &doc.Authentication
+		case peer.PurposeCapabilityInvocationCode:
+			relationship = &doc.CapabilityInvocation
+		case peer.PurposeCapabilityDelegationCode:
+			relationship = &doc.CapabilityDelegation
+		default:
+			return nil, fmt.Errorf("unsupported did:peer purpose: %c", entry[0])
+		}
+		*relationship = append(*relationship, vmID)
+		if hasVerificationMethod(doc, vmID) {
+			continue
+		}
+		pubKeyBytes, ldKeyType, cryptoKeyType, err := didsdk.DecodeMultibaseEncodedKey(value)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not decode did:peer key")
+		}
+		verificationMethod, err := didsdk.ConstructJWKVerificationMethod(id, vmID, pubKeyBytes, ldKeyType, cryptoKeyType)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not construct verification method")
+		}
+		doc.VerificationMethod = append(doc.VerificationMethod, *verificationMethod)
+	}
+	return &doc, nil
+}
+
+// EncodePeerService encodes a service as the value of a numalgo 2 did:peer service entry, without its purpose.
+func EncodePeerService(service didsdk.Service) (string, error) {
+	endpoint, ok := service.ServiceEndpoint.(string)
+	if !ok {
+		return "", fmt.Errorf("service<%s> endpoint must be a string for did:peer", service.ID)
+	}
+	serviceBlock := peerServiceBlock{
+		ID:              service.ID,
+		ServiceType:     service.Type,
+		ServiceEndpoint: endpoint,
+		RoutingKeys:     service.RoutingKeys,
+		Accept:          service.Accept,
+	}
+	if serviceBlock.ServiceType == peer.DIDCommMessaging {
+		serviceBlock.ServiceType = peer.DIDCommMessagingAbbr
+	}
+	serviceBytes, err := json.Marshal(serviceBlock)
+	if err != nil {
+		return "", errors.Wrap(err, "could not encode service for did:peer")
+	}
+	return b64.RawURLEncoding.EncodeToString(serviceBytes), nil
+}
+
+func decodePeerService(encoded string) (*didsdk.Service, error) {
+	serviceBytes, err := b64.RawURLEncoding.DecodeString(strings.TrimRight(encoded, "="))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not decode did:peer service")
+	}
+	var serviceBlock peerServiceBlock
+	if err = json.Unmarshal(serviceBytes, &serviceBlock); err != nil {
+		return nil, errors.Wrap(err, "could not decode did:peer service")
+	}
+	if serviceBlock.ServiceType == peer.DIDCommMessagingAbbr {
+		serviceBlock.ServiceType = peer.DIDCommMessaging
+	}
+	return &didsdk.Service{
+		ID:              serviceBlock.ID,
+		Type:            serviceBlock.ServiceType,
+		ServiceEndpoint: serviceBlock.ServiceEndpoint,
+		RoutingKeys:     serviceBlock.RoutingKeys,
+		Accept:          serviceBlock.Accept,
+	}, nil
+}
+
+func hasVerificationMethod(doc didsdk.Document, id string) bool {
+	for _, method := range doc.VerificationMethod {
+		if method.ID == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,64 @@
+package did
+
+import (
+	"context"
+	"testing"
+
+	didsdk "github.com/TBD54566975/ssi-sdk/did"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeerResolver(t *testing.T) {
+	resolver := new(peerResolver)
+
+	t.Run("numalgo 2 with a key for several purposes and a service", func(tt *testing.T) {
+		id := "did:peer:2" +
+			".Ez6LSbysY2xFMRpGMhb7tFTLMpeuPRaqaWM1yECx2AtzE3KCc" +
+			".Az6MkqRYqQiSgvZQdnBytw86Qbs2ZWUkGv22od935YF4s8M7V" +
+			".Vz6MkqRYqQiSgvZQdnBytw86Qbs2ZWUkGv22od935YF4s8M7V" +
+			".SeyJ0IjoiZG0iLCJzIjoiaHR0cHM6Ly9leGFtcGxlLmNvbS9lbmRwb2ludCIsInIiOlsiZGlkOmV4YW1wbGU6c29tZW1lZGlhdG9yI3NvbWVrZXkiXSwiYSI6WyJkaWRjb21tL3YyIiwiZGlkY29tbS9haXAyO2Vudj1yZmM1ODciXX0"
+		resolved, err := resolver.Resolve(context.Background(), id)
+		require.NoError(tt, err)
+		doc := resolved.Document
+		assert.Equal(tt, id, doc.ID)
+
+		require.Len(tt, doc.VerificationMethod, 2)
+		agreementKeyID := id + "#z6LSbysY2xFMRpGMhb7tFTLMpeuPRaqaWM1yECx2AtzE3KCc"
+		signingKeyID := id + "#z6MkqRYqQiSgvZQdnBytw86Qbs2ZWUkGv22od935YF4s8M7V"
+		assert.Equal(tt, agreementKeyID, doc.VerificationMethod[0].ID)
+		assert.Equal(tt, signingKeyID, doc.VerificationMethod[1].ID)
+		assert.Equal(tt, []didsdk.VerificationMethodSet{agreementKeyID}, doc.KeyAgreement)
+		assert.Equal(tt, []didsdk.VerificationMethodSet{signingKeyID}, doc.AssertionMethod)
+		assert.Equal(tt, []didsdk.VerificationMethodSet{signingKeyID}, doc.Authentication)
+
+		require.Len(tt, doc.Services, 1)
+		assert.Equal(tt, didsdk.Service{
+			ID:              "#service",
+			Type:            "DIDCommMessaging",
+			ServiceEndpoint: "https://example.com/endpoint",
+			RoutingKeys:     []string{"did:example:somemediator#somekey"},
+			Accept:          []string{"didcomm/v2", "didcomm/aip2;env=rfc587"},
+		}, doc.Services[0])
+	})
+
+	t.Run("services keep their ids", func(tt *testing.T) {
+		service := didsdk.Service{ID: "#linked-domain", Type: "LinkedDomains", ServiceEndpoint: "https://example.com"}
+		encoded, err := EncodePeerService(service)
+		require.NoError(tt, err)
+		resolved, err := resolver.Resolve(context.Background(), "did:peer:2.Vz6MkqRYqQiSgvZQdnBytw86Qbs2ZWUkGv22od935YF4s8M7V.S"+encoded)
+		require.NoError(tt, err)
+		assert.Equal(tt, []didsdk.Service{service}, resolved.Document.Services)
+	})
+
+	t.Run("other numalgos are resolved by the sdk", func(tt *testing.T) {
+		resolved, err := resolver.Resolve(context.Background(), "did:peer:0z6MkqRYqQiSgvZQdnBytw86Qbs2ZWUkGv22od935YF4s8M7V")
+		require.NoError(tt, err)
+		assert.NotEmpty(tt, resolved.Document.Authentication)
+	})
+
+	t.Run("unknown purpose", func(tt *testing.T) {
+		_, err := resolver.Resolve(context.Background(), "did:peer:2.Xz6MkqRYqQiSgvZQdnBytw86Qbs2ZWUkGv22od935YF4s8M7V")
+		assert.ErrorContains(tt, err, "unsupported did:peer purpose: X")
+	})
+}
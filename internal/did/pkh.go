@@ -0,0 +1,119 @@
+package did
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/TBD54566975/ssi-sdk/cryptosuite"
+	didsdk "github.com/TBD54566975/ssi-sdk/did"
+	"github.com/TBD54566975/ssi-sdk/did/pkh"
+	"github.com/TBD54566975/ssi-sdk/did/resolution"
+	sdkutil "github.com/TBD54566975/ssi-sdk/util"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/mr-tron/base58"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/sha3"
+)
+
+// CAIP-2 namespaces of the blockchain accounts did:pkh DIDs can be resolved for.
+const (
+	EIP155Namespace = "eip155"
+	BIP122Namespace = "bip122"
+	SolanaNamespace = "solana"
+)
+
+var (
+	// caip10Regex matches CAIP-10 account ids, https://github.com/ChainAgnostic/CAIPs/blob/master/CAIPs/caip-10.md
+	caip10Regex = regexp.MustCompile(`^([-a-z0-9]{3,8}):([-_a-zA-Z0-9]{1,32}):([-.%a-zA-Z0-9]{1,128})$`)
+	// eip155AddressRegex matches Ethereum addresses, with or without an EIP-55 checksum
+	eip155AddressRegex = regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`)
+)
+
+// pkhResolver resolves did:pkh DIDs of accounts in the CAIP-10 namespaces we support, according to
+// https://github.com/w3c-ccg/did-pkh/blob/main/did-pkh-method-draft.md. Unlike the sdk's resolver, any chain of
+// a namespace is supported, and Solana accounts resolve to their Ed25519 public key.
+type pkhResolver struct{}
+
+var _ resolution.Resolver = (*pkhResolver)(nil)
+
+func (pkhResolver) Resolve(_ context.Context, id string, _ ...resolution.ResolutionOption) (*resolution.ResolutionResult, error) {
+	if !strings.HasPrefix(id, pkh.DIDPKHPrefix+":") {
+		return nil, fmt.Errorf("not a did:pkh DID: %s", id)
+	}
+	accountID := strings.TrimPrefix(id, pkh.DIDPKHPrefix+":")
+	namespace, _, address, err := ParseCAIP10AccountID(accountID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not resolve did:pkh DID: %s", id)
+	}
+
+	verificationMethod := didsdk.VerificationMethod{
+		Controller:          id,
+		BlockchainAccountID: accountID,
+	}
+	switch namespace {
+	case EIP155Namespace:
+		if !eip155AddressRegex.MatchString(address) {
+			return nil, fmt.Errorf("invalid %s address: %s", namespace, address)
+		}
+		verificationMethod.ID = id + "#blockchainAccountId"
+		verificationMethod.Type = pkh.ECDSASECP256k1RecoveryMethod2020
+	case BIP122Namespace:
+		verificationMethod.ID = id + "#blockchainAccountId"
+		verificationMethod.Type = pkh.ECDSASECP256k1RecoveryMethod2020
+	case SolanaNamespace:
+		// Solana addresses are base58 encoded Ed25519 public keys
+		pubKey, err := base58.Decode(address)
+		if err != nil || len(pubKey) != 32 {
+			return nil, fmt.Errorf("invalid %s address: %s", namespace, address)
+		}
+		verificationMethod.ID = id + "#controller"
+		verificationMethod.Type = cryptosuite.Ed25519VerificationKey2018
+		verificationMethod.PublicKeyBase58 = address
+	default:
+		return nil, fmt.Errorf("unsupported did:pkh namespace: %s", namespace)
+	}
+
+	knownContext, err := pkh.GetDIDPKHContext()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get did:pkh context")
+	}
+	pkhContext, err := sdkutil.ToJSONInterface(knownContext)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not convert did:pkh context to json")
+	}
+	verificationMethodSet := []didsdk.VerificationMethodSet{verificationMethod.ID}
+	doc := didsdk.Document{
+		Context:              pkhContext,
+		ID:                   id,
+		VerificationMethod:   []didsdk.VerificationMethod{verificationMethod},
+		Authentication:       verificationMethodSet,
+		AssertionMethod:      verificationMethodSet,
+		CapabilityDelegation: verificationMethodSet,
+		CapabilityInvocation: verificationMethodSet,
+	}
+	return &resolution.ResolutionResult{Document: doc}, nil
+}
+
+func (pkhResolver) Methods() []didsdk.Method {
+	return []didsdk.Method{didsdk.PKHMethod}
+}
+
+// ParseCAIP10AccountID splits a CAIP-10 account id into the namespace and reference of its chain, and its address.
+func ParseCAIP10AccountID(accountID string) (namespace, reference, address string, err error) {
+	match := caip10Regex.FindStringSubmatch(accountID)
+	if match == nil {
+		return "", "", "", fmt.Errorf("invalid CAIP-10 account id: %s", accountID)
+	}
+	return match[1], match[2], match[3], nil
+}
+
+// ethereumAddress returns the address of the Ethereum account of the key, which is the last 20 bytes of the
+// Keccak-256 hash of the uncompressed key.
+func ethereumAddress(pubKey *secp256k1.PublicKey) string {
+	hash := sha3.NewLegacyKeccak256()
+	_, _ = hash.Write(pubKey.SerializeUncompressed()[1:])
+	return "0x" + hex.EncodeToString(hash.Sum(nil)[12:])
+}
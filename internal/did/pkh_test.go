@@ -0,0 +1,145 @@
+package did
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"testing"
+
+	"github.com/TBD54566975/ssi-sdk/crypto"
+	"github.com/TBD54566975/ssi-sdk/crypto/jwx"
+	"github.com/TBD54566975/ssi-sdk/cryptosuite"
+	didsdk "github.com/TBD54566975/ssi-sdk/did"
+	"github.com/TBD54566975/ssi-sdk/did/key"
+	"github.com/TBD54566975/ssi-sdk/did/pkh"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	"github.com/goccy/go-json"
+	"github.com/mr-tron/base58"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tbd54566975/ssi-service/internal/keyaccess"
+)
+
+func TestPKHResolver(t *testing.T) {
+	resolver, err := BuildMultiMethodResolver([]string{"pkh"})
+	require.NoError(t, err)
+
+	t.Run("resolves Ethereum accounts on any chain", func(t *testing.T) {
+		for _, id := range []string{
+			"did:pkh:eip155:1:0xb9c5714089478a327f09197987f16f9e5d936e8a",
+			"did:pkh:eip155:10:0xB9C5714089478a327F09197987f16f9E5d936E8a",
+		} {
+			resolved, err := resolver.Resolve(context.Background(), id)
+			require.NoError(t, err)
+			assert.Equal(t, id, resolved.Document.ID)
+			require.Len(t, resolved.Document.VerificationMethod, 1)
+			vm := resolved.Document.VerificationMethod[0]
+			assert.Equal(t, id+"#blockchainAccountId", vm.ID)
+			assert.Equal(t, cryptosuite.LDKeyType(pkh.ECDSASECP256k1RecoveryMethod2020), vm.Type)
+			assert.Equal(t, id[len("did:pkh:"):], vm.BlockchainAccountID)
+			assert.Equal(t, []didsdk.VerificationMethodSet{vm.ID}, resolved.Document.AssertionMethod)
+		}
+	})
+
+	t.Run("resolves Solana accounts to their key", func(t *testing.T) {
+		pubKey, _, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		id := "did:pkh:solana:4sGjMW1sUnHzSxGspuhpqLDx6wiyjNtZ:" + base58.Encode(pubKey)
+		resolved, err := resolver.Resolve(context.Background(), id)
+		require.NoError(t, err)
+		require.Len(t, resolved.Document.VerificationMethod, 1)
+		assert.Equal(t, id+"#controller", resolved.Document.VerificationMethod[0].ID)
+
+		gotKey, err := didsdk.GetKeyFromVerificationMethod(resolved.Document, "controller")
+		require.NoError(t, err)
+		assert.Equal(t, pubKey, gotKey)
+	})
+
+	t.Run("invalid accounts are not resolved", func(t *testing.T) {
+		for _, id := range []string{
+			"did:pkh:eip155:1",
+			"did:pkh:eip155:1:0x1234",
+			"did:pkh:solana:4sGjMW1sUnHzSxGspuhpqLDx6wiyjNtZ:not-a-key",
+			"did:pkh:tezos:NetXdQprcVkpaWU:tz1TzrmTBSuiVHV2VfMnGRMYvTEPCP42oSM8",
+		} {
+			_, err := resolver.Resolve(context.Background(), id)
+			assert.Error(t, err, id)
+		}
+	})
+}
+
+func TestVerifyRecoverableTokenFromDID(t *testing.T) {
+	resolver, err := BuildMultiMethodResolver([]string{"pkh", "key"})
+	require.NoError(t, err)
+	privKey, err := secp256k1.GeneratePrivateKey()
+	require.NoError(t, err)
+	id := "did:pkh:eip155:1:" + ethereumAddress(privKey.PubKey())
+	kid := id + "#blockchainAccountId"
+
+	t.Run("account address is derived from the key", func(t *testing.T) {
+		// test vector from the web3.js documentation
+		keyBytes, err := hex.DecodeString("4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318")
+		require.NoError(t, err)
+		address := ethereumAddress(secp256k1.PrivKeyFromBytes(keyBytes).PubKey())
+		assert.Equal(t, "0x2c7536e3605d9c16a7a3d7b1898e529396a65c23", address)
+	})
+
+	t.Run("token signed by the account is verified", func(t *testing.T) {
+		token := signRecoverableJWT(t, privKey, kid, map[string]any{"iss": id})
+		assert.NoError(t, VerifyTokenFromDID(context.Background(), resolver, id, kid, token))
+	})
+
+	t.Run("token signed by another account is not verified", func(t *testing.T) {
+		otherKey, err := secp256k1.GeneratePrivateKey()
+		require.NoError(t, err)
+		token := signRecoverableJWT(t, otherKey, kid, map[string]any{"iss": id})
+		assert.ErrorContains(t, VerifyTokenFromDID(context.Background(), resolver, id, kid, token), "token was not signed by account")
+	})
+
+	t.Run("tokens of DIDs with keys are verified with the key", func(t *testing.T) {
+		privKey, err := secp256k1.GeneratePrivateKey()
+		require.NoError(t, err)
+		didKey, err := key.CreateDIDKey(crypto.SECP256k1, privKey.PubKey().SerializeCompressed())
+		require.NoError(t, err)
+		resolved, err := resolver.Resolve(context.Background(), didKey.String())
+		require.NoError(t, err)
+		didKeyKID := resolved.Document.VerificationMethod[0].ID
+
+		token := signRecoverableJWT(t, privKey, didKeyKID, map[string]any{"iss": didKey.String()})
+		assert.NoError(t, VerifyTokenFromDID(context.Background(), resolver, didKey.String(), didKeyKID, token))
+
+		otherKey, err := secp256k1.GeneratePrivateKey()
+		require.NoError(t, err)
+		token = signRecoverableJWT(t, otherKey, didKeyKID, map[string]any{"iss": didKey.String()})
+		assert.ErrorContains(t, VerifyTokenFromDID(context.Background(), resolver, didKey.String(), didKeyKID, token), "token was not signed by the key")
+	})
+
+	t.Run("tokens of Solana accounts are verified with their key", func(t *testing.T) {
+		pubKey, privKey, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		id := "did:pkh:solana:4sGjMW1sUnHzSxGspuhpqLDx6wiyjNtZ:" + base58.Encode(pubKey)
+		signer, err := jwx.NewJWXSigner(id, id+"#controller", privKey)
+		require.NoError(t, err)
+		token, err := signer.SignWithDefaults(map[string]any{"iss": id})
+		require.NoError(t, err)
+		assert.NoError(t, VerifyTokenFromDID(context.Background(), resolver, id, id+"#controller", keyaccess.JWT(token)))
+	})
+}
+
+// signRecoverableJWT signs the claims with ES256K-R.
+func signRecoverableJWT(t *testing.T, privKey *secp256k1.PrivateKey, kid string, claims map[string]any) keyaccess.JWT {
+	header, err := json.Marshal(map[string]any{"alg": keyaccess.ES256KR, "kid": kid, "typ": "JWT"})
+	require.NoError(t, err)
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	hash := sha256.Sum256([]byte(signingInput))
+	compact := ecdsa.SignCompact(privKey, hash[:], false)
+	signature := append(compact[1:], compact[0]-27)
+	return keyaccess.JWT(signingInput + "." + base64.RawURLEncoding.EncodeToString(signature))
+}
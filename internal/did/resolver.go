@@ -6,8 +6,6 @@ import (
 	didsdk "github.com/TBD54566975/ssi-sdk/did"
 	"github.com/TBD54566975/ssi-sdk/did/jwk"
 	"github.com/TBD54566975/ssi-sdk/did/key"
-	"github.com/TBD54566975/ssi-sdk/did/peer"
-	"github.com/TBD54566975/ssi-sdk/did/pkh"
 	"github.com/TBD54566975/ssi-sdk/did/resolution"
 	"github.com/TBD54566975/ssi-sdk/did/web"
 	"github.com/pkg/errors"
@@ -43,9 +41,9 @@ func getKnownResolver(method string) (resolution.Resolver, error) {
 	case didsdk.WebMethod:
 		return new(web.Resolver), nil
 	case didsdk.PKHMethod:
-		return new(pkh.Resolver), nil
+		return new(pkhResolver), nil
 	case didsdk.PeerMethod:
-		return new(peer.Resolver), nil
+		return new(peerResolver), nil
 	case didsdk.JWKMethod:
 		return new(jwk.Resolver), nil
 	}
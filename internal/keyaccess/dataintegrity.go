@@ -4,14 +4,14 @@ import (
 	gocrypto "crypto"
 
 	"github.com/TBD54566975/ssi-sdk/credential"
+	"github.com/TBD54566975/ssi-sdk/crypto"
 	"github.com/TBD54566975/ssi-sdk/crypto/jwx"
 	"github.com/TBD54566975/ssi-sdk/cryptosuite"
+	sdkutil "github.com/TBD54566975/ssi-sdk/util"
 	"github.com/goccy/go-json"
 	"github.com/pkg/errors"
 )
 
-// TODO(gabe) integrate signing for Data Integrity as well in https://github.com/TBD54566975/ssi-service/issues/105
-
 // DataIntegrityKeyAccess represents a key access object for data integrity using the JsonWebSignature2020 suite:
 // https://w3c.github.io/vc-jws-2020/
 type DataIntegrityKeyAccess struct {
@@ -48,6 +48,29 @@ func NewDataIntegrityKeyAccess(id, kid string, key gocrypto.PrivateKey) (*DataIn
 	}, nil
 }
 
+// NewDataIntegrityKeyAccessVerifier creates a new DataIntegrityKeyAccess object from an id, key id, and public key,
+// generating only a JSON Web Key Verifier object.
+func NewDataIntegrityKeyAccessVerifier(id, kid string, key gocrypto.PublicKey) (*DataIntegrityKeyAccess, error) {
+	if kid == "" {
+		return nil, errors.New("kid cannot be empty")
+	}
+	if key == nil {
+		return nil, errors.New("key cannot be nil")
+	}
+	publicKeyJWK, err := jwx.PublicKeyToPublicKeyJWK(kid, key)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not convert public key to JWK: %s", kid)
+	}
+	verifier, err := cryptosuite.NewJSONWebKeyVerifier(id, *publicKeyJWK)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not create JWK verifier: %s", kid)
+	}
+	return &DataIntegrityKeyAccess{
+		Verifier:    *verifier,
+		CryptoSuite: cryptosuite.GetJSONWebSignature2020Suite(),
+	}, nil
+}
+
 // DataIntegrityJSON represents a response from a DataIntegrityKeyAccess.Sign() call represented
 // as a serialized JSON object
 type DataIntegrityJSON struct {
@@ -78,10 +101,151 @@ func (ka DataIntegrityKeyAccess) Verify(payload cryptosuite.Provable) error {
 	return nil
 }
 
-func (ka DataIntegrityKeyAccess) SignVerifiablePresentation(_ string, _ credential.VerifiablePresentation) (*DataIntegrityJSON, error) {
-	return nil, errors.New("not implemented")
+// PresentationProofOptions binds a data integrity presentation to a verifier's request. The challenge prevents the
+// presentation from being replayed, and the domain restricts it to the intended verifier.
+type PresentationProofOptions struct {
+	Challenge string `json:"challenge,omitempty"`
+	Domain    string `json:"domain,omitempty"`
+}
+
+// PresentationProof is a JsonWebSignature2020 proof with an authentication purpose. Unlike the sdk's proof, it
+// carries both the challenge and the domain, which are signed along with the rest of the proof.
+type PresentationProof struct {
+	Type               cryptosuite.SignatureType `json:"type,omitempty"`
+	Created            string                    `json:"created,omitempty"`
+	JWS                string                    `json:"jws,omitempty"`
+	ProofPurpose       cryptosuite.ProofPurpose  `json:"proofPurpose,omitempty"`
+	Challenge          string                    `json:"challenge,omitempty"`
+	Domain             string                    `json:"domain,omitempty"`
+	VerificationMethod string                    `json:"verificationMethod,omitempty"`
+}
+
+// SignVerifiablePresentation signs the presentation with an authentication proof that contains the given challenge
+// and domain.
+func (ka DataIntegrityKeyAccess) SignVerifiablePresentation(presentation credential.VerifiablePresentation, opts PresentationProofOptions) (*DataIntegrityJSON, error) {
+	if err := presentation.IsValid(); err != nil {
+		return nil, errors.Wrap(err, "cannot sign invalid presentation")
+	}
+	if ka.Signer.GetKeyID() == "" {
+		return nil, errors.New("cannot sign with an empty signer")
+	}
+
+	proof := PresentationProof{
+		Type:               ka.CryptoSuite.SignatureAlgorithm(),
+		Created:            sdkutil.GetRFC3339Timestamp(),
+		ProofPurpose:       cryptosuite.Authentication,
+		Challenge:          opts.Challenge,
+		Domain:             opts.Domain,
+		VerificationMethod: ka.Signer.GetKeyID(),
+	}
+	presentation.Proof = nil
+	tbs, err := ka.createVerifyHash(&presentation, proof)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := ka.Signer.Sign(tbs)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not sign presentation")
+	}
+	proof.JWS = string(signature)
+
+	genericProof := crypto.Proof(proof)
+	presentation.SetProof(&genericProof)
+	signedJSONBytes, err := json.Marshal(presentation)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not marshal signed presentation")
+	}
+	return &DataIntegrityJSON{Data: signedJSONBytes}, nil
+}
+
+// VerifyVerifiablePresentation verifies the presentation's authentication proof. When set, the challenge and domain
+// in opts must match the ones in the proof.
+func (ka DataIntegrityKeyAccess) VerifyVerifiablePresentation(presentation *credential.VerifiablePresentation, opts PresentationProofOptions) error {
+	if presentation == nil {
+		return errors.New("presentation cannot be nil")
+	}
+	if presentation.Proof == nil {
+		return errors.New("presentation has no proof")
+	}
+	proof, err := PresentationProofFromGenericProof(*presentation.Proof)
+	if err != nil {
+		return err
+	}
+	if proof.ProofPurpose != cryptosuite.Authentication {
+		return errors.Errorf("presentation proof purpose must be %s, got: %s", cryptosuite.Authentication, proof.ProofPurpose)
+	}
+	if opts.Challenge != "" && proof.Challenge != opts.Challenge {
+		return errors.Errorf("presentation challenge<%s> does not match expected challenge<%s>", proof.Challenge, opts.Challenge)
+	}
+	if opts.Domain != "" && proof.Domain != opts.Domain {
+		return errors.Errorf("presentation domain<%s> does not match expected domain<%s>", proof.Domain, opts.Domain)
+	}
+
+	signature := []byte(proof.JWS)
+	proof.JWS = ""
+	unsigned := *presentation
+	unsigned.Proof = nil
+	tbv, err := ka.createVerifyHash(&unsigned, *proof)
+	if err != nil {
+		return err
+	}
+	if err = ka.Verifier.Verify(tbv, signature); err != nil {
+		return errors.Wrap(err, "could not verify presentation's signature")
+	}
+	return nil
+}
+
+// PresentationProofFromGenericProof parses a presentation proof, exposing its challenge, domain and verification method.
+func PresentationProofFromGenericProof(p crypto.Proof) (*PresentationProof, error) {
+	proofBytes, err := json.Marshal(p)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not marshal proof")
+	}
+	var proof PresentationProof
+	if err = json.Unmarshal(proofBytes, &proof); err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal proof")
+	}
+	return &proof, nil
+}
+
+func (ka DataIntegrityKeyAccess) createVerifyHash(presentation *credential.VerifiablePresentation, proof PresentationProof) ([]byte, error) {
+	suite, ok := ka.CryptoSuite.(cryptosuite.CryptoSuiteProofType)
+	if !ok {
+		return nil, errors.Errorf("crypto suite<%s> cannot create a verify hash", ka.CryptoSuite.ID())
+	}
+	contexts, err := cryptosuite.GetContextsFromProvable(presentation)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get contexts from presentation")
+	}
+	contexts = ensureContexts(contexts, ka.CryptoSuite.RequiredContexts())
+
+	var genericPresentation map[string]any
+	presentationBytes, err := json.Marshal(presentation)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not marshal presentation")
+	}
+	if err = json.Unmarshal(presentationBytes, &genericPresentation); err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal presentation")
+	}
+	tbs, err := suite.CreateVerifyHash(genericPresentation, crypto.Proof(proof), &cryptosuite.ProofOptions{Contexts: contexts})
+	if err != nil {
+		return nil, errors.Wrap(err, "create verify hash algorithm failed")
+	}
+	return tbs, nil
 }
 
-func (ka DataIntegrityKeyAccess) VerifyVerifiablePresentation(_ cryptosuite.Provable) error {
-	return errors.New("not implemented")
+func ensureContexts(contexts []any, required []string) []any {
+	for _, r := range required {
+		found := false
+		for _, c := range contexts {
+			if c == r {
+				found = true
+				break
+			}
+		}
+		if !found {
+			contexts = append(contexts, r)
+		}
+	}
+	return contexts
 }
@@ -1,6 +1,12 @@
 package keyaccess
 
 import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/TBD54566975/ssi-sdk/credential"
@@ -39,6 +45,32 @@ func TestCreateDataIntegrityKeyAccess(t *testing.T) {
 	})
 }
 
+func TestCreateDataIntegrityKeyAccessVerifier(t *testing.T) {
+	t.Run("Create a Verifier - Happy Path", func(tt *testing.T) {
+		pubKey, _, err := crypto.GenerateEd25519Key()
+		assert.NoError(tt, err)
+		ka, err := NewDataIntegrityKeyAccessVerifier("test-id", "test-kid", pubKey)
+		assert.NoError(tt, err)
+		assert.NotEmpty(tt, ka)
+	})
+
+	t.Run("Create a Verifier - Bad Key", func(tt *testing.T) {
+		ka, err := NewDataIntegrityKeyAccessVerifier("test-id", "test-kid", nil)
+		assert.Error(tt, err)
+		assert.Contains(tt, err.Error(), "key cannot be nil")
+		assert.Empty(tt, ka)
+	})
+
+	t.Run("Create a Verifier - No KID", func(tt *testing.T) {
+		pubKey, _, err := crypto.GenerateEd25519Key()
+		assert.NoError(tt, err)
+		ka, err := NewDataIntegrityKeyAccessVerifier("test-id", "", pubKey)
+		assert.Error(tt, err)
+		assert.Contains(tt, err.Error(), "kid cannot be empty")
+		assert.Empty(tt, ka)
+	})
+}
+
 func TestDataIntegrityKeyAccessSignVerify(t *testing.T) {
 	t.Run("Sign and Verify Credential - Happy Path", func(tt *testing.T) {
 		_, privKey, err := crypto.GenerateEd25519Key()
@@ -93,6 +125,10 @@ func TestDataIntegrityKeyAccessSignVerify(t *testing.T) {
 		assert.Error(tt, err)
 		assert.Contains(tt, err.Error(), "payload cannot be nil")
 	})
+}
+
+func TestDataIntegrityKeyAccessSignVerifyPresentation(t *testing.T) {
+	useStaticContexts(t)
 
 	t.Run("Sign and Verify Presentation", func(tt *testing.T) {
 		_, privKey, err := crypto.GenerateEd25519Key()
@@ -116,10 +152,99 @@ func TestDataIntegrityKeyAccessSignVerify(t *testing.T) {
 		// verify
 		err = ka.Verify(&pres)
 		assert.NoError(tt, err)
+	})
+
+	t.Run("Sign and Verify Presentation With Challenge and Domain", func(tt *testing.T) {
+		_, privKey, err := crypto.GenerateEd25519Key()
+		assert.NoError(tt, err)
+		ka, err := NewDataIntegrityKeyAccess("test-id", "test-kid", privKey)
+		assert.NoError(tt, err)
+
+		opts := PresentationProofOptions{Challenge: "test-challenge", Domain: "test-domain"}
+		testPres := getDataIntegrityTestPresentation(*ka)
+		signedPres, err := ka.SignVerifiablePresentation(testPres, opts)
+		assert.NoError(tt, err)
+		assert.NotEmpty(tt, signedPres)
+
+		var pres credential.VerifiablePresentation
+		err = json.Unmarshal(signedPres.Data, &pres)
+		assert.NoError(tt, err)
+
+		proof, err := PresentationProofFromGenericProof(*pres.Proof)
+		assert.NoError(tt, err)
+		assert.Equal(tt, "test-challenge", proof.Challenge)
+		assert.Equal(tt, "test-domain", proof.Domain)
+
+		// verify
+		err = ka.VerifyVerifiablePresentation(&pres, opts)
+		assert.NoError(tt, err)
+
+		// a different challenge
+		err = ka.VerifyVerifiablePresentation(&pres, PresentationProofOptions{Challenge: "other-challenge"})
+		assert.Error(tt, err)
+		assert.Contains(tt, err.Error(), "does not match expected challenge")
+
+		// a different domain
+		err = ka.VerifyVerifiablePresentation(&pres, PresentationProofOptions{Domain: "other-domain"})
+		assert.Error(tt, err)
+		assert.Contains(tt, err.Error(), "does not match expected domain")
+
+		// a tampered challenge
+		tampered := *proof
+		tampered.Challenge = "other-challenge"
+		tamperedProof := crypto.Proof(tampered)
+		pres.Proof = &tamperedProof
+		err = ka.VerifyVerifiablePresentation(&pres, PresentationProofOptions{Challenge: "other-challenge"})
+		assert.Error(tt, err)
+		assert.Contains(tt, err.Error(), "could not verify presentation's signature")
+	})
+
+	t.Run("Verify Presentation - No Proof", func(tt *testing.T) {
+		_, privKey, err := crypto.GenerateEd25519Key()
+		assert.NoError(tt, err)
+		ka, err := NewDataIntegrityKeyAccess("test-id", "test-kid", privKey)
+		assert.NoError(tt, err)
+
+		testPres := getDataIntegrityTestPresentation(*ka)
+		err = ka.VerifyVerifiablePresentation(&testPres, PresentationProofOptions{})
+		assert.Error(tt, err)
+		assert.Contains(tt, err.Error(), "presentation has no proof")
+	})
+}
 
-		// TODO(gabe) enable with https://github.com/TBD54566975/ssi-sdk/issues/352, https://github.com/TBD54566975/ssi-service/issues/105
-		err = ka.VerifyVerifiablePresentation(&pres)
-		assert.Error(t, err)
-		assert.Contains(t, err.Error(), "not implemented")
+// staticContexts are the local copies of the JSON-LD contexts of the test presentations, by URL.
+var staticContexts = map[string]string{
+	"https://www.w3.org/2018/credentials/v1":          "credentials_v1.jsonld",
+	"https://www.w3.org/2018/credentials/examples/v1": "credentials-examples_v1.jsonld",
+	"https://www.w3.org/ns/odrl.jsonld":               "odrl.jsonld",
+	"https://w3id.org/security/suites/jws-2020/v1":    "lds-jws2020_v1.jsonld",
+}
+
+// useStaticContexts serves the JSON-LD contexts of the test presentations from testdata for the duration of the test,
+// since canonicalization otherwise fetches them with the default HTTP client.
+func useStaticContexts(t *testing.T) {
+	transport := http.DefaultClient.Transport
+	http.DefaultClient.Transport = staticContextTransport{}
+	t.Cleanup(func() {
+		http.DefaultClient.Transport = transport
 	})
 }
+
+type staticContextTransport struct{}
+
+func (staticContextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	file, ok := staticContexts[req.URL.String()]
+	if !ok {
+		return nil, fmt.Errorf("no static context for: %s", req.URL)
+	}
+	contextBytes, err := os.ReadFile(filepath.Join("testdata", "contexts", file))
+	if err != nil {
+		return nil, err
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/ld+json"}},
+		Body:       io.NopCloser(bytes.NewReader(contextBytes)),
+		Request:    req,
+	}, nil
+}
@@ -159,8 +159,12 @@ func (ka JWKKeyAccess) VerifyVerifiablePresentation(ctx context.Context, resolve
 	return presentation, err
 }
 
-// GetJWTHeaders returns the headers of a JWT token, assuming there is only one signature.
+// GetJWTHeaders returns the headers of a JWT token, assuming there is only one signature. Tokens signed with ES256K-R
+// are supported too, though their headers have no algorithm.
 func GetJWTHeaders(token []byte) (jws.Headers, error) {
+	if IsRecoverableJWT(JWT(token)) {
+		return recoverableJWTHeaders(JWT(token))
+	}
 	msg, err := jws.Parse(token)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,170 @@
+package keyaccess
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/TBD54566975/ssi-sdk/credential"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	"github.com/goccy/go-json"
+	"github.com/lestrrat-go/jwx/jws"
+	jwsv2 "github.com/lestrrat-go/jwx/v2/jws"
+	jwtv2 "github.com/lestrrat-go/jwx/v2/jwt"
+	"github.com/pkg/errors"
+)
+
+// ES256KR is the JWS algorithm of ECDSA signatures using secp256k1 and SHA-256, followed by the recovery id needed to
+// recover the signer's public key from the signature, as defined in
+// https://identity.foundation/EcdsaSecp256k1RecoverySignature2020/#es256k-r
+// It's used by holders identified by blockchain accounts, such as did:pkh DIDs, whose documents have no public key.
+const ES256KR = "ES256K-R"
+
+// recoverableJWT is a compact JWT signed with ES256K-R. jwx rejects algorithms it doesn't know, so these tokens are
+// parsed here.
+type recoverableJWT struct {
+	headers      map[string]any
+	payload      []byte
+	signingInput []byte
+	signature    []byte
+}
+
+func parseRecoverableJWT(token JWT) (*recoverableJWT, error) {
+	parts := strings.Split(token.String(), ".")
+	if len(parts) != 3 {
+		return nil, errors.New("token is not a compact JWS")
+	}
+	headerBytes, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, errors.Wrap(err, "decoding token header")
+	}
+	var headers map[string]any
+	if err = json.Unmarshal(headerBytes, &headers); err != nil {
+		return nil, errors.Wrap(err, "unmarshalling token header")
+	}
+	if headers[jws.AlgorithmKey] != ES256KR {
+		return nil, fmt.Errorf("token is not signed with %s", ES256KR)
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, errors.Wrap(err, "decoding token payload")
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, errors.Wrap(err, "decoding token signature")
+	}
+	return &recoverableJWT{
+		headers:      headers,
+		payload:      payload,
+		signingInput: []byte(parts[0] + "." + parts[1]),
+		signature:    signature,
+	}, nil
+}
+
+// IsRecoverableJWT is whether the token is a compact JWS signed with ES256K-R.
+func IsRecoverableJWT(token JWT) bool {
+	_, err := parseRecoverableJWT(token)
+	return err == nil
+}
+
+// RecoverJWTSigner returns the public key the ES256K-R signed token was signed with, which is recovered from its
+// signature. The signature is valid for the returned key, so the token is verified by checking that the key is the
+// one expected of the signer.
+func RecoverJWTSigner(token JWT) (*secp256k1.PublicKey, error) {
+	parsed, err := parseRecoverableJWT(token)
+	if err != nil {
+		return nil, err
+	}
+	// the signature is R || S || recovery id, where the recovery id may be offset by 27 as in Ethereum signatures
+	if len(parsed.signature) != 65 {
+		return nil, fmt.Errorf("expected a 65 byte signature, got %d bytes", len(parsed.signature))
+	}
+	recoveryID := parsed.signature[64]
+	if recoveryID >= 27 {
+		recoveryID -= 27
+	}
+	if recoveryID > 3 {
+		return nil, fmt.Errorf("invalid recovery id: %d", parsed.signature[64])
+	}
+	compact := make([]byte, 0, 65)
+	compact = append(compact, 27+recoveryID)
+	compact = append(compact, parsed.signature[:64]...)
+	hash := sha256.Sum256(parsed.signingInput)
+	pubKey, _, err := ecdsa.RecoverCompact(compact, hash[:])
+	if err != nil {
+		return nil, errors.Wrap(err, "recovering public key from signature")
+	}
+	return pubKey, nil
+}
+
+// RecoverableJWTPayload returns the payload of an ES256K-R signed token, without verifying it.
+func RecoverableJWTPayload(token JWT) ([]byte, error) {
+	parsed, err := parseRecoverableJWT(token)
+	if err != nil {
+		return nil, err
+	}
+	return parsed.payload, nil
+}
+
+// ParseVerifiablePresentationFromJWT parses a presentation from a JWT without verifying it, as the sdk does, but also
+// for tokens signed with ES256K-R. The returned headers have no algorithm for those.
+func ParseVerifiablePresentationFromJWT(token JWT) (jwsv2.Headers, jwtv2.Token, *credential.VerifiablePresentation, error) {
+	parsed, err := parseRecoverableJWT(token)
+	if err != nil {
+		return credential.ParseVerifiablePresentationFromJWT(token.String())
+	}
+
+	headers := jwsv2.NewHeaders()
+	for k, v := range parsed.headers {
+		if k == jwsv2.AlgorithmKey {
+			continue
+		}
+		if err = headers.Set(k, v); err != nil {
+			return nil, nil, nil, errors.Wrapf(err, "setting header %s", k)
+		}
+	}
+	claims, err := jwtv2.Parse(parsed.payload, jwtv2.WithVerify(false), jwtv2.WithValidate(false))
+	if err != nil {
+		return nil, nil, nil, errors.Wrap(err, "parsing vp token")
+	}
+	vpClaim, ok := claims.Get(credential.VPJWTProperty)
+	if !ok {
+		return nil, nil, nil, fmt.Errorf("did not find %s property in token", credential.VPJWTProperty)
+	}
+	vpBytes, err := json.Marshal(vpClaim)
+	if err != nil {
+		return nil, nil, nil, errors.Wrap(err, "marshalling vp claim")
+	}
+	var presentation credential.VerifiablePresentation
+	if err = json.Unmarshal(vpBytes, &presentation); err != nil {
+		return nil, nil, nil, errors.Wrap(err, "reconstructing Verifiable Presentation")
+	}
+	if claims.Issuer() == "" {
+		return nil, nil, nil, fmt.Errorf("did not find %s property in token", jwtv2.IssuerKey)
+	}
+	presentation.Holder = claims.Issuer()
+	if claims.JwtID() != "" {
+		presentation.ID = claims.JwtID()
+	}
+	return headers, claims, &presentation, nil
+}
+
+// recoverableJWTHeaders returns the headers of an ES256K-R signed token, except for its algorithm.
+func recoverableJWTHeaders(token JWT) (jws.Headers, error) {
+	parsed, err := parseRecoverableJWT(token)
+	if err != nil {
+		return nil, err
+	}
+	headers := jws.NewHeaders()
+	for k, v := range parsed.headers {
+		if k == jws.AlgorithmKey {
+			continue
+		}
+		if err = headers.Set(k, v); err != nil {
+			return nil, errors.Wrapf(err, "setting header %s", k)
+		}
+	}
+	return headers, nil
+}
@@ -0,0 +1,88 @@
+package keyaccess
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/TBD54566975/ssi-sdk/credential"
+	"github.com/TBD54566975/ssi-sdk/crypto"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	"github.com/goccy/go-json"
+	"github.com/lestrrat-go/jwx/jws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecoverableJWT(t *testing.T) {
+	privKey, err := secp256k1.GeneratePrivateKey()
+	require.NoError(t, err)
+	holder := "did:pkh:eip155:1:0xb9c5714089478a327f09197987f16f9e5d936e8a"
+	kid := holder + "#blockchainAccountId"
+	presentation := credential.VerifiablePresentation{
+		Context: []string{"https://www.w3.org/2018/credentials/v1"},
+		ID:      "presentation-id",
+		Type:    []string{"VerifiablePresentation"},
+	}
+	token := signRecoverableJWT(t, privKey, kid, map[string]any{"iss": holder, "jti": "presentation-id", credential.VPJWTProperty: presentation})
+
+	t.Run("signer is recovered from the signature", func(t *testing.T) {
+		assert.True(t, IsRecoverableJWT(token))
+		signer, err := RecoverJWTSigner(token)
+		require.NoError(t, err)
+		assert.True(t, privKey.PubKey().IsEqual(signer))
+	})
+
+	t.Run("tampered token recovers another key", func(t *testing.T) {
+		otherToken := signRecoverableJWT(t, privKey, kid, map[string]any{"iss": "did:example:other"})
+		parts := strings.Split(token.String(), ".")
+		otherParts := strings.Split(otherToken.String(), ".")
+		tampered := JWT(parts[0] + "." + otherParts[1] + "." + parts[2])
+		signer, err := RecoverJWTSigner(tampered)
+		if err == nil {
+			assert.False(t, privKey.PubKey().IsEqual(signer))
+		}
+	})
+
+	t.Run("presentation and headers are parsed", func(t *testing.T) {
+		headers, claims, vp, err := ParseVerifiablePresentationFromJWT(token)
+		require.NoError(t, err)
+		assert.Equal(t, kid, headers.KeyID())
+		assert.Equal(t, holder, claims.Issuer())
+		assert.Equal(t, holder, vp.Holder)
+		assert.Equal(t, "presentation-id", vp.ID)
+
+		v1Headers, err := GetJWTHeaders([]byte(token))
+		require.NoError(t, err)
+		gotKID, ok := v1Headers.Get(jws.KeyIDKey)
+		require.True(t, ok)
+		assert.Equal(t, kid, gotKID)
+	})
+
+	t.Run("tokens signed with other algorithms are not recoverable", func(t *testing.T) {
+		_, privKey, err := crypto.GenerateSECP256k1Key()
+		require.NoError(t, err)
+		ka, err := NewJWKKeyAccess(holder, kid, privKey)
+		require.NoError(t, err)
+		signed, err := ka.Sign(map[string]any{"iss": holder})
+		require.NoError(t, err)
+		assert.False(t, IsRecoverableJWT(*signed))
+		_, err = RecoverJWTSigner(*signed)
+		assert.ErrorContains(t, err, "token is not signed with ES256K-R")
+	})
+}
+
+// signRecoverableJWT signs the claims with ES256K-R.
+func signRecoverableJWT(t *testing.T, privKey *secp256k1.PrivateKey, kid string, claims map[string]any) JWT {
+	header, err := json.Marshal(map[string]any{"alg": ES256KR, "kid": kid, "typ": "JWT"})
+	require.NoError(t, err)
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	hash := sha256.Sum256([]byte(signingInput))
+	compact := ecdsa.SignCompact(privKey, hash[:], false)
+	signature := append(compact[1:], compact[0]-27)
+	return JWT(signingInput + "." + base64.RawURLEncoding.EncodeToString(signature))
+}
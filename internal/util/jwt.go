@@ -9,8 +9,20 @@ import (
 	"github.com/tbd54566975/ssi-service/internal/keyaccess"
 )
 
-// ParseJWT parses a JWT token and returns the jws signature and jwt claims
+// ParseJWT parses a JWT token and returns the jws signature and jwt claims. jwx can't parse tokens signed with
+// ES256K-R, so only their claims are returned.
 func ParseJWT(token keyaccess.JWT) (*jws.Signature, jwt.Token, error) {
+	if keyaccess.IsRecoverableJWT(token) {
+		payload, err := keyaccess.RecoverableJWTPayload(token)
+		if err != nil {
+			return nil, nil, err
+		}
+		parsedJWT, err := jwt.Parse(payload)
+		if err != nil {
+			return nil, nil, err
+		}
+		return nil, parsedJWT, nil
+	}
 	tokenBytes := []byte(token)
 	parsedJWS, err := jws.Parse(tokenBytes)
 	if err != nil {
@@ -0,0 +1,563 @@
+package authorizationserver
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	sdkutil "github.com/TBD54566975/ssi-sdk/util"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ory/fosite"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/square/go-jose.v2"
+
+	"github.com/tbd54566975/ssi-service/pkg/server/framework"
+)
+
+const (
+	registrationPath  = "/oauth2/register"
+	adminClientsPath  = "/admin/clients"
+	clientSecretBytes = 32
+
+	// Token endpoint auth methods defined in https://www.rfc-editor.org/rfc/rfc7591#section-2 and
+	// https://openid.net/specs/openid-connect-registration-1_0.html#ClientMetadata
+	authMethodNone              = "none"
+	authMethodClientSecretBasic = "client_secret_basic"
+	authMethodClientSecretPost  = "client_secret_post"
+	authMethodPrivateKeyJWT     = "private_key_jwt"
+
+	// Errors defined in https://www.rfc-editor.org/rfc/rfc7591#section-3.2.2
+	invalidRedirectURIError    = "invalid_redirect_uri"
+	invalidClientMetadataError = "invalid_client_metadata"
+)
+
+var (
+	supportedAuthMethods = []string{authMethodNone, authMethodClientSecretBasic, authMethodClientSecretPost, authMethodPrivateKeyJWT}
+
+	// registrationGrantTypes are the grant types clients can register with. Only admins can create clients with the
+	// password grant.
+	registrationGrantTypes = []string{"authorization_code", "implicit", "refresh_token", "client_credentials", "urn:ietf:params:oauth:grant-type:jwt-bearer"}
+	adminGrantTypes        = append([]string{"password"}, registrationGrantTypes...)
+
+	supportedResponseTypes = []string{"code", "token", "id_token", "code id_token", "code token", "id_token token", "code id_token token"}
+)
+
+// Client is a client of the authorization server. Besides what fosite needs to authenticate it, it keeps the metadata
+// it was registered with.
+type Client struct {
+	fosite.DefaultOpenIDConnectClient
+
+	Name            string   `json:"client_name,omitempty"`
+	URI             string   `json:"client_uri,omitempty"`
+	LogoURI         string   `json:"logo_uri,omitempty"`
+	Contacts        []string `json:"contacts,omitempty"`
+	TOSURI          string   `json:"tos_uri,omitempty"`
+	PolicyURI       string   `json:"policy_uri,omitempty"`
+	SoftwareID      string   `json:"software_id,omitempty"`
+	SoftwareVersion string   `json:"software_version,omitempty"`
+	IssuedAt        int64    `json:"client_id_issued_at,omitempty"`
+
+	RequirePKCE                        bool `json:"require_pkce,omitempty"`
+	RequirePushedAuthorizationRequests bool `json:"require_pushed_authorization_requests,omitempty"`
+}
+
+func (c *Client) metadata() ClientMetadata {
+	return ClientMetadata{
+		RedirectURIs:                       c.RedirectURIs,
+		TokenEndpointAuthMethod:            c.TokenEndpointAuthMethod,
+		TokenEndpointAuthSigningAlg:        c.TokenEndpointAuthSigningAlgorithm,
+		GrantTypes:                         c.GetGrantTypes(),
+		ResponseTypes:                      c.GetResponseTypes(),
+		ClientName:                         c.Name,
+		ClientURI:                          c.URI,
+		LogoURI:                            c.LogoURI,
+		Scope:                              strings.Join(c.Scopes, " "),
+		Contacts:                           c.Contacts,
+		TOSURI:                             c.TOSURI,
+		PolicyURI:                          c.PolicyURI,
+		JWKSURI:                            c.JSONWebKeysURI,
+		JWKS:                               c.JSONWebKeys,
+		SoftwareID:                         c.SoftwareID,
+		SoftwareVersion:                    c.SoftwareVersion,
+		RequirePKCE:                        c.RequirePKCE,
+		RequirePushedAuthorizationRequests: c.RequirePushedAuthorizationRequests,
+	}
+}
+
+// ClientMetadata is the metadata clients are registered with, as defined in https://www.rfc-editor.org/rfc/rfc7591#section-2
+type ClientMetadata struct {
+	RedirectURIs []string `json:"redirect_uris,omitempty"`
+
+	// One of none, client_secret_basic, client_secret_post or private_key_jwt. Defaults to client_secret_basic.
+	// Clients with none are public clients, such as wallets, which can't keep a secret.
+	TokenEndpointAuthMethod string `json:"token_endpoint_auth_method,omitempty"`
+
+	// The algorithm of the JWTs private_key_jwt clients authenticate with. Defaults to RS256.
+	TokenEndpointAuthSigningAlg string `json:"token_endpoint_auth_signing_alg,omitempty"`
+
+	// Defaults to authorization_code.
+	GrantTypes []string `json:"grant_types,omitempty"`
+
+	// Defaults to code.
+	ResponseTypes []string `json:"response_types,omitempty"`
+
+	ClientName string `json:"client_name,omitempty"`
+	ClientURI  string `json:"client_uri,omitempty"`
+	LogoURI    string `json:"logo_uri,omitempty"`
+
+	// Space separated scopes the client can request. Defaults to openid.
+	Scope string `json:"scope,omitempty"`
+
+	Contacts  []string `json:"contacts,omitempty"`
+	TOSURI    string   `json:"tos_uri,omitempty"`
+	PolicyURI string   `json:"policy_uri,omitempty"`
+
+	// The keys private_key_jwt clients authenticate with, either by reference or by value.
+	JWKSURI string              `json:"jwks_uri,omitempty"`
+	JWKS    *jose.JSONWebKeySet `json:"jwks,omitempty" swaggertype:"object"`
+
+	SoftwareID      string `json:"software_id,omitempty"`
+	SoftwareVersion string `json:"software_version,omitempty"`
+
+	// Whether the client must use PKCE in the authorization code flow, even when it's confidential.
+	RequirePKCE bool `json:"require_pkce,omitempty"`
+
+	// Whether the client can only send authorization requests through the pushed authorization request endpoint, as
+	// defined in https://www.rfc-editor.org/rfc/rfc9126#section-6
+	RequirePushedAuthorizationRequests bool `json:"require_pushed_authorization_requests,omitempty"`
+}
+
+// ClientInformation is the response of a successful registration, as defined in https://www.rfc-editor.org/rfc/rfc7591#section-3.2.1
+type ClientInformation struct {
+	ClientID         string `json:"client_id"`
+	ClientIDIssuedAt int64  `json:"client_id_issued_at,omitempty"`
+
+	// Only present when the secret is issued. It's not stored, so it can't be retrieved again.
+	ClientSecret          string `json:"client_secret,omitempty"`
+	ClientSecretExpiresAt *int64 `json:"client_secret_expires_at,omitempty"`
+
+	ClientMetadata
+}
+
+// ClientRegistrationError is the body of an error response to a registration, as defined in
+// https://www.rfc-editor.org/rfc/rfc7591#section-3.2.2
+type ClientRegistrationError struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description,omitempty"`
+}
+
+type ListClientsResponse struct {
+	Clients []ClientInformation `json:"clients"`
+}
+
+// clientMetadataError is an error in the metadata of a client, which is returned to the client with its code.
+type clientMetadataError struct {
+	code        string
+	description string
+}
+
+func (e clientMetadataError) Error() string {
+	return e.description
+}
+
+func invalidClientMetadata(format string, args ...any) error {
+	return clientMetadataError{code: invalidClientMetadataError, description: fmt.Sprintf(format, args...)}
+}
+
+func invalidRedirectURI(format string, args ...any) error {
+	return clientMetadataError{code: invalidRedirectURIError, description: fmt.Sprintf(format, args...)}
+}
+
+// clientPolicy is what clients can be registered with.
+type clientPolicy struct {
+	// Whether public clients, which authenticate with none, can be registered.
+	allowPublicClients bool
+
+	// When set, dynamic registration requires it as a bearer token, as the initial access token of
+	// https://www.rfc-editor.org/rfc/rfc7591#section-3
+	initialAccessToken string
+}
+
+// RegisterClient godoc
+//
+//	@Summary		Register client
+//	@Description	Registers an OAuth 2.0 client, as defined in https://www.rfc-editor.org/rfc/rfc7591
+//	@Tags			ClientAPI
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		ClientMetadata	true	"request body"
+//	@Success		201		{object}	ClientInformation
+//	@Failure		400		{object}	ClientRegistrationError
+//	@Failure		401		{string}	string	"Unauthorized"
+//	@Failure		500		{string}	string	"Internal server error"
+//	@Router			/oauth2/register [post]
+func (s AuthService) RegisterClient(c *gin.Context) {
+	if s.clientPolicy.initialAccessToken != "" && !hasBearerToken(c.Request, s.clientPolicy.initialAccessToken) {
+		framework.LoggingRespondErrMsg(c, "registration requires a valid initial access token", http.StatusUnauthorized)
+		return
+	}
+	var metadata ClientMetadata
+	if err := framework.Decode(c.Request, &metadata); err != nil {
+		writeRegistrationError(c, invalidClientMetadata("could not decode client metadata"))
+		return
+	}
+	info, err := s.createClient(c, uuid.NewString(), metadata, registrationGrantTypes)
+	if err != nil {
+		writeRegistrationError(c, err)
+		return
+	}
+	framework.Respond(c, info, http.StatusCreated)
+}
+
+// CreateClient godoc
+//
+//	@Summary		Create client
+//	@Description	Creates an OAuth 2.0 client. Unlike registered clients, they can use the password grant.
+//	@Tags			ClientAPI
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		ClientMetadata	true	"request body"
+//	@Success		201		{object}	ClientInformation
+//	@Failure		400		{object}	ClientRegistrationError
+//	@Failure		401		{string}	string	"Unauthorized"
+//	@Failure		500		{string}	string	"Internal server error"
+//	@Router			/admin/clients [put]
+func (s AuthService) CreateClient(c *gin.Context) {
+	var metadata ClientMetadata
+	if err := framework.Decode(c.Request, &metadata); err != nil {
+		writeRegistrationError(c, invalidClientMetadata("could not decode client metadata"))
+		return
+	}
+	info, err := s.createClient(c, uuid.NewString(), metadata, adminGrantTypes)
+	if err != nil {
+		writeRegistrationError(c, err)
+		return
+	}
+	framework.Respond(c, info, http.StatusCreated)
+}
+
+// GetClient godoc
+//
+//	@Summary		Get client
+//	@Description	Returns the metadata of an OAuth 2.0 client. Its secret is never returned.
+//	@Tags			ClientAPI
+//	@Produce		json
+//	@Param			id	path		string	true	"ID"
+//	@Success		200	{object}	ClientInformation
+//	@Failure		401	{string}	string	"Unauthorized"
+//	@Failure		404	{string}	string	"Not found"
+//	@Failure		500	{string}	string	"Internal server error"
+//	@Router			/admin/clients/{id} [get]
+func (s AuthService) GetClient(c *gin.Context) {
+	id := framework.GetParam(c, "id")
+	if id == nil {
+		framework.LoggingRespondErrMsg(c, "cannot get client without ID parameter", http.StatusBadRequest)
+		return
+	}
+	client, err := s.getClient(c, *id)
+	if err != nil {
+		framework.LoggingRespondErrWithMsg(c, err, "could not get client", getClientErrorStatus(err))
+		return
+	}
+	framework.Respond(c, clientInformation(client, ""), http.StatusOK)
+}
+
+// ListClients godoc
+//
+//	@Summary		List clients
+//	@Description	Lists the OAuth 2.0 clients.
+//	@Tags			ClientAPI
+//	@Produce		json
+//	@Success		200	{object}	ListClientsResponse
+//	@Failure		401	{string}	string	"Unauthorized"
+//	@Failure		500	{string}	string	"Internal server error"
+//	@Router			/admin/clients [get]
+func (s AuthService) ListClients(c *gin.Context) {
+	clients, err := s.store.ListClients(c)
+	if err != nil {
+		framework.LoggingRespondErrWithMsg(c, err, "could not list clients", http.StatusInternalServerError)
+		return
+	}
+	resp := ListClientsResponse{Clients: make([]ClientInformation, 0, len(clients))}
+	for i := range clients {
+		resp.Clients = append(resp.Clients, clientInformation(&clients[i], ""))
+	}
+	framework.Respond(c, resp, http.StatusOK)
+}
+
+// UpdateClient godoc
+//
+//	@Summary		Update client
+//	@Description	Replaces the metadata of an OAuth 2.0 client. Its secret is kept, unless the client becomes confidential, in which case a secret is issued.
+//	@Tags			ClientAPI
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string			true	"ID"
+//	@Param			request	body		ClientMetadata	true	"request body"
+//	@Success		200		{object}	ClientInformation
+//	@Failure		400		{object}	ClientRegistrationError
+//	@Failure		401		{string}	string	"Unauthorized"
+//	@Failure		404		{string}	string	"Not found"
+//	@Failure		500		{string}	string	"Internal server error"
+//	@Router			/admin/clients/{id} [put]
+func (s AuthService) UpdateClient(c *gin.Context) {
+	id := framework.GetParam(c, "id")
+	if id == nil {
+		framework.LoggingRespondErrMsg(c, "cannot update client without ID parameter", http.StatusBadRequest)
+		return
+	}
+	existing, err := s.getClient(c, *id)
+	if err != nil {
+		framework.LoggingRespondErrWithMsg(c, err, "could not get client", getClientErrorStatus(err))
+		return
+	}
+	var metadata ClientMetadata
+	if err = framework.Decode(c.Request, &metadata); err != nil {
+		writeRegistrationError(c, invalidClientMetadata("could not decode client metadata"))
+		return
+	}
+	client, err := newClient(*id, metadata, adminGrantTypes, s.clientPolicy)
+	if err != nil {
+		writeRegistrationError(c, err)
+		return
+	}
+	client.IssuedAt = existing.IssuedAt
+	var secret string
+	if !client.Public {
+		if len(existing.Secret) > 0 {
+			client.Secret = existing.Secret
+		} else if secret, err = s.issueSecret(c, client); err != nil {
+			framework.LoggingRespondErrWithMsg(c, err, "could not issue client secret", http.StatusInternalServerError)
+			return
+		}
+	}
+	if err = s.store.StoreClient(c, client); err != nil {
+		framework.LoggingRespondErrWithMsg(c, err, "could not store client", http.StatusInternalServerError)
+		return
+	}
+	framework.Respond(c, clientInformation(client, secret), http.StatusOK)
+}
+
+// DeleteClient godoc
+//
+//	@Summary		Delete client
+//	@Description	Deletes an OAuth 2.0 client. The tokens already issued to it stay valid until they expire.
+//	@Tags			ClientAPI
+//	@Param			id	path		string	true	"ID"
+//	@Success		204	{string}	string	"No Content"
+//	@Failure		401	{string}	string	"Unauthorized"
+//	@Failure		500	{string}	string	"Internal server error"
+//	@Router			/admin/clients/{id} [delete]
+func (s AuthService) DeleteClient(c *gin.Context) {
+	id := framework.GetParam(c, "id")
+	if id == nil {
+		framework.LoggingRespondErrMsg(c, "cannot delete client without ID parameter", http.StatusBadRequest)
+		return
+	}
+	if err := s.store.DeleteClient(c, *id); err != nil {
+		framework.LoggingRespondErrWithMsg(c, err, "could not delete client", http.StatusInternalServerError)
+		return
+	}
+	framework.Respond(c, nil, http.StatusNoContent)
+}
+
+func (s AuthService) createClient(ctx context.Context, id string, metadata ClientMetadata, allowedGrantTypes []string) (*ClientInformation, error) {
+	client, err := newClient(id, metadata, allowedGrantTypes, s.clientPolicy)
+	if err != nil {
+		return nil, err
+	}
+	client.IssuedAt = time.Now().Unix()
+	var secret string
+	if !client.Public {
+		if secret, err = s.issueSecret(ctx, client); err != nil {
+			return nil, err
+		}
+	}
+	if err = s.store.StoreClient(ctx, client); err != nil {
+		return nil, errors.Wrap(err, "storing client")
+	}
+	info := clientInformation(client, secret)
+	return &info, nil
+}
+
+func (s AuthService) getClient(ctx context.Context, id string) (*Client, error) {
+	client, err := s.store.GetClient(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return client.(*Client), nil
+}
+
+// getClientErrorStatus is the status to respond with when a client can't be read. Only missing clients are not found.
+func getClientErrorStatus(err error) int {
+	if errors.Is(err, fosite.ErrNotFound) {
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
+
+// issueSecret generates a secret for the client, and keeps its hash.
+func (s AuthService) issueSecret(ctx context.Context, client *Client) (string, error) {
+	secretBytes := make([]byte, clientSecretBytes)
+	if _, err := rand.Read(secretBytes); err != nil {
+		return "", errors.Wrap(err, "generating client secret")
+	}
+	secret := base64.RawURLEncoding.EncodeToString(secretBytes)
+	hashedSecret, err := s.hasher.Hash(ctx, []byte(secret))
+	if err != nil {
+		return "", errors.Wrap(err, "hashing client secret")
+	}
+	client.Secret = hashedSecret
+	return secret, nil
+}
+
+// newClient validates the metadata against the policy, and creates a client with it. Defaults are applied as defined
+// in https://www.rfc-editor.org/rfc/rfc7591#section-2
+func newClient(id string, metadata ClientMetadata, allowedGrantTypes []string, policy clientPolicy) (*Client, error) {
+	authMethod := metadata.TokenEndpointAuthMethod
+	if authMethod == "" {
+		authMethod = authMethodClientSecretBasic
+	}
+	if !sdkutil.Contains(authMethod, supportedAuthMethods) {
+		return nil, invalidClientMetadata("token_endpoint_auth_method %q is not supported", authMethod)
+	}
+	public := authMethod == authMethodNone
+	if public && !policy.allowPublicClients {
+		return nil, invalidClientMetadata("public clients are not allowed")
+	}
+	if authMethod == authMethodPrivateKeyJWT && (metadata.JWKS == nil) == (metadata.JWKSURI == "") {
+		return nil, invalidClientMetadata("private_key_jwt clients must have either jwks or jwks_uri")
+	}
+	if metadata.JWKS != nil {
+		for i, key := range metadata.JWKS.Keys {
+			if !key.IsPublic() {
+				return nil, invalidClientMetadata("jwks.keys[%d] must be a public key", i)
+			}
+		}
+	}
+
+	grantTypes := metadata.GrantTypes
+	if len(grantTypes) == 0 {
+		grantTypes = []string{"authorization_code"}
+	}
+	for _, grantType := range grantTypes {
+		if !sdkutil.Contains(grantType, allowedGrantTypes) {
+			return nil, invalidClientMetadata("grant_type %q is not allowed", grantType)
+		}
+	}
+	if public && sdkutil.Contains("client_credentials", grantTypes) {
+		return nil, invalidClientMetadata("public clients can't use the client_credentials grant")
+	}
+
+	responseTypes := metadata.ResponseTypes
+	if len(responseTypes) == 0 {
+		responseTypes = []string{"code"}
+	}
+	for _, responseType := range responseTypes {
+		if !sdkutil.Contains(responseType, supportedResponseTypes) {
+			return nil, invalidClientMetadata("response_type %q is not supported", responseType)
+		}
+	}
+
+	redirected := sdkutil.Contains("authorization_code", grantTypes) || sdkutil.Contains("implicit", grantTypes)
+	if redirected && len(metadata.RedirectURIs) == 0 {
+		return nil, invalidRedirectURI("redirect_uris are required for the authorization_code and implicit grants")
+	}
+	for _, redirectURI := range metadata.RedirectURIs {
+		u, err := url.Parse(redirectURI)
+		if err != nil || !u.IsAbs() || u.Fragment != "" {
+			return nil, invalidRedirectURI("redirect_uri %q must be an absolute URI without a fragment", redirectURI)
+		}
+	}
+
+	scopes := strings.Fields(metadata.Scope)
+	if len(scopes) == 0 {
+		scopes = []string{"openid"}
+	}
+
+	return &Client{
+		DefaultOpenIDConnectClient: fosite.DefaultOpenIDConnectClient{
+			DefaultClient: &fosite.DefaultClient{
+				ID:            id,
+				RedirectURIs:  metadata.RedirectURIs,
+				GrantTypes:    grantTypes,
+				ResponseTypes: responseTypes,
+				Scopes:        scopes,
+				Public:        public,
+			},
+			JSONWebKeysURI:                    metadata.JWKSURI,
+			JSONWebKeys:                       metadata.JWKS,
+			TokenEndpointAuthMethod:           authMethod,
+			TokenEndpointAuthSigningAlgorithm: metadata.TokenEndpointAuthSigningAlg,
+		},
+		Name:            metadata.ClientName,
+		URI:             metadata.ClientURI,
+		LogoURI:         metadata.LogoURI,
+		Contacts:        metadata.Contacts,
+		TOSURI:          metadata.TOSURI,
+		PolicyURI:       metadata.PolicyURI,
+		SoftwareID:      metadata.SoftwareID,
+		SoftwareVersion: metadata.SoftwareVersion,
+
+		RequirePKCE:                        metadata.RequirePKCE,
+		RequirePushedAuthorizationRequests: metadata.RequirePushedAuthorizationRequests,
+	}, nil
+}
+
+// clientInformation describes the client. The secret is only included when it was just issued.
+func clientInformation(client *Client, secret string) ClientInformation {
+	info := ClientInformation{
+		ClientID:         client.GetID(),
+		ClientIDIssuedAt: client.IssuedAt,
+		ClientMetadata:   client.metadata(),
+	}
+	if secret != "" {
+		// secrets don't expire
+		var expiresAt int64
+		info.ClientSecret = secret
+		info.ClientSecretExpiresAt = &expiresAt
+	}
+	return info
+}
+
+func defaultTokenEndpointAuthMethod(public bool) string {
+	if public {
+		return authMethodNone
+	}
+	return authMethodClientSecretBasic
+}
+
+func writeRegistrationError(c *gin.Context, err error) {
+	var metadataErr clientMetadataError
+	if errors.As(err, &metadataErr) {
+		logrus.WithError(err).Error("invalid client metadata")
+		c.JSON(http.StatusBadRequest, ClientRegistrationError{Error: metadataErr.code, ErrorDescription: metadataErr.description})
+		return
+	}
+	framework.LoggingRespondErrWithMsg(c, err, "could not register client", http.StatusInternalServerError)
+}
+
+// requireAdminToken only lets through requests with the admin token as their bearer token.
+func requireAdminToken(adminToken string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !hasBearerToken(c.Request, adminToken) {
+			framework.LoggingRespondErrMsg(c, "a valid admin token is required", http.StatusUnauthorized)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+func hasBearerToken(r *http.Request, token string) bool {
+	bearer, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return found && subtle.ConstantTimeCompare([]byte(bearer), []byte(token)) == 1
+}
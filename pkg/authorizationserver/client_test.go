@@ -0,0 +1,266 @@
+package authorizationserver
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/ory/fosite"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterClient(t *testing.T) {
+	t.Run("confidential client gets a secret it can authenticate with", func(t *testing.T) {
+		resp := postClientMetadata(t, http.MethodPost, registrationPath, "", ClientMetadata{
+			ClientName: "issuer backend",
+			GrantTypes: []string{"client_credentials"},
+			Scope:      "openid",
+		})
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		var info ClientInformation
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&info))
+		assert.NotEmpty(t, info.ClientID)
+		assert.NotZero(t, info.ClientIDIssuedAt)
+		assert.NotEmpty(t, info.ClientSecret)
+		require.NotNil(t, info.ClientSecretExpiresAt)
+		assert.Zero(t, *info.ClientSecretExpiresAt)
+		assert.Equal(t, authMethodClientSecretBasic, info.TokenEndpointAuthMethod)
+		assert.Equal(t, "issuer backend", info.ClientName)
+
+		form := url.Values{}
+		form.Set("grant_type", "client_credentials")
+		req, err := http.NewRequest(http.MethodPost, server.URL+tokenPath, strings.NewReader(form.Encode()))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.SetBasicAuth(info.ClientID, info.ClientSecret)
+		tokenResp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer tokenResp.Body.Close()
+		assert.Equal(t, http.StatusOK, tokenResp.StatusCode)
+	})
+
+	t.Run("public client must use PKCE", func(t *testing.T) {
+		createUniversityDegreeTemplate(t)
+		var code string
+		clientServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			code = r.URL.Query().Get("code")
+		}))
+		defer clientServer.Close()
+
+		resp := postClientMetadata(t, http.MethodPost, registrationPath, "", ClientMetadata{
+			RedirectURIs:            []string{clientServer.URL},
+			TokenEndpointAuthMethod: authMethodNone,
+		})
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		var info ClientInformation
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&info))
+		assert.Empty(t, info.ClientSecret)
+		assert.Nil(t, info.ClientSecretExpiresAt)
+
+		u, err := url.Parse(server.URL + authorizationPath)
+		require.NoError(t, err)
+		query := createQuery(u, info.ClientID, clientServer.URL, universityDegreeAuthorizationDetails)
+		u.RawQuery = query.Encode()
+		authResp, err := http.Post(u.String(), "application/x-www-form-urlencoded", strings.NewReader(createForm().Encode()))
+		require.NoError(t, err)
+		require.NoError(t, authResp.Body.Close())
+		assert.Empty(t, code)
+
+		verifier := "a-code-verifier-that-is-long-enough-for-pkce-0123456789"
+		challenge := sha256.Sum256([]byte(verifier))
+		query.Set("code_challenge", base64.RawURLEncoding.EncodeToString(challenge[:]))
+		query.Set("code_challenge_method", "S256")
+		u.RawQuery = query.Encode()
+		authResp, err = http.Post(u.String(), "application/x-www-form-urlencoded", strings.NewReader(createForm().Encode()))
+		require.NoError(t, err)
+		require.NoError(t, authResp.Body.Close())
+		require.NotEmpty(t, code)
+
+		form := url.Values{}
+		form.Set("grant_type", "authorization_code")
+		form.Set("code", code)
+		form.Set("redirect_uri", clientServer.URL)
+		form.Set("client_id", info.ClientID)
+		form.Set("code_verifier", verifier)
+		tokenResp, err := http.Post(server.URL+tokenPath, "application/x-www-form-urlencoded", strings.NewReader(form.Encode()))
+		require.NoError(t, err)
+		defer tokenResp.Body.Close()
+		assert.Equal(t, http.StatusOK, tokenResp.StatusCode)
+	})
+
+	t.Run("invalid metadata is rejected", func(t *testing.T) {
+		testCases := []struct {
+			name      string
+			metadata  ClientMetadata
+			wantError string
+		}{
+			{
+				name:      "authorization code grant without redirect URIs",
+				metadata:  ClientMetadata{},
+				wantError: invalidRedirectURIError,
+			},
+			{
+				name:      "redirect URI with a fragment",
+				metadata:  ClientMetadata{RedirectURIs: []string{"https://wallet.example.com/callback#fragment"}},
+				wantError: invalidRedirectURIError,
+			},
+			{
+				name:      "unsupported auth method",
+				metadata:  ClientMetadata{RedirectURIs: []string{"https://wallet.example.com/callback"}, TokenEndpointAuthMethod: "client_secret_jwt"},
+				wantError: invalidClientMetadataError,
+			},
+			{
+				name:      "password grant",
+				metadata:  ClientMetadata{GrantTypes: []string{"password"}},
+				wantError: invalidClientMetadataError,
+			},
+			{
+				name:      "private_key_jwt without keys",
+				metadata:  ClientMetadata{GrantTypes: []string{"client_credentials"}, TokenEndpointAuthMethod: authMethodPrivateKeyJWT},
+				wantError: invalidClientMetadataError,
+			},
+			{
+				name:      "public client with the client credentials grant",
+				metadata:  ClientMetadata{GrantTypes: []string{"client_credentials"}, TokenEndpointAuthMethod: authMethodNone},
+				wantError: invalidClientMetadataError,
+			},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				resp := postClientMetadata(t, http.MethodPost, registrationPath, "", tc.metadata)
+				assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+				var registrationError ClientRegistrationError
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&registrationError))
+				assert.Equal(t, tc.wantError, registrationError.Error)
+			})
+		}
+	})
+
+	t.Run("public clients can be disallowed", func(t *testing.T) {
+		metadata := ClientMetadata{RedirectURIs: []string{"https://wallet.example.com/callback"}, TokenEndpointAuthMethod: authMethodNone}
+		_, err := newClient("a-client", metadata, registrationGrantTypes, clientPolicy{allowPublicClients: false})
+		assert.ErrorContains(t, err, "public clients are not allowed")
+		client, err := newClient("a-client", metadata, registrationGrantTypes, clientPolicy{allowPublicClients: true})
+		require.NoError(t, err)
+		assert.True(t, client.IsPublic())
+	})
+
+	t.Run("registration endpoint is in the discovery metadata", func(t *testing.T) {
+		resp, err := http.Get(server.URL + openIDConfigurationPath)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var metadata ProviderMetadata
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&metadata))
+		assert.Equal(t, "https://auth-server.example.com/oauth2/register", metadata.RegistrationEndpoint)
+	})
+}
+
+func TestAdminClients(t *testing.T) {
+	t.Run("admin token is required", func(t *testing.T) {
+		resp := postClientMetadata(t, http.MethodPut, adminClientsPath, "", ClientMetadata{GrantTypes: []string{"password"}})
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		resp = postClientMetadata(t, http.MethodPut, adminClientsPath, "not-the-admin-token", ClientMetadata{GrantTypes: []string{"password"}})
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("clients are created, read, updated and deleted", func(t *testing.T) {
+		resp := postClientMetadata(t, http.MethodPut, adminClientsPath, testAdminToken, ClientMetadata{
+			ClientName: "first party app",
+			GrantTypes: []string{"password", "refresh_token"},
+			Scope:      "openid offline",
+		})
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		var created ClientInformation
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&created))
+		assert.NotEmpty(t, created.ClientSecret)
+		clientPath := adminClientsPath + "/" + created.ClientID
+
+		got := getClientInformation(t, clientPath)
+		assert.Equal(t, created.ClientID, got.ClientID)
+		assert.Equal(t, "first party app", got.ClientName)
+		assert.Equal(t, []string{"password", "refresh_token"}, got.GrantTypes)
+		assert.Equal(t, "openid offline", got.Scope)
+		assert.Empty(t, got.ClientSecret)
+
+		listResp := doAdminRequest(t, http.MethodGet, adminClientsPath, nil)
+		require.Equal(t, http.StatusOK, listResp.StatusCode)
+		var clients ListClientsResponse
+		require.NoError(t, json.NewDecoder(listResp.Body).Decode(&clients))
+		assert.Contains(t, clients.Clients, got)
+
+		// the secret is kept when the metadata is replaced
+		resp = postClientMetadata(t, http.MethodPut, clientPath, testAdminToken, ClientMetadata{
+			ClientName: "renamed app",
+			GrantTypes: []string{"client_credentials"},
+		})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var updated ClientInformation
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&updated))
+		assert.Empty(t, updated.ClientSecret)
+		assert.Equal(t, created.ClientIDIssuedAt, updated.ClientIDIssuedAt)
+		assert.Equal(t, "renamed app", getClientInformation(t, clientPath).ClientName)
+
+		form := url.Values{}
+		form.Set("grant_type", "client_credentials")
+		req, err := http.NewRequest(http.MethodPost, server.URL+tokenPath, strings.NewReader(form.Encode()))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.SetBasicAuth(created.ClientID, created.ClientSecret)
+		tokenResp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer tokenResp.Body.Close()
+		assert.Equal(t, http.StatusOK, tokenResp.StatusCode)
+
+		deleteResp := doAdminRequest(t, http.MethodDelete, clientPath, nil)
+		assert.Equal(t, http.StatusNoContent, deleteResp.StatusCode)
+		getResp := doAdminRequest(t, http.MethodGet, clientPath, nil)
+		assert.Equal(t, http.StatusNotFound, getResp.StatusCode)
+	})
+}
+
+func TestGetClientErrorStatus(t *testing.T) {
+	assert.Equal(t, http.StatusNotFound, getClientErrorStatus(fosite.ErrNotFound))
+	assert.Equal(t, http.StatusNotFound, getClientErrorStatus(errors.Wrap(fosite.ErrNotFound, "reading client")))
+	assert.Equal(t, http.StatusInternalServerError, getClientErrorStatus(errors.New("storage is unavailable")))
+}
+
+func postClientMetadata(t *testing.T, method, path, token string, metadata ClientMetadata) *http.Response {
+	body, err := json.Marshal(metadata)
+	require.NoError(t, err)
+	req, err := http.NewRequest(method, server.URL+path, bytes.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = resp.Body.Close() })
+	return resp
+}
+
+func doAdminRequest(t *testing.T, method, path string, body []byte) *http.Response {
+	req, err := http.NewRequest(method, server.URL+path, bytes.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+testAdminToken)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = resp.Body.Close() })
+	return resp
+}
+
+func getClientInformation(t *testing.T, path string) ClientInformation {
+	resp := doAdminRequest(t, http.MethodGet, path, nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var info ClientInformation
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&info))
+	return info
+}
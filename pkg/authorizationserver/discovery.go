@@ -0,0 +1,100 @@
+package authorizationserver
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/TBD54566975/ssi-sdk/oidc/issuance"
+	"github.com/gin-gonic/gin"
+	"gopkg.in/square/go-jose.v2"
+
+	"github.com/tbd54566975/ssi-service/pkg/authorizationserver/request"
+	"github.com/tbd54566975/ssi-service/pkg/server/framework"
+)
+
+const (
+	authorizationPath            = "/oauth2/auth"
+	tokenPath                    = "/oauth2/token"
+	jwksPath                     = "/.well-known/jwks.json"
+	openIDConfigurationPath      = "/.well-known/openid-configuration"
+	oauthAuthorizationServerPath = "/.well-known/oauth-authorization-server"
+)
+
+// ProviderMetadata describes the authorization server, as defined in both
+// https://openid.net/specs/openid-connect-discovery-1_0.html#ProviderMetadata and
+// https://www.rfc-editor.org/rfc/rfc8414#section-2
+type ProviderMetadata struct {
+	Issuer                             string   `json:"issuer"`
+	AuthorizationEndpoint              string   `json:"authorization_endpoint"`
+	TokenEndpoint                      string   `json:"token_endpoint"`
+	JWKSURI                            string   `json:"jwks_uri"`
+	RegistrationEndpoint               string   `json:"registration_endpoint,omitempty"`
+	ResponseTypesSupported             []string `json:"response_types_supported"`
+	GrantTypesSupported                []string `json:"grant_types_supported"`
+	SubjectTypesSupported              []string `json:"subject_types_supported"`
+	IDTokenSigningAlgValuesSupported   []string `json:"id_token_signing_alg_values_supported"`
+	TokenEndpointAuthMethodsSupported  []string `json:"token_endpoint_auth_methods_supported"`
+	CodeChallengeMethodsSupported      []string `json:"code_challenge_methods_supported"`
+	AuthorizationDetailsTypesSupported []string `json:"authorization_details_types_supported"`
+
+	// Defined in https://www.rfc-editor.org/rfc/rfc9126#section-5
+	PushedAuthorizationRequestEndpoint string `json:"pushed_authorization_request_endpoint"`
+	RequirePushedAuthorizationRequests bool   `json:"require_pushed_authorization_requests"`
+
+	// Defined in https://openid.net/specs/openid-4-verifiable-credential-issuance-1_0.html#name-oauth-20-authorization-serv
+	PreAuthorizedGrantAnonymousAccessSupported bool `json:"pre-authorized_grant_anonymous_access_supported"`
+}
+
+func newProviderMetadata(issuer string) ProviderMetadata {
+	return ProviderMetadata{
+		Issuer:                 issuer,
+		AuthorizationEndpoint:  issuer + authorizationPath,
+		TokenEndpoint:          issuer + tokenPath,
+		JWKSURI:                issuer + jwksPath,
+		ResponseTypesSupported: []string{"code", "token", "id_token", "code id_token", "code token", "id_token token", "code id_token token"},
+		GrantTypesSupported: []string{
+			"authorization_code",
+			"implicit",
+			"refresh_token",
+			"client_credentials",
+			"urn:ietf:params:oauth:grant-type:jwt-bearer",
+			preAuthorizedCodeGrantType,
+		},
+		SubjectTypesSupported:                      []string{"public"},
+		IDTokenSigningAlgValuesSupported:           []string{string(jose.RS256)},
+		TokenEndpointAuthMethodsSupported:          []string{"client_secret_basic", "client_secret_post", "private_key_jwt", "none"},
+		CodeChallengeMethodsSupported:              []string{"S256"},
+		AuthorizationDetailsTypesSupported:         []string{request.OpenIDCredentialType},
+		PushedAuthorizationRequestEndpoint:         issuer + pushedAuthorizationRequestPath,
+		PreAuthorizedGrantAnonymousAccessSupported: true,
+	}
+}
+
+// issuerURL is the configured issuer of the authorization server. When none is configured, it's the authorization
+// server of the credential issuer, or else the credential issuer itself.
+func issuerURL(config *AuthConfig, im *issuance.IssuerMetadata) string {
+	issuer := config.Issuer
+	if issuer == "" {
+		if im.AuthorizationServer != nil {
+			issuer = im.AuthorizationServer.String()
+		} else {
+			issuer = im.CredentialIssuer.String()
+		}
+	}
+	return strings.TrimSuffix(issuer, "/")
+}
+
+// providerMetadata serves the discovery metadata of the authorization server.
+func providerMetadata(metadata ProviderMetadata) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		framework.Respond(c, metadata, http.StatusOK)
+	}
+}
+
+// jwks serves the public keys that verify the JWTs signed by the authorization server.
+func jwks(keys keySet) gin.HandlerFunc {
+	set := keys.jwks()
+	return func(c *gin.Context) {
+		framework.Respond(c, set, http.StatusOK)
+	}
+}
@@ -0,0 +1,56 @@
+package authorizationserver
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/lestrrat-go/jwx/v2/jwk"
+	"github.com/lestrrat-go/jwx/v2/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProviderMetadata(t *testing.T) {
+	for _, path := range []string{openIDConfigurationPath, oauthAuthorizationServerPath} {
+		resp, err := http.Get(server.URL + path)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var metadata ProviderMetadata
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&metadata))
+		assert.Equal(t, "https://auth-server.example.com", metadata.Issuer)
+		assert.Equal(t, "https://auth-server.example.com/oauth2/token", metadata.TokenEndpoint)
+		assert.Equal(t, "https://auth-server.example.com/.well-known/jwks.json", metadata.JWKSURI)
+		assert.Contains(t, metadata.GrantTypesSupported, preAuthorizedCodeGrantType)
+		assert.Equal(t, []string{"openid_credential"}, metadata.AuthorizationDetailsTypesSupported)
+	}
+}
+
+func TestJWKS(t *testing.T) {
+	keySet, err := jwk.Fetch(context.Background(), server.URL+jwksPath)
+	require.NoError(t, err)
+	require.Equal(t, 1, keySet.Len())
+	key, ok := keySet.Key(0)
+	require.True(t, ok)
+	assert.Equal(t, "authorization-server-signing-key", key.KeyID())
+	_, isPrivate := key.(jwk.RSAPrivateKey)
+	assert.False(t, isPrivate)
+
+	// ID tokens are signed by the key in the JWKS, and issued by the authorization server of the credential issuer
+	createUniversityDegreeTemplate(t)
+	clientID, tokenForm := authorize(t, universityDegreeAuthorizationDetails)
+	tokenResp := postToken(t, clientID, tokenForm)
+	require.Equal(t, http.StatusOK, tokenResp.StatusCode)
+	var token tokenResponse
+	require.NoError(t, json.NewDecoder(tokenResp.Body).Decode(&token))
+	require.NotEmpty(t, token.IDToken)
+
+	idToken, err := jwt.Parse([]byte(token.IDToken), jwt.WithKeySet(keySet))
+	require.NoError(t, err)
+	assert.Equal(t, "https://auth-server.example.com", idToken.Issuer())
+	assert.Equal(t, []string{clientID}, idToken.Audience())
+	assert.Equal(t, "peter", idToken.Subject())
+}
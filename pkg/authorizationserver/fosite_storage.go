@@ -0,0 +1,673 @@
+package authorizationserver
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/ory/fosite"
+	"github.com/ory/fosite/handler/oauth2"
+	"github.com/ory/fosite/handler/openid"
+	"github.com/ory/fosite/handler/pkce"
+	"github.com/ory/fosite/handler/rfc7523"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/square/go-jose.v2"
+
+	"github.com/tbd54566975/ssi-service/pkg/storage"
+)
+
+const (
+	oauth2ClientNamespace          = "oauth2_client"
+	authorizeCodeNamespace         = "oauth2_authorize_code"
+	accessTokenNamespace           = "oauth2_access_token"
+	accessTokenRequestIDNamespace  = "oauth2_access_token_request_id"
+	refreshTokenNamespace          = "oauth2_refresh_token"
+	refreshTokenRequestIDNamespace = "oauth2_refresh_token_request_id"
+	pkceRequestNamespace           = "oauth2_pkce_request"
+	openIDConnectSessionNamespace  = "oauth2_openid_connect_session"
+	parSessionNamespace            = "oauth2_par_session"
+	usedJTINamespace               = "oauth2_used_jti"
+
+	// parSessionRetention is how long pushed authorization requests without an expiry in their session are kept.
+	parSessionRetention = time.Hour
+)
+
+// expiringNamespaces hold records with an expiresAt, which are deleted once expired.
+var expiringNamespaces = []string{
+	authorizeCodeNamespace,
+	accessTokenNamespace,
+	refreshTokenNamespace,
+	pkceRequestNamespace,
+	openIDConnectSessionNamespace,
+	parSessionNamespace,
+	usedJTINamespace,
+}
+
+var errCNonceAlreadyUsed = errors.New("the c_nonce was already used")
+
+// Storage persists the clients, grants and sessions of the authorization server in a storage.ServiceStorage, so that
+// they survive restarts and can be shared by replicas.
+type Storage struct {
+	db storage.ServiceStorage
+}
+
+var (
+	_ fosite.Storage                                      = (*Storage)(nil)
+	_ oauth2.CoreStorage                                  = (*Storage)(nil)
+	_ oauth2.TokenRevocationStorage                       = (*Storage)(nil)
+	_ oauth2.ResourceOwnerPasswordCredentialsGrantStorage = (*Storage)(nil)
+	_ openid.OpenIDConnectRequestStorage                  = (*Storage)(nil)
+	_ pkce.PKCERequestStorage                             = (*Storage)(nil)
+	_ rfc7523.RFC7523KeyStorage                           = (*Storage)(nil)
+	_ fosite.PARStorage                                   = (*Storage)(nil)
+)
+
+func NewStorage(db storage.ServiceStorage) (*Storage, error) {
+	if db == nil {
+		return nil, errors.New("storage cannot be nil")
+	}
+	return &Storage{db: db}, nil
+}
+
+// storedRequest is how the request of a grant is persisted. The client is referenced by its ID, and the session is
+// kept as JSON.
+type storedRequest struct {
+	ID                string           `json:"id"`
+	RequestedAt       time.Time        `json:"requestedAt"`
+	ClientID          string           `json:"clientId,omitempty"`
+	RequestedScope    fosite.Arguments `json:"requestedScope,omitempty"`
+	GrantedScope      fosite.Arguments `json:"grantedScope,omitempty"`
+	RequestedAudience fosite.Arguments `json:"requestedAudience,omitempty"`
+	GrantedAudience   fosite.Arguments `json:"grantedAudience,omitempty"`
+	Form              url.Values       `json:"form,omitempty"`
+	Session           json.RawMessage  `json:"session,omitempty"`
+
+	// Only set for pushed authorization requests.
+	Authorize *storedAuthorizeRequest `json:"authorize,omitempty"`
+
+	// Authorize codes and refresh tokens are kept once invalidated, so that their reuse is detected.
+	Active bool `json:"active"`
+
+	// When the grant expires, after which it's deleted. Zero when it doesn't expire.
+	ExpiresAt time.Time `json:"expiresAt,omitempty"`
+}
+
+type storedAuthorizeRequest struct {
+	ResponseTypes       fosite.Arguments        `json:"responseTypes,omitempty"`
+	RedirectURI         string                  `json:"redirectUri,omitempty"`
+	State               string                  `json:"state,omitempty"`
+	ResponseMode        fosite.ResponseModeType `json:"responseMode,omitempty"`
+	DefaultResponseMode fosite.ResponseModeType `json:"defaultResponseMode,omitempty"`
+}
+
+type storedJTI struct {
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+func newStoredRequest(requester fosite.Requester, expiresAt time.Time) (*storedRequest, error) {
+	stored := storedRequest{
+		ID:                requester.GetID(),
+		RequestedAt:       requester.GetRequestedAt(),
+		RequestedScope:    requester.GetRequestedScopes(),
+		GrantedScope:      requester.GetGrantedScopes(),
+		RequestedAudience: requester.GetRequestedAudience(),
+		GrantedAudience:   requester.GetGrantedAudience(),
+		Form:              requester.GetRequestForm(),
+		Active:            true,
+		ExpiresAt:         expiresAt,
+	}
+	if client := requester.GetClient(); client != nil {
+		stored.ClientID = client.GetID()
+	}
+	if session := requester.GetSession(); session != nil {
+		sessionBytes, err := json.Marshal(session)
+		if err != nil {
+			return nil, errors.Wrapf(err, "marshalling session of request<%s>", stored.ID)
+		}
+		stored.Session = sessionBytes
+	}
+	return &stored, nil
+}
+
+// sessionExpiry is when the session of the requester expires for tokens of tokenType.
+func sessionExpiry(requester fosite.Requester, tokenType fosite.TokenType) time.Time {
+	if session := requester.GetSession(); session != nil {
+		return session.GetExpiresAt(tokenType)
+	}
+	return time.Time{}
+}
+
+// toRequester hydrates the stored request, along with a session of its own. As with fosite's memory store, the sessions
+// given to the getters are left untouched: fosite reads authorize codes more than once while exchanging them, which
+// would otherwise overwrite what was set in the session in between.
+func (s *Storage) toRequester(ctx context.Context, stored storedRequest) (*fosite.Request, error) {
+	client, err := s.requestClient(ctx, stored.ClientID)
+	if err != nil {
+		return nil, err
+	}
+	session := newSession("")
+	if len(stored.Session) > 0 {
+		if err = json.Unmarshal(stored.Session, session); err != nil {
+			return nil, errors.Wrapf(err, "unmarshalling session of request<%s>", stored.ID)
+		}
+	}
+	form := stored.Form
+	if form == nil {
+		form = url.Values{}
+	}
+	return &fosite.Request{
+		ID:                stored.ID,
+		RequestedAt:       stored.RequestedAt,
+		Client:            client,
+		RequestedScope:    stored.RequestedScope,
+		GrantedScope:      stored.GrantedScope,
+		Form:              form,
+		Session:           session,
+		RequestedAudience: stored.RequestedAudience,
+		GrantedAudience:   stored.GrantedAudience,
+	}, nil
+}
+
+// requestClient returns the client of a stored request. Requests without a client come from the pre-authorized code
+// grant, which is used by wallets that never registered.
+func (s *Storage) requestClient(ctx context.Context, clientID string) (fosite.Client, error) {
+	if clientID == "" {
+		return &fosite.DefaultClient{Public: true}, nil
+	}
+	return s.GetClient(ctx, clientID)
+}
+
+func (s *Storage) writeRequest(ctx context.Context, namespace, key string, stored *storedRequest) error {
+	storedBytes, err := json.Marshal(stored)
+	if err != nil {
+		return errors.Wrapf(err, "marshalling request<%s>", stored.ID)
+	}
+	return s.db.Write(ctx, namespace, key, storedBytes)
+}
+
+func (s *Storage) readRequest(ctx context.Context, namespace, key string) (*storedRequest, error) {
+	storedBytes, err := s.db.Read(ctx, namespace, key)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading from namespace<%s>", namespace)
+	}
+	return decodeRequest(namespace, storedBytes)
+}
+
+// readRequestTx reads a stored request within the transaction.
+func readRequestTx(ctx context.Context, tx storage.Tx, namespace, key string) (*storedRequest, error) {
+	storedBytes, err := tx.Read(ctx, namespace, key)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading from namespace<%s>", namespace)
+	}
+	return decodeRequest(namespace, storedBytes)
+}
+
+func decodeRequest(namespace string, storedBytes []byte) (*storedRequest, error) {
+	if len(storedBytes) == 0 {
+		return nil, fosite.ErrNotFound
+	}
+	var stored storedRequest
+	if err := json.Unmarshal(storedBytes, &stored); err != nil {
+		return nil, errors.Wrapf(err, "unmarshalling request from namespace<%s>", namespace)
+	}
+	return &stored, nil
+}
+
+// createTokenSession stores the request of a token, along with the index from its request ID to its signature.
+func (s *Storage) createTokenSession(ctx context.Context, namespace, requestIDNamespace, signature string, requester fosite.Requester, tokenType fosite.TokenType) error {
+	stored, err := newStoredRequest(requester, sessionExpiry(requester, tokenType))
+	if err != nil {
+		return err
+	}
+	storedBytes, err := json.Marshal(stored)
+	if err != nil {
+		return errors.Wrapf(err, "marshalling request<%s>", stored.ID)
+	}
+	return s.db.WriteMany(ctx,
+		[]string{namespace, requestIDNamespace},
+		[]string{signature, stored.ID},
+		[][]byte{storedBytes, []byte(signature)},
+	)
+}
+
+// createAccessTokenSessionTx stores the access token session within tx, for access tokens stored along with other
+// changes.
+func (s *Storage) createAccessTokenSessionTx(ctx context.Context, tx storage.Tx, signature string, requester fosite.Requester) error {
+	stored, err := newStoredRequest(requester, sessionExpiry(requester, fosite.AccessToken))
+	if err != nil {
+		return err
+	}
+	storedBytes, err := json.Marshal(stored)
+	if err != nil {
+		return errors.Wrapf(err, "marshalling request<%s>", stored.ID)
+	}
+	if err = tx.Write(ctx, accessTokenNamespace, signature, storedBytes); err != nil {
+		return errors.Wrapf(err, "writing request<%s>", stored.ID)
+	}
+	return tx.Write(ctx, accessTokenRequestIDNamespace, stored.ID, []byte(signature))
+}
+
+func (s *Storage) signatureOfRequest(ctx context.Context, requestIDNamespace, requestID string) (string, error) {
+	signature, err := s.db.Read(ctx, requestIDNamespace, requestID)
+	if err != nil {
+		return "", errors.Wrapf(err, "reading signature of request<%s>", requestID)
+	}
+	return string(signature), nil
+}
+
+// deactivate marks the stored request as inactive.
+func (s *Storage) deactivate(ctx context.Context, namespace, key string) error {
+	stored, err := s.readRequest(ctx, namespace, key)
+	if err != nil {
+		return err
+	}
+	stored.Active = false
+	return s.writeRequest(ctx, namespace, key, stored)
+}
+
+// StoreClient creates or replaces a client.
+func (s *Storage) StoreClient(ctx context.Context, client *Client) error {
+	if client == nil || client.GetID() == "" {
+		return errors.New("cannot store client without an ID")
+	}
+	clientBytes, err := json.Marshal(client)
+	if err != nil {
+		return errors.Wrapf(err, "marshalling client<%s>", client.GetID())
+	}
+	return s.db.Write(ctx, oauth2ClientNamespace, client.GetID(), clientBytes)
+}
+
+func (s *Storage) GetClient(ctx context.Context, id string) (fosite.Client, error) {
+	clientBytes, err := s.db.Read(ctx, oauth2ClientNamespace, id)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading client<%s>", id)
+	}
+	if len(clientBytes) == 0 {
+		return nil, fosite.ErrNotFound
+	}
+	return unmarshalClient(id, clientBytes)
+}
+
+// ListClients returns all the clients.
+func (s *Storage) ListClients(ctx context.Context) ([]Client, error) {
+	records, err := s.db.ReadAll(ctx, oauth2ClientNamespace)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading clients")
+	}
+	clients := make([]Client, 0, len(records))
+	for id, clientBytes := range records {
+		client, err := unmarshalClient(id, clientBytes)
+		if err != nil {
+			return nil, err
+		}
+		clients = append(clients, *client)
+	}
+	return clients, nil
+}
+
+// DeleteClient deletes a client. The grants already issued to it stay valid until they expire.
+func (s *Storage) DeleteClient(ctx context.Context, id string) error {
+	return s.db.Delete(ctx, oauth2ClientNamespace, id)
+}
+
+// unmarshalClient unmarshals a stored client. Clients stored before they had a token endpoint auth method get the
+// default of their type.
+func unmarshalClient(id string, clientBytes []byte) (*Client, error) {
+	client := Client{DefaultOpenIDConnectClient: fosite.DefaultOpenIDConnectClient{DefaultClient: &fosite.DefaultClient{}}}
+	if err := json.Unmarshal(clientBytes, &client); err != nil {
+		return nil, errors.Wrapf(err, "unmarshalling client<%s>", id)
+	}
+	if client.TokenEndpointAuthMethod == "" {
+		client.TokenEndpointAuthMethod = defaultTokenEndpointAuthMethod(client.Public)
+	}
+	return &client, nil
+}
+
+func (s *Storage) ClientAssertionJWTValid(ctx context.Context, jti string) error {
+	jtiBytes, err := s.db.Read(ctx, usedJTINamespace, jti)
+	if err != nil {
+		return errors.Wrapf(err, "reading jti<%s>", jti)
+	}
+	if len(jtiBytes) == 0 {
+		return nil
+	}
+	var used storedJTI
+	if err = json.Unmarshal(jtiBytes, &used); err != nil {
+		return errors.Wrapf(err, "unmarshalling jti<%s>", jti)
+	}
+	if used.ExpiresAt.After(time.Now()) {
+		return fosite.ErrJTIKnown
+	}
+	return nil
+}
+
+func (s *Storage) SetClientAssertionJWT(ctx context.Context, jti string, exp time.Time) error {
+	if err := s.ClientAssertionJWTValid(ctx, jti); err != nil {
+		return err
+	}
+	jtiBytes, err := json.Marshal(storedJTI{ExpiresAt: exp})
+	if err != nil {
+		return errors.Wrapf(err, "marshalling jti<%s>", jti)
+	}
+	return s.db.Write(ctx, usedJTINamespace, jti, jtiBytes)
+}
+
+func (s *Storage) CreateAuthorizeCodeSession(ctx context.Context, code string, requester fosite.Requester) error {
+	stored, err := newStoredRequest(requester, sessionExpiry(requester, fosite.AuthorizeCode))
+	if err != nil {
+		return err
+	}
+	return s.writeRequest(ctx, authorizeCodeNamespace, code, stored)
+}
+
+func (s *Storage) GetAuthorizeCodeSession(ctx context.Context, code string, _ fosite.Session) (fosite.Requester, error) {
+	stored, err := s.readRequest(ctx, authorizeCodeNamespace, code)
+	if err != nil {
+		return nil, err
+	}
+	requester, err := s.toRequester(ctx, *stored)
+	if err != nil {
+		return nil, err
+	}
+	if !stored.Active {
+		return requester, fosite.ErrInvalidatedAuthorizeCode
+	}
+	return requester, nil
+}
+
+func (s *Storage) InvalidateAuthorizeCodeSession(ctx context.Context, code string) error {
+	return s.deactivate(ctx, authorizeCodeNamespace, code)
+}
+
+func (s *Storage) CreateAccessTokenSession(ctx context.Context, signature string, requester fosite.Requester) error {
+	return s.createTokenSession(ctx, accessTokenNamespace, accessTokenRequestIDNamespace, signature, requester, fosite.AccessToken)
+}
+
+func (s *Storage) GetAccessTokenSession(ctx context.Context, signature string, _ fosite.Session) (fosite.Requester, error) {
+	stored, err := s.readRequest(ctx, accessTokenNamespace, signature)
+	if err != nil {
+		return nil, err
+	}
+	return s.toRequester(ctx, *stored)
+}
+
+func (s *Storage) DeleteAccessTokenSession(ctx context.Context, signature string) error {
+	return s.db.Delete(ctx, accessTokenNamespace, signature)
+}
+
+// UpdateAccessTokenSession applies update to the session of the access token issued for the request with the given
+// ID. The read and the write happen in a single transaction.
+func (s *Storage) UpdateAccessTokenSession(ctx context.Context, requestID string, update func(*Session) error) error {
+	signature, err := s.signatureOfRequest(ctx, accessTokenRequestIDNamespace, requestID)
+	if err != nil {
+		return err
+	}
+	if signature == "" {
+		return fosite.ErrNotFound
+	}
+
+	watchKeys := []storage.WatchKey{{Namespace: accessTokenNamespace, Key: signature}}
+	_, err = s.db.Execute(ctx, func(ctx context.Context, tx storage.Tx) (any, error) {
+		stored, err := readRequestTx(ctx, tx, accessTokenNamespace, signature)
+		if err != nil {
+			return nil, err
+		}
+		session := newSession("")
+		if err = json.Unmarshal(stored.Session, session); err != nil {
+			return nil, errors.Wrapf(err, "unmarshalling session of request<%s>", requestID)
+		}
+		if err = update(session); err != nil {
+			return nil, err
+		}
+		if stored.Session, err = json.Marshal(session); err != nil {
+			return nil, errors.Wrapf(err, "marshalling session of request<%s>", requestID)
+		}
+		storedBytes, err := json.Marshal(stored)
+		if err != nil {
+			return nil, errors.Wrapf(err, "marshalling request<%s>", requestID)
+		}
+		return nil, tx.Write(ctx, accessTokenNamespace, signature, storedBytes)
+	}, watchKeys)
+	return err
+}
+
+func (s *Storage) CreateRefreshTokenSession(ctx context.Context, signature string, requester fosite.Requester) error {
+	return s.createTokenSession(ctx, refreshTokenNamespace, refreshTokenRequestIDNamespace, signature, requester, fosite.RefreshToken)
+}
+
+func (s *Storage) GetRefreshTokenSession(ctx context.Context, signature string, _ fosite.Session) (fosite.Requester, error) {
+	stored, err := s.readRequest(ctx, refreshTokenNamespace, signature)
+	if err != nil {
+		return nil, err
+	}
+	requester, err := s.toRequester(ctx, *stored)
+	if err != nil {
+		return nil, err
+	}
+	if !stored.Active {
+		return requester, fosite.ErrInactiveToken
+	}
+	return requester, nil
+}
+
+func (s *Storage) DeleteRefreshTokenSession(ctx context.Context, signature string) error {
+	return s.db.Delete(ctx, refreshTokenNamespace, signature)
+}
+
+func (s *Storage) RevokeRefreshToken(ctx context.Context, requestID string) error {
+	signature, err := s.signatureOfRequest(ctx, refreshTokenRequestIDNamespace, requestID)
+	if err != nil || signature == "" {
+		return err
+	}
+	return s.deactivate(ctx, refreshTokenNamespace, signature)
+}
+
+// RevokeRefreshTokenMaybeGracePeriod revokes the refresh token right away, as there is no grace period.
+func (s *Storage) RevokeRefreshTokenMaybeGracePeriod(ctx context.Context, requestID string, _ string) error {
+	return s.RevokeRefreshToken(ctx, requestID)
+}
+
+func (s *Storage) RevokeAccessToken(ctx context.Context, requestID string) error {
+	signature, err := s.signatureOfRequest(ctx, accessTokenRequestIDNamespace, requestID)
+	if err != nil || signature == "" {
+		return err
+	}
+	return s.DeleteAccessTokenSession(ctx, signature)
+}
+
+// Authenticate always fails, as resource owners don't have passwords in the authorization server.
+func (s *Storage) Authenticate(context.Context, string, string) error {
+	return fosite.ErrNotFound
+}
+
+func (s *Storage) CreateOpenIDConnectSession(ctx context.Context, authorizeCode string, requester fosite.Requester) error {
+	stored, err := newStoredRequest(requester, sessionExpiry(requester, fosite.AuthorizeCode))
+	if err != nil {
+		return err
+	}
+	return s.writeRequest(ctx, openIDConnectSessionNamespace, authorizeCode, stored)
+}
+
+func (s *Storage) GetOpenIDConnectSession(ctx context.Context, authorizeCode string, _ fosite.Requester) (fosite.Requester, error) {
+	stored, err := s.readRequest(ctx, openIDConnectSessionNamespace, authorizeCode)
+	if err != nil {
+		return nil, err
+	}
+	return s.toRequester(ctx, *stored)
+}
+
+func (s *Storage) DeleteOpenIDConnectSession(ctx context.Context, authorizeCode string) error {
+	return s.db.Delete(ctx, openIDConnectSessionNamespace, authorizeCode)
+}
+
+func (s *Storage) CreatePKCERequestSession(ctx context.Context, signature string, requester fosite.Requester) error {
+	stored, err := newStoredRequest(requester, sessionExpiry(requester, fosite.AuthorizeCode))
+	if err != nil {
+		return err
+	}
+	return s.writeRequest(ctx, pkceRequestNamespace, signature, stored)
+}
+
+func (s *Storage) GetPKCERequestSession(ctx context.Context, signature string, _ fosite.Session) (fosite.Requester, error) {
+	stored, err := s.readRequest(ctx, pkceRequestNamespace, signature)
+	if err != nil {
+		return nil, err
+	}
+	return s.toRequester(ctx, *stored)
+}
+
+func (s *Storage) DeletePKCERequestSession(ctx context.Context, signature string) error {
+	return s.db.Delete(ctx, pkceRequestNamespace, signature)
+}
+
+// GetPublicKey always fails, as no issuers are trusted for the JWT bearer grant of RFC 7523.
+func (s *Storage) GetPublicKey(context.Context, string, string, string) (*jose.JSONWebKey, error) {
+	return nil, fosite.ErrNotFound
+}
+
+// GetPublicKeys always fails, as no issuers are trusted for the JWT bearer grant of RFC 7523.
+func (s *Storage) GetPublicKeys(context.Context, string, string) (*jose.JSONWebKeySet, error) {
+	return nil, fosite.ErrNotFound
+}
+
+// GetPublicKeyScopes always fails, as no issuers are trusted for the JWT bearer grant of RFC 7523.
+func (s *Storage) GetPublicKeyScopes(context.Context, string, string, string) ([]string, error) {
+	return nil, fosite.ErrNotFound
+}
+
+func (s *Storage) IsJWTUsed(ctx context.Context, jti string) (bool, error) {
+	if err := s.ClientAssertionJWTValid(ctx, jti); err != nil {
+		return true, nil
+	}
+	return false, nil
+}
+
+func (s *Storage) MarkJWTUsedForTime(ctx context.Context, jti string, exp time.Time) error {
+	return s.SetClientAssertionJWT(ctx, jti, exp)
+}
+
+func (s *Storage) CreatePARSession(ctx context.Context, requestURI string, request fosite.AuthorizeRequester) error {
+	expiresAt := sessionExpiry(request, fosite.PushedAuthorizeRequestContext)
+	if expiresAt.IsZero() {
+		expiresAt = time.Now().Add(parSessionRetention)
+	}
+	stored, err := newStoredRequest(request, expiresAt)
+	if err != nil {
+		return err
+	}
+	stored.Authorize = &storedAuthorizeRequest{
+		ResponseTypes:       request.GetResponseTypes(),
+		State:               request.GetState(),
+		ResponseMode:        request.GetResponseMode(),
+		DefaultResponseMode: request.GetDefaultResponseMode(),
+	}
+	if redirectURI := request.GetRedirectURI(); redirectURI != nil {
+		stored.Authorize.RedirectURI = redirectURI.String()
+	}
+	return s.writeRequest(ctx, parSessionNamespace, requestURI, stored)
+}
+
+func (s *Storage) GetPARSession(ctx context.Context, requestURI string) (fosite.AuthorizeRequester, error) {
+	stored, err := s.readRequest(ctx, parSessionNamespace, requestURI)
+	if err != nil {
+		return nil, err
+	}
+	if time.Now().After(stored.ExpiresAt) {
+		return nil, fosite.ErrNotFound
+	}
+	requester, err := s.toRequester(ctx, *stored)
+	if err != nil {
+		return nil, err
+	}
+	authorizeRequest := fosite.NewAuthorizeRequest()
+	authorizeRequest.Request = *requester
+	if stored.Authorize != nil {
+		authorizeRequest.ResponseTypes = stored.Authorize.ResponseTypes
+		authorizeRequest.State = stored.Authorize.State
+		authorizeRequest.ResponseMode = stored.Authorize.ResponseMode
+		authorizeRequest.DefaultResponseMode = stored.Authorize.DefaultResponseMode
+		if stored.Authorize.RedirectURI != "" {
+			if authorizeRequest.RedirectURI, err = url.Parse(stored.Authorize.RedirectURI); err != nil {
+				return nil, errors.Wrapf(err, "parsing redirect_uri of request<%s>", stored.ID)
+			}
+		}
+	}
+	return authorizeRequest, nil
+}
+
+func (s *Storage) DeletePARSession(ctx context.Context, requestURI string) error {
+	return s.db.Delete(ctx, parSessionNamespace, requestURI)
+}
+
+// DeleteExpired deletes the grants, sessions and used JWT IDs that have expired, along with the indexes of the
+// deleted tokens.
+func (s *Storage) DeleteExpired(ctx context.Context) error {
+	now := time.Now()
+	for _, namespace := range expiringNamespaces {
+		records, err := s.db.ReadAll(ctx, namespace)
+		if err != nil {
+			return errors.Wrapf(err, "reading namespace<%s>", namespace)
+		}
+		for key, recordBytes := range records {
+			var record struct {
+				ExpiresAt time.Time `json:"expiresAt"`
+			}
+			if err = json.Unmarshal(recordBytes, &record); err != nil {
+				logrus.WithError(err).Warnf("skipping unreadable record<%s> in namespace<%s>", key, namespace)
+				continue
+			}
+			if record.ExpiresAt.IsZero() || record.ExpiresAt.After(now) {
+				continue
+			}
+			if err = s.db.Delete(ctx, namespace, key); err != nil {
+				return errors.Wrapf(err, "deleting record<%s> in namespace<%s>", key, namespace)
+			}
+		}
+	}
+
+	tokenIndexes := map[string]string{
+		accessTokenRequestIDNamespace:  accessTokenNamespace,
+		refreshTokenRequestIDNamespace: refreshTokenNamespace,
+	}
+	for requestIDNamespace, tokenNamespace := range tokenIndexes {
+		signatures, err := s.db.ReadAll(ctx, requestIDNamespace)
+		if err != nil {
+			return errors.Wrapf(err, "reading namespace<%s>", requestIDNamespace)
+		}
+		for requestID, signature := range signatures {
+			exists, err := s.db.Exists(ctx, tokenNamespace, string(signature))
+			if err != nil {
+				return errors.Wrapf(err, "checking token of request<%s>", requestID)
+			}
+			if exists {
+				continue
+			}
+			if err = s.db.Delete(ctx, requestIDNamespace, requestID); err != nil {
+				return errors.Wrapf(err, "deleting index of request<%s>", requestID)
+			}
+		}
+	}
+	return nil
+}
+
+// DeleteExpiredEvery runs DeleteExpired every interval until the context is done. Nothing is deleted when the interval
+// isn't positive.
+func (s *Storage) DeleteExpiredEvery(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.DeleteExpired(ctx); err != nil {
+				logrus.WithError(err).Error("could not delete expired grants")
+			}
+		}
+	}
+}
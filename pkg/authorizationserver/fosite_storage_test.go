@@ -0,0 +1,281 @@
+package authorizationserver
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ory/fosite"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tbd54566975/ssi-service/pkg/storage"
+)
+
+func TestStorage(t *testing.T) {
+	ctx := context.Background()
+	client := &Client{
+		DefaultOpenIDConnectClient: fosite.DefaultOpenIDConnectClient{
+			DefaultClient: &fosite.DefaultClient{
+				ID:           "storage-test-client",
+				Secret:       []byte(`$2a$10$IxMdI6d.LIRZPpSfEwNoeu4rY3FhDREsxFJXikcgdRRAStxUlsuEO`),
+				RedirectURIs: []string{"https://client.example.com/callback"},
+				GrantTypes:   []string{"authorization_code", "refresh_token"},
+				Scopes:       []string{"openid"},
+			},
+			TokenEndpointAuthMethod: authMethodClientSecretBasic,
+		},
+		Name: "storage test client",
+	}
+
+	t.Run("clients and grants survive a restart", func(t *testing.T) {
+		db := setupTestDB(t)
+		s, err := NewStorage(db)
+		require.NoError(t, err)
+		require.NoError(t, s.StoreClient(ctx, client))
+		requester := newTestRequester(client, time.Now().Add(time.Hour))
+		require.NoError(t, s.CreateAuthorizeCodeSession(ctx, "code-signature", requester))
+		require.NoError(t, s.CreateAccessTokenSession(ctx, "token-signature", requester))
+
+		restarted, err := NewStorage(db)
+		require.NoError(t, err)
+		gotClient, err := restarted.GetClient(ctx, client.ID)
+		require.NoError(t, err)
+		assert.Equal(t, client, gotClient)
+
+		got, err := restarted.GetAuthorizeCodeSession(ctx, "code-signature", nil)
+		require.NoError(t, err)
+		assert.Equal(t, requester.GetID(), got.GetID())
+		assert.Equal(t, client.ID, got.GetClient().GetID())
+		assert.Equal(t, fosite.Arguments{"openid"}, got.GetGrantedScopes())
+		assert.Equal(t, "bar", got.GetRequestForm().Get("foo"))
+		session, ok := got.GetSession().(*Session)
+		require.True(t, ok)
+		assert.Equal(t, "alice", session.Subject)
+		assert.Equal(t, "a-nonce", session.CNonce)
+
+		_, err = restarted.GetAccessTokenSession(ctx, "token-signature", nil)
+		assert.NoError(t, err)
+	})
+
+	t.Run("clients are listed and deleted", func(t *testing.T) {
+		s, err := NewStorage(setupTestDB(t))
+		require.NoError(t, err)
+		require.NoError(t, s.StoreClient(ctx, client))
+
+		clients, err := s.ListClients(ctx)
+		require.NoError(t, err)
+		require.Len(t, clients, 1)
+		assert.Equal(t, *client, clients[0])
+
+		require.NoError(t, s.DeleteClient(ctx, client.ID))
+		_, err = s.GetClient(ctx, client.ID)
+		assert.ErrorIs(t, err, fosite.ErrNotFound)
+	})
+
+	t.Run("unknown grants are not found", func(t *testing.T) {
+		s, err := NewStorage(setupTestDB(t))
+		require.NoError(t, err)
+		_, err = s.GetClient(ctx, "unknown")
+		assert.ErrorIs(t, err, fosite.ErrNotFound)
+		_, err = s.GetAuthorizeCodeSession(ctx, "unknown", nil)
+		assert.ErrorIs(t, err, fosite.ErrNotFound)
+		_, err = s.GetAccessTokenSession(ctx, "unknown", nil)
+		assert.ErrorIs(t, err, fosite.ErrNotFound)
+	})
+
+	t.Run("invalidated authorize codes and revoked tokens are kept inactive", func(t *testing.T) {
+		s, err := NewStorage(setupTestDB(t))
+		require.NoError(t, err)
+		require.NoError(t, s.StoreClient(ctx, client))
+		requester := newTestRequester(client, time.Now().Add(time.Hour))
+		require.NoError(t, s.CreateAuthorizeCodeSession(ctx, "code-signature", requester))
+		require.NoError(t, s.CreateAccessTokenSession(ctx, "access-signature", requester))
+		require.NoError(t, s.CreateRefreshTokenSession(ctx, "refresh-signature", requester))
+
+		require.NoError(t, s.InvalidateAuthorizeCodeSession(ctx, "code-signature"))
+		got, err := s.GetAuthorizeCodeSession(ctx, "code-signature", nil)
+		assert.ErrorIs(t, err, fosite.ErrInvalidatedAuthorizeCode)
+		require.NotNil(t, got)
+		assert.Equal(t, requester.GetID(), got.GetID())
+
+		require.NoError(t, s.RevokeAccessToken(ctx, requester.GetID()))
+		_, err = s.GetAccessTokenSession(ctx, "access-signature", nil)
+		assert.ErrorIs(t, err, fosite.ErrNotFound)
+
+		require.NoError(t, s.RevokeRefreshToken(ctx, requester.GetID()))
+		got, err = s.GetRefreshTokenSession(ctx, "refresh-signature", nil)
+		assert.ErrorIs(t, err, fosite.ErrInactiveToken)
+		assert.NotNil(t, got)
+	})
+
+	t.Run("access token session is updated in place", func(t *testing.T) {
+		s, err := NewStorage(setupTestDB(t))
+		require.NoError(t, err)
+		require.NoError(t, s.StoreClient(ctx, client))
+		requester := newTestRequester(client, time.Now().Add(time.Hour))
+		require.NoError(t, s.CreateAccessTokenSession(ctx, "access-signature", requester))
+
+		err = s.UpdateAccessTokenSession(ctx, requester.GetID(), func(session *Session) error {
+			session.CNonce = "another-nonce"
+			return nil
+		})
+		require.NoError(t, err)
+		got, err := s.GetAccessTokenSession(ctx, "access-signature", nil)
+		require.NoError(t, err)
+		assert.Equal(t, "another-nonce", got.GetSession().(*Session).CNonce)
+
+		err = s.UpdateAccessTokenSession(ctx, "unknown-request", func(*Session) error { return nil })
+		assert.ErrorIs(t, err, fosite.ErrNotFound)
+	})
+
+	t.Run("concurrent access token session updates see each other", func(t *testing.T) {
+		s, err := NewStorage(setupTestDB(t))
+		require.NoError(t, err)
+		require.NoError(t, s.StoreClient(ctx, client))
+		requester := newTestRequester(client, time.Now().Add(time.Hour))
+		require.NoError(t, s.CreateAccessTokenSession(ctx, "access-signature", requester))
+		cNonce := requester.GetSession().(*Session).CNonce
+
+		// each update only succeeds with the c_nonce it started from, so only one of them can
+		const updates = 10
+		var wg sync.WaitGroup
+		var succeeded atomic.Int32
+		for i := 0; i < updates; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				err := s.UpdateAccessTokenSession(ctx, requester.GetID(), func(session *Session) error {
+					if session.CNonce != cNonce {
+						return errors.New("c_nonce already used")
+					}
+					session.CNonce = fmt.Sprintf("nonce-%d", i)
+					return nil
+				})
+				if err == nil {
+					succeeded.Add(1)
+				}
+			}(i)
+		}
+		wg.Wait()
+		assert.EqualValues(t, 1, succeeded.Load())
+	})
+
+	t.Run("used JWT IDs are rejected until they expire", func(t *testing.T) {
+		s, err := NewStorage(setupTestDB(t))
+		require.NoError(t, err)
+		require.NoError(t, s.ClientAssertionJWTValid(ctx, "a-jti"))
+		require.NoError(t, s.SetClientAssertionJWT(ctx, "a-jti", time.Now().Add(time.Hour)))
+		assert.ErrorIs(t, s.ClientAssertionJWTValid(ctx, "a-jti"), fosite.ErrJTIKnown)
+		assert.ErrorIs(t, s.SetClientAssertionJWT(ctx, "a-jti", time.Now().Add(time.Hour)), fosite.ErrJTIKnown)
+
+		require.NoError(t, s.MarkJWTUsedForTime(ctx, "expired-jti", time.Now().Add(-time.Minute)))
+		used, err := s.IsJWTUsed(ctx, "expired-jti")
+		require.NoError(t, err)
+		assert.False(t, used)
+	})
+
+	t.Run("pushed authorization requests keep their authorize parameters", func(t *testing.T) {
+		s, err := NewStorage(setupTestDB(t))
+		require.NoError(t, err)
+		require.NoError(t, s.StoreClient(ctx, client))
+		authorizeRequest := fosite.NewAuthorizeRequest()
+		authorizeRequest.Request = *newTestRequester(client, time.Time{})
+		authorizeRequest.ResponseTypes = fosite.Arguments{"code"}
+		authorizeRequest.State = "some-state"
+		authorizeRequest.RedirectURI, err = url.Parse(client.RedirectURIs[0])
+		require.NoError(t, err)
+		requestURI := "urn:ietf:params:oauth:request_uri:abc"
+		require.NoError(t, s.CreatePARSession(ctx, requestURI, authorizeRequest))
+
+		got, err := s.GetPARSession(ctx, requestURI)
+		require.NoError(t, err)
+		assert.Equal(t, fosite.Arguments{"code"}, got.GetResponseTypes())
+		assert.Equal(t, "some-state", got.GetState())
+		assert.Equal(t, client.RedirectURIs[0], got.GetRedirectURI().String())
+		assert.Equal(t, client.ID, got.GetClient().GetID())
+
+		require.NoError(t, s.DeletePARSession(ctx, requestURI))
+		_, err = s.GetPARSession(ctx, requestURI)
+		assert.ErrorIs(t, err, fosite.ErrNotFound)
+	})
+
+	t.Run("expired grants are deleted", func(t *testing.T) {
+		db := setupTestDB(t)
+		s, err := NewStorage(db)
+		require.NoError(t, err)
+		require.NoError(t, s.StoreClient(ctx, client))
+		expired := newTestRequester(client, time.Now().Add(-time.Minute))
+		valid := newTestRequester(client, time.Now().Add(time.Hour))
+		require.NoError(t, s.CreateAuthorizeCodeSession(ctx, "expired-code", expired))
+		require.NoError(t, s.CreateAuthorizeCodeSession(ctx, "valid-code", valid))
+		require.NoError(t, s.CreateAccessTokenSession(ctx, "expired-token", expired))
+		require.NoError(t, s.CreateAccessTokenSession(ctx, "valid-token", valid))
+		require.NoError(t, s.SetClientAssertionJWT(ctx, "expired-jti", time.Now().Add(-time.Minute)))
+
+		require.NoError(t, s.DeleteExpired(ctx))
+
+		_, err = s.GetAuthorizeCodeSession(ctx, "expired-code", nil)
+		assert.ErrorIs(t, err, fosite.ErrNotFound)
+		_, err = s.GetAuthorizeCodeSession(ctx, "valid-code", nil)
+		assert.NoError(t, err)
+		_, err = s.GetAccessTokenSession(ctx, "expired-token", nil)
+		assert.ErrorIs(t, err, fosite.ErrNotFound)
+		_, err = s.GetAccessTokenSession(ctx, "valid-token", nil)
+		assert.NoError(t, err)
+
+		requestIDs, err := db.ReadAll(ctx, accessTokenRequestIDNamespace)
+		require.NoError(t, err)
+		assert.Len(t, requestIDs, 1)
+		assert.Contains(t, requestIDs, valid.GetID())
+		jtis, err := db.ReadAll(ctx, usedJTINamespace)
+		require.NoError(t, err)
+		assert.Empty(t, jtis)
+
+		// the client is never deleted
+		_, err = s.GetClient(ctx, client.ID)
+		assert.NoError(t, err)
+	})
+}
+
+// newTestRequester creates a request of the client whose grants all expire at expiresAt.
+func newTestRequester(client fosite.Client, expiresAt time.Time) *fosite.Request {
+	session := newSession("alice")
+	session.Subject = "alice"
+	session.CNonce = "a-nonce"
+	if !expiresAt.IsZero() {
+		session.SetExpiresAt(fosite.AuthorizeCode, expiresAt)
+		session.SetExpiresAt(fosite.AccessToken, expiresAt)
+		session.SetExpiresAt(fosite.RefreshToken, expiresAt)
+	}
+	requester := fosite.NewRequest()
+	requester.Client = client
+	requester.Session = session
+	requester.GrantScope("openid")
+	requester.Form.Set("foo", "bar")
+	return requester
+}
+
+func setupTestDB(t *testing.T) storage.ServiceStorage {
+	file, err := os.CreateTemp("", "bolt")
+	require.NoError(t, err)
+	name := file.Name()
+	err = file.Close()
+	require.NoError(t, err)
+	s, err := storage.NewStorage(storage.Bolt, storage.Option{
+		ID:     storage.BoltDBFilePathOption,
+		Option: name,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = s.Close()
+		_ = os.Remove(s.URI())
+	})
+	return s
+}
@@ -1,17 +1,349 @@
 package authorizationserver
 
 import (
+	"context"
 	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
 
+	credsdk "github.com/TBD54566975/ssi-sdk/credential"
+	manifestsdk "github.com/TBD54566975/ssi-sdk/credential/manifest"
+	"github.com/TBD54566975/ssi-sdk/credential/rendering"
+	"github.com/TBD54566975/ssi-sdk/did"
 	"github.com/TBD54566975/ssi-sdk/oidc/issuance"
+	sdkutil "github.com/TBD54566975/ssi-sdk/util"
 	"github.com/gin-gonic/gin"
+	"github.com/goccy/go-json"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 
 	"github.com/tbd54566975/ssi-service/pkg/server/framework"
+	issuancesvc "github.com/tbd54566975/ssi-service/pkg/service/issuance"
+	"github.com/tbd54566975/ssi-service/pkg/service/manifest/model"
+	"github.com/tbd54566975/ssi-service/pkg/service/schema"
 )
 
-// Allow retrieval of credential issuer metadata according to https://openid.net/specs/openid-4-verifiable-credential-issuance-1_0.html#name-credential-issuer-metadata
-func credentialIssuerMetadata(im *issuance.IssuerMetadata) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		framework.Respond(c, im, http.StatusOK)
+// CredentialIssuerMetadata allows retrieval of credential issuer metadata according to https://openid.net/specs/openid-4-verifiable-credential-issuance-1_0.html#name-credential-issuer-metadata
+// The credentials_supported are built from the issuance templates on every request, so they are always the ones the
+// credential endpoint can issue.
+func (s AuthService) CredentialIssuerMetadata(c *gin.Context) {
+	im, err := s.currentIssuerMetadata(c)
+	if err != nil {
+		framework.LoggingRespondErrWithMsg(c, err, "could not build credential issuer metadata", http.StatusInternalServerError)
+		return
 	}
+	framework.Respond(c, im, http.StatusOK)
+}
+
+// loadIssuerMetadata returns the static part of the credential issuer metadata. The credential issuer is the configured
+// one, and the credential endpoint is the one of this server. When a credential issuer file is configured, the values
+// in it override those. Its credentials_supported are only used to override the generated entries with the same id.
+func loadIssuerMetadata(config *AuthConfig) (*issuance.IssuerMetadata, error) {
+	var im issuance.IssuerMetadata
+	if config.CredentialIssuerFile != "" {
+		jsonData, err := os.ReadFile(config.CredentialIssuerFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading credential issuer file<%s>", config.CredentialIssuerFile)
+		}
+		if err = json.Unmarshal(jsonData, &im); err != nil {
+			return nil, errors.Wrapf(err, "unmarshalling credential issuer file<%s>", config.CredentialIssuerFile)
+		}
+	}
+
+	if im.CredentialIssuer.String() == "" {
+		if config.CredentialIssuer == "" {
+			return nil, errors.New("either a credential issuer or a credential issuer file must be configured")
+		}
+		credentialIssuer, err := url.Parse(strings.TrimSuffix(config.CredentialIssuer, "/"))
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing credential issuer<%s>", config.CredentialIssuer)
+		}
+		im.CredentialIssuer.URL = *credentialIssuer
+	}
+	if im.CredentialEndpoint.String() == "" {
+		im.CredentialEndpoint.URL = *im.CredentialIssuer.JoinPath(strings.TrimPrefix(credentialPath, oidcPrefix))
+	}
+	return &im, nil
+}
+
+// currentIssuerMetadata is the static issuer metadata with the credentials_supported generated from the issuance
+// templates, overridden by the static entries with the same id.
+func (s AuthService) currentIssuerMetadata(ctx context.Context) (*issuance.IssuerMetadata, error) {
+	generated, err := s.credentialsSupported(ctx)
+	if err != nil {
+		return nil, err
+	}
+	im := *s.issuerMetadata
+	im.CredentialsSupported = make(map[string]issuance.CredentialSupported, len(generated))
+	im.OtherCredentialsSupported = nil
+	for _, cs := range generated {
+		if override, ok := s.issuerMetadata.CredentialsSupported[*cs.ID]; ok {
+			cs = overrideCredentialSupported(cs, override)
+		}
+		im.CredentialsSupported[*cs.ID] = cs
+	}
+	for id := range s.issuerMetadata.CredentialsSupported {
+		if _, ok := im.CredentialsSupported[id]; !ok {
+			logrus.Debugf("credentials_supported<%s> of the credential issuer file overrides no issuance template", id)
+		}
+	}
+	return &im, nil
+}
+
+// credentialsSupported builds an entry for each credential of the issuance templates that can be issued from the
+// credential endpoint, which are the ones with types that don't need a credential application. Display values come
+// from the output descriptor of the manifest, the supported suites from the manifest's format, and the claims from the
+// credential schema.
+func (s AuthService) credentialsSupported(ctx context.Context) ([]issuance.CredentialSupported, error) {
+	templates, err := s.ssi.Issuance.ListIssuanceTemplates(ctx, &issuancesvc.ListIssuanceTemplatesRequest{})
+	if err != nil {
+		return nil, errors.Wrap(err, "listing issuance templates")
+	}
+	bindingMethods := s.cryptographicBindingMethods()
+
+	manifests := make(map[string]*manifestsdk.CredentialManifest)
+	supported := make([]issuance.CredentialSupported, 0, len(templates.IssuanceTemplates))
+	ids := make(map[string]bool)
+	for _, template := range templates.IssuanceTemplates {
+		for _, ct := range template.Credentials {
+			if ct.CredentialInputDescriptor != "" || len(ct.Types) == 0 {
+				continue
+			}
+			// the credential endpoint issues credentials with the first template that has their types
+			types := sdkutil.MergeUniqueValues([]string{credsdk.VerifiableCredentialType}, ct.Types)
+			if isSupported(supported, types) {
+				continue
+			}
+
+			cm, ok := manifests[template.CredentialManifest]
+			if !ok {
+				gotManifest, err := s.ssi.Manifest.GetManifest(ctx, model.GetManifestRequest{ID: template.CredentialManifest})
+				if err != nil {
+					logrus.WithError(err).Warnf("skipping credential<%s> of issuance template<%s>", ct.ID, template.ID)
+					continue
+				}
+				cm = &gotManifest.Manifest
+				manifests[template.CredentialManifest] = cm
+			}
+			od := outputDescriptor(cm, ct.ID)
+			schemaID := ct.Schema
+			if schemaID == "" && od != nil {
+				schemaID = od.Schema
+			}
+
+			id := ct.ID
+			if ids[id] {
+				id = template.ID + "/" + ct.ID
+			}
+			ids[id] = true
+			cs := issuance.CredentialSupported{
+				Format:                               issuance.JWTVCJSON,
+				ID:                                   &id,
+				CryptographicBindingMethodsSupported: bindingMethods,
+				CryptographicSuitesSupported:         cryptographicSuites(cm),
+				JWTVCJSONCredentialMetadata:          &issuance.JWTVCJSONCredentialMetadata{Types: types},
+			}
+			var schemaName string
+			if schemaID != "" {
+				gotSchema, err := s.ssi.Schema.GetSchema(ctx, schema.GetSchemaRequest{ID: schemaID})
+				if err != nil {
+					logrus.WithError(err).Warnf("credential<%s> of issuance template<%s> has no claims", ct.ID, template.ID)
+				} else {
+					schemaName = gotSchema.Schema.Name
+					cs.CredentialSubject = schemaClaims(gotSchema.Schema.Schema)
+				}
+			}
+			cs.Display = credentialDisplay(cm, od, schemaName)
+			supported = append(supported, cs)
+		}
+	}
+	return supported, nil
+}
+
+// cryptographicBindingMethods are the DID methods the services resolve, which verify the proofs of possession.
+func (s AuthService) cryptographicBindingMethods() []issuance.CryptographicBindingMethodSupported {
+	methods := s.ssi.DID.GetResolver().Methods()
+	seen := make(map[did.Method]bool, len(methods))
+	bindingMethods := make([]issuance.CryptographicBindingMethodSupported, 0, len(methods))
+	for _, method := range methods {
+		if seen[method] {
+			continue
+		}
+		seen[method] = true
+		bindingMethods = append(bindingMethods, issuance.CryptographicBindingMethodSupported("did:"+method.String()))
+	}
+	sort.Slice(bindingMethods, func(i, j int) bool { return bindingMethods[i] < bindingMethods[j] })
+	return bindingMethods
+}
+
+func isSupported(supported []issuance.CredentialSupported, types []string) bool {
+	for _, cs := range supported {
+		if sameTypes(cs.Types, types) {
+			return true
+		}
+	}
+	return false
+}
+
+func outputDescriptor(cm *manifestsdk.CredentialManifest, id string) *manifestsdk.OutputDescriptor {
+	for i, od := range cm.OutputDescriptors {
+		if od.ID == id {
+			return &cm.OutputDescriptors[i]
+		}
+	}
+	return nil
+}
+
+// cryptographicSuites are the JWT algorithms of the manifest's format.
+func cryptographicSuites(cm *manifestsdk.CredentialManifest) []string {
+	if cm.Format == nil {
+		return nil
+	}
+	jwtType := cm.Format.JWTVC
+	if jwtType == nil {
+		jwtType = cm.Format.JWT
+	}
+	if jwtType == nil {
+		return nil
+	}
+	suites := make([]string, 0, len(jwtType.Alg))
+	for _, alg := range jwtType.Alg {
+		suites = append(suites, string(alg))
+	}
+	return suites
+}
+
+// credentialDisplay is named after the output descriptor, or else after the schema, and styled with the styles of the
+// output descriptor, or else with those of the manifest's issuer.
+func credentialDisplay(cm *manifestsdk.CredentialManifest, od *manifestsdk.OutputDescriptor, schemaName string) []issuance.CredentialDisplay {
+	name := schemaName
+	styles := cm.Issuer.Styles
+	var description string
+	if od != nil {
+		if od.Name != "" {
+			name = od.Name
+		}
+		if od.Styles != nil {
+			styles = od.Styles
+		}
+		description = od.Description
+	}
+	if name == "" {
+		return nil
+	}
+
+	display := issuance.CredentialDisplay{Display: issuance.Display{Name: &name}}
+	if description != "" {
+		display.Description = &description
+	}
+	if styles != nil {
+		display.Logo = logo(styles.Thumbnail)
+		if styles.Background != nil && styles.Background.Color != "" {
+			display.BackgroundColor = &styles.Background.Color
+		}
+		if styles.Text != nil && styles.Text.Color != "" {
+			display.TextColor = &styles.Text.Color
+		}
+	}
+	return []issuance.CredentialDisplay{display}
+}
+
+func logo(thumbnail *rendering.ImageResource) *issuance.Logo {
+	if thumbnail == nil {
+		return nil
+	}
+	logoURL, err := url.Parse(thumbnail.URI)
+	if err != nil {
+		logrus.WithError(err).Warnf("ignoring thumbnail<%s>", thumbnail.URI)
+		return nil
+	}
+	l := issuance.Logo{URL: &sdkutil.URL{URL: *logoURL}}
+	if thumbnail.Alt != "" {
+		l.AltText = &thumbnail.Alt
+	}
+	return &l
+}
+
+// schemaClaims describes the properties of a credential schema, which are the claims of the credential subject.
+func schemaClaims(jsonSchema map[string]any) map[string]issuance.Claim {
+	properties, ok := jsonSchema["properties"].(map[string]any)
+	if !ok {
+		return nil
+	}
+	required := make(map[string]bool)
+	if requiredProperties, ok := jsonSchema["required"].([]any); ok {
+		for _, r := range requiredProperties {
+			if name, ok := r.(string); ok {
+				required[name] = true
+			}
+		}
+	}
+
+	claims := make(map[string]issuance.Claim, len(properties))
+	for name, property := range properties {
+		var claim issuance.Claim
+		if required[name] {
+			mandatory := true
+			claim.Mandatory = &mandatory
+		}
+		if p, ok := property.(map[string]any); ok {
+			if valueType, ok := p["type"].(string); ok {
+				claim.ValueType = &valueType
+			}
+			if title, ok := p["title"].(string); ok {
+				claim.OtherDisplays = []issuance.Display{{Name: &title}}
+			}
+		}
+		claims[name] = claim
+	}
+	return claims
+}
+
+// overrideCredentialSupported replaces the generated values with the ones of the override. The format and types
+// identify the credential, so they are never overridden. Claims are overridden one by one, and claims that are not in
+// the schema are added.
+func overrideCredentialSupported(generated, override issuance.CredentialSupported) issuance.CredentialSupported {
+	if len(override.CryptographicBindingMethodsSupported) > 0 {
+		generated.CryptographicBindingMethodsSupported = override.CryptographicBindingMethodsSupported
+	}
+	if len(override.CryptographicSuitesSupported) > 0 {
+		generated.CryptographicSuitesSupported = override.CryptographicSuitesSupported
+	}
+	if len(override.Display) > 0 {
+		generated.Display = override.Display
+	}
+	if override.JWTVCJSONCredentialMetadata != nil {
+		metadata := *generated.JWTVCJSONCredentialMetadata
+		if len(override.CredentialSubject) > 0 {
+			claims := make(map[string]issuance.Claim, len(metadata.CredentialSubject)+len(override.CredentialSubject))
+			for name, claim := range metadata.CredentialSubject {
+				claims[name] = claim
+			}
+			for name, claim := range override.CredentialSubject {
+				claims[name] = overrideClaim(claims[name], claim)
+			}
+			metadata.CredentialSubject = claims
+		}
+		if len(override.Order) > 0 {
+			metadata.Order = override.Order
+		}
+		generated.JWTVCJSONCredentialMetadata = &metadata
+	}
+	return generated
+}
+
+func overrideClaim(generated, override issuance.Claim) issuance.Claim {
+	if override.Mandatory != nil {
+		generated.Mandatory = override.Mandatory
+	}
+	if override.ValueType != nil {
+		generated.ValueType = override.ValueType
+	}
+	if len(override.Display) > 0 || len(override.OtherDisplays) > 0 {
+		generated.Display = override.Display
+		generated.OtherDisplays = override.OtherDisplays
+	}
+	return generated
 }
@@ -0,0 +1,136 @@
+package authorizationserver
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/TBD54566975/ssi-sdk/credential/exchange"
+	manifestsdk "github.com/TBD54566975/ssi-sdk/credential/manifest"
+	"github.com/TBD54566975/ssi-sdk/credential/rendering"
+	"github.com/TBD54566975/ssi-sdk/crypto"
+	"github.com/TBD54566975/ssi-sdk/oidc/issuance"
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tbd54566975/ssi-service/pkg/service/did"
+	issuancesvc "github.com/tbd54566975/ssi-service/pkg/service/issuance"
+	"github.com/tbd54566975/ssi-service/pkg/service/manifest/model"
+	"github.com/tbd54566975/ssi-service/pkg/service/schema"
+)
+
+func TestLoadIssuerMetadata(t *testing.T) {
+	t.Run("credential endpoint is the one of the configured credential issuer", func(t *testing.T) {
+		im, err := loadIssuerMetadata(&AuthConfig{CredentialIssuer: "https://issuer.example.com/oidc/issuer/"})
+		require.NoError(t, err)
+		assert.Equal(t, "https://issuer.example.com/oidc/issuer", im.CredentialIssuer.String())
+		assert.Equal(t, "https://issuer.example.com/oidc/issuer/credential", im.CredentialEndpoint.String())
+		assert.Empty(t, im.CredentialsSupported)
+	})
+
+	t.Run("credential issuer file overrides the configured credential issuer", func(t *testing.T) {
+		im, err := loadIssuerMetadata(&AuthConfig{
+			CredentialIssuer:     "https://issuer.example.com/oidc/issuer",
+			CredentialIssuerFile: "../../config/credential_issuer_metadata.example.json",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "https://credential-issuer.example.com", im.CredentialIssuer.String())
+		assert.Equal(t, "https://credential-issuer.example.com/credentials", im.CredentialEndpoint.String())
+		assert.Contains(t, im.CredentialsSupported, "university_degree")
+	})
+
+	t.Run("credential issuer is required", func(t *testing.T) {
+		_, err := loadIssuerMetadata(&AuthConfig{})
+		assert.ErrorContains(t, err, "either a credential issuer or a credential issuer file must be configured")
+	})
+}
+
+func TestGeneratedCredentialsSupported(t *testing.T) {
+	ctx := context.Background()
+	issuerResp, err := ssi.DID.CreateDIDByMethod(ctx, did.CreateDIDRequest{Method: "key", KeyType: crypto.Ed25519})
+	require.NoError(t, err)
+	issuerKID := issuerResp.DID.VerificationMethod[0].ID
+
+	cardSchema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"cardNumber": map[string]any{"type": "string", "title": "Card Number"},
+			"branch":     map[string]any{"type": "string"},
+		},
+		"required": []any{"cardNumber"},
+	}
+	createdSchema, err := ssi.Schema.CreateSchema(ctx, schema.CreateSchemaRequest{Author: issuerResp.DID.ID, Name: "library card", Schema: cardSchema})
+	require.NoError(t, err)
+	createdManifest, err := ssi.Manifest.CreateManifest(ctx, model.CreateManifestRequest{
+		IssuerDID: issuerResp.DID.ID,
+		IssuerKID: issuerKID,
+		ClaimFormat: &exchange.ClaimFormat{
+			JWTVC: &exchange.JWTType{Alg: []crypto.SignatureAlgorithm{crypto.EdDSA}},
+		},
+		OutputDescriptors: []manifestsdk.OutputDescriptor{{
+			ID:          "library_card",
+			Schema:      createdSchema.ID,
+			Name:        "Library Card",
+			Description: "A card to borrow books",
+			Styles: &rendering.EntityStyleDescriptor{
+				Thumbnail:  &rendering.ImageResource{URI: "https://library.example.com/logo.png", Alt: "library logo"},
+				Background: &rendering.ColorResource{Color: "#000000"},
+			},
+		}},
+	})
+	require.NoError(t, err)
+
+	// the metadata doesn't have the credential until a template issues it
+	assert.NotContains(t, fetchIssuerMetadata(t).CredentialsSupported, "library_card")
+	template, err := ssi.Issuance.CreateIssuanceTemplate(ctx, &issuancesvc.CreateIssuanceTemplateRequest{
+		IssuanceTemplate: issuancesvc.Template{
+			CredentialManifest: createdManifest.Manifest.ID,
+			Issuer:             issuerResp.DID.ID,
+			IssuerKID:          issuerKID,
+			Credentials: []issuancesvc.CredentialTemplate{{
+				ID:    "library_card",
+				Types: []string{"LibraryCardCredential"},
+				Data:  issuancesvc.ClaimTemplates{"cardNumber": "123"},
+			}},
+		},
+	})
+	require.NoError(t, err)
+
+	cs, ok := fetchIssuerMetadata(t).CredentialsSupported["library_card"]
+	require.True(t, ok)
+	assert.Equal(t, issuance.JWTVCJSON, cs.Format)
+	assert.Equal(t, []string{"VerifiableCredential", "LibraryCardCredential"}, cs.Types)
+	assert.Equal(t, []string{"EdDSA"}, cs.CryptographicSuitesSupported)
+	assert.Contains(t, cs.CryptographicBindingMethodsSupported, issuance.CryptographicBindingMethodSupported("did:key"))
+
+	require.Len(t, cs.Display, 1)
+	assert.Equal(t, "Library Card", *cs.Display[0].Name)
+	assert.Equal(t, "A card to borrow books", *cs.Display[0].Description)
+	assert.Equal(t, "https://library.example.com/logo.png", cs.Display[0].Logo.URL.String())
+	assert.Equal(t, "#000000", *cs.Display[0].BackgroundColor)
+	assert.Nil(t, cs.Display[0].TextColor)
+
+	require.Len(t, cs.CredentialSubject, 2)
+	cardNumber := cs.CredentialSubject["cardNumber"]
+	require.NotNil(t, cardNumber.Mandatory)
+	assert.True(t, *cardNumber.Mandatory)
+	assert.Equal(t, "string", *cardNumber.ValueType)
+	require.Len(t, cardNumber.OtherDisplays, 1)
+	assert.Equal(t, "Card Number", *cardNumber.OtherDisplays[0].Name)
+	assert.Nil(t, cs.CredentialSubject["branch"].Mandatory)
+
+	// nor once the template is deleted
+	require.NoError(t, ssi.Issuance.DeleteIssuanceTemplate(ctx, &issuancesvc.DeleteIssuanceTemplateRequest{ID: template.ID}))
+	assert.NotContains(t, fetchIssuerMetadata(t).CredentialsSupported, "library_card")
+}
+
+func fetchIssuerMetadata(t *testing.T) issuance.IssuerMetadata {
+	resp, err := http.Get(server.URL + issuerMetadataPath)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var im issuance.IssuerMetadata
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&im))
+	return im
+}
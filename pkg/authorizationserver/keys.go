@@ -0,0 +1,223 @@
+package authorizationserver
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/sha256"
+	"io"
+	"os"
+
+	"github.com/TBD54566975/ssi-sdk/crypto"
+	"github.com/goccy/go-json"
+	"github.com/mr-tron/base58"
+	"github.com/ory/fosite/token/jwt"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/hkdf"
+	"gopkg.in/square/go-jose.v2"
+
+	"github.com/tbd54566975/ssi-service/pkg/service/keystore"
+)
+
+const (
+	// defaultSigningKeyID is the ID of the key in the keystore when no signing key IDs are configured.
+	defaultSigningKeyID = "authorization-server-signing-key"
+
+	// signingKeyController is the controller of the signing keys the authorization server keeps in the keystore.
+	signingKeyController = "authorization-server"
+
+	// globalSecretInfo binds the global secrets derived from the signing keys to their use.
+	globalSecretInfo = "ssi-service authorization server global secret"
+
+	// globalSecretLength is the length fosite requires for HMAC secrets.
+	globalSecretLength = 32
+)
+
+// signingKey is an RSA key of the authorization server, identified in the kid header of the JWTs it signs.
+type signingKey struct {
+	ID  string
+	Key *rsa.PrivateKey
+}
+
+// keySet has the keys of the authorization server. The first key is the current one, which signs ID tokens and JWTs.
+// All the keys are published in the JWKS, so that tokens signed before a rotation can still be verified.
+//
+// The global secret that fosite uses to sign authorize codes, access and refresh tokens is derived from each key, so
+// rotating the keys also rotates the secret, while tokens issued with the previous secrets stay valid.
+type keySet []signingKey
+
+func (k keySet) current() signingKey {
+	return k[0]
+}
+
+// globalSecret is the secret derived from the current key.
+func (k keySet) globalSecret() ([]byte, error) {
+	return deriveGlobalSecret(k.current())
+}
+
+// rotatedGlobalSecrets are the secrets derived from the keys that were rotated.
+func (k keySet) rotatedGlobalSecrets() ([][]byte, error) {
+	secrets := make([][]byte, 0, len(k)-1)
+	for _, key := range k[1:] {
+		secret, err := deriveGlobalSecret(key)
+		if err != nil {
+			return nil, err
+		}
+		secrets = append(secrets, secret)
+	}
+	return secrets, nil
+}
+
+// jwks is the set of the public keys, to be served at the JWKS endpoint.
+func (k keySet) jwks() jose.JSONWebKeySet {
+	keys := make([]jose.JSONWebKey, 0, len(k))
+	for _, key := range k {
+		keys = append(keys, jose.JSONWebKey{
+			Key:       &key.Key.PublicKey,
+			KeyID:     key.ID,
+			Algorithm: string(jose.RS256),
+			Use:       "sig",
+		})
+	}
+	return jose.JSONWebKeySet{Keys: keys}
+}
+
+func deriveGlobalSecret(key signingKey) ([]byte, error) {
+	secret := make([]byte, globalSecretLength)
+	reader := hkdf.New(sha256.New, key.Key.D.Bytes(), []byte(key.ID), []byte(globalSecretInfo))
+	if _, err := io.ReadFull(reader, secret); err != nil {
+		return nil, errors.Wrapf(err, "deriving global secret from key<%s>", key.ID)
+	}
+	return secret, nil
+}
+
+// loadKeySet loads the keys of the authorization server from the key file when one is configured, and otherwise from
+// the keystore.
+func loadKeySet(ctx context.Context, config *AuthConfig, keyStore *keystore.Service) (keySet, error) {
+	if config.KeyFile != "" {
+		return loadKeyFile(config.KeyFile)
+	}
+	return loadKeystoreKeys(ctx, keyStore, config.SigningKeyIDs)
+}
+
+// loadKeyFile reads a JWKS of private RSA keys. The first key is the current one. Keys are rotated by adding a new key
+// at the beginning of the set.
+func loadKeyFile(path string) (keySet, error) {
+	jwksBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading key file<%s>", path)
+	}
+	var jwks jose.JSONWebKeySet
+	if err = json.Unmarshal(jwksBytes, &jwks); err != nil {
+		return nil, errors.Wrapf(err, "unmarshalling key file<%s>", path)
+	}
+	if len(jwks.Keys) == 0 {
+		return nil, errors.Errorf("key file<%s> has no keys", path)
+	}
+	keys := make(keySet, 0, len(jwks.Keys))
+	for i, jwk := range jwks.Keys {
+		privateKey, ok := jwk.Key.(*rsa.PrivateKey)
+		if !ok {
+			return nil, errors.Errorf("keys[%d] of key file<%s> is not a private RSA key", i, path)
+		}
+		if jwk.KeyID == "" {
+			return nil, errors.Errorf("keys[%d] of key file<%s> has no kid", i, path)
+		}
+		keys = append(keys, signingKey{ID: jwk.KeyID, Key: privateKey})
+	}
+	return keys, nil
+}
+
+// loadKeystoreKeys gets the keys with the given IDs from the keystore. The first ID is the current key, which is
+// generated when it's not in the keystore yet. Keys are rotated by adding a new ID at the beginning of the list; the
+// previous keys are kept until they are removed from the list, or revoked in the keystore.
+func loadKeystoreKeys(ctx context.Context, keyStore *keystore.Service, keyIDs []string) (keySet, error) {
+	if len(keyIDs) == 0 {
+		keyIDs = []string{defaultSigningKeyID}
+	}
+	if keyStore == nil {
+		return nil, errors.New("keystore cannot be nil")
+	}
+
+	currentID := keyIDs[0]
+	exists, err := keyStore.KeyExists(ctx, currentID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		logrus.Infof("generating signing key<%s> of the authorization server", currentID)
+		if err = generateKeystoreKey(ctx, keyStore, currentID); err != nil {
+			return nil, err
+		}
+	}
+
+	keys := make(keySet, 0, len(keyIDs))
+	for i, id := range keyIDs {
+		gotKey, err := keyStore.GetKey(ctx, keystore.GetKeyRequest{ID: id})
+		if err != nil {
+			if i == 0 {
+				return nil, errors.Wrapf(err, "getting current signing key<%s>", id)
+			}
+			logrus.WithError(err).Warnf("skipping previous signing key<%s>", id)
+			continue
+		}
+		if gotKey.Revoked {
+			if i == 0 {
+				return nil, errors.Errorf("current signing key<%s> was revoked", id)
+			}
+			continue
+		}
+		privateKey, ok := gotKey.Key.(rsa.PrivateKey)
+		if !ok {
+			return nil, errors.Errorf("signing key<%s> is not an RSA key", id)
+		}
+		keys = append(keys, signingKey{ID: id, Key: &privateKey})
+	}
+	return keys, nil
+}
+
+func generateKeystoreKey(ctx context.Context, keyStore *keystore.Service, id string) error {
+	_, privateKey, err := crypto.GenerateRSA2048Key()
+	if err != nil {
+		return errors.Wrap(err, "generating signing key")
+	}
+	privateKeyBytes, err := crypto.PrivKeyToBytes(privateKey)
+	if err != nil {
+		return errors.Wrap(err, "serializing signing key")
+	}
+	return keyStore.StoreKey(ctx, keystore.StoreKeyRequest{
+		ID:               id,
+		Type:             crypto.RSA,
+		Controller:       signingKeyController,
+		PrivateKeyBase58: base58.Encode(privateKeyBytes),
+	})
+}
+
+// keyIDSigner signs JWTs with the current key, and sets its ID as the kid header so that clients know which key of
+// the JWKS verifies them.
+type keyIDSigner struct {
+	*jwt.DefaultSigner
+	keyID string
+}
+
+func newKeyIDSigner(key signingKey) *keyIDSigner {
+	return &keyIDSigner{
+		DefaultSigner: &jwt.DefaultSigner{
+			GetPrivateKey: func(context.Context) (any, error) {
+				return key.Key, nil
+			},
+		},
+		keyID: key.ID,
+	}
+}
+
+func (s *keyIDSigner) Generate(ctx context.Context, claims jwt.MapClaims, header jwt.Mapper) (string, string, error) {
+	headers := &jwt.Headers{Extra: make(map[string]any)}
+	if header != nil {
+		for k, v := range header.ToMap() {
+			headers.Add(k, v)
+		}
+	}
+	headers.Add("kid", s.keyID)
+	return s.DefaultSigner.Generate(ctx, claims, headers)
+}
@@ -0,0 +1,125 @@
+package authorizationserver
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/TBD54566975/ssi-sdk/crypto"
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/square/go-jose.v2"
+
+	"github.com/tbd54566975/ssi-service/config"
+	"github.com/tbd54566975/ssi-service/pkg/service/keystore"
+)
+
+func TestLoadKeyFile(t *testing.T) {
+	t.Run("rotated keys are published and keep their secrets", func(t *testing.T) {
+		_, newKey, err := crypto.GenerateRSA2048Key()
+		require.NoError(t, err)
+		_, oldKey, err := crypto.GenerateRSA2048Key()
+		require.NoError(t, err)
+		path := writeKeyFile(t, jose.JSONWebKeySet{Keys: []jose.JSONWebKey{
+			{Key: &newKey, KeyID: "new-key"},
+			{Key: &oldKey, KeyID: "old-key"},
+		}})
+
+		keys, err := loadKeyFile(path)
+		require.NoError(t, err)
+		require.Len(t, keys, 2)
+		assert.Equal(t, "new-key", keys.current().ID)
+
+		jwks := keys.jwks()
+		require.Len(t, jwks.Keys, 2)
+		assert.Len(t, jwks.Key("old-key"), 1)
+		for _, key := range jwks.Keys {
+			assert.True(t, key.IsPublic())
+		}
+
+		secret, err := keys.globalSecret()
+		require.NoError(t, err)
+		assert.Len(t, secret, globalSecretLength)
+		rotated, err := keys.rotatedGlobalSecrets()
+		require.NoError(t, err)
+		require.Len(t, rotated, 1)
+		assert.NotEqual(t, secret, rotated[0])
+
+		// the secret of a key doesn't change when it's rotated
+		oldKeys, err := loadKeyFile(writeKeyFile(t, jose.JSONWebKeySet{Keys: []jose.JSONWebKey{{Key: &oldKey, KeyID: "old-key"}}}))
+		require.NoError(t, err)
+		oldSecret, err := oldKeys.globalSecret()
+		require.NoError(t, err)
+		assert.Equal(t, oldSecret, rotated[0])
+	})
+
+	t.Run("keys must be private and have a kid", func(t *testing.T) {
+		publicKey, privateKey, err := crypto.GenerateRSA2048Key()
+		require.NoError(t, err)
+
+		_, err = loadKeyFile(writeKeyFile(t, jose.JSONWebKeySet{Keys: []jose.JSONWebKey{{Key: &publicKey, KeyID: "public-key"}}}))
+		assert.ErrorContains(t, err, "is not a private RSA key")
+		_, err = loadKeyFile(writeKeyFile(t, jose.JSONWebKeySet{Keys: []jose.JSONWebKey{{Key: &privateKey}}}))
+		assert.ErrorContains(t, err, "has no kid")
+		_, err = loadKeyFile(writeKeyFile(t, jose.JSONWebKeySet{}))
+		assert.ErrorContains(t, err, "has no keys")
+	})
+}
+
+func TestLoadKeystoreKeys(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("current key is generated once", func(t *testing.T) {
+		keyStore := newTestKeyStore(t)
+		keys, err := loadKeystoreKeys(ctx, keyStore, nil)
+		require.NoError(t, err)
+		require.Len(t, keys, 1)
+		assert.Equal(t, defaultSigningKeyID, keys.current().ID)
+
+		reloaded, err := loadKeystoreKeys(ctx, keyStore, []string{defaultSigningKeyID})
+		require.NoError(t, err)
+		require.Len(t, reloaded, 1)
+		assert.True(t, keys.current().Key.Equal(reloaded.current().Key))
+	})
+
+	t.Run("previous keys are kept until revoked", func(t *testing.T) {
+		keyStore := newTestKeyStore(t)
+		_, err := loadKeystoreKeys(ctx, keyStore, []string{"first-key"})
+		require.NoError(t, err)
+
+		keys, err := loadKeystoreKeys(ctx, keyStore, []string{"second-key", "first-key", "unknown-key"})
+		require.NoError(t, err)
+		require.Len(t, keys, 2)
+		assert.Equal(t, "second-key", keys[0].ID)
+		assert.Equal(t, "first-key", keys[1].ID)
+
+		require.NoError(t, keyStore.RevokeKey(ctx, keystore.RevokeKeyRequest{ID: "first-key"}))
+		keys, err = loadKeystoreKeys(ctx, keyStore, []string{"second-key", "first-key"})
+		require.NoError(t, err)
+		require.Len(t, keys, 1)
+		assert.Equal(t, "second-key", keys.current().ID)
+
+		_, err = loadKeystoreKeys(ctx, keyStore, []string{"first-key"})
+		assert.ErrorContains(t, err, "was revoked")
+	})
+}
+
+func writeKeyFile(t *testing.T, jwks jose.JSONWebKeySet) string {
+	jwksBytes, err := json.Marshal(jwks)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "keys.json")
+	require.NoError(t, os.WriteFile(path, jwksBytes, 0600))
+	return path
+}
+
+func newTestKeyStore(t *testing.T) *keystore.Service {
+	serviceConfig := config.KeyStoreServiceConfig{
+		BaseServiceConfig: &config.BaseServiceConfig{Name: "test-keystore"},
+		MasterKeyPassword: "test-password",
+	}
+	keyStore, err := keystore.NewKeyStoreService(serviceConfig, setupTestDB(t))
+	require.NoError(t, err)
+	return keyStore
+}
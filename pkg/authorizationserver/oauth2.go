@@ -1,54 +1,24 @@
 package authorizationserver
 
 import (
-	"crypto/rand"
-	"crypto/rsa"
+	"context"
 	"os"
 	"time"
 
-	"github.com/TBD54566975/ssi-sdk/oidc/issuance"
 	"github.com/ardanlabs/conf"
 	"github.com/gin-gonic/gin"
-	"github.com/goccy/go-json"
 	"github.com/ory/fosite"
 	"github.com/ory/fosite/compose"
 	"github.com/ory/fosite/handler/openid"
-	"github.com/ory/fosite/storage"
-	"github.com/ory/fosite/token/jwt"
+	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 
 	"github.com/tbd54566975/ssi-service/config"
 	"github.com/tbd54566975/ssi-service/pkg/server/framework"
 	"github.com/tbd54566975/ssi-service/pkg/server/middleware"
+	"github.com/tbd54566975/ssi-service/pkg/service"
 )
 
-// A session is passed from the `/auth` to the `/token` endpoint. You probably want to store data like: "Who made the request",
-// "What organization does that person belong to" and so on.
-// For our use case, the session will meet the requirements imposed by JWT access tokens, HMAC access tokens and OpenID Connect
-// ID Tokens plus a custom field
-
-// newSession is a helper function for creating a new session. This may look like a lot of code but since we are
-// setting up multiple strategies it is a bit longer.
-// Usually, you could do:
-//
-//	session = new(fosite.DefaultSession)
-func newSession(user string) *openid.DefaultSession {
-	return &openid.DefaultSession{
-		Claims: &jwt.IDTokenClaims{
-			Issuer:      "https://fosite.my-application.com",
-			Subject:     user,
-			Audience:    []string{"https://my-client.my-application.com"},
-			ExpiresAt:   time.Now().Add(time.Hour * 6),
-			IssuedAt:    time.Now(),
-			RequestedAt: time.Now(),
-			AuthTime:    time.Now(),
-		},
-		Headers: &jwt.Headers{
-			Extra: make(map[string]interface{}),
-		},
-	}
-}
-
 type Server struct {
 	*framework.Server
 }
@@ -56,14 +26,31 @@ type Server struct {
 const (
 	oidcPrefix         = "/oidc/issuer"
 	issuerMetadataPath = oidcPrefix + "/.well-known/openid-credential-issuer"
+	credentialPath     = oidcPrefix + "/credential"
 )
 
-func NewServer(shutdown chan os.Signal, config *AuthConfig, store *storage.MemoryStore) (*Server, error) {
-	// This secret is used to sign authorize codes, access and refresh tokens.
-	// It has to be 32-bytes long for HMAC signing. This requirement can be configured via `compose.Config`
-	secret := make([]byte, 32)
+// NewServer creates the authorization server, which keeps its clients and grants in store. Credentials requested from
+// the credential endpoint are issued with the services of ssi.
+func NewServer(shutdown chan os.Signal, config *AuthConfig, store *Storage, ssi *service.SSIService) (*Server, error) {
+	im, err := loadIssuerMetadata(config)
+	if err != nil {
+		logrus.WithError(err).Fatal("could not load issuer metadata")
+		os.Exit(1)
+	}
+	issuer := issuerURL(config, im)
 
-	if _, err := rand.Read(secret); err != nil {
+	// The keys sign ID tokens and JWTs, and the global secret derived from them signs authorize codes, access and
+	// refresh tokens. Both outlive restarts, so that issued tokens stay valid.
+	keys, err := loadKeySet(context.Background(), config, ssi.KeyStore)
+	if err != nil {
+		return nil, errors.Wrap(err, "loading signing keys")
+	}
+	globalSecret, err := keys.globalSecret()
+	if err != nil {
+		return nil, err
+	}
+	rotatedGlobalSecrets, err := keys.rotatedGlobalSecrets()
+	if err != nil {
 		return nil, err
 	}
 
@@ -74,20 +61,63 @@ func NewServer(shutdown chan os.Signal, config *AuthConfig, store *storage.Memor
 	//  3. secret - required for code, access and refresh token generation.
 	//  4. privateKey - required for id/jwt token generation.
 
+	offers, err := newOfferStorage(store.db)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating credential offer storage")
+	}
+
 	var (
 		// Check the api documentation of `compose.Config` for further configuration options.
 		fositeConfig = &fosite.Config{
-			AccessTokenLifespan:        time.Minute * 30,
-			GlobalSecret:               secret,
-			SendDebugMessagesToClients: true,
+			AccessTokenLifespan:         time.Minute * 30,
+			GlobalSecret:                globalSecret,
+			RotatedGlobalSecrets:        rotatedGlobalSecrets,
+			IDTokenIssuer:               issuer,
+			AccessTokenIssuer:           issuer,
+			SendDebugMessagesToClients:  config.isDev(),
+			EnforcePKCE:                 config.RequirePKCE,
+			EnforcePKCEForPublicClients: config.RequirePKCEForPublicClients,
+
+			// Pushed authorization requests are stored under a request_uri, which can be used once before it expires.
+			PushedAuthorizeRequestURIPrefix: parRequestURIPrefix,
+			PushedAuthorizeContextLifespan:  config.PushedAuthorizationRequestLifespan,
+			IsPushedAuthorizeEnforced:       config.RequirePushedAuthorizationRequests,
 			// ...
 		}
 
-		// privateKey is used to sign JWT tokens. The default strategy uses RS256 (RSA Signature with SHA-256)
-		privateKey, _ = rsa.GenerateKey(rand.Reader, 2048)
+		// signer signs JWTs with the current key. The default strategy uses RS256 (RSA Signature with SHA-256)
+		signer = newKeyIDSigner(keys.current())
 
 		// Build a fosite instance with all OAuth2 and OpenID Connect handlers enabled, plugging in our configurations as specified above.
-		oauth2 = compose.ComposeAllEnabled(fositeConfig, store, privateKey)
+		// On top of those, the pre-authorized code grant lets wallets redeem credential offers.
+		oauth2 = compose.Compose(
+			fositeConfig,
+			store,
+			&compose.CommonStrategy{
+				CoreStrategy:               compose.NewOAuth2HMACStrategy(fositeConfig),
+				OpenIDConnectTokenStrategy: &openid.DefaultStrategy{Signer: signer, Config: fositeConfig},
+				Signer:                     signer,
+			},
+			compose.OAuth2AuthorizeExplicitFactory,
+			compose.OAuth2AuthorizeImplicitFactory,
+			compose.OAuth2ClientCredentialsGrantFactory,
+			compose.OAuth2RefreshTokenGrantFactory,
+			compose.OAuth2ResourceOwnerPasswordCredentialsFactory,
+			compose.RFC7523AssertionGrantFactory,
+
+			compose.OpenIDConnectExplicitFactory,
+			compose.OpenIDConnectImplicitFactory,
+			compose.OpenIDConnectHybridFactory,
+			compose.OpenIDConnectRefreshFactory,
+
+			compose.OAuth2TokenIntrospectionFactory,
+			compose.OAuth2TokenRevocationFactory,
+
+			compose.OAuth2PKCEFactory,
+			compose.PushedAuthorizeHandlerFactory,
+
+			preAuthorizedCodeGrantFactory(offers),
+		)
 	)
 
 	middlewares := gin.HandlersChain{
@@ -100,39 +130,126 @@ func NewServer(shutdown chan os.Signal, config *AuthConfig, store *storage.Memor
 	engine.Use(middlewares...)
 	httpServer := framework.NewServer(config.Server, engine, shutdown)
 
-	im, err := loadIssuerMetadata(config)
-	if err != nil {
-		logrus.WithError(err).Fatal("could not load issuer metadata")
-		os.Exit(1)
+	engine.GET(jwksPath, jwks(keys))
+	metadata := newProviderMetadata(issuer)
+	if config.AllowDynamicClientRegistration {
+		metadata.RegistrationEndpoint = issuer + registrationPath
 	}
-
-	engine.GET(issuerMetadataPath, credentialIssuerMetadata(im))
+	metadata.RequirePushedAuthorizationRequests = config.RequirePushedAuthorizationRequests
+	engine.GET(openIDConfigurationPath, providerMetadata(metadata))
+	engine.GET(oauthAuthorizationServerPath, providerMetadata(metadata))
 
 	// Set up oauth2 endpoints.
-	authService := NewAuthService(im, oauth2)
-	engine.GET("/oauth2/auth", authService.AuthEndpoint)
-	engine.POST("/oauth2/auth", authService.AuthEndpoint)
+	authService, err := NewAuthService(im, oauth2, store, config, ssi)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating auth service")
+	}
+	engine.GET(issuerMetadataPath, authService.CredentialIssuerMetadata)
+	engine.GET(authorizationPath, authService.AuthEndpoint)
+	engine.POST(authorizationPath, authService.AuthEndpoint)
+	engine.POST(pushedAuthorizationRequestPath, authService.PushedAuthorizationRequestEndpoint)
+	engine.POST(tokenPath, authService.TokenEndpoint)
+	engine.POST(credentialPath, authService.CredentialEndpoint)
+
+	// Set up client endpoints. Clients can register themselves when dynamic registration is enabled, and are managed
+	// by admins when an admin token is configured.
+	if config.AllowDynamicClientRegistration {
+		engine.POST(registrationPath, authService.RegisterClient)
+	}
+	if config.ClientAdminToken != "" {
+		admin := engine.Group(adminClientsPath, requireAdminToken(config.ClientAdminToken))
+		admin.PUT("", authService.CreateClient)
+		admin.GET("", authService.ListClients)
+		admin.GET("/:id", authService.GetClient)
+		admin.PUT("/:id", authService.UpdateClient)
+		admin.DELETE("/:id", authService.DeleteClient)
+	} else {
+		logrus.Warn("no client admin token configured, clients can't be managed")
+	}
+
+	// Set up credential offer endpoints. Offers pre-approve the claims of the credentials issued for them, so only admins
+	// can create them.
+	if config.ClientAdminToken != "" {
+		engine.PUT(oidcPrefix+credentialOffersPath, requireAdminToken(config.ClientAdminToken), authService.CreateCredentialOffer)
+	} else {
+		logrus.Warn("no client admin token configured, credential offers can't be created")
+	}
+	engine.GET(oidcPrefix+credentialOffersPath+"/:id", authService.GetCredentialOffer)
 
 	return &Server{
 		Server: httpServer,
 	}, nil
 }
 
-func loadIssuerMetadata(config *AuthConfig) (*issuance.IssuerMetadata, error) {
-	jsonData, err := os.ReadFile(config.CredentialIssuerFile)
-	if err != nil {
-		return nil, err
-	}
+type AuthConfig struct {
+	conf.Version
+	Server config.ServerConfig
 
-	var im issuance.IssuerMetadata
-	if err := json.Unmarshal(jsonData, &im); err != nil {
-		return nil, err
-	}
-	return &im, nil
+	// Identifies the credential issuer in the issuer metadata and in credential offers. The issuer metadata is served
+	// at this URL followed by /.well-known/openid-credential-issuer.
+	CredentialIssuer string `toml:"credential_issuer" conf:"default:http://localhost:3000/oidc/issuer"`
+
+	// Optional path to a JSON file with credential issuer metadata. Its values override the generated ones, and its
+	// credentials_supported override the generated entries with the same id.
+	CredentialIssuerFile string `toml:"credential_issuer_file"`
+
+	// How long the c_nonce returned by the token endpoint can be used in proofs sent to the credential endpoint.
+	CNonceLifespan time.Duration `toml:"c_nonce_lifespan" conf:"default:5m"`
+
+	// Identifies the authorization server in the tokens it issues and in its discovery metadata. Defaults to the
+	// authorization_server of the credential issuer metadata, or else to its credential_issuer.
+	Issuer string `toml:"issuer"`
+
+	// Path to a JWKS file with the private RSA keys of the authorization server, the current one first. When empty,
+	// the keys are kept in the keystore of the services.
+	KeyFile string `toml:"key_file"`
+
+	// IDs of the keys of the authorization server in the keystore, the current one first. The current key is
+	// generated when missing. The previous keys are still published, so that tokens issued before a rotation stay
+	// valid until they are removed from the list or revoked.
+	SigningKeyIDs []string `toml:"signing_key_ids" conf:"default:authorization-server-signing-key"`
+
+	// How often expired grants are deleted from storage.
+	GrantCleanupInterval time.Duration `toml:"grant_cleanup_interval" conf:"default:1h"`
+
+	// How long the pre-authorized code of a credential offer can be redeemed.
+	PreAuthorizedCodeLifespan time.Duration `toml:"pre_authorized_code_lifespan" conf:"default:24h"`
+
+	// Whether clients can register themselves at the registration endpoint of https://www.rfc-editor.org/rfc/rfc7591
+	// Disabled by default, since anyone can then register a client unless an initial access token is set.
+	AllowDynamicClientRegistration bool `toml:"allow_dynamic_client_registration" conf:"default:false"`
+
+	// Optional. When set, the registration endpoint requires it as a bearer token.
+	InitialAccessToken string `toml:"initial_access_token" conf:"noprint"`
+
+	// Whether public clients, such as wallets, can be registered. They authenticate with none, since they can't keep
+	// a secret. Disabled by default.
+	AllowPublicClients bool `toml:"allow_public_clients" conf:"default:false"`
+
+	// Whether public clients must use PKCE in the authorization code flow.
+	RequirePKCEForPublicClients bool `toml:"require_pkce_for_public_clients" conf:"default:true"`
+
+	// Whether all clients must use PKCE in the authorization code flow. Clients can also be registered to require it.
+	// Only the S256 code challenge method is accepted.
+	RequirePKCE bool `toml:"require_pkce"`
+
+	// Whether all clients must send their authorization requests to the pushed authorization request endpoint of
+	// https://www.rfc-editor.org/rfc/rfc9126 first. Clients can also be registered to require it.
+	RequirePushedAuthorizationRequests bool `toml:"require_pushed_authorization_requests"`
+
+	// How long the request_uri of a pushed authorization request can be used at the authorization endpoint.
+	PushedAuthorizationRequestLifespan time.Duration `toml:"pushed_authorization_request_lifespan" conf:"default:5m"`
+
+	// Optional. The bearer token of the admin endpoints that manage clients and create credential offers, which are
+	// disabled when empty.
+	ClientAdminToken string `toml:"client_admin_token" conf:"noprint"`
+
+	// Path to the TOML config of the SSI Service whose services issue credentials from the credential endpoint. The
+	// default services config is used when empty.
+	ServicesConfigPath string `toml:"services_config_path"`
 }
 
-type AuthConfig struct {
-	conf.Version
-	Server               config.ServerConfig
-	CredentialIssuerFile string `toml:"credential_issuer_file" conf:"default:config/credential_issuer_metadata.example.json"`
+// isDev is whether the server runs in the dev environment, where error details are sent to clients to help debugging.
+func (c *AuthConfig) isDev() bool {
+	return c.Server.Environment == config.EnvironmentDev
 }
@@ -1,7 +1,9 @@
 package authorizationserver
 
 import (
+	"context"
 	"fmt"
+	"time"
 
 	"github.com/TBD54566975/ssi-sdk/oidc/issuance"
 	"github.com/gin-gonic/gin"
@@ -11,15 +13,57 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/tbd54566975/ssi-service/pkg/authorizationserver/request"
+	"github.com/tbd54566975/ssi-service/pkg/service"
 )
 
+// defaultCNonceLifespan is used when no c_nonce lifespan is configured.
+const defaultCNonceLifespan = 5 * time.Minute
+
 type AuthService struct {
-	issuerMetadata *issuance.IssuerMetadata
-	provider       fosite.OAuth2Provider
+	issuerMetadata            *issuance.IssuerMetadata
+	provider                  fosite.OAuth2Provider
+	store                     *Storage
+	cNonceLifespan            time.Duration
+	preAuthorizedCodeLifespan time.Duration
+
+	// ssi has the services used to issue credentials from the credential endpoint.
+	ssi    *service.SSIService
+	offers *offerStorage
+
+	clientPolicy clientPolicy
+	hasher       fosite.Hasher
 }
 
-func NewAuthService(issuerMetadata *issuance.IssuerMetadata, provider fosite.OAuth2Provider) *AuthService {
-	return &AuthService{issuerMetadata: issuerMetadata, provider: provider}
+func NewAuthService(issuerMetadata *issuance.IssuerMetadata, provider fosite.OAuth2Provider, store *Storage, config *AuthConfig, ssi *service.SSIService) (*AuthService, error) {
+	if store == nil {
+		return nil, errors.New("storage cannot be nil")
+	}
+	offers, err := newOfferStorage(ssi.GetStorage())
+	if err != nil {
+		return nil, errors.Wrap(err, "creating credential offer storage")
+	}
+	cNonceLifespan := config.CNonceLifespan
+	if cNonceLifespan <= 0 {
+		cNonceLifespan = defaultCNonceLifespan
+	}
+	preAuthorizedCodeLifespan := config.PreAuthorizedCodeLifespan
+	if preAuthorizedCodeLifespan <= 0 {
+		preAuthorizedCodeLifespan = defaultPreAuthorizedCodeLifespan
+	}
+	return &AuthService{
+		issuerMetadata:            issuerMetadata,
+		provider:                  provider,
+		store:                     store,
+		cNonceLifespan:            cNonceLifespan,
+		preAuthorizedCodeLifespan: preAuthorizedCodeLifespan,
+		ssi:                       ssi,
+		offers:                    offers,
+		clientPolicy: clientPolicy{
+			allowPublicClients: config.AllowPublicClients,
+			initialAccessToken: config.InitialAccessToken,
+		},
+		hasher: &fosite.BCrypt{Config: &fosite.Config{}},
+	}, nil
 }
 
 // AuthEndpoint is a Handler that implements https://openid.net/specs/openid-4-verifiable-credential-issuance-1_0.html#name-authorization-endpoint
@@ -31,42 +75,19 @@ func (s AuthService) AuthEndpoint(c *gin.Context) {
 		return
 	}
 
-	authorizationDetailsJSON := ar.GetRequestForm().Get("authorization_details")
-	var authorizationDetails request.AuthorizationDetails
-	if err = json.Unmarshal([]byte(authorizationDetailsJSON), &authorizationDetails); err != nil {
-		logrus.WithError(err).Error("failed Unmarshal")
+	if err = checkRequestRequirements(ar, isPushedAuthorizationRequest(c.Request)); err != nil {
+		logrus.WithError(err).Error("failed request requirements")
 		s.provider.WriteAuthorizeError(c, c.Writer, ar, err)
 		return
 	}
 
-	if err = authorizationDetails.IsValid(); err != nil {
-		logrus.WithError(err).Error("failed Unmarshal")
+	authorizationDetails, err := s.authorizationDetails(c, ar)
+	if err != nil {
+		logrus.WithError(err).Error("failed processing authorization_details")
 		s.provider.WriteAuthorizeError(c, c.Writer, ar, err)
 		return
 	}
 
-	// If the Credential Issuer metadata contains an authorization_server parameter, the authorization detail's
-	// locations common data field MUST be set to the Credential Issuer Identifier value
-	if s.issuerMetadata.AuthorizationServer != nil {
-		for i, d := range authorizationDetails {
-			switch d.Type {
-			case "openid_credential":
-				if err := s.processOpenIDCredential(d); err != nil {
-					logrus.WithError(err).Error("failed processing openid_credential")
-					s.provider.WriteAuthorizeError(c, c.Writer, ar, err)
-					return
-				}
-				// TODO(https://github.com/TBD54566975/ssi-service/issues/368): support dynamic auth request
-
-			default:
-				err := errors.Errorf("the value of authorization_details[%d].type found was %q, which is not recognized", i, d.Type)
-				logrus.WithError(err).Error("unrecognized type")
-				s.provider.WriteAuthorizeError(c, c.Writer, ar, err)
-				return
-			}
-		}
-	}
-
 	// You have now access to authorizeRequest, Code ResponseTypes, Scopes ...
 	var requestedScopes string
 	for _, this := range ar.GetRequestedScopes() {
@@ -103,8 +124,10 @@ func (s AuthService) AuthEndpoint(c *gin.Context) {
 		ar.GrantScope(scope)
 	}
 
-	// Now that the user is authorized, we set up a session:
+	// Now that the user is authorized, we set up a session. The authorization details are kept in it, so that the
+	// token and credential endpoints know which credentials were granted.
 	mySessionData := newSession("peter")
+	mySessionData.AuthorizationDetails = authorizationDetails
 
 	// When using the HMACSHA strategy you must use something that implements the HMACSessionContainer.
 	// It brings you the power of overriding the default values.
@@ -146,16 +169,105 @@ func (s AuthService) AuthEndpoint(c *gin.Context) {
 	s.provider.WriteAuthorizeResponse(c, c.Writer, ar, response)
 }
 
-func (s AuthService) processOpenIDCredential(d request.AuthorizationDetail) error {
-	if len(d.Locations) != 1 {
-		return errors.New("locations expected to have a single element")
+// authorizationDetails returns the authorization_details of the request, once it's checked that they are valid and
+// that they request credentials the issuer supports.
+func (s AuthService) authorizationDetails(ctx context.Context, ar fosite.AuthorizeRequester) (request.AuthorizationDetails, error) {
+	var authorizationDetails request.AuthorizationDetails
+	if err := json.Unmarshal([]byte(ar.GetRequestForm().Get("authorization_details")), &authorizationDetails); err != nil {
+		return nil, invalidAuthorizationDetails(errors.Wrap(err, "unmarshalling authorization_details"))
 	}
-	if d.Locations[0] != s.issuerMetadata.CredentialIssuer.String() {
-		return errors.Errorf(
-			"locations[0] expected to be equal to %q, but received %q",
-			s.issuerMetadata.CredentialIssuer.String(),
-			d.Locations[0],
-		)
+	if err := authorizationDetails.IsValid(); err != nil {
+		return nil, invalidAuthorizationDetails(err)
+	}
+
+	im, err := s.currentIssuerMetadata(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "building issuer metadata")
+	}
+	for i, d := range authorizationDetails {
+		switch d.Type {
+		case request.OpenIDCredentialType:
+			if err = s.processOpenIDCredential(im, i, d); err != nil {
+				return nil, invalidAuthorizationDetails(err)
+			}
+		default:
+			return nil, invalidAuthorizationDetails(errors.Errorf("the value of authorization_details[%d].type found was %q, which is not recognized", i, d.Type))
+		}
+	}
+	return authorizationDetails, nil
+}
+
+// invalidAuthorizationDetails is the invalid_request error returned to the client when its authorization_details are
+// rejected. The reason is in the hint, so that clients get it even when debug messages aren't sent to them.
+func invalidAuthorizationDetails(err error) error {
+	return fosite.ErrInvalidRequest.WithHint(err.Error()).WithWrap(err)
+}
+
+// processOpenIDCredential checks that an authorization detail of type openid_credential requests a credential that
+// the issuer supports.
+func (s AuthService) processOpenIDCredential(im *issuance.IssuerMetadata, index int, d request.AuthorizationDetail) error {
+	// If the Credential Issuer metadata contains an authorization_server parameter, the authorization detail's
+	// locations common data field MUST be set to the Credential Issuer Identifier value
+	if s.issuerMetadata.AuthorizationServer != nil {
+		if len(d.Locations) != 1 {
+			return errors.New("locations expected to have a single element")
+		}
+		if d.Locations[0] != s.issuerMetadata.CredentialIssuer.String() {
+			return errors.Errorf(
+				"locations[0] expected to be equal to %q, but received %q",
+				s.issuerMetadata.CredentialIssuer.String(),
+				d.Locations[0],
+			)
+		}
+	}
+
+	if _, ok := findCredentialSupported(im, d); !ok {
+		return errors.Errorf("authorization_details[%d] requests a credential that is not in the issuer's credentials_supported", index)
 	}
 	return nil
 }
+
+// findCredentialSupported returns the entry of the issuer's credentials_supported matching the format and types of the
+// authorization detail.
+func findCredentialSupported(im *issuance.IssuerMetadata, d request.AuthorizationDetail) (*issuance.CredentialSupported, bool) {
+	if d.Format == nil {
+		return nil, false
+	}
+	var requestedTypes []string
+	if d.JWTVCDetails != nil {
+		requestedTypes = d.JWTVCDetails.Types
+	}
+
+	candidates := make([]issuance.CredentialSupported, 0, len(im.CredentialsSupported)+len(im.OtherCredentialsSupported))
+	for _, cs := range im.CredentialsSupported {
+		candidates = append(candidates, cs)
+	}
+	candidates = append(candidates, im.OtherCredentialsSupported...)
+	for i, cs := range candidates {
+		if cs.Format != *d.Format {
+			continue
+		}
+		// formats without types in the metadata are matched by format only
+		if cs.JWTVCJSONCredentialMetadata == nil || sameTypes(cs.Types, requestedTypes) {
+			return &candidates[i], true
+		}
+	}
+	return nil, false
+}
+
+func sameTypes(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	seen := make(map[string]int, len(a))
+	for _, t := range a {
+		seen[t]++
+	}
+	for _, t := range b {
+		if seen[t] == 0 {
+			return false
+		}
+		seen[t]--
+	}
+	return true
+}
@@ -0,0 +1,294 @@
+package authorizationserver
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	credsdk "github.com/TBD54566975/ssi-sdk/credential"
+	"github.com/TBD54566975/ssi-sdk/oidc/issuance"
+	sdkutil "github.com/TBD54566975/ssi-sdk/util"
+	"github.com/gin-gonic/gin"
+	"github.com/goccy/go-json"
+	"github.com/google/uuid"
+	"github.com/lestrrat-go/jwx/jwt"
+	"github.com/ory/fosite"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	didint "github.com/tbd54566975/ssi-service/internal/did"
+	"github.com/tbd54566975/ssi-service/internal/keyaccess"
+	"github.com/tbd54566975/ssi-service/pkg/authorizationserver/request"
+	"github.com/tbd54566975/ssi-service/pkg/service/credential"
+	issuancesvc "github.com/tbd54566975/ssi-service/pkg/service/issuance"
+	"github.com/tbd54566975/ssi-service/pkg/service/manifest/model"
+)
+
+// Values of the credential endpoint defined in https://openid.net/specs/openid-4-verifiable-credential-issuance-1_0.html#name-credential-endpoint
+const (
+	proofJWTType = "openid4vci-proof+jwt"
+	nonceClaim   = "nonce"
+
+	invalidRequestError              = "invalid_request"
+	invalidTokenError                = "invalid_token"
+	insufficientScopeError           = "insufficient_scope"
+	invalidProofError                = "invalid_proof"
+	unsupportedCredentialFormatError = "unsupported_credential_format"
+	unsupportedCredentialTypeError   = "unsupported_credential_type"
+	serverError                      = "server_error"
+)
+
+// CredentialResponse is the body of a successful response from the credential endpoint, as defined in
+// https://openid.net/specs/openid-4-verifiable-credential-issuance-1_0.html#name-credential-response
+type CredentialResponse struct {
+	Format          issuance.Format `json:"format"`
+	Credential      any             `json:"credential"`
+	CNonce          string          `json:"c_nonce,omitempty"`
+	CNonceExpiresIn int64           `json:"c_nonce_expires_in,omitempty"`
+}
+
+// CredentialErrorResponse is the body of an error response from the credential endpoint. A fresh c_nonce is included
+// when the proof was rejected, so that the client can try again.
+type CredentialErrorResponse struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description,omitempty"`
+	CNonce           string `json:"c_nonce,omitempty"`
+	CNonceExpiresIn  int64  `json:"c_nonce_expires_in,omitempty"`
+}
+
+// CredentialEndpoint is a Handler that implements https://openid.net/specs/openid-4-verifiable-credential-issuance-1_0.html#name-credential-endpoint
+// The access token must have been granted the requested credential, and the proof must be signed by the key in its
+// `kid` over the c_nonce of the access token's session. The credential is issued to the DID of that key with the
+// issuance template whose credential types match the requested ones.
+func (s AuthService) CredentialEndpoint(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	accessToken := fosite.AccessTokenFromRequest(c.Request)
+	if accessToken == "" {
+		writeCredentialError(c, http.StatusUnauthorized, invalidTokenError, "the request has no access token", nil)
+		return
+	}
+	_, accessRequest, err := s.provider.IntrospectToken(ctx, accessToken, fosite.AccessToken, newSession(""))
+	if err != nil {
+		logrus.WithError(err).Error("failed IntrospectToken")
+		writeCredentialError(c, http.StatusUnauthorized, invalidTokenError, "the access token is invalid", nil)
+		return
+	}
+	session, ok := accessRequest.GetSession().(*Session)
+	if !ok {
+		writeCredentialError(c, http.StatusInternalServerError, serverError, "the session is not a credential issuance session", nil)
+		return
+	}
+
+	var credentialRequest request.CredentialRequest
+	if err = json.NewDecoder(c.Request.Body).Decode(&credentialRequest); err != nil {
+		writeCredentialError(c, http.StatusBadRequest, invalidRequestError, "could not decode credential request", nil)
+		return
+	}
+	if err = credentialRequest.IsValid(); err != nil {
+		writeCredentialError(c, http.StatusBadRequest, invalidRequestError, err.Error(), nil)
+		return
+	}
+	if credentialRequest.Format != issuance.JWTVCJSON {
+		writeCredentialError(c, http.StatusBadRequest, unsupportedCredentialFormatError, "only jwt_vc_json credentials can be issued", nil)
+		return
+	}
+	if !session.grantsCredential(credentialRequest.Format, credentialRequest.Types) {
+		writeCredentialError(c, http.StatusForbidden, insufficientScopeError, "the access token was not granted the requested credential", nil)
+		return
+	}
+
+	// a c_nonce can only be used in a single proof, so a fresh one is issued whatever the outcome
+	holderDID, err := s.verifyProof(ctx, session, *credentialRequest.Proof)
+	if rotateErr := s.rotateCNonce(ctx, accessRequest.GetID(), session); rotateErr != nil {
+		logrus.WithError(rotateErr).Error("failed rotating c_nonce")
+		if errors.Is(rotateErr, errCNonceAlreadyUsed) {
+			writeCredentialError(c, http.StatusBadRequest, invalidProofError, rotateErr.Error(), nil)
+			return
+		}
+		writeCredentialError(c, http.StatusInternalServerError, serverError, "could not issue a fresh c_nonce", nil)
+		return
+	}
+	if err != nil {
+		logrus.WithError(err).Error("invalid proof")
+		writeCredentialError(c, http.StatusBadRequest, invalidProofError, err.Error(), session)
+		return
+	}
+
+	// credentials offered with the pre-authorized code grant are issued with the claims approved in the offer
+	var offered *OfferedCredential
+	if session.CredentialOfferID != "" {
+		offer, err := s.offers.GetOffer(ctx, session.CredentialOfferID)
+		if err != nil {
+			logrus.WithError(err).Error("failed GetOffer")
+			writeCredentialError(c, http.StatusInternalServerError, serverError, "could not get the redeemed credential offer", nil)
+			return
+		}
+		offered, _ = offer.findOfferedCredential(credentialRequest.Format, credentialRequest.Types)
+	}
+
+	createRequest, err := s.credentialRequestFromTemplate(ctx, holderDID, credentialRequest.Types, offered)
+	if err != nil {
+		logrus.WithError(err).Error("no issuance template for the requested credential")
+		writeCredentialError(c, http.StatusBadRequest, unsupportedCredentialTypeError, err.Error(), nil)
+		return
+	}
+	createdCredential, err := s.ssi.Credential.CreateCredential(ctx, *createRequest)
+	if err != nil {
+		logrus.WithError(err).Error("failed CreateCredential")
+		writeCredentialError(c, http.StatusInternalServerError, serverError, "could not issue the credential", nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, CredentialResponse{
+		Format:          credentialRequest.Format,
+		Credential:      createdCredential.CredentialJWT.String(),
+		CNonce:          session.CNonce,
+		CNonceExpiresIn: int64(s.cNonceLifespan.Seconds()),
+	})
+}
+
+// verifyProof checks that the proof JWT is signed by the key in its `kid` header, and is bound to the session's
+// c_nonce and to this credential issuer. It returns the DID of the key.
+func (s AuthService) verifyProof(ctx context.Context, session *Session, proof request.Proof) (string, error) {
+	headers, err := keyaccess.GetJWTHeaders([]byte(proof.JWT))
+	if err != nil {
+		return "", errors.Wrap(err, "parsing proof headers")
+	}
+	if headers.Type() != proofJWTType {
+		return "", errors.Errorf("proof typ must be %q", proofJWTType)
+	}
+	// the kid is a DID URL whose fragment identifies the key in the DID document
+	kid := headers.KeyID()
+	holderDID, fragment, found := strings.Cut(kid, "#")
+	if !found || fragment == "" || !strings.HasPrefix(holderDID, "did:") {
+		return "", errors.Errorf("proof kid<%s> is not a DID URL", kid)
+	}
+	if err = didint.VerifyTokenFromDID(ctx, s.ssi.DID.GetResolver(), holderDID, fragment, keyaccess.JWT(proof.JWT)); err != nil {
+		return "", errors.Wrapf(err, "verifying proof signature with kid<%s>", kid)
+	}
+
+	token, err := jwt.Parse([]byte(proof.JWT))
+	if err != nil {
+		return "", errors.Wrap(err, "parsing proof")
+	}
+	nonce, _ := token.Get(nonceClaim)
+	if session.CNonce == "" || nonce != session.CNonce {
+		return "", errors.New("proof nonce does not match the c_nonce")
+	}
+	if time.Now().After(session.CNonceExpiresAt) {
+		return "", errors.New("the c_nonce has expired")
+	}
+	credentialIssuer := s.issuerMetadata.CredentialIssuer.String()
+	if !sdkutil.Contains(credentialIssuer, token.Audience()) {
+		return "", errors.Errorf("proof aud must be the credential issuer %q", credentialIssuer)
+	}
+	if token.IssuedAt().IsZero() {
+		return "", errors.New("proof iat is required")
+	}
+	return holderDID, nil
+}
+
+// credentialRequestFromTemplate builds the request to create a credential for the holder from the first issuance
+// template that has a credential with the requested types. Only templates that don't need a credential application
+// can be used. When the credential was offered, the offer's template is used, and the offer's data is applied on top of
+// the template's.
+func (s AuthService) credentialRequestFromTemplate(ctx context.Context, holderDID string, types []string, offered *OfferedCredential) (*credential.CreateCredentialRequest, error) {
+	templates, err := s.ssi.Issuance.ListIssuanceTemplates(ctx, &issuancesvc.ListIssuanceTemplatesRequest{})
+	if err != nil {
+		return nil, errors.Wrap(err, "listing issuance templates")
+	}
+	for _, template := range templates.IssuanceTemplates {
+		if offered != nil && offered.IssuanceTemplateID != "" && offered.IssuanceTemplateID != template.ID {
+			continue
+		}
+		for _, ct := range template.Credentials {
+			if ct.CredentialInputDescriptor != "" {
+				continue
+			}
+			if !sameTypes(sdkutil.MergeUniqueValues([]string{credsdk.VerifiableCredentialType}, ct.Types), types) {
+				continue
+			}
+
+			schemaID := ct.Schema
+			if schemaID == "" {
+				schemaID, err = s.outputDescriptorSchema(ctx, template.CredentialManifest, ct.ID)
+				if err != nil {
+					return nil, err
+				}
+			}
+			createRequest := credential.CreateCredentialRequest{
+				Issuer:    template.Issuer,
+				IssuerKID: template.IssuerKID,
+				Subject:   holderDID,
+				SchemaID:  schemaID,
+				Data:      make(map[string]any, len(ct.Data)),
+				Revocable: ct.Revocable,
+				Types:     ct.Types,
+			}
+			// without an application, the template's data is used as is
+			for k, v := range ct.Data {
+				createRequest.Data[k] = v
+			}
+			if offered != nil {
+				for k, v := range offered.Data {
+					createRequest.Data[k] = v
+				}
+			}
+			if ct.Expiry.Time != nil {
+				createRequest.Expiry = ct.Expiry.Time.Format(time.RFC3339)
+			}
+			if ct.Expiry.Duration != nil {
+				createRequest.Expiry = time.Now().Add(*ct.Expiry.Duration).Format(time.RFC3339)
+			}
+			return &createRequest, nil
+		}
+	}
+	return nil, errors.Errorf("no issuance template issues credentials of types %v", types)
+}
+
+// outputDescriptorSchema returns the schema of the output descriptor with the given ID in a manifest.
+func (s AuthService) outputDescriptorSchema(ctx context.Context, manifestID, outputDescriptorID string) (string, error) {
+	gotManifest, err := s.ssi.Manifest.GetManifest(ctx, model.GetManifestRequest{ID: manifestID})
+	if err != nil {
+		return "", errors.Wrapf(err, "getting manifest<%s>", manifestID)
+	}
+	for _, od := range gotManifest.Manifest.OutputDescriptors {
+		if od.ID == outputDescriptorID {
+			return od.Schema, nil
+		}
+	}
+	return "", errors.Errorf("manifest<%s> has no output descriptor<%s>", manifestID, outputDescriptorID)
+}
+
+// rotateCNonce replaces the c_nonce of the access token's session with a fresh one, both in storage and in session.
+// It fails with errCNonceAlreadyUsed when the stored c_nonce was rotated by a concurrent request.
+func (s AuthService) rotateCNonce(ctx context.Context, requestID string, session *Session) error {
+	usedCNonce := session.CNonce
+	freshCNonce := uuid.NewString()
+	expiresAt := time.Now().Add(s.cNonceLifespan)
+	err := s.store.UpdateAccessTokenSession(ctx, requestID, func(stored *Session) error {
+		if stored.CNonce != usedCNonce {
+			return errCNonceAlreadyUsed
+		}
+		stored.CNonce = freshCNonce
+		stored.CNonceExpiresAt = expiresAt
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	session.CNonce = freshCNonce
+	session.CNonceExpiresAt = expiresAt
+	return nil
+}
+
+func writeCredentialError(c *gin.Context, status int, errorCode, description string, session *Session) {
+	resp := CredentialErrorResponse{Error: errorCode, ErrorDescription: description}
+	if session != nil {
+		resp.CNonce = session.CNonce
+		resp.CNonceExpiresIn = int64(time.Until(session.CNonceExpiresAt).Seconds())
+	}
+	c.JSON(status, resp)
+}
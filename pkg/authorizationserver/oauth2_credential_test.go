@@ -0,0 +1,227 @@
+package authorizationserver
+
+import (
+	"bytes"
+	"context"
+	gocrypto "crypto"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	credsdk "github.com/TBD54566975/ssi-sdk/credential"
+	"github.com/TBD54566975/ssi-sdk/credential/exchange"
+	manifestsdk "github.com/TBD54566975/ssi-sdk/credential/manifest"
+	"github.com/TBD54566975/ssi-sdk/crypto"
+	"github.com/TBD54566975/ssi-sdk/did/key"
+	"github.com/goccy/go-json"
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/lestrrat-go/jwx/v2/jws"
+	"github.com/lestrrat-go/jwx/v2/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tbd54566975/ssi-service/pkg/authorizationserver/request"
+	"github.com/tbd54566975/ssi-service/pkg/service/did"
+	"github.com/tbd54566975/ssi-service/pkg/service/issuance"
+	"github.com/tbd54566975/ssi-service/pkg/service/manifest/model"
+	"github.com/tbd54566975/ssi-service/pkg/service/schema"
+)
+
+func TestCredentialEndpoint(t *testing.T) {
+	issuerDID, schemaID := createUniversityDegreeTemplate(t)
+
+	holderKey, holderDID, err := key.GenerateDIDKey(crypto.Ed25519)
+	require.NoError(t, err)
+	expanded, err := holderDID.Expand()
+	require.NoError(t, err)
+	holderKID := holderDID.String() + expanded.VerificationMethod[0].ID
+
+	clientID, tokenForm := authorize(t, universityDegreeAuthorizationDetails)
+	tokenResp := postToken(t, clientID, tokenForm)
+	require.Equal(t, http.StatusOK, tokenResp.StatusCode)
+	var token tokenResponse
+	require.NoError(t, json.NewDecoder(tokenResp.Body).Decode(&token))
+
+	universityDegree := []string{"VerifiableCredential", "UniversityDegreeCredential"}
+
+	t.Run("missing access token returns invalid_token", func(t *testing.T) {
+		resp := postCredential(t, "", request.CredentialRequest{})
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		assert.Equal(t, invalidTokenError, decodeCredentialError(t, resp).Error)
+	})
+
+	t.Run("unknown access token returns invalid_token", func(t *testing.T) {
+		resp := postCredential(t, "not-a-token", request.CredentialRequest{})
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		assert.Equal(t, invalidTokenError, decodeCredentialError(t, resp).Error)
+	})
+
+	t.Run("types that were not granted return insufficient_scope", func(t *testing.T) {
+		proof := createProof(t, holderKey, holderKID, token.CNonce)
+		resp := postCredential(t, token.AccessToken, credentialRequest([]string{"VerifiableCredential", "DriversLicenseCredential"}, proof))
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		assert.Equal(t, insufficientScopeError, decodeCredentialError(t, resp).Error)
+	})
+
+	t.Run("proof with the wrong nonce returns invalid_proof and a fresh c_nonce", func(t *testing.T) {
+		proof := createProof(t, holderKey, holderKID, "wrong-nonce")
+		resp := postCredential(t, token.AccessToken, credentialRequest(universityDegree, proof))
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		credentialErr := decodeCredentialError(t, resp)
+		assert.Equal(t, invalidProofError, credentialErr.Error)
+		assert.Contains(t, credentialErr.ErrorDescription, "proof nonce does not match the c_nonce")
+		assert.NotEmpty(t, credentialErr.CNonce)
+		assert.NotEqual(t, token.CNonce, credentialErr.CNonce)
+		token.CNonce = credentialErr.CNonce
+	})
+
+	t.Run("proof signed by another key returns invalid_proof", func(t *testing.T) {
+		otherKey, _, err := key.GenerateDIDKey(crypto.Ed25519)
+		require.NoError(t, err)
+		proof := createProof(t, otherKey, holderKID, token.CNonce)
+		resp := postCredential(t, token.AccessToken, credentialRequest(universityDegree, proof))
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		credentialErr := decodeCredentialError(t, resp)
+		assert.Equal(t, invalidProofError, credentialErr.Error)
+		token.CNonce = credentialErr.CNonce
+	})
+
+	var usedProof string
+	t.Run("credential is issued to the DID of the proof", func(t *testing.T) {
+		usedProof = createProof(t, holderKey, holderKID, token.CNonce)
+		resp := postCredential(t, token.AccessToken, credentialRequest(universityDegree, usedProof))
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var credentialResp CredentialResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&credentialResp))
+		assert.Equal(t, "jwt_vc_json", string(credentialResp.Format))
+		assert.NotEmpty(t, credentialResp.CNonce)
+		assert.NotEqual(t, token.CNonce, credentialResp.CNonce)
+
+		credentialJWT, ok := credentialResp.Credential.(string)
+		require.True(t, ok)
+		_, _, cred, err := credsdk.ParseVerifiableCredentialFromJWT(credentialJWT)
+		require.NoError(t, err)
+		assert.Equal(t, issuerDID, cred.Issuer)
+		assert.ElementsMatch(t, universityDegree, cred.Type)
+		assert.Equal(t, holderDID.String(), cred.CredentialSubject.GetID())
+		assert.Equal(t, "Bachelor of Science", cred.CredentialSubject["degree"])
+		require.NotNil(t, cred.CredentialSchema)
+		assert.Equal(t, schemaID, cred.CredentialSchema.ID)
+	})
+
+	t.Run("proof can't be replayed", func(t *testing.T) {
+		resp := postCredential(t, token.AccessToken, credentialRequest(universityDegree, usedProof))
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Equal(t, invalidProofError, decodeCredentialError(t, resp).Error)
+	})
+}
+
+// universityDegreeTemplate is created once, since the credential endpoint issues with the first template of the
+// requested types.
+var universityDegreeTemplate struct {
+	once      sync.Once
+	issuerDID string
+	schemaID  string
+}
+
+// createUniversityDegreeTemplate creates an issuance template for UniversityDegreeCredential the first time it's
+// called, and returns the DID of its issuer and the schema of the credential's output descriptor.
+func createUniversityDegreeTemplate(t *testing.T) (string, string) {
+	universityDegreeTemplate.once.Do(func() {
+		universityDegreeTemplate.issuerDID, universityDegreeTemplate.schemaID = newUniversityDegreeTemplate(t)
+	})
+	require.NotEmpty(t, universityDegreeTemplate.issuerDID, "the university degree template could not be created")
+	return universityDegreeTemplate.issuerDID, universityDegreeTemplate.schemaID
+}
+
+func newUniversityDegreeTemplate(t *testing.T) (string, string) {
+	ctx := context.Background()
+	issuerResp, err := ssi.DID.CreateDIDByMethod(ctx, did.CreateDIDRequest{Method: "key", KeyType: crypto.Ed25519})
+	require.NoError(t, err)
+	issuerKID := issuerResp.DID.VerificationMethod[0].ID
+
+	degreeSchema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"degree": map[string]any{
+				"type": "string",
+			},
+		},
+		"required":             []any{"degree"},
+		"additionalProperties": true,
+	}
+	createdSchema, err := ssi.Schema.CreateSchema(ctx, schema.CreateSchemaRequest{Author: issuerResp.DID.ID, Name: "university degree", Schema: degreeSchema})
+	require.NoError(t, err)
+
+	createdManifest, err := ssi.Manifest.CreateManifest(ctx, model.CreateManifestRequest{
+		IssuerDID: issuerResp.DID.ID,
+		IssuerKID: issuerKID,
+		ClaimFormat: &exchange.ClaimFormat{
+			JWT: &exchange.JWTType{Alg: []crypto.SignatureAlgorithm{crypto.EdDSA}},
+		},
+		OutputDescriptors: []manifestsdk.OutputDescriptor{{ID: "university_degree", Schema: createdSchema.ID}},
+	})
+	require.NoError(t, err)
+
+	_, err = ssi.Issuance.CreateIssuanceTemplate(ctx, &issuance.CreateIssuanceTemplateRequest{
+		IssuanceTemplate: issuance.Template{
+			CredentialManifest: createdManifest.Manifest.ID,
+			Issuer:             issuerResp.DID.ID,
+			IssuerKID:          issuerKID,
+			Credentials: []issuance.CredentialTemplate{
+				{
+					ID:    "university_degree",
+					Types: []string{"UniversityDegreeCredential"},
+					Data:  issuance.ClaimTemplates{"degree": "Bachelor of Science"},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	return issuerResp.DID.ID, createdSchema.ID
+}
+
+func createProof(t *testing.T, privateKey gocrypto.PrivateKey, kid, nonce string) string {
+	token := jwt.New()
+	require.NoError(t, token.Set(nonceClaim, nonce))
+	require.NoError(t, token.Set(jwt.AudienceKey, "https://credential-issuer.example.com"))
+	require.NoError(t, token.Set(jwt.IssuedAtKey, time.Now()))
+
+	headers := jws.NewHeaders()
+	require.NoError(t, headers.Set(jws.TypeKey, proofJWTType))
+	require.NoError(t, headers.Set(jws.KeyIDKey, kid))
+	signed, err := jwt.Sign(token, jwt.WithKey(jwa.EdDSA, privateKey, jws.WithProtectedHeaders(headers)))
+	require.NoError(t, err)
+	return string(signed)
+}
+
+func credentialRequest(types []string, proof string) request.CredentialRequest {
+	return request.CredentialRequest{
+		Format: "jwt_vc_json",
+		Types:  types,
+		Proof:  &request.Proof{ProofType: request.JWTProofType, JWT: proof},
+	}
+}
+
+func postCredential(t *testing.T, accessToken string, credentialRequest request.CredentialRequest) *http.Response {
+	body, err := json.Marshal(credentialRequest)
+	require.NoError(t, err)
+	req, err := http.NewRequest(http.MethodPost, server.URL+credentialPath, bytes.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	if accessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = resp.Body.Close() })
+	return resp
+}
+
+func decodeCredentialError(t *testing.T, resp *http.Response) CredentialErrorResponse {
+	var credentialErr CredentialErrorResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&credentialErr))
+	return credentialErr
+}
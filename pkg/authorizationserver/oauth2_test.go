@@ -1,6 +1,7 @@
 package authorizationserver
 
 import (
+	"context"
 	_ "embed"
 	"io"
 	"net/http"
@@ -9,26 +10,58 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/ardanlabs/conf"
+	"github.com/goccy/go-json"
 	"github.com/ory/fosite"
-	"github.com/ory/fosite/storage"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/tbd54566975/ssi-service/config"
+	"github.com/tbd54566975/ssi-service/pkg/service"
+	ssistorage "github.com/tbd54566975/ssi-service/pkg/storage"
+	"github.com/tbd54566975/ssi-service/pkg/testutil"
 )
 
+const testAdminToken = "test-admin-token"
+
 var (
 	server *httptest.Server
-	store  *storage.MemoryStore
+	store  *Storage
+	ssi    *service.SSIService
 )
 
 func TestMain(m *testing.M) {
-	store = storage.NewMemoryStore()
+	testutil.EnableSchemaCaching()
+
+	dbFile, err := os.CreateTemp("", "authserver-bolt")
+	if err != nil {
+		logrus.WithError(err).Fatal("cannot create db file")
+	}
+	ssiConfig, err := config.LoadConfig("")
+	if err != nil {
+		logrus.WithError(err).Fatal("cannot load services config")
+	}
+	ssiConfig.Services.StorageOptions = []ssistorage.Option{{ID: ssistorage.BoltDBFilePathOption, Option: dbFile.Name()}}
+	ssi, err = service.InstantiateSSIService(ssiConfig.Services)
+	if err != nil {
+		logrus.WithError(err).Fatal("cannot instantiate services")
+	}
+	store, err = NewStorage(ssi.GetStorage())
+	if err != nil {
+		logrus.WithError(err).Fatal("cannot create authserver storage")
+	}
 
 	// Create an httptest server with the metadataHandler
 	authServer, err := NewServer(make(chan os.Signal, 1), &AuthConfig{
-		CredentialIssuerFile: "../../config/credential_issuer_metadata.example.json",
-	}, store)
+		CredentialIssuerFile:           "../../config/credential_issuer_metadata.example.json",
+		AllowDynamicClientRegistration: true,
+		AllowPublicClients:             true,
+		RequirePKCEForPublicClients:    true,
+		ClientAdminToken:               testAdminToken,
+	}, store, ssi)
 	if err != nil {
 		logrus.WithError(err).Fatal("cannot create authserver")
 		os.Exit(1)
@@ -38,6 +71,9 @@ func TestMain(m *testing.M) {
 	code := m.Run()
 
 	server.Close()
+	_ = ssi.GetStorage().Close()
+	_ = dbFile.Close()
+	_ = os.Remove(dbFile.Name())
 	os.Exit(code)
 }
 
@@ -61,7 +97,19 @@ func fetchMetadata(url string) ([]byte, error) {
 //go:embed expected_issuer_metadata.json
 var expectedIssuerMetadata []byte
 
+func TestAuthConfigDefaults(t *testing.T) {
+	var cfg AuthConfig
+	require.NoError(t, conf.Parse(nil, "AUTHSERVER", &cfg))
+
+	// unauthenticated clients are opted into
+	assert.False(t, cfg.AllowDynamicClientRegistration)
+	assert.False(t, cfg.AllowPublicClients)
+	assert.True(t, cfg.RequirePKCEForPublicClients)
+}
+
 func TestCredentialIssuerMetadata(t *testing.T) {
+	createUniversityDegreeTemplate(t)
+
 	// Fetch the metadata from the test server
 	metadata, err := fetchMetadata(server.URL + "/oidc/issuer/.well-known/openid-credential-issuer")
 	require.NoError(t, err)
@@ -71,11 +119,12 @@ func TestCredentialIssuerMetadata(t *testing.T) {
 }
 
 func TestAuthorizationEndpoint(t *testing.T) {
+	createUniversityDegreeTemplate(t)
 	callbackCalled := false
 	h := new(handler)
 	clientServer := httptest.NewServer(http.HandlerFunc(h.callbackHandler(t, &callbackCalled)))
 
-	clientID := createClient(clientServer)
+	clientID := createClient(t, clientServer)
 
 	testCases := []struct {
 		name                 string
@@ -132,6 +181,21 @@ func TestAuthorizationEndpoint(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "credential types the issuer does not support returns error",
+			authorizationDetails: `[
+			  {
+				 "type":"openid_credential",
+				 "format":"jwt_vc_json",
+				 "locations":["https://credential-issuer.example.com"],
+				 "types":[
+				   "VerifiableCredential",
+				   "DriversLicenseCredential"
+				 ]
+			  }
+			]`,
+			wantError: "authorization_details[0] requests a credential that is not in the issuer's credentials_supported",
+		},
 		{
 			name: "unknown type returns error",
 			authorizationDetails: `[
@@ -164,6 +228,106 @@ func TestAuthorizationEndpoint(t *testing.T) {
 	}
 }
 
+const universityDegreeAuthorizationDetails = `[
+  {
+	 "type":"openid_credential",
+	 "format":"jwt_vc_json",
+	 "locations":["https://credential-issuer.example.com"],
+	 "types":[
+	   "VerifiableCredential",
+	   "UniversityDegreeCredential"
+	 ]
+  }
+]`
+
+type tokenResponse struct {
+	AccessToken          string           `json:"access_token"`
+	TokenType            string           `json:"token_type"`
+	IDToken              string           `json:"id_token"`
+	CNonce               string           `json:"c_nonce"`
+	CNonceExpiresIn      int64            `json:"c_nonce_expires_in"`
+	AuthorizationDetails []map[string]any `json:"authorization_details"`
+}
+
+func TestTokenEndpoint(t *testing.T) {
+	createUniversityDegreeTemplate(t)
+	clientID, tokenForm := authorize(t, universityDegreeAuthorizationDetails)
+
+	tokenResp := postToken(t, clientID, tokenForm)
+	require.Equal(t, http.StatusOK, tokenResp.StatusCode)
+
+	var token tokenResponse
+	require.NoError(t, json.NewDecoder(tokenResp.Body).Decode(&token))
+	assert.NotEmpty(t, token.AccessToken)
+	assert.Equal(t, "bearer", token.TokenType)
+	assert.NotEmpty(t, token.CNonce)
+	assert.Equal(t, int64(defaultCNonceLifespan.Seconds()), token.CNonceExpiresIn)
+	require.Len(t, token.AuthorizationDetails, 1)
+	assert.Equal(t, "openid_credential", token.AuthorizationDetails[0]["type"])
+	assert.Equal(t, []any{"VerifiableCredential", "UniversityDegreeCredential"}, token.AuthorizationDetails[0]["types"])
+
+	// the granted credentials and the c_nonce are in the session of the access token
+	session := accessTokenSession(t, token.AccessToken)
+	assert.Equal(t, token.CNonce, session.CNonce)
+	require.Len(t, session.AuthorizationDetails, 1)
+	assert.Equal(t, []string{"VerifiableCredential", "UniversityDegreeCredential"}, session.AuthorizationDetails[0].Types)
+	assert.True(t, session.CNonceExpiresAt.After(time.Now()))
+
+	// the code can only be exchanged once
+	replayResp := postToken(t, clientID, tokenForm)
+	assert.Equal(t, http.StatusBadRequest, replayResp.StatusCode)
+	body, err := io.ReadAll(replayResp.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), "invalid_grant")
+}
+
+// authorize goes through the authorization endpoint, and returns the client and the form to exchange the code at the
+// token endpoint.
+func authorize(t *testing.T, authorizationDetails string) (string, url.Values) {
+	var code string
+	clientServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		code = r.URL.Query().Get("code")
+	}))
+	t.Cleanup(clientServer.Close)
+	clientID := createClient(t, clientServer)
+
+	u, err := url.Parse(server.URL + "/oauth2/auth")
+	require.NoError(t, err)
+	u.RawQuery = createQuery(u, clientID, clientServer.URL, authorizationDetails).Encode()
+	resp, err := http.Post(u.String(), "application/x-www-form-urlencoded", strings.NewReader(createForm().Encode()))
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.NotEmpty(t, code)
+
+	tokenForm := url.Values{}
+	tokenForm.Set("grant_type", "authorization_code")
+	tokenForm.Set("code", code)
+	tokenForm.Set("redirect_uri", clientServer.URL)
+	return clientID, tokenForm
+}
+
+// accessTokenSession returns the session stored with an access token. The signature of the HMAC access token, under
+// which it's stored, is the part after the dot.
+func accessTokenSession(t *testing.T, accessToken string) *Session {
+	signature := accessToken[strings.LastIndex(accessToken, ".")+1:]
+	requester, err := store.GetAccessTokenSession(context.Background(), signature, nil)
+	require.NoError(t, err)
+	session, ok := requester.GetSession().(*Session)
+	require.True(t, ok)
+	return session
+}
+
+func postToken(t *testing.T, clientID string, form url.Values) *http.Response {
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/oauth2/token", strings.NewReader(form.Encode()))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(clientID, "foobar")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = resp.Body.Close() })
+	return resp
+}
+
 func createForm() url.Values {
 	form := url.Values{}
 	form.Set("username", "peter")
@@ -184,17 +348,23 @@ func createQuery(u *url.URL, clientID string, clientServerURL string, authorizat
 	return q
 }
 
-func createClient(clientServer *httptest.Server) string {
+func createClient(t *testing.T, clientServer *httptest.Server) string {
 	clientID := "my-test-client"
-	store.Clients[clientID] = &fosite.DefaultClient{
-		ID:             clientID,
-		Secret:         []byte(`$2a$10$IxMdI6d.LIRZPpSfEwNoeu4rY3FhDREsxFJXikcgdRRAStxUlsuEO`),            // = "foobar"
-		RotatedSecrets: [][]byte{[]byte(`$2y$10$X51gLxUQJ.hGw1epgHTE5u0bt64xM0COU7K9iAp.OFg8p2pUd.1zC `)}, // = "foobaz",
-		RedirectURIs:   []string{clientServer.URL},
-		ResponseTypes:  []string{"id_token", "code", "token", "id_token token", "code id_token", "code token", "code id_token token"},
-		GrantTypes:     []string{"implicit", "refresh_token", "authorization_code", "password", "client_credentials"},
-		Scopes:         []string{"fosite", "openid", "photos", "offline"},
-	}
+	err := store.StoreClient(context.Background(), &Client{
+		DefaultOpenIDConnectClient: fosite.DefaultOpenIDConnectClient{
+			DefaultClient: &fosite.DefaultClient{
+				ID:             clientID,
+				Secret:         []byte(`$2a$10$IxMdI6d.LIRZPpSfEwNoeu4rY3FhDREsxFJXikcgdRRAStxUlsuEO`),            // = "foobar"
+				RotatedSecrets: [][]byte{[]byte(`$2y$10$X51gLxUQJ.hGw1epgHTE5u0bt64xM0COU7K9iAp.OFg8p2pUd.1zC `)}, // = "foobaz",
+				RedirectURIs:   []string{clientServer.URL},
+				ResponseTypes:  []string{"id_token", "code", "token", "id_token token", "code id_token", "code token", "code id_token token"},
+				GrantTypes:     []string{"implicit", "refresh_token", "authorization_code", "password", "client_credentials"},
+				Scopes:         []string{"fosite", "openid", "photos", "offline"},
+			},
+			TokenEndpointAuthMethod: authMethodClientSecretBasic,
+		},
+	})
+	require.NoError(t, err)
 	return clientID
 }
 
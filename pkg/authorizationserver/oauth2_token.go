@@ -0,0 +1,57 @@
+package authorizationserver
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ory/fosite"
+	"github.com/sirupsen/logrus"
+)
+
+// Parameters of the token response defined in https://openid.net/specs/openid-4-verifiable-credential-issuance-1_0.html#name-successful-token-response
+const (
+	cNonceParam               = "c_nonce"
+	cNonceExpiresInParam      = "c_nonce_expires_in"
+	authorizationDetailsParam = "authorization_details"
+)
+
+// TokenEndpoint is a Handler that implements https://openid.net/specs/openid-4-verifiable-credential-issuance-1_0.html#name-token-endpoint
+// Besides the access token, the response has a fresh c_nonce, and the authorization details granted at the
+// authorization endpoint. Both are kept in the session of the access token.
+func (s AuthService) TokenEndpoint(c *gin.Context) {
+	// The session is replaced with the one stored at the authorization endpoint when exchanging an authorization code.
+	session := newSession("")
+	accessRequest, err := s.provider.NewAccessRequest(c, c.Request, session)
+	if err != nil {
+		logrus.WithError(err).Error("failed NewAccessRequest")
+		s.provider.WriteAccessError(c, c.Writer, accessRequest, err)
+		return
+	}
+
+	credentialSession, ok := accessRequest.GetSession().(*Session)
+	if !ok {
+		err = fosite.ErrServerError.WithHint("The session is not a credential issuance session.")
+		logrus.WithError(err).Error("unexpected session type")
+		s.provider.WriteAccessError(c, c.Writer, accessRequest, err)
+		return
+	}
+	credentialSession.CNonce = uuid.NewString()
+	credentialSession.CNonceExpiresAt = time.Now().Add(s.cNonceLifespan)
+
+	// Next we create a response for the access request. Again, we iterate through the TokenEndpointHandlers
+	// and aggregate the result in response. This is also where the session is stored with the access token.
+	response, err := s.provider.NewAccessResponse(c, accessRequest)
+	if err != nil {
+		logrus.WithError(err).Error("failed NewAccessResponse")
+		s.provider.WriteAccessError(c, c.Writer, accessRequest, err)
+		return
+	}
+	response.SetExtra(cNonceParam, credentialSession.CNonce)
+	response.SetExtra(cNonceExpiresInParam, int64(s.cNonceLifespan.Seconds()))
+	if len(credentialSession.AuthorizationDetails) > 0 {
+		response.SetExtra(authorizationDetailsParam, credentialSession.AuthorizationDetails)
+	}
+
+	s.provider.WriteAccessResponse(c, c.Writer, accessRequest, response)
+}
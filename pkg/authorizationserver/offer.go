@@ -0,0 +1,279 @@
+package authorizationserver
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/TBD54566975/ssi-sdk/oidc/issuance"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/tbd54566975/ssi-service/pkg/authorizationserver/request"
+	"github.com/tbd54566975/ssi-service/pkg/server/framework"
+	issuancesvc "github.com/tbd54566975/ssi-service/pkg/service/issuance"
+)
+
+// Values of the credential offer defined in https://openid.net/specs/openid-4-verifiable-credential-issuance-1_0.html#name-credential-offer
+const (
+	credentialOfferScheme       = "openid-credential-offer://"
+	credentialOfferURIParam     = "credential_offer_uri"
+	credentialOffersPath        = "/credential_offers"
+	preAuthorizedCodeGrantType  = "urn:ietf:params:oauth:grant-type:pre-authorized_code"
+	preAuthorizedCodeParam      = "pre-authorized_code"
+	userPinParam                = "user_pin"
+	userPinDigits               = 6
+	preAuthorizedCodeByteLength = 32
+
+	// defaultPreAuthorizedCodeLifespan is used when no pre-authorized code lifespan is configured.
+	defaultPreAuthorizedCodeLifespan = 24 * time.Hour
+)
+
+// OfferedCredential is a credential offered to a subject, along with the claims pre-approved for it.
+type OfferedCredential struct {
+	// The format in which the Credential is offered.
+	Format issuance.Format `json:"format" validate:"required"`
+
+	// The types of the offered credential, which must be in the issuer's credentials_supported.
+	Types []string `json:"types" validate:"required,min=1"`
+
+	// Optional. ID of the issuance template the credential is issued with. When absent, the first template issuing
+	// credentials of Types is used.
+	IssuanceTemplateID string `json:"issuanceTemplateId,omitempty"`
+
+	// Optional. Claims about the subject that were approved when creating the offer. They take precedence over the
+	// issuance template's data.
+	Data map[string]any `json:"data,omitempty"`
+}
+
+// CreateCredentialOfferRequest creates an offer of credentials for a subject, which is redeemed with the pre-authorized
+// code grant.
+type CreateCredentialOfferRequest struct {
+	// Identifies who the credentials are offered to, e.g. the ID of the user that was onboarded. It's the subject of
+	// the access token the pre-authorized code is exchanged for.
+	Subject string `json:"subject" validate:"required"`
+
+	Credentials []OfferedCredential `json:"credentials" validate:"required,min=1,dive"`
+
+	// When true, a user PIN is generated. The wallet must send it along with the pre-authorized code, so it should be
+	// given to the user through a different channel than the offer.
+	UserPinRequired bool `json:"userPinRequired,omitempty"`
+}
+
+// CredentialOffer is the credential offer object sent to wallets.
+type CredentialOffer struct {
+	CredentialIssuer string                   `json:"credential_issuer"`
+	Credentials      []CredentialOfferFormat  `json:"credentials"`
+	Grants           CredentialOfferGrantsMap `json:"grants"`
+}
+
+// CredentialOfferFormat describes an offered credential by its format and types.
+type CredentialOfferFormat struct {
+	Format issuance.Format `json:"format"`
+	Types  []string        `json:"types,omitempty"`
+}
+
+type CredentialOfferGrantsMap struct {
+	PreAuthorizedCode *PreAuthorizedCodeGrant `json:"urn:ietf:params:oauth:grant-type:pre-authorized_code,omitempty"`
+}
+
+type PreAuthorizedCodeGrant struct {
+	PreAuthorizedCode string `json:"pre-authorized_code"`
+	UserPinRequired   bool   `json:"user_pin_required"`
+}
+
+type CreateCredentialOfferResponse struct {
+	ID string `json:"id"`
+
+	// The offer, to be passed by value.
+	CredentialOffer CredentialOffer `json:"credentialOffer"`
+
+	// Where wallets can fetch the offer from.
+	CredentialOfferURI string `json:"credentialOfferUri"`
+
+	// The offer by reference, to be presented to the user, e.g. as a QR code.
+	CredentialOfferURL string `json:"credentialOfferUrl"`
+
+	// Only present when a user PIN is required. It's not stored, so it can't be retrieved again.
+	UserPin string `json:"userPin,omitempty"`
+
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// CreateCredentialOffer godoc
+//
+//	@Summary		Create credential offer
+//	@Description	Creates an OpenID4VCI credential offer for a subject, redeemable with the pre-authorized code grant.
+//	@Description	Requires the client admin token as a bearer token.
+//	@Tags			CredentialOfferAPI
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		CreateCredentialOfferRequest	true	"request body"
+//	@Success		201		{object}	CreateCredentialOfferResponse
+//	@Failure		400		{string}	string	"Bad request"
+//	@Failure		401		{string}	string	"Unauthorized"
+//	@Failure		500		{string}	string	"Internal server error"
+//	@Router			/oidc/issuer/credential_offers [put]
+func (s AuthService) CreateCredentialOffer(c *gin.Context) {
+	var offerRequest CreateCredentialOfferRequest
+	if err := framework.Decode(c.Request, &offerRequest); err != nil {
+		framework.LoggingRespondErrWithMsg(c, err, "invalid create credential offer request", http.StatusBadRequest)
+		return
+	}
+	if err := framework.ValidateRequest(offerRequest); err != nil {
+		framework.LoggingRespondErrWithMsg(c, err, "invalid create credential offer request", http.StatusBadRequest)
+		return
+	}
+	if err := s.validateOfferedCredentials(c, offerRequest.Credentials); err != nil {
+		framework.LoggingRespondErrWithMsg(c, err, "invalid create credential offer request", http.StatusBadRequest)
+		return
+	}
+
+	resp, err := s.createOffer(c, offerRequest)
+	if err != nil {
+		framework.LoggingRespondErrWithMsg(c, err, "could not create credential offer", http.StatusInternalServerError)
+		return
+	}
+	framework.Respond(c, resp, http.StatusCreated)
+}
+
+// GetCredentialOffer godoc
+//
+//	@Summary		Get credential offer
+//	@Description	Returns the credential offer object that the credential_offer_uri of an offer points to.
+//	@Tags			CredentialOfferAPI
+//	@Produce		json
+//	@Param			id	path		string	true	"ID"
+//	@Success		200	{object}	CredentialOffer
+//	@Failure		404	{string}	string	"Not found"
+//	@Router			/oidc/issuer/credential_offers/{id} [get]
+func (s AuthService) GetCredentialOffer(c *gin.Context) {
+	id := framework.GetParam(c, "id")
+	if id == nil {
+		framework.LoggingRespondErrMsg(c, "cannot get credential offer without ID parameter", http.StatusBadRequest)
+		return
+	}
+	offer, err := s.offers.GetOffer(c, *id)
+	if err != nil {
+		framework.LoggingRespondErrWithMsg(c, err, "could not get credential offer", http.StatusNotFound)
+		return
+	}
+	if offer.Redeemed || time.Now().After(offer.ExpiresAt) {
+		framework.LoggingRespondErrMsg(c, "the credential offer can no longer be redeemed", http.StatusNotFound)
+		return
+	}
+	framework.Respond(c, s.credentialOffer(*offer), http.StatusOK)
+}
+
+// validateOfferedCredentials checks that each offered credential is one the issuer supports, and that its issuance
+// template, when given, exists.
+func (s AuthService) validateOfferedCredentials(ctx context.Context, credentials []OfferedCredential) error {
+	im, err := s.currentIssuerMetadata(ctx)
+	if err != nil {
+		return err
+	}
+	for i, offered := range credentials {
+		format := offered.Format
+		detail := request.AuthorizationDetail{
+			Type:         request.OpenIDCredentialType,
+			Format:       &format,
+			JWTVCDetails: &request.JWTVCDetails{Types: offered.Types},
+		}
+		if _, ok := findCredentialSupported(im, detail); !ok {
+			return errors.Errorf("credentials[%d] is not in the issuer's credentials_supported", i)
+		}
+		if offered.IssuanceTemplateID != "" {
+			if _, err := s.ssi.Issuance.GetIssuanceTemplate(ctx, &issuancesvc.GetIssuanceTemplateRequest{ID: offered.IssuanceTemplateID}); err != nil {
+				return errors.Wrapf(err, "getting issuance template of credentials[%d]", i)
+			}
+		}
+	}
+	return nil
+}
+
+func (s AuthService) createOffer(ctx context.Context, offerRequest CreateCredentialOfferRequest) (*CreateCredentialOfferResponse, error) {
+	preAuthorizedCode, err := newPreAuthorizedCode()
+	if err != nil {
+		return nil, err
+	}
+	offer := StoredCredentialOffer{
+		ID:                uuid.NewString(),
+		Subject:           offerRequest.Subject,
+		Credentials:       offerRequest.Credentials,
+		PreAuthorizedCode: preAuthorizedCode,
+		ExpiresAt:         time.Now().Add(s.preAuthorizedCodeLifespan),
+	}
+	var userPin string
+	if offerRequest.UserPinRequired {
+		if userPin, err = newUserPin(); err != nil {
+			return nil, err
+		}
+		if err = offer.setUserPin(userPin); err != nil {
+			return nil, err
+		}
+	}
+	if err = s.offers.StoreOffer(ctx, offer); err != nil {
+		return nil, errors.Wrap(err, "storing credential offer")
+	}
+
+	offerURI := s.issuerMetadata.CredentialIssuer.String() + credentialOffersPath + "/" + offer.ID
+	params := url.Values{}
+	params.Set(credentialOfferURIParam, offerURI)
+	return &CreateCredentialOfferResponse{
+		ID:                 offer.ID,
+		CredentialOffer:    s.credentialOffer(offer),
+		CredentialOfferURI: offerURI,
+		CredentialOfferURL: credentialOfferScheme + "?" + params.Encode(),
+		UserPin:            userPin,
+		ExpiresAt:          offer.ExpiresAt,
+	}, nil
+}
+
+func (s AuthService) credentialOffer(offer StoredCredentialOffer) CredentialOffer {
+	credentials := make([]CredentialOfferFormat, 0, len(offer.Credentials))
+	for _, offered := range offer.Credentials {
+		credentials = append(credentials, CredentialOfferFormat{Format: offered.Format, Types: offered.Types})
+	}
+	return CredentialOffer{
+		CredentialIssuer: s.issuerMetadata.CredentialIssuer.String(),
+		Credentials:      credentials,
+		Grants: CredentialOfferGrantsMap{
+			PreAuthorizedCode: &PreAuthorizedCodeGrant{
+				PreAuthorizedCode: offer.PreAuthorizedCode,
+				UserPinRequired:   offer.userPinRequired(),
+			},
+		},
+	}
+}
+
+// findOfferedCredential returns the credential of the offer with the given format and types.
+func (o StoredCredentialOffer) findOfferedCredential(format issuance.Format, types []string) (*OfferedCredential, bool) {
+	for i, offered := range o.Credentials {
+		if offered.Format == format && sameTypes(offered.Types, types) {
+			return &o.Credentials[i], true
+		}
+	}
+	return nil, false
+}
+
+func newPreAuthorizedCode() (string, error) {
+	code := make([]byte, preAuthorizedCodeByteLength)
+	if _, err := rand.Read(code); err != nil {
+		return "", errors.Wrap(err, "generating pre-authorized code")
+	}
+	return base64.RawURLEncoding.EncodeToString(code), nil
+}
+
+func newUserPin() (string, error) {
+	n, err := rand.Int(rand.Reader, new(big.Int).Exp(big.NewInt(10), big.NewInt(userPinDigits), nil))
+	if err != nil {
+		return "", errors.Wrap(err, "generating user pin")
+	}
+	return fmt.Sprintf("%0*d", userPinDigits, n.Int64()), nil
+}
@@ -0,0 +1,230 @@
+package authorizationserver
+
+import (
+	"context"
+	"crypto/subtle"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/pkg/errors"
+
+	"github.com/tbd54566975/ssi-service/internal/util"
+	"github.com/tbd54566975/ssi-service/pkg/storage"
+)
+
+const (
+	credentialOfferNamespace   = "credential_offer"
+	preAuthorizedCodeNamespace = "pre_authorized_code"
+)
+
+var (
+	errOfferNotFound        = errors.New("credential offer not found")
+	errOfferExpired         = errors.New("the pre-authorized code has expired")
+	errOfferAlreadyRedeemed = errors.New("the pre-authorized code has already been redeemed")
+	errInvalidUserPin       = errors.New("the user_pin is invalid")
+
+	errTooManyUserPinAttempts = errors.New("too many invalid user_pin attempts, the pre-authorized code can no longer be redeemed")
+)
+
+const (
+	// maxUserPinAttempts is how many times a wrong user PIN can be sent before the pre-authorized code is invalidated.
+	maxUserPinAttempts = 5
+
+	userPinHashLength = 32
+)
+
+// StoredCredentialOffer is a credential offer created for a subject, along with its pre-authorized code.
+type StoredCredentialOffer struct {
+	ID          string              `json:"id"`
+	Subject     string              `json:"subject"`
+	Credentials []OfferedCredential `json:"credentials"`
+
+	PreAuthorizedCode string    `json:"preAuthorizedCode"`
+	ExpiresAt         time.Time `json:"expiresAt"`
+
+	// Argon2id hash of the user PIN, salted with UserPinSalt. Empty when no PIN is required.
+	UserPinHash []byte `json:"userPinHash,omitempty"`
+	UserPinSalt []byte `json:"userPinSalt,omitempty"`
+
+	FailedUserPinAttempts int `json:"failedUserPinAttempts,omitempty"`
+
+	Redeemed bool `json:"redeemed"`
+}
+
+func (o StoredCredentialOffer) userPinRequired() bool {
+	return len(o.UserPinHash) != 0
+}
+
+// setUserPin stores the hash of the user PIN under a new random salt.
+func (o *StoredCredentialOffer) setUserPin(userPin string) error {
+	salt, err := util.GenerateSalt(util.Argon2SaltSize)
+	if err != nil {
+		return errors.Wrap(err, "generating user PIN salt")
+	}
+	hash, err := util.Argon2KeyGen(userPin, salt, userPinHashLength)
+	if err != nil {
+		return errors.Wrap(err, "hashing user PIN")
+	}
+	o.UserPinHash = hash
+	o.UserPinSalt = salt
+	return nil
+}
+
+// userPinMatches is whether the user PIN hashes to the stored hash.
+func (o StoredCredentialOffer) userPinMatches(userPin string) bool {
+	if userPin == "" {
+		return false
+	}
+	hash, err := util.Argon2KeyGen(userPin, o.UserPinSalt, userPinHashLength)
+	if err != nil {
+		return false
+	}
+	return subtle.ConstantTimeCompare(hash, o.UserPinHash) == 1
+}
+
+type offerStorage struct {
+	db storage.ServiceStorage
+}
+
+func newOfferStorage(db storage.ServiceStorage) (*offerStorage, error) {
+	if db == nil {
+		return nil, errors.New("storage cannot be nil")
+	}
+	return &offerStorage{db: db}, nil
+}
+
+func (s *offerStorage) StoreOffer(ctx context.Context, offer StoredCredentialOffer) error {
+	offerBytes, err := json.Marshal(offer)
+	if err != nil {
+		return errors.Wrapf(err, "marshalling credential offer<%s>", offer.ID)
+	}
+	return s.db.WriteMany(ctx,
+		[]string{credentialOfferNamespace, preAuthorizedCodeNamespace},
+		[]string{offer.ID, offer.PreAuthorizedCode},
+		[][]byte{offerBytes, []byte(offer.ID)},
+	)
+}
+
+func (s *offerStorage) GetOffer(ctx context.Context, id string) (*StoredCredentialOffer, error) {
+	offerBytes, err := s.db.Read(ctx, credentialOfferNamespace, id)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading credential offer<%s>", id)
+	}
+	if len(offerBytes) == 0 {
+		return nil, errors.Wrapf(errOfferNotFound, "credential offer<%s>", id)
+	}
+	var offer StoredCredentialOffer
+	if err = json.Unmarshal(offerBytes, &offer); err != nil {
+		return nil, errors.Wrapf(err, "unmarshalling credential offer<%s>", id)
+	}
+	return &offer, nil
+}
+
+// validateResult carries the outcome of a validation out of its transaction, so that failed user PIN attempts are
+// recorded even though the validation fails.
+type validateResult struct {
+	offer *StoredCredentialOffer
+	err   error
+}
+
+// ValidateOffer returns the offer with the pre-authorized code, after checking it can still be redeemed and that the
+// user PIN matches. The pre-authorized code is invalidated after maxUserPinAttempts wrong user PINs.
+func (s *offerStorage) ValidateOffer(ctx context.Context, preAuthorizedCode, userPin string) (*StoredCredentialOffer, error) {
+	idBytes, err := s.db.Read(ctx, preAuthorizedCodeNamespace, preAuthorizedCode)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading pre-authorized code")
+	}
+	if len(idBytes) == 0 {
+		return nil, errOfferNotFound
+	}
+	id := string(idBytes)
+
+	watchKeys := []storage.WatchKey{{Namespace: credentialOfferNamespace, Key: id}}
+	result, err := s.db.Execute(ctx, func(ctx context.Context, tx storage.Tx) (any, error) {
+		offer, err := readOffer(ctx, tx, id)
+		if err != nil {
+			return nil, err
+		}
+		if err = offer.checkRedeemable(); err != nil {
+			return validateResult{err: err}, nil
+		}
+		if offer.FailedUserPinAttempts >= maxUserPinAttempts {
+			return validateResult{err: errTooManyUserPinAttempts}, nil
+		}
+		if !offer.userPinRequired() || offer.userPinMatches(userPin) {
+			return validateResult{offer: offer}, nil
+		}
+
+		offer.FailedUserPinAttempts++
+		if err = writeOffer(ctx, tx, *offer); err != nil {
+			return nil, err
+		}
+		return validateResult{err: errInvalidUserPin}, nil
+	}, watchKeys)
+	if err != nil {
+		return nil, err
+	}
+	validated, ok := result.(validateResult)
+	if !ok {
+		return nil, errors.New("casting to validate result")
+	}
+	return validated.offer, validated.err
+}
+
+// RedeemOffer marks the offer as redeemed in the same transaction as issue, which stores what the offer is redeemed
+// for. The pre-authorized code is therefore only used up once what it's redeemed for is stored, and can only be
+// redeemed once.
+func (s *offerStorage) RedeemOffer(ctx context.Context, id string, issue func(context.Context, storage.Tx) error) error {
+	watchKeys := []storage.WatchKey{{Namespace: credentialOfferNamespace, Key: id}}
+	_, err := s.db.Execute(ctx, func(ctx context.Context, tx storage.Tx) (any, error) {
+		offer, err := readOffer(ctx, tx, id)
+		if err != nil {
+			return nil, err
+		}
+		if err = offer.checkRedeemable(); err != nil {
+			return nil, err
+		}
+		offer.Redeemed = true
+		if err = writeOffer(ctx, tx, *offer); err != nil {
+			return nil, err
+		}
+		return nil, issue(ctx, tx)
+	}, watchKeys)
+	return err
+}
+
+func (o StoredCredentialOffer) checkRedeemable() error {
+	if o.Redeemed {
+		return errOfferAlreadyRedeemed
+	}
+	if time.Now().After(o.ExpiresAt) {
+		return errOfferExpired
+	}
+	return nil
+}
+
+func readOffer(ctx context.Context, tx storage.Tx, id string) (*StoredCredentialOffer, error) {
+	offerBytes, err := tx.Read(ctx, credentialOfferNamespace, id)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading credential offer<%s>", id)
+	}
+	if len(offerBytes) == 0 {
+		return nil, errors.Wrapf(errOfferNotFound, "credential offer<%s>", id)
+	}
+	var offer StoredCredentialOffer
+	if err = json.Unmarshal(offerBytes, &offer); err != nil {
+		return nil, errors.Wrapf(err, "unmarshalling credential offer<%s>", id)
+	}
+	return &offer, nil
+}
+
+func writeOffer(ctx context.Context, tx storage.Tx, offer StoredCredentialOffer) error {
+	offerBytes, err := json.Marshal(offer)
+	if err != nil {
+		return errors.Wrapf(err, "marshalling credential offer<%s>", offer.ID)
+	}
+	if err = tx.Write(ctx, credentialOfferNamespace, offer.ID, offerBytes); err != nil {
+		return errors.Wrapf(err, "writing credential offer<%s>", offer.ID)
+	}
+	return nil
+}
@@ -0,0 +1,272 @@
+package authorizationserver
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	credsdk "github.com/TBD54566975/ssi-sdk/credential"
+	"github.com/TBD54566975/ssi-sdk/crypto"
+	"github.com/TBD54566975/ssi-sdk/did/key"
+	"github.com/goccy/go-json"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tbd54566975/ssi-service/pkg/storage"
+)
+
+func TestCredentialOffer(t *testing.T) {
+	createUniversityDegreeTemplate(t)
+	universityDegree := []string{"VerifiableCredential", "UniversityDegreeCredential"}
+
+	t.Run("offers can only be created with the admin token", func(t *testing.T) {
+		body, err := json.Marshal(CreateCredentialOfferRequest{
+			Subject:     "alice",
+			Credentials: []OfferedCredential{{Format: "jwt_vc_json", Types: universityDegree}},
+		})
+		require.NoError(t, err)
+		for _, authorization := range []string{"", "Bearer wrong-token"} {
+			req, err := http.NewRequest(http.MethodPut, server.URL+oidcPrefix+credentialOffersPath, bytes.NewReader(body))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", authorization)
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			_ = resp.Body.Close()
+			assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		}
+	})
+
+	t.Run("credentials the issuer doesn't support can't be offered", func(t *testing.T) {
+		resp := putCredentialOffer(t, CreateCredentialOfferRequest{
+			Subject:     "alice",
+			Credentials: []OfferedCredential{{Format: "jwt_vc_json", Types: []string{"VerifiableCredential", "DriversLicenseCredential"}}},
+		})
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("offer is served at its credential_offer_uri", func(t *testing.T) {
+		resp := putCredentialOffer(t, CreateCredentialOfferRequest{
+			Subject:     "alice",
+			Credentials: []OfferedCredential{{Format: "jwt_vc_json", Types: universityDegree}},
+		})
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		var offerResp CreateCredentialOfferResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&offerResp))
+		assert.Empty(t, offerResp.UserPin)
+		assert.Equal(t, "https://credential-issuer.example.com/credential_offers/"+offerResp.ID, offerResp.CredentialOfferURI)
+		assert.Equal(t, "openid-credential-offer://?credential_offer_uri="+url.QueryEscape(offerResp.CredentialOfferURI), offerResp.CredentialOfferURL)
+
+		grant := offerResp.CredentialOffer.Grants.PreAuthorizedCode
+		require.NotNil(t, grant)
+		assert.NotEmpty(t, grant.PreAuthorizedCode)
+		assert.False(t, grant.UserPinRequired)
+
+		getResp, err := http.Get(server.URL + oidcPrefix + credentialOffersPath + "/" + offerResp.ID)
+		require.NoError(t, err)
+		defer getResp.Body.Close()
+		require.Equal(t, http.StatusOK, getResp.StatusCode)
+		var offer CredentialOffer
+		require.NoError(t, json.NewDecoder(getResp.Body).Decode(&offer))
+		assert.Equal(t, offerResp.CredentialOffer, offer)
+		assert.Equal(t, "https://credential-issuer.example.com", offer.CredentialIssuer)
+		require.Len(t, offer.Credentials, 1)
+		assert.Equal(t, universityDegree, offer.Credentials[0].Types)
+
+		// once redeemed, the offer is no longer served
+		tokenResp := postPreAuthorizedCode(t, grant.PreAuthorizedCode, "")
+		require.Equal(t, http.StatusOK, tokenResp.StatusCode)
+		getResp, err = http.Get(server.URL + oidcPrefix + credentialOffersPath + "/" + offerResp.ID)
+		require.NoError(t, err)
+		defer getResp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, getResp.StatusCode)
+	})
+
+	t.Run("unknown pre-authorized code returns invalid_grant", func(t *testing.T) {
+		resp := postPreAuthorizedCode(t, "not-a-code", "")
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assertTokenError(t, resp, "invalid_grant")
+	})
+
+	t.Run("user pin is required when the offer has one", func(t *testing.T) {
+		resp := putCredentialOffer(t, CreateCredentialOfferRequest{
+			Subject:         "alice",
+			Credentials:     []OfferedCredential{{Format: "jwt_vc_json", Types: universityDegree}},
+			UserPinRequired: true,
+		})
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		var offerResp CreateCredentialOfferResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&offerResp))
+		assert.Len(t, offerResp.UserPin, userPinDigits)
+		grant := offerResp.CredentialOffer.Grants.PreAuthorizedCode
+		require.NotNil(t, grant)
+		assert.True(t, grant.UserPinRequired)
+
+		wrongPinResp := postPreAuthorizedCode(t, grant.PreAuthorizedCode, "not-the-pin")
+		assert.Equal(t, http.StatusBadRequest, wrongPinResp.StatusCode)
+		assertTokenError(t, wrongPinResp, "invalid_grant")
+
+		tokenResp := postPreAuthorizedCode(t, grant.PreAuthorizedCode, offerResp.UserPin)
+		assert.Equal(t, http.StatusOK, tokenResp.StatusCode)
+	})
+
+	t.Run("pre-authorized code is invalidated after too many wrong user pins", func(t *testing.T) {
+		resp := putCredentialOffer(t, CreateCredentialOfferRequest{
+			Subject:         "alice",
+			Credentials:     []OfferedCredential{{Format: "jwt_vc_json", Types: universityDegree}},
+			UserPinRequired: true,
+		})
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		var offerResp CreateCredentialOfferResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&offerResp))
+		preAuthorizedCode := offerResp.CredentialOffer.Grants.PreAuthorizedCode.PreAuthorizedCode
+
+		for i := 0; i < maxUserPinAttempts; i++ {
+			wrongPinResp := postPreAuthorizedCode(t, preAuthorizedCode, "not-the-pin")
+			require.Equal(t, http.StatusBadRequest, wrongPinResp.StatusCode)
+		}
+		tokenResp := postPreAuthorizedCode(t, preAuthorizedCode, offerResp.UserPin)
+		assert.Equal(t, http.StatusBadRequest, tokenResp.StatusCode)
+		assertTokenError(t, tokenResp, "invalid_grant")
+	})
+
+	t.Run("redeemed offer is issued with the pre-approved claims", func(t *testing.T) {
+		resp := putCredentialOffer(t, CreateCredentialOfferRequest{
+			Subject: "alice",
+			Credentials: []OfferedCredential{{
+				Format: "jwt_vc_json",
+				Types:  universityDegree,
+				Data:   map[string]any{"degree": "Master of Science", "graduationYear": float64(2023)},
+			}},
+		})
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		var offerResp CreateCredentialOfferResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&offerResp))
+		preAuthorizedCode := offerResp.CredentialOffer.Grants.PreAuthorizedCode.PreAuthorizedCode
+
+		tokenResp := postPreAuthorizedCode(t, preAuthorizedCode, "")
+		require.Equal(t, http.StatusOK, tokenResp.StatusCode)
+		var token tokenResponse
+		require.NoError(t, json.NewDecoder(tokenResp.Body).Decode(&token))
+		assert.NotEmpty(t, token.AccessToken)
+		assert.NotEmpty(t, token.CNonce)
+		require.Len(t, token.AuthorizationDetails, 1)
+		assert.Equal(t, []any{"VerifiableCredential", "UniversityDegreeCredential"}, token.AuthorizationDetails[0]["types"])
+
+		// the code can only be redeemed once
+		replayResp := postPreAuthorizedCode(t, preAuthorizedCode, "")
+		assert.Equal(t, http.StatusBadRequest, replayResp.StatusCode)
+		assertTokenError(t, replayResp, "invalid_grant")
+
+		holderKey, holderDID, err := key.GenerateDIDKey(crypto.Ed25519)
+		require.NoError(t, err)
+		expanded, err := holderDID.Expand()
+		require.NoError(t, err)
+		holderKID := holderDID.String() + expanded.VerificationMethod[0].ID
+
+		proof := createProof(t, holderKey, holderKID, token.CNonce)
+		credentialResp := postCredential(t, token.AccessToken, credentialRequest(universityDegree, proof))
+		require.Equal(t, http.StatusOK, credentialResp.StatusCode)
+		var issued CredentialResponse
+		require.NoError(t, json.NewDecoder(credentialResp.Body).Decode(&issued))
+		credentialJWT, ok := issued.Credential.(string)
+		require.True(t, ok)
+		_, _, cred, err := credsdk.ParseVerifiableCredentialFromJWT(credentialJWT)
+		require.NoError(t, err)
+		assert.Equal(t, holderDID.String(), cred.CredentialSubject.GetID())
+		assert.Equal(t, "Master of Science", cred.CredentialSubject["degree"])
+		assert.EqualValues(t, 2023, cred.CredentialSubject["graduationYear"])
+	})
+}
+
+func TestRedeemOffer(t *testing.T) {
+	offers, err := newOfferStorage(setupTestDB(t))
+	require.NoError(t, err)
+	ctx := context.Background()
+	offer := StoredCredentialOffer{
+		ID:                "offer-id",
+		Subject:           "alice",
+		PreAuthorizedCode: "pre-authorized-code",
+		ExpiresAt:         time.Now().Add(time.Minute),
+	}
+	require.NoError(t, offers.StoreOffer(ctx, offer))
+
+	// the offer stays redeemable when what it's redeemed for can't be stored
+	err = offers.RedeemOffer(ctx, offer.ID, func(context.Context, storage.Tx) error {
+		return errors.New("storing access token")
+	})
+	assert.ErrorContains(t, err, "storing access token")
+	validated, err := offers.ValidateOffer(ctx, offer.PreAuthorizedCode, "")
+	require.NoError(t, err)
+	assert.False(t, validated.Redeemed)
+
+	issued := false
+	err = offers.RedeemOffer(ctx, offer.ID, func(context.Context, storage.Tx) error {
+		issued = true
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.True(t, issued)
+
+	// and only once
+	err = offers.RedeemOffer(ctx, offer.ID, func(context.Context, storage.Tx) error {
+		return nil
+	})
+	assert.ErrorIs(t, err, errOfferAlreadyRedeemed)
+	_, err = offers.ValidateOffer(ctx, offer.PreAuthorizedCode, "")
+	assert.ErrorIs(t, err, errOfferAlreadyRedeemed)
+}
+
+func TestOfferUserPin(t *testing.T) {
+	var offer, other StoredCredentialOffer
+	require.NoError(t, offer.setUserPin("123456"))
+	require.NoError(t, other.setUserPin("123456"))
+	assert.True(t, offer.userPinRequired())
+
+	// the same PIN is hashed under a different salt for each offer
+	assert.NotEqual(t, offer.UserPinSalt, other.UserPinSalt)
+	assert.NotEqual(t, offer.UserPinHash, other.UserPinHash)
+
+	assert.True(t, offer.userPinMatches("123456"))
+	assert.False(t, offer.userPinMatches("654321"))
+	assert.False(t, offer.userPinMatches(""))
+}
+
+func putCredentialOffer(t *testing.T, offerRequest CreateCredentialOfferRequest) *http.Response {
+	body, err := json.Marshal(offerRequest)
+	require.NoError(t, err)
+	req, err := http.NewRequest(http.MethodPut, server.URL+oidcPrefix+credentialOffersPath, bytes.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+testAdminToken)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = resp.Body.Close() })
+	return resp
+}
+
+// postPreAuthorizedCode exchanges a pre-authorized code at the token endpoint, without authenticating a client.
+func postPreAuthorizedCode(t *testing.T, preAuthorizedCode, userPin string) *http.Response {
+	form := url.Values{}
+	form.Set("grant_type", preAuthorizedCodeGrantType)
+	form.Set(preAuthorizedCodeParam, preAuthorizedCode)
+	if userPin != "" {
+		form.Set(userPinParam, userPin)
+	}
+	resp, err := http.Post(server.URL+"/oauth2/token", "application/x-www-form-urlencoded", strings.NewReader(form.Encode()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = resp.Body.Close() })
+	return resp
+}
+
+func assertTokenError(t *testing.T, resp *http.Response, errorCode string) {
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), errorCode)
+}
@@ -0,0 +1,79 @@
+package authorizationserver
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ory/fosite"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	pushedAuthorizationRequestPath = "/oauth2/par"
+
+	// parRequestURIPrefix prefixes the request_uri returned by the pushed authorization request endpoint, as defined in
+	// https://www.rfc-editor.org/rfc/rfc9126#section-2.2
+	parRequestURIPrefix = "urn:ietf:params:oauth:request_uri:"
+)
+
+// PushedAuthorizationRequestEndpoint is a Handler that implements https://www.rfc-editor.org/rfc/rfc9126
+// The authorization request is validated as at the authorization endpoint, including its authorization_details, and
+// stored under the returned request_uri, which the client then sends to the authorization endpoint.
+func (s AuthService) PushedAuthorizationRequestEndpoint(c *gin.Context) {
+	ar, err := s.provider.NewPushedAuthorizeRequest(c, c.Request)
+	if err != nil {
+		logrus.WithError(err).Error("failed NewPushedAuthorizeRequest")
+		s.provider.WritePushedAuthorizeError(c, c.Writer, ar, err)
+		return
+	}
+
+	// PKCE is checked now, so that clients learn it's missing before redirecting the user.
+	if err = checkRequestRequirements(ar, true); err != nil {
+		logrus.WithError(err).Error("failed request requirements")
+		s.provider.WritePushedAuthorizeError(c, c.Writer, ar, err)
+		return
+	}
+
+	if _, err = s.authorizationDetails(c, ar); err != nil {
+		logrus.WithError(err).Error("failed processing authorization_details")
+		s.provider.WritePushedAuthorizeError(c, c.Writer, ar, err)
+		return
+	}
+
+	// The user isn't known until the authorization endpoint is reached, where the session is replaced.
+	response, err := s.provider.NewPushedAuthorizeResponse(c, ar, newSession(""))
+	if err != nil {
+		logrus.WithError(err).Error("failed NewPushedAuthorizeResponse")
+		s.provider.WritePushedAuthorizeError(c, c.Writer, ar, err)
+		return
+	}
+	s.provider.WritePushedAuthorizeResponse(c, c.Writer, ar, response)
+}
+
+// isPushedAuthorizationRequest is whether the authorization request refers to a pushed authorization request.
+// fosite rejects requests whose request_uri has the prefix but wasn't pushed, so the prefix is enough to tell.
+func isPushedAuthorizationRequest(r *http.Request) bool {
+	return strings.HasPrefix(r.Form.Get("request_uri"), parRequestURIPrefix)
+}
+
+// checkRequestRequirements enforces what the client was registered to require of its authorization requests, on top
+// of what the server requires of all clients. fosite checks the code challenge method only when the code is issued,
+// which for pushed requests is too late, so it's checked here too.
+func checkRequestRequirements(ar fosite.AuthorizeRequester, pushed bool) error {
+	form := ar.GetRequestForm()
+	if form.Get("code_challenge") != "" && form.Get("code_challenge_method") != "S256" {
+		return fosite.ErrInvalidRequest.WithHint("Clients must use code_challenge_method=S256, plain is not allowed.")
+	}
+	client, ok := ar.GetClient().(*Client)
+	if !ok {
+		return nil
+	}
+	if client.RequirePushedAuthorizationRequests && !pushed {
+		return fosite.ErrInvalidRequest.WithHint("This client must use a pushed authorization request.")
+	}
+	if client.RequirePKCE && ar.GetResponseTypes().Has("code") && form.Get("code_challenge") == "" {
+		return fosite.ErrInvalidRequest.WithHint("This client must include a code_challenge when performing the authorize code flow, but it is missing.")
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+package authorizationserver
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testCodeVerifier = "a-code-verifier-that-is-long-enough-for-pkce-0123456789"
+
+type parResponse struct {
+	RequestURI       string `json:"request_uri"`
+	ExpiresIn        int    `json:"expires_in"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+func TestPushedAuthorizationRequest(t *testing.T) {
+	createUniversityDegreeTemplate(t)
+
+	t.Run("pushed request is redeemed once at the authorization endpoint", func(t *testing.T) {
+		redirect := newRedirectServer(t)
+		clientID := createClient(t, redirect.Server)
+
+		form := createQuery(&url.URL{}, clientID, redirect.URL, universityDegreeAuthorizationDetails)
+		setCodeChallenge(form, testCodeVerifier)
+		resp, par := pushAuthorizationRequest(t, form, clientID, "foobar")
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		assert.True(t, strings.HasPrefix(par.RequestURI, parRequestURIPrefix))
+		assert.Positive(t, par.ExpiresIn)
+
+		authorizeWithRequestURI(t, clientID, par.RequestURI)
+		require.NotEmpty(t, redirect.code)
+		assert.Equal(t, "my-state", redirect.state)
+
+		tokenForm := url.Values{}
+		tokenForm.Set("grant_type", "authorization_code")
+		tokenForm.Set("code", redirect.code)
+		tokenForm.Set("redirect_uri", redirect.URL)
+		tokenForm.Set("code_verifier", testCodeVerifier)
+		assert.Equal(t, http.StatusOK, postToken(t, clientID, tokenForm).StatusCode)
+
+		// the request_uri can't be used again
+		redirect.code = ""
+		authorizeWithRequestURI(t, clientID, par.RequestURI)
+		assert.Empty(t, redirect.code)
+	})
+
+	t.Run("authorization details are validated", func(t *testing.T) {
+		redirect := newRedirectServer(t)
+		clientID := createClient(t, redirect.Server)
+
+		form := createQuery(&url.URL{}, clientID, redirect.URL, `[{"type":"crazy_type"}]`)
+		resp, par := pushAuthorizationRequest(t, form, clientID, "foobar")
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Equal(t, "invalid_request", par.Error)
+		assert.Contains(t, par.ErrorDescription, "authorization_details[0].type found was 'crazy_type'")
+	})
+
+	t.Run("client must be authenticated", func(t *testing.T) {
+		redirect := newRedirectServer(t)
+		clientID := createClient(t, redirect.Server)
+
+		form := createQuery(&url.URL{}, clientID, redirect.URL, universityDegreeAuthorizationDetails)
+		resp, par := pushAuthorizationRequest(t, form, clientID, "not-the-secret")
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		assert.Equal(t, "invalid_client", par.Error)
+	})
+
+	t.Run("clients registered to require pushed requests can't skip them", func(t *testing.T) {
+		redirect := newRedirectServer(t)
+		info := registerClient(t, ClientMetadata{
+			RedirectURIs:                       []string{redirect.URL},
+			RequirePushedAuthorizationRequests: true,
+		})
+
+		u, err := url.Parse(server.URL + authorizationPath)
+		require.NoError(t, err)
+		query := createQuery(u, info.ClientID, redirect.URL, universityDegreeAuthorizationDetails)
+		u.RawQuery = query.Encode()
+		authResp, err := http.Post(u.String(), "application/x-www-form-urlencoded", strings.NewReader(createForm().Encode()))
+		require.NoError(t, err)
+		require.NoError(t, authResp.Body.Close())
+		assert.Empty(t, redirect.code)
+		assert.Contains(t, redirect.errorDescription, "This client must use a pushed authorization request.")
+
+		resp, par := pushAuthorizationRequest(t, query, info.ClientID, info.ClientSecret)
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		authorizeWithRequestURI(t, info.ClientID, par.RequestURI)
+		assert.NotEmpty(t, redirect.code)
+	})
+
+	t.Run("clients registered to require PKCE must send an S256 code challenge", func(t *testing.T) {
+		redirect := newRedirectServer(t)
+		info := registerClient(t, ClientMetadata{
+			RedirectURIs: []string{redirect.URL},
+			RequirePKCE:  true,
+		})
+
+		form := createQuery(&url.URL{}, info.ClientID, redirect.URL, universityDegreeAuthorizationDetails)
+		resp, par := pushAuthorizationRequest(t, form, info.ClientID, info.ClientSecret)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Contains(t, par.ErrorDescription, "This client must include a code_challenge")
+
+		form.Set("code_challenge", testCodeVerifier)
+		form.Set("code_challenge_method", "plain")
+		resp, par = pushAuthorizationRequest(t, form, info.ClientID, info.ClientSecret)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Contains(t, par.ErrorDescription, "plain is not allowed")
+
+		setCodeChallenge(form, testCodeVerifier)
+		resp, par = pushAuthorizationRequest(t, form, info.ClientID, info.ClientSecret)
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		authorizeWithRequestURI(t, info.ClientID, par.RequestURI)
+		assert.NotEmpty(t, redirect.code)
+	})
+
+	t.Run("endpoint and S256 are in the discovery metadata", func(t *testing.T) {
+		resp, err := http.Get(server.URL + openIDConfigurationPath)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var metadata ProviderMetadata
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&metadata))
+		assert.Equal(t, "https://auth-server.example.com/oauth2/par", metadata.PushedAuthorizationRequestEndpoint)
+		assert.False(t, metadata.RequirePushedAuthorizationRequests)
+		assert.Equal(t, []string{"S256"}, metadata.CodeChallengeMethodsSupported)
+	})
+}
+
+// redirectServer is the redirect URI of a client, which keeps the parameters it was last redirected with.
+type redirectServer struct {
+	*httptest.Server
+	code             string
+	state            string
+	errorDescription string
+}
+
+func newRedirectServer(t *testing.T) *redirectServer {
+	redirect := new(redirectServer)
+	redirect.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		redirect.code = r.URL.Query().Get("code")
+		redirect.state = r.URL.Query().Get("state")
+		redirect.errorDescription = r.URL.Query().Get("error_description")
+	}))
+	t.Cleanup(redirect.Close)
+	return redirect
+}
+
+func setCodeChallenge(form url.Values, verifier string) {
+	challenge := sha256.Sum256([]byte(verifier))
+	form.Set("code_challenge", base64.RawURLEncoding.EncodeToString(challenge[:]))
+	form.Set("code_challenge_method", "S256")
+}
+
+func registerClient(t *testing.T, metadata ClientMetadata) ClientInformation {
+	resp := postClientMetadata(t, http.MethodPost, registrationPath, "", metadata)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var info ClientInformation
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&info))
+	return info
+}
+
+func pushAuthorizationRequest(t *testing.T, form url.Values, clientID, secret string) (*http.Response, parResponse) {
+	req, err := http.NewRequest(http.MethodPost, server.URL+pushedAuthorizationRequestPath, strings.NewReader(form.Encode()))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(clientID, secret)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = resp.Body.Close() })
+	var par parResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&par))
+	return resp, par
+}
+
+// authorizeWithRequestURI logs in at the authorization endpoint with a pushed authorization request.
+func authorizeWithRequestURI(t *testing.T, clientID, requestURI string) {
+	query := url.Values{}
+	query.Set("client_id", clientID)
+	query.Set("request_uri", requestURI)
+	resp, err := http.Post(server.URL+authorizationPath+"?"+query.Encode(), "application/x-www-form-urlencoded", strings.NewReader(createForm().Encode()))
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+}
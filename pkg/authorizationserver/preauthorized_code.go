@@ -0,0 +1,143 @@
+package authorizationserver
+
+import (
+	"context"
+	"time"
+
+	"github.com/ory/fosite"
+	"github.com/ory/fosite/compose"
+	"github.com/ory/fosite/handler/oauth2"
+	"github.com/pkg/errors"
+
+	"github.com/tbd54566975/ssi-service/pkg/authorizationserver/request"
+	"github.com/tbd54566975/ssi-service/pkg/storage"
+)
+
+// preAuthorizedCodeGrantHandler exchanges the pre-authorized code of a credential offer for an access token, as
+// described in https://openid.net/specs/openid-4-verifiable-credential-issuance-1_0.html#name-token-request
+// The session of the access token is tied to the offer, so that the credential endpoint issues the offered credentials
+// with the claims approved for them.
+type preAuthorizedCodeGrantHandler struct {
+	accessTokens oauth2.AccessTokenStrategy
+	store        *Storage
+	offers       *offerStorage
+	config       fosite.AccessTokenLifespanProvider
+}
+
+var _ fosite.TokenEndpointHandler = (*preAuthorizedCodeGrantHandler)(nil)
+
+// preAuthorizedCodeGrantFactory creates the handler of the pre-authorized code grant, to be composed with the other
+// fosite handlers. The offers must be kept in the same database as the store, so that offers are redeemed in the same
+// transaction as their access tokens are stored.
+func preAuthorizedCodeGrantFactory(offers *offerStorage) compose.Factory {
+	return func(config fosite.Configurator, store any, strategy any) any {
+		return &preAuthorizedCodeGrantHandler{
+			accessTokens: strategy.(oauth2.AccessTokenStrategy),
+			store:        store.(*Storage),
+			offers:       offers,
+			config:       config,
+		}
+	}
+}
+
+func (h *preAuthorizedCodeGrantHandler) HandleTokenEndpointRequest(ctx context.Context, requester fosite.AccessRequester) error {
+	if !h.CanHandleTokenEndpointRequest(ctx, requester) {
+		return fosite.ErrUnknownRequest
+	}
+
+	form := requester.GetRequestForm()
+	preAuthorizedCode := form.Get(preAuthorizedCodeParam)
+	if preAuthorizedCode == "" {
+		return fosite.ErrInvalidRequest.WithHintf("The '%s' parameter is missing.", preAuthorizedCodeParam)
+	}
+	offer, err := h.offers.ValidateOffer(ctx, preAuthorizedCode, form.Get(userPinParam))
+	if err != nil {
+		return redeemError(err)
+	}
+
+	session, ok := requester.GetSession().(*Session)
+	if !ok {
+		return fosite.ErrServerError.WithHint("The session is not a credential issuance session.")
+	}
+	session.Subject = offer.Subject
+	session.Claims.Subject = offer.Subject
+	session.CredentialOfferID = offer.ID
+	session.AuthorizationDetails = offer.authorizationDetails()
+	session.SetExpiresAt(fosite.AccessToken, time.Now().UTC().Add(h.config.GetAccessTokenLifespan(ctx)))
+
+	// The client_id is optional in this grant, so wallets that never registered are issued tokens as public clients.
+	if accessRequest, ok := requester.(*fosite.AccessRequest); ok && accessRequest.Client == nil {
+		accessRequest.Client = &fosite.DefaultClient{Public: true}
+	}
+	return nil
+}
+
+func (h *preAuthorizedCodeGrantHandler) PopulateTokenEndpointResponse(ctx context.Context, requester fosite.AccessRequester, responder fosite.AccessResponder) error {
+	if !h.CanHandleTokenEndpointRequest(ctx, requester) {
+		return fosite.ErrUnknownRequest
+	}
+	session, ok := requester.GetSession().(*Session)
+	if !ok {
+		return fosite.ErrServerError.WithHint("The session is not a credential issuance session.")
+	}
+	token, signature, err := h.accessTokens.GenerateAccessToken(ctx, requester)
+	if err != nil {
+		return err
+	}
+
+	// The offer is redeemed along with storing the access token, so that the pre-authorized code isn't used up when
+	// the token can't be issued.
+	sanitized := requester.Sanitize([]string{})
+	err = h.offers.RedeemOffer(ctx, session.CredentialOfferID, func(ctx context.Context, tx storage.Tx) error {
+		return h.store.createAccessTokenSessionTx(ctx, tx, signature, sanitized)
+	})
+	if err != nil {
+		return redeemError(err)
+	}
+
+	responder.SetAccessToken(token)
+	responder.SetTokenType("bearer")
+	responder.SetExpiresIn(time.Until(session.GetExpiresAt(fosite.AccessToken)))
+	responder.SetScopes(requester.GetGrantedScopes())
+	return nil
+}
+
+// CanSkipClientAuth is true because the pre-authorized code is all that's needed to redeem an offer.
+func (h *preAuthorizedCodeGrantHandler) CanSkipClientAuth(context.Context, fosite.AccessRequester) bool {
+	return true
+}
+
+func (h *preAuthorizedCodeGrantHandler) CanHandleTokenEndpointRequest(_ context.Context, requester fosite.AccessRequester) bool {
+	return requester.GetGrantTypes().ExactOne(preAuthorizedCodeGrantType)
+}
+
+// authorizationDetails are the credentials the offer grants access to.
+func (o StoredCredentialOffer) authorizationDetails() request.AuthorizationDetails {
+	details := make(request.AuthorizationDetails, 0, len(o.Credentials))
+	for _, offered := range o.Credentials {
+		format := offered.Format
+		details = append(details, request.AuthorizationDetail{
+			Type:         request.OpenIDCredentialType,
+			Format:       &format,
+			JWTVCDetails: &request.JWTVCDetails{Types: offered.Types},
+		})
+	}
+	return details
+}
+
+// redeemError is the token endpoint error for a failure to redeem an offer. Offers that can't be redeemed are invalid
+// grants.
+func redeemError(err error) error {
+	if isRedeemError(err) {
+		return fosite.ErrInvalidGrant.WithHint(errors.Cause(err).Error()).WithWrap(err)
+	}
+	return fosite.ErrServerError.WithWrap(err).WithDebug(err.Error())
+}
+
+func isRedeemError(err error) bool {
+	return errors.Is(err, errOfferNotFound) ||
+		errors.Is(err, errOfferExpired) ||
+		errors.Is(err, errOfferAlreadyRedeemed) ||
+		errors.Is(err, errInvalidUserPin) ||
+		errors.Is(err, errTooManyUserPinAttempts)
+}
@@ -5,6 +5,10 @@ import (
 	"github.com/pkg/errors"
 )
 
+// OpenIDCredentialType is the authorization details type used to request credentials, as defined in
+// https://openid.net/specs/openid-4-verifiable-credential-issuance-1_0.html#name-request-issuance-of-a-certa
+const OpenIDCredentialType = "openid_credential"
+
 type AuthorizationDetail struct {
 	// Type is the type of the requested authorization, e.g., "payment_initiation".
 	Type string `json:"type"`
@@ -57,7 +61,7 @@ func (d AuthorizationDetail) IsValid() error {
 		return errors.New("type is required")
 	}
 
-	if d.Type == "openid_credential" {
+	if d.Type == OpenIDCredentialType {
 		if d.Format == nil {
 			return errors.New("format is required when type is `openid_credential`")
 		}
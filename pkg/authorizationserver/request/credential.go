@@ -0,0 +1,49 @@
+package request
+
+import (
+	"github.com/TBD54566975/ssi-sdk/oidc/issuance"
+	"github.com/pkg/errors"
+)
+
+// JWTProofType is the proof type of a proof of possession sent as a JWT.
+const JWTProofType = "jwt"
+
+// CredentialRequest is the body of a request to the credential endpoint, as defined in
+// https://openid.net/specs/openid-4-verifiable-credential-issuance-1_0.html#name-credential-request
+type CredentialRequest struct {
+	// The format in which the Credential is requested to be issued.
+	Format issuance.Format `json:"format"`
+
+	// Present when format == jwt_vc_json. The types of the requested credential.
+	Types []string `json:"types,omitempty"`
+
+	// Proof of possession of the key material the issued credential is bound to.
+	Proof *Proof `json:"proof,omitempty"`
+}
+
+// Proof is a proof of possession of the key material the issued credential is bound to.
+type Proof struct {
+	ProofType string `json:"proof_type"`
+
+	// Present when proof_type == jwt.
+	JWT string `json:"jwt,omitempty"`
+}
+
+func (r CredentialRequest) IsValid() error {
+	if r.Format == "" {
+		return errors.New("format is required")
+	}
+	if len(r.Types) == 0 {
+		return errors.New("types is required")
+	}
+	if r.Proof == nil {
+		return errors.New("proof is required")
+	}
+	if r.Proof.ProofType != JWTProofType {
+		return errors.Errorf("proof_type %q is not supported", r.Proof.ProofType)
+	}
+	if r.Proof.JWT == "" {
+		return errors.New("proof.jwt is required")
+	}
+	return nil
+}
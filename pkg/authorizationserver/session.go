@@ -0,0 +1,81 @@
+package authorizationserver
+
+import (
+	"time"
+
+	"github.com/TBD54566975/ssi-sdk/oidc/issuance"
+	"github.com/ory/fosite"
+	"github.com/ory/fosite/handler/openid"
+	"github.com/ory/fosite/token/jwt"
+
+	"github.com/tbd54566975/ssi-service/pkg/authorizationserver/request"
+)
+
+// A session is passed from the `/auth` to the `/token` endpoint. You probably want to store data like: "Who made the request",
+// "What organization does that person belong to" and so on.
+// For our use case, the session will meet the requirements imposed by JWT access tokens, HMAC access tokens and OpenID Connect
+// ID Tokens plus the credential issuance fields below.
+type Session struct {
+	*openid.DefaultSession
+
+	// AuthorizationDetails are the credentials the user granted the client access to. Each has the format and types
+	// of a credential the client can request from the credential endpoint.
+	AuthorizationDetails request.AuthorizationDetails `json:"authorization_details,omitempty"`
+
+	// CNonce is the nonce the client must sign in the proof of possession sent to the credential endpoint, as
+	// described in https://openid.net/specs/openid-4-verifiable-credential-issuance-1_0.html#name-successful-token-response
+	CNonce          string    `json:"c_nonce,omitempty"`
+	CNonceExpiresAt time.Time `json:"c_nonce_expires_at,omitempty"`
+
+	// CredentialOfferID is the ID of the credential offer redeemed with the pre-authorized code grant, if any. The
+	// credentials are issued with the claims approved in the offer.
+	CredentialOfferID string `json:"credential_offer_id,omitempty"`
+}
+
+// Clone clones the session, including the credential issuance fields.
+func (s *Session) Clone() fosite.Session {
+	if s == nil {
+		return nil
+	}
+	clone := *s
+	if s.DefaultSession != nil {
+		clone.DefaultSession = s.DefaultSession.Clone().(*openid.DefaultSession)
+	}
+	clone.AuthorizationDetails = append(request.AuthorizationDetails(nil), s.AuthorizationDetails...)
+	return &clone
+}
+
+// grantsCredential returns whether the authorization details granted a credential with the given format and types.
+func (s *Session) grantsCredential(format issuance.Format, types []string) bool {
+	for _, d := range s.AuthorizationDetails {
+		if d.Type != request.OpenIDCredentialType || d.Format == nil || *d.Format != format {
+			continue
+		}
+		if d.JWTVCDetails != nil && sameTypes(d.Types, types) {
+			return true
+		}
+	}
+	return false
+}
+
+// newSession is a helper function for creating a new session. This may look like a lot of code but since we are
+// setting up multiple strategies it is a bit longer.
+// Usually, you could do:
+//
+//	session = new(fosite.DefaultSession)
+func newSession(user string) *Session {
+	return &Session{
+		DefaultSession: &openid.DefaultSession{
+			Claims: &jwt.IDTokenClaims{
+				Subject:     user,
+				ExpiresAt:   time.Now().Add(time.Hour * 6),
+				IssuedAt:    time.Now(),
+				RequestedAt: time.Now(),
+				AuthTime:    time.Now(),
+			},
+			Headers: &jwt.Headers{
+				Extra: make(map[string]interface{}),
+			},
+		},
+	}
+}
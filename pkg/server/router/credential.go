@@ -41,8 +41,9 @@ type CreateCredentialRequest struct {
 	// The issuer id.
 	Issuer string `json:"issuer" validate:"required" example:"did:key:z6MkiTBz1ymuepAQ4HEHYSF1H8quG5GLVVQR3djdX3mDooWp"`
 
-	// The KID used to sign the credential
-	IssuerKID string `json:"issuerKid" validate:"required" example:"#z6MkiTBz1ymuepAQ4HEHYSF1H8quG5GLVVQR3djdX3mDooWp"`
+	// The KID used to sign the credential. When omitted, it's the current key of the issuer's assertion methods, which
+	// follows key rotations.
+	IssuerKID string `json:"issuerKid,omitempty" example:"#z6MkiTBz1ymuepAQ4HEHYSF1H8quG5GLVVQR3djdX3mDooWp"`
 
 	// The subject id.
 	Subject string `json:"subject" validate:"required" example:"did:key:z6MkiTBz1ymuepAQ4HEHYSF1H8quG5GLVVQR3djdX3mDooWp"`
@@ -335,6 +336,9 @@ type VerifyCredentialRequest struct {
 
 	// A JWT that encodes a credential.
 	CredentialJWT *keyaccess.JWT `json:"credentialJwt,omitempty"`
+
+	// When true, verification fails unless the trust registry trusts the credential's issuer for its schema.
+	RequireTrustedIssuer bool `json:"requireTrustedIssuer,omitempty"`
 }
 
 func (vcr VerifyCredentialRequest) IsValid() bool {
@@ -358,6 +362,7 @@ type VerifyCredentialResponse struct {
 //	@Description	2. Makes sure the credential has is not expired
 //	@Description	3. Makes sure the credential complies with the VC Data Model
 //	@Description	4. If the credential has a schema, makes sure its data complies with the schema
+//	@Description	5. If `requireTrustedIssuer` is set, makes sure the issuer is trusted for the credential's schema
 //	@Tags			CredentialAPI
 //	@Accept			json
 //	@Produce		json
@@ -383,6 +388,7 @@ func (cr CredentialRouter) VerifyCredential(c *gin.Context) {
 	verificationResult, err := cr.service.VerifyCredential(c, credential.VerifyCredentialRequest{
 		DataIntegrityCredential: request.DataIntegrityCredential,
 		CredentialJWT:           request.CredentialJWT,
+		RequireTrustedIssuer:    request.RequireTrustedIssuer,
 	})
 	if err != nil {
 		errMsg := "could not verify credential"
@@ -4,10 +4,12 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/TBD54566975/ssi-sdk/crypto"
 	didsdk "github.com/TBD54566975/ssi-sdk/did"
 	"github.com/TBD54566975/ssi-sdk/did/resolution"
+	"github.com/TBD54566975/ssi-sdk/did/web"
 	"github.com/gin-gonic/gin"
 	"github.com/goccy/go-json"
 	"github.com/pkg/errors"
@@ -77,9 +79,9 @@ type CreateDIDByMethodResponse struct {
 //
 //	@Summary		Create DID Document
 //	@Description	Creates a fully custodial DID document with the given method. The document created is stored internally
-//	@Description	and can be retrieved using the GetOperation. Method dependent registration (for example, DID web
-//	@Description	registration) is left up to the clients of this API. The private key(s) created by the method are stored
-//	@Description	internally never leave the service boundary.
+//	@Description	and can be retrieved using the GetOperation. Method dependent registration is left up to the clients of
+//	@Description	this API, except for did:web documents, which are hosted by the service. The private key(s) created by
+//	@Description	the method are stored internally never leave the service boundary.
 //	@Tags			DecentralizedIdentityAPI
 //	@Accept			json
 //	@Produce		json
@@ -127,6 +129,79 @@ func (dr DIDRouter) CreateDIDByMethod(c *gin.Context) {
 	framework.Respond(c, resp, http.StatusCreated)
 }
 
+type ImportDIDByMethodRequest struct {
+	// Document of the DID to import. Only the id is needed, since the document of did:key DIDs is derived from it, and
+	// the document of did:web DIDs is the one their domain serves.
+	// Decoded once the request is validated, since documents can't be validated as part of the request.
+	DID any `json:"did" validate:"required"`
+
+	// Private keys of verification methods of the document, which the service can then sign with.
+	Keys []did.ImportDIDKey `json:"keys" validate:"required,min=1,dive"`
+}
+
+type ImportDIDByMethodResponse struct {
+	DID didsdk.Document `json:"did,omitempty"`
+}
+
+// ImportDIDByMethod godoc
+//
+//	@Summary		Import DID
+//	@Description	Imports a DID created outside the service, along with the private keys of its verification methods.
+//	@Description	Each key is given either as a JWK, or as base58 along with its key type, and must be the private key
+//	@Description	of the verification method it's given for. The keys are stored internally with the DID as their
+//	@Description	controller, and the DID can then be used like the DIDs created by the service. Supported for did:key
+//	@Description	and did:web. The document of a did:web DID is fetched from its domain, and hosted by the service
+//	@Description	once imported.
+//	@Tags			DecentralizedIdentityAPI
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		ImportDIDByMethodRequest	true	"request body"
+//	@Param			method	path		string						true	"Method"
+//	@Success		201		{object}	ImportDIDByMethodResponse
+//	@Failure		400		{string}	string	"Bad request"
+//	@Failure		500		{string}	string	"Internal server error"
+//	@Router			/v1/dids/{method}/import [put]
+func (dr DIDRouter) ImportDIDByMethod(c *gin.Context) {
+	method := framework.GetParam(c, MethodParam)
+	if method == nil {
+		errMsg := "import DID request missing method parameter"
+		framework.LoggingRespondErrMsg(c, errMsg, http.StatusBadRequest)
+		return
+	}
+
+	var request ImportDIDByMethodRequest
+	invalidImportDIDRequest := "invalid import DID request"
+	if err := framework.Decode(c.Request, &request); err != nil {
+		framework.LoggingRespondErrWithMsg(c, err, invalidImportDIDRequest, http.StatusBadRequest)
+		return
+	}
+	if err := framework.ValidateRequest(request); err != nil {
+		framework.LoggingRespondErrWithMsg(c, err, invalidImportDIDRequest, http.StatusBadRequest)
+		return
+	}
+
+	var doc didsdk.Document
+	if err := optionsToType(request.DID, &doc); err != nil {
+		framework.LoggingRespondErrWithMsg(c, err, invalidImportDIDRequest, http.StatusBadRequest)
+		return
+	}
+
+	importDIDRequest := did.ImportDIDRequest{
+		Method: didsdk.Method(*method),
+		DID:    doc,
+		Keys:   request.Keys,
+	}
+	imported, err := dr.service.ImportDID(c, importDIDRequest)
+	if err != nil {
+		errMsg := fmt.Sprintf("could not import DID for method<%s> with id: %s", *method, doc.ID)
+		framework.LoggingRespondErrWithMsg(c, err, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	resp := ImportDIDByMethodResponse{DID: imported.DID}
+	framework.Respond(c, resp, http.StatusCreated)
+}
+
 // toCreateDIDRequest converts CreateDIDByMethodRequest to did.CreateDIDRequest, parsing options according to method
 func toCreateDIDRequest(m didsdk.Method, request CreateDIDByMethodRequest) (*did.CreateDIDRequest, error) {
 	createRequest := did.CreateDIDRequest{
@@ -153,6 +228,12 @@ func toCreateDIDRequest(m didsdk.Method, request CreateDIDByMethodRequest) (*did
 			return nil, errors.Wrap(err, "parsing web options")
 		}
 		createRequest.Options = opts
+	case didsdk.PeerMethod:
+		var opts did.CreatePeerDIDOptions
+		if err := optionsToType(request.Options, &opts); err != nil {
+			return nil, errors.Wrap(err, "parsing peer options")
+		}
+		createRequest.Options = opts
 	default:
 		if request.Options != nil {
 			return nil, fmt.Errorf("invalid options for method<%s>", m)
@@ -222,6 +303,270 @@ func (dr DIDRouter) GetDIDByMethod(c *gin.Context) {
 	framework.Respond(c, resp, http.StatusOK)
 }
 
+type UpdateDIDByMethodRequest struct {
+	// Verification methods to add, whose keys are generated by the service and never leave the service boundary.
+	VerificationMethodsToAdd []did.VerificationMethodToAdd `json:"verificationMethodsToAdd,omitempty" validate:"dive"`
+	// IDs of the verification methods to remove, along with their verification relationships. Their keys are revoked.
+	VerificationMethodIDsToRemove []string `json:"verificationMethodIdsToRemove,omitempty"`
+	// Verification relationships replacing those of verification methods in the document.
+	VerificationRelationshipsToSet []did.VerificationRelationships `json:"verificationRelationshipsToSet,omitempty" validate:"dive"`
+	// Services to add, such as LinkedDomains or DIDCommMessaging endpoints.
+	ServicesToAdd      []didsdk.Service `json:"servicesToAdd,omitempty" validate:"dive"`
+	ServiceIDsToRemove []string         `json:"serviceIdsToRemove,omitempty"`
+}
+
+func (r UpdateDIDByMethodRequest) toServiceRequest(method didsdk.Method, id string) did.UpdateDIDRequest {
+	return did.UpdateDIDRequest{
+		Method:                         method,
+		ID:                             id,
+		VerificationMethodsToAdd:       r.VerificationMethodsToAdd,
+		VerificationMethodIDsToRemove:  r.VerificationMethodIDsToRemove,
+		VerificationRelationshipsToSet: r.VerificationRelationshipsToSet,
+		ServicesToAdd:                  r.ServicesToAdd,
+		ServiceIDsToRemove:             r.ServiceIDsToRemove,
+	}
+}
+
+type UpdateDIDByMethodResponse struct {
+	DID didsdk.Document `json:"did"`
+	// ID of the operation tracking the anchoring of the update, for did:ion DIDs.
+	OperationID string `json:"operationId,omitempty"`
+}
+
+// UpdateDIDByMethod godoc
+//
+//	@Summary		Update DID
+//	@Description	Updates the document of a DID with a patch. Services and verification methods are removed first, then
+//	@Description	added, and verification relationships are set last. Keys can be rotated by adding a verification
+//	@Description	method and removing the one it replaces. Supported for did:web, whose hosted document is updated
+//	@Description	immediately, and did:ion, for which a Sidetree update operation is submitted. The anchoring of
+//	@Description	did:ion updates is tracked by the operation returned.
+//	@Tags			DecentralizedIdentityAPI
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		UpdateDIDByMethodRequest	true	"request body"
+//	@Param			method	path		string						true	"Method"
+//	@Param			id		path		string						true	"ID"
+//	@Success		200		{object}	UpdateDIDByMethodResponse
+//	@Failure		400		{string}	string	"Bad request"
+//	@Failure		500		{string}	string	"Internal server error"
+//	@Router			/v1/dids/{method}/{id} [patch]
+func (dr DIDRouter) UpdateDIDByMethod(c *gin.Context) {
+	method := framework.GetParam(c, MethodParam)
+	if method == nil {
+		errMsg := "update DID by method request missing method parameter"
+		framework.LoggingRespondErrMsg(c, errMsg, http.StatusBadRequest)
+		return
+	}
+	id := framework.GetParam(c, IDParam)
+	if id == nil {
+		errMsg := fmt.Sprintf("update DID request missing id parameter for method: %s", *method)
+		framework.LoggingRespondErrMsg(c, errMsg, http.StatusBadRequest)
+		return
+	}
+
+	var request UpdateDIDByMethodRequest
+	invalidUpdateDIDRequest := "invalid update DID request"
+	if err := framework.Decode(c.Request, &request); err != nil {
+		framework.LoggingRespondErrWithMsg(c, err, invalidUpdateDIDRequest, http.StatusBadRequest)
+		return
+	}
+	if err := framework.ValidateRequest(request); err != nil {
+		framework.LoggingRespondErrWithMsg(c, err, invalidUpdateDIDRequest, http.StatusBadRequest)
+		return
+	}
+	updateDIDRequest := request.toServiceRequest(didsdk.Method(*method), *id)
+	if updateDIDRequest.IsEmpty() {
+		framework.LoggingRespondErrMsg(c, invalidUpdateDIDRequest+": no changes", http.StatusBadRequest)
+		return
+	}
+
+	updatedDID, err := dr.service.UpdateDIDByMethod(c, updateDIDRequest)
+	if err != nil {
+		errMsg := fmt.Sprintf("could not update DID for method<%s> with id: %s", *method, *id)
+		framework.LoggingRespondErrWithMsg(c, err, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	resp := UpdateDIDByMethodResponse{DID: updatedDID.DID, OperationID: updatedDID.OperationID}
+	framework.Respond(c, resp, http.StatusOK)
+}
+
+type RotateDIDKeyRequest struct {
+	// ID of the verification method of the key to replace. When omitted, it's the key currently used to sign assertions.
+	KeyID   string         `json:"keyId,omitempty"`
+	KeyType crypto.KeyType `json:"keyType" validate:"required"`
+}
+
+type RotateDIDKeyResponse struct {
+	DID didsdk.Document `json:"did"`
+	// ID of the verification method of the new key.
+	KeyID string `json:"keyId"`
+	// ID of the operation tracking the anchoring of the update, for did:ion DIDs.
+	OperationID string `json:"operationId,omitempty"`
+}
+
+// RotateDIDKey godoc
+//
+//	@Summary		Rotate DID key
+//	@Description	Replaces a key of a DID with a newly generated one, using the method's update mechanism. The new key
+//	@Description	takes over the verification relationships of the replaced key, and always becomes an assertion method.
+//	@Description	The replaced key stays in the document so that what it signed can still be verified, but it's revoked
+//	@Description	for signing. Credentials and manifests issued without an issuerKid are signed with the new key.
+//	@Description	Supported for did:web and did:ion.
+//	@Tags			DecentralizedIdentityAPI
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		RotateDIDKeyRequest	true	"request body"
+//	@Param			method	path		string				true	"Method"
+//	@Param			id		path		string				true	"ID"
+//	@Success		200		{object}	RotateDIDKeyResponse
+//	@Failure		400		{string}	string	"Bad request"
+//	@Failure		500		{string}	string	"Internal server error"
+//	@Router			/v1/dids/{method}/{id}/rotate-key [post]
+func (dr DIDRouter) RotateDIDKey(c *gin.Context) {
+	method := framework.GetParam(c, MethodParam)
+	if method == nil {
+		errMsg := "rotate DID key request missing method parameter"
+		framework.LoggingRespondErrMsg(c, errMsg, http.StatusBadRequest)
+		return
+	}
+	id := framework.GetParam(c, IDParam)
+	if id == nil {
+		errMsg := fmt.Sprintf("rotate DID key request missing id parameter for method: %s", *method)
+		framework.LoggingRespondErrMsg(c, errMsg, http.StatusBadRequest)
+		return
+	}
+
+	var request RotateDIDKeyRequest
+	invalidRotateDIDKeyRequest := "invalid rotate DID key request"
+	if err := framework.Decode(c.Request, &request); err != nil {
+		framework.LoggingRespondErrWithMsg(c, err, invalidRotateDIDKeyRequest, http.StatusBadRequest)
+		return
+	}
+	if err := framework.ValidateRequest(request); err != nil {
+		framework.LoggingRespondErrWithMsg(c, err, invalidRotateDIDKeyRequest, http.StatusBadRequest)
+		return
+	}
+
+	rotateDIDKeyRequest := did.RotateDIDKeyRequest{
+		Method:  didsdk.Method(*method),
+		ID:      *id,
+		KeyID:   request.KeyID,
+		KeyType: request.KeyType,
+	}
+	rotated, err := dr.service.RotateDIDKey(c, rotateDIDKeyRequest)
+	if err != nil {
+		errMsg := fmt.Sprintf("could not rotate key of DID for method<%s> with id: %s", *method, *id)
+		framework.LoggingRespondErrWithMsg(c, err, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	resp := RotateDIDKeyResponse{DID: rotated.DID, KeyID: rotated.KeyID, OperationID: rotated.OperationID}
+	framework.Respond(c, resp, http.StatusOK)
+}
+
+type RecoverIONDIDRequest struct {
+	// Verification methods of the new document, whose keys are generated by the service and never leave the service
+	// boundary.
+	VerificationMethods []did.VerificationMethodToAdd `json:"verificationMethods" validate:"required,dive"`
+	Services            []didsdk.Service              `json:"services,omitempty" validate:"dive"`
+}
+
+type RecoverIONDIDResponse struct {
+	DID didsdk.Document `json:"did"`
+	// ID of the operation tracking the anchoring of the recovery.
+	OperationID string `json:"operationId"`
+}
+
+// RecoverIONDID godoc
+//
+//	@Summary		Recover ION DID
+//	@Description	Replaces the document of a did:ion DID by submitting a Sidetree recover operation, signed with the
+//	@Description	recovery key of the DID. The keys of the replaced document are revoked, and new update and recovery
+//	@Description	keys are committed to. The anchoring of the recovery is tracked by the operation returned.
+//	@Tags			DecentralizedIdentityAPI
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		RecoverIONDIDRequest	true	"request body"
+//	@Param			id		path		string					true	"ID"
+//	@Success		200		{object}	RecoverIONDIDResponse
+//	@Failure		400		{string}	string	"Bad request"
+//	@Failure		500		{string}	string	"Internal server error"
+//	@Router			/v1/dids/ion/{id}/recover [post]
+func (dr DIDRouter) RecoverIONDID(c *gin.Context) {
+	id := framework.GetParam(c, IDParam)
+	if id == nil {
+		errMsg := "recover DID request missing id parameter"
+		framework.LoggingRespondErrMsg(c, errMsg, http.StatusBadRequest)
+		return
+	}
+
+	var request RecoverIONDIDRequest
+	invalidRecoverDIDRequest := "invalid recover DID request"
+	if err := framework.Decode(c.Request, &request); err != nil {
+		framework.LoggingRespondErrWithMsg(c, err, invalidRecoverDIDRequest, http.StatusBadRequest)
+		return
+	}
+	if err := framework.ValidateRequest(request); err != nil {
+		framework.LoggingRespondErrWithMsg(c, err, invalidRecoverDIDRequest, http.StatusBadRequest)
+		return
+	}
+
+	recoverDIDRequest := did.RecoverIONDIDRequest{
+		ID:                  *id,
+		VerificationMethods: request.VerificationMethods,
+		Services:            request.Services,
+	}
+	recoveredDID, err := dr.service.RecoverIONDID(c, recoverDIDRequest)
+	if err != nil {
+		errMsg := fmt.Sprintf("could not recover DID with id: %s", *id)
+		framework.LoggingRespondErrWithMsg(c, err, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	resp := RecoverIONDIDResponse{DID: recoveredDID.DID, OperationID: recoveredDID.OperationID}
+	framework.Respond(c, resp, http.StatusOK)
+}
+
+type DeactivateIONDIDResponse struct {
+	// ID of the operation tracking the anchoring of the deactivation.
+	OperationID string `json:"operationId"`
+}
+
+// DeactivateIONDID godoc
+//
+//	@Summary		Deactivate ION DID
+//	@Description	Permanently deactivates a did:ion DID by submitting a Sidetree deactivate operation, signed with the
+//	@Description	recovery key of the DID. All keys of the DID are revoked. The anchoring of the deactivation is
+//	@Description	tracked by the operation returned.
+//	@Tags			DecentralizedIdentityAPI
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string	true	"ID"
+//	@Success		200	{object}	DeactivateIONDIDResponse
+//	@Failure		400	{string}	string	"Bad request"
+//	@Failure		500	{string}	string	"Internal server error"
+//	@Router			/v1/dids/ion/{id}/deactivate [post]
+func (dr DIDRouter) DeactivateIONDID(c *gin.Context) {
+	id := framework.GetParam(c, IDParam)
+	if id == nil {
+		errMsg := "deactivate DID request missing id parameter"
+		framework.LoggingRespondErrMsg(c, errMsg, http.StatusBadRequest)
+		return
+	}
+
+	deactivated, err := dr.service.DeactivateIONDID(c, did.DeactivateIONDIDRequest{ID: *id})
+	if err != nil {
+		errMsg := fmt.Sprintf("could not deactivate DID with id: %s", *id)
+		framework.LoggingRespondErrWithMsg(c, err, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	resp := DeactivateIONDIDResponse{OperationID: deactivated.OperationID}
+	framework.Respond(c, resp, http.StatusOK)
+}
+
 type ListDIDsByMethodResponse struct {
 	DIDs []didsdk.Document `json:"dids,omitempty"`
 }
@@ -327,7 +672,9 @@ func (dr DIDRouter) SoftDeleteDIDByMethod(c *gin.Context) {
 // ResolveDID godoc
 //
 //	@Summary		Resolve a DID
-//	@Description	Resolve a DID that may not be stored in this service
+//	@Description	Resolve a DID that may not be stored in this service. DIDs managed by this service are resolved with the
+//	@Description	metadata of their document: when it was created and last updated, its version, and whether the DID is
+//	@Description	deactivated.
 //	@Tags			DecentralizedIdentityAPI
 //	@Accept			json
 //	@Produce		json
@@ -354,3 +701,115 @@ func (dr DIDRouter) ResolveDID(c *gin.Context) {
 	resp := ResolveDIDResponse{ResolutionMetadata: resolvedDID.ResolutionMetadata, DIDDocument: resolvedDID.DIDDocument, DIDDocumentMetadata: resolvedDID.DIDDocumentMetadata}
 	framework.Respond(c, resp, http.StatusOK)
 }
+
+// DIDURLQueryParam is the query parameter of the DID URL to dereference.
+const DIDURLQueryParam = "didUrl"
+
+type DereferenceDIDURLResponse struct {
+	DereferencingMetadata did.DereferencingMetadata `json:"dereferencingMetadata"`
+	// The resource the DID URL identifies, which is a document, verification method, service, or URLs of service
+	// endpoints.
+	ContentStream   any                          `json:"contentStream"`
+	ContentMetadata *resolution.DocumentMetadata `json:"contentMetadata,omitempty"`
+}
+
+// DereferenceDIDURL godoc
+//
+//	@Summary		Dereference a DID URL
+//	@Description	Resolves the DID of a DID URL, and returns the resource the rest of the URL identifies. A fragment
+//	@Description	identifies a verification method or service of the document. The service parameter identifies the
+//	@Description	endpoints of a service, to which the relativeRef parameter and the fragment are applied. A DID URL
+//	@Description	with neither identifies the document.
+//	@Tags			DecentralizedIdentityAPI
+//	@Produce		json
+//	@Param			didUrl	query		string	true	"DID URL, such as did:example:123#key-1 or did:example:123?service=files&relativeRef=/report.pdf"
+//	@Success		200		{object}	DereferenceDIDURLResponse
+//	@Failure		400		{string}	string	"Bad request"
+//	@Router			/v1/dids/resolver/dereference [get]
+func (dr DIDRouter) DereferenceDIDURL(c *gin.Context) {
+	didURL := framework.GetQueryValue(c, DIDURLQueryParam)
+	if didURL == nil {
+		errMsg := "dereference DID URL request missing didUrl parameter"
+		framework.LoggingRespondErrMsg(c, errMsg, http.StatusBadRequest)
+		return
+	}
+
+	dereferenced, err := dr.service.DereferenceDIDURL(c, did.DereferenceDIDURLRequest{DIDURL: *didURL})
+	if err != nil {
+		errMsg := fmt.Sprintf("could not dereference DID URL: %s", *didURL)
+		framework.LoggingRespondErrWithMsg(c, err, errMsg, http.StatusBadRequest)
+		return
+	}
+
+	resp := DereferenceDIDURLResponse{
+		DereferencingMetadata: dereferenced.DereferencingMetadata,
+		ContentStream:         dereferenced.ContentStream,
+		ContentMetadata:       dereferenced.ContentMetadata,
+	}
+	framework.Respond(c, resp, http.StatusOK)
+}
+
+const (
+	// WellKnownDIDPath is the path of the document of a did:web DID without a path
+	WellKnownDIDPath = "/.well-known/did.json"
+	// DIDDocumentFile is the file name of the document of a did:web DID with a path
+	DIDDocumentFile = "did.json"
+)
+
+// GetWebDIDDocument godoc
+//
+//	@Summary		Get did:web Document
+//	@Description	Hosts the documents of did:web DIDs created by the service, so they can be resolved without copying
+//	@Description	them to another web server. The DID is identified by the host and path of the request, which are
+//	@Description	/.well-known/did.json for DIDs without a path, and /<path>/did.json otherwise. Documents of soft
+//	@Description	deleted DIDs are reported as deactivated with a 410 status.
+//	@Tags			DecentralizedIdentityAPI
+//	@Produce		json
+//	@Success		200	{object}	didsdk.Document
+//	@Failure		404	{string}	string	"Not found"
+//	@Failure		410	{string}	string	"Deactivated"
+//	@Router			/.well-known/did.json [get]
+func (dr DIDRouter) GetWebDIDDocument(c *gin.Context) {
+	id, err := webDIDFromRequest(c.Request.Host, c.Request.URL.Path)
+	if err != nil {
+		framework.LoggingRespondErrWithMsg(c, err, "page not found", http.StatusNotFound)
+		return
+	}
+
+	gotDID, err := dr.service.GetWebDIDDocument(c, did.GetWebDIDDocumentRequest{ID: id})
+	if err != nil {
+		errMsg := fmt.Sprintf("could not get document of DID: %s", id)
+		framework.LoggingRespondErrWithMsg(c, err, errMsg, http.StatusNotFound)
+		return
+	}
+	if gotDID.Deactivated {
+		errMsg := fmt.Sprintf("DID has been deactivated: %s", id)
+		framework.LoggingRespondErrMsg(c, errMsg, http.StatusGone)
+		return
+	}
+
+	framework.Respond(c, gotDID.DID, http.StatusOK)
+}
+
+// webDIDFromRequest returns the did:web DID whose document is at the host and path of a request, reversing the
+// transformation in https://w3c-ccg.github.io/did-method-web/#read-resolve
+func webDIDFromRequest(host, path string) (string, error) {
+	if host == "" {
+		return "", errors.New("request has no host")
+	}
+	// ports are percent encoded in the DID
+	id := web.WebPrefix + ":" + strings.ReplaceAll(strings.ToLower(host), ":", "%3A")
+	if path == WellKnownDIDPath {
+		return id, nil
+	}
+	if !strings.HasSuffix(path, "/"+DIDDocumentFile) {
+		return "", fmt.Errorf("not the path of a DID document: %s", path)
+	}
+	segments := strings.Split(strings.TrimPrefix(strings.TrimSuffix(path, "/"+DIDDocumentFile), "/"), "/")
+	for _, segment := range segments {
+		if segment == "" || strings.Contains(segment, ":") {
+			return "", fmt.Errorf("not the path of a DID document: %s", path)
+		}
+	}
+	return id + ":" + strings.Join(segments, ":"), nil
+}
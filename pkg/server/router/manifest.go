@@ -39,12 +39,13 @@ func NewManifestRouter(s svcframework.Service) (*ManifestRouter, error) {
 }
 
 // CreateManifestRequest is the request body for creating a manifest, which populates all remaining fields
-// and builds a well-formed manifest object.
+// and builds a well-formed manifest object. When IssuerKID is omitted, the manifest and its responses are signed with
+// the issuer's current assertion key, which follows key rotations.
 type CreateManifestRequest struct {
 	Name                   *string                          `json:"name,omitempty"`
 	Description            *string                          `json:"description,omitempty"`
 	IssuerDID              string                           `json:"issuerDid" validate:"required"`
-	IssuerKID              string                           `json:"issuerKid" validate:"required"`
+	IssuerKID              string                           `json:"issuerKid,omitempty"`
 	IssuerName             *string                          `json:"issuerName,omitempty"`
 	ClaimFormat            *exchange.ClaimFormat            `json:"format" validate:"required,dive"`
 	OutputDescriptors      []manifestsdk.OutputDescriptor   `json:"outputDescriptors" validate:"required,dive"`
@@ -18,6 +18,7 @@ import (
 	svcframework "github.com/tbd54566975/ssi-service/pkg/service/framework"
 	"github.com/tbd54566975/ssi-service/pkg/service/presentation"
 	"github.com/tbd54566975/ssi-service/pkg/service/presentation/model"
+	presentationstorage "github.com/tbd54566975/ssi-service/pkg/service/presentation/storage"
 )
 
 type PresentationRouter struct {
@@ -225,15 +226,40 @@ type CreateSubmissionRequest struct {
 	// A Verifiable Presentation that's encoded as a JWT.
 	// Verifiable Presentation are described in https://www.w3.org/TR/vc-data-model/#presentations-0
 	// JWT encoding of the Presentation as described in https://www.w3.org/TR/vc-data-model/#presentations-0
-	SubmissionJWT keyaccess.JWT `json:"submissionJwt" validate:"required"`
+	// Exactly one of `submissionJwt` or `submission` must be present.
+	SubmissionJWT keyaccess.JWT `json:"submissionJwt,omitempty"`
+
+	// A Verifiable Presentation secured via data integrity (`ldp_vp`). Must have the "proof" property set, with an
+	// `authentication` proof purpose.
+	Submission *credential.VerifiablePresentation `json:"submission,omitempty"`
+
+	// ID of the presentation request this submission answers. When present, the presentation must be bound to the
+	// request: a JWT's `nonce` claim must be the request's nonce and its `aud` must contain the request's issuer. A
+	// data integrity proof's `challenge` and `domain` must be the request's nonce and issuer. Each request can only
+	// be answered once, and only before it expires.
+	PresentationRequestID string `json:"presentationRequestId,omitempty"`
+}
+
+func (r CreateSubmissionRequest) IsValid() error {
+	if (r.SubmissionJWT == "") == (r.Submission == nil) {
+		return errors.New("exactly one of submissionJwt or submission must be provided")
+	}
+	if r.Submission != nil && r.Submission.Proof == nil {
+		return errors.New("submission must have a proof")
+	}
+	return nil
 }
 
 func (r CreateSubmissionRequest) toServiceRequest() (*model.CreateSubmissionRequest, error) {
-	_, _, vp, err := credential.ParseVerifiablePresentationFromJWT(r.SubmissionJWT.String())
-	if err != nil {
-		return nil, errors.Wrap(err, "parsing presentation from jwt")
+	vp := r.Submission
+	if r.SubmissionJWT != "" {
+		var err error
+		_, _, vp, err = keyaccess.ParseVerifiablePresentationFromJWT(r.SubmissionJWT)
+		if err != nil {
+			return nil, errors.Wrap(err, "parsing presentation from jwt")
+		}
 	}
-	if err = vp.IsValid(); err != nil {
+	if err := vp.IsValid(); err != nil {
 		return nil, errors.Wrap(err, "verifying vp validity")
 	}
 
@@ -256,16 +282,18 @@ func (r CreateSubmissionRequest) toServiceRequest() (*model.CreateSubmissionRequ
 	}
 
 	return &model.CreateSubmissionRequest{
-		Presentation:  *vp,
-		SubmissionJWT: r.SubmissionJWT,
-		Submission:    s,
-		Credentials:   credContainers}, nil
+		Presentation:          *vp,
+		PresentationRequestID: r.PresentationRequestID,
+		SubmissionJWT:         r.SubmissionJWT,
+		Submission:            s,
+		Credentials:           credContainers}, nil
 }
 
 // CreateSubmission godoc
 //
 //	@Summary		Create Submission
-//	@Description	Creates a submission in this server ready to be reviewed.
+//	@Description	Creates a submission in this server ready to be reviewed. The submission is either a presentation
+//	@Description	encoded as a JWT (`jwt_vp`) or a presentation secured via data integrity (`ldp_vp`).
 //	@Tags			PresentationSubmissionAPI
 //	@Accept			json
 //	@Produce		json
@@ -282,6 +310,11 @@ func (pr PresentationRouter) CreateSubmission(c *gin.Context) {
 		return
 	}
 
+	if err := request.IsValid(); err != nil {
+		framework.LoggingRespondErrWithMsg(c, err, invalidCreateSubmissionRequestErr, http.StatusBadRequest)
+		return
+	}
+
 	req, err := request.toServiceRequest()
 	if err != nil {
 		framework.LoggingRespondErrWithMsg(c, err, invalidCreateSubmissionRequestErr, http.StatusBadRequest)
@@ -406,13 +439,18 @@ func (pr PresentationRouter) ListSubmissions(c *gin.Context) {
 type ReviewSubmissionRequest struct {
 	Approved bool   `json:"approved" validate:"required"`
 	Reason   string `json:"reason,omitempty"`
+
+	// When true, the submission is denied instead of approved if any of its credentials was issued by an issuer
+	// that the trust registry does not trust for that credential.
+	RequireTrustedIssuers bool `json:"requireTrustedIssuers,omitempty"`
 }
 
 func (r ReviewSubmissionRequest) toServiceRequest(id string) model.ReviewSubmissionRequest {
 	return model.ReviewSubmissionRequest{
-		ID:       id,
-		Approved: r.Approved,
-		Reason:   r.Reason,
+		ID:                    id,
+		Approved:              r.Approved,
+		Reason:                r.Reason,
+		RequireTrustedIssuers: r.RequireTrustedIssuers,
 	}
 }
 
@@ -457,6 +495,107 @@ func (pr PresentationRouter) ReviewSubmission(c *gin.Context) {
 	framework.Respond(c, ReviewSubmissionResponse{Submission: submission}, http.StatusOK)
 }
 
+type VerifyPresentationRequest struct {
+	// A JWT that encodes a verifiable presentation, signed by its holder.
+	PresentationJWT *keyaccess.JWT `json:"presentationJwt,omitempty"`
+
+	// A presentation secured via data integrity. Must have the "proof" property set.
+	DataIntegrityPresentation *credential.VerifiablePresentation `json:"presentation,omitempty"`
+
+	// When present, the presentation must be a presentation submission that fulfills this definition.
+	// Optional.
+	PresentationDefinition *exchange.PresentationDefinition `json:"presentationDefinition,omitempty"`
+
+	// When present, the presentation must be bound to this challenge. It's the proof's `challenge` for data integrity
+	// presentations, and the `nonce` claim for JWTs.
+	// Optional.
+	Challenge string `json:"challenge,omitempty"`
+
+	// When present, the presentation must be bound to this domain. It's the proof's `domain` for data integrity
+	// presentations, and one of the `aud` values for JWTs.
+	// Optional.
+	Domain string `json:"domain,omitempty"`
+}
+
+func (r VerifyPresentationRequest) toServiceRequest() model.VerifyPresentationRequest {
+	return model.VerifyPresentationRequest{
+		PresentationJWT:           r.PresentationJWT,
+		DataIntegrityPresentation: r.DataIntegrityPresentation,
+		PresentationDefinition:    r.PresentationDefinition,
+		Challenge:                 r.Challenge,
+		Domain:                    r.Domain,
+	}
+}
+
+type VerifyPresentationResponse struct {
+	// Whether the holder's signature, every credential and, when requested, the presentation definition were verified.
+	Verified bool `json:"verified"`
+
+	// The reason why the presentation couldn't be verified.
+	Reason string `json:"reason,omitempty"`
+
+	// DID of the holder of the presentation.
+	Holder string `json:"holder,omitempty"`
+
+	// Whether the presentation was signed by its holder.
+	HolderVerified bool `json:"holderVerified"`
+
+	// Whether the presentation fulfills the given definition. Only present when a definition was given.
+	DefinitionVerified *bool `json:"definitionVerified,omitempty"`
+
+	// The result of verifying each credential in the presentation, in the order they appear.
+	Credentials []model.CredentialVerificationResult `json:"credentials"`
+}
+
+// VerifyPresentation godoc
+//
+//	@Summary		Verify Presentation
+//	@Description	Verify a presentation without storing it. The system does the following levels of verification:
+//	@Description	1. Makes sure the presentation was signed by its holder, resolving the holder's DID
+//	@Description	2. Verifies each credential in the presentation, as done by the credential verification endpoint
+//	@Description	3. If `presentationDefinition` is set, makes sure the presentation is a submission that fulfills it
+//	@Description	4. If `challenge` or `domain` are set, makes sure the presentation is bound to them
+//	@Description	Exactly one of `presentationJwt` or `presentation` must be present.
+//	@Tags			PresentationAPI
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		VerifyPresentationRequest	true	"request body"
+//	@Success		200		{object}	VerifyPresentationResponse
+//	@Failure		400		{string}	string	"Bad request"
+//	@Router			/v1/presentations/verification [put]
+func (pr PresentationRouter) VerifyPresentation(c *gin.Context) {
+	var request VerifyPresentationRequest
+	if err := framework.Decode(c.Request, &request); err != nil {
+		errMsg := "invalid verify presentation request"
+		framework.LoggingRespondErrWithMsg(c, err, errMsg, http.StatusBadRequest)
+		return
+	}
+
+	req := request.toServiceRequest()
+	if !req.IsValid() {
+		errMsg := "request must contain either a Data Integrity Presentation or a JWT Presentation"
+		framework.LoggingRespondErrMsg(c, errMsg, http.StatusBadRequest)
+		return
+	}
+
+	verificationResult, err := pr.service.VerifyPresentation(c, req)
+	if err != nil {
+		errMsg := "could not verify presentation"
+		framework.LoggingRespondErrWithMsg(c, err, errMsg, http.StatusBadRequest)
+		return
+	}
+
+	resp := VerifyPresentationResponse{
+		Verified:           verificationResult.Verified,
+		Reason:             verificationResult.Reason,
+		Holder:             verificationResult.Holder,
+		HolderVerified:     verificationResult.HolderVerified,
+		DefinitionVerified: verificationResult.DefinitionVerified,
+		Credentials:        verificationResult.Credentials,
+	}
+	framework.Respond(c, resp, http.StatusOK)
+}
+
 type CreateRequestRequest struct {
 	// Audience as defined in https://www.rfc-editor.org/rfc/rfc7519.html#section-4.1.3
 	// Optional
@@ -600,3 +739,169 @@ func (pr PresentationRouter) DeleteRequest(c *gin.Context) {
 
 	framework.Respond(c, nil, http.StatusNoContent)
 }
+
+type CreateAuthorizationRequestRequest struct {
+	// DID of the verifier. It's the request's `client_id`, and wallets must set it as the `aud` of the `vp_token`.
+	VerifierDID string `json:"verifierDid" validate:"required"`
+
+	// ID of the key in the keystore used to sign the request object. Its controller must be `verifierDid`.
+	VerifierKID string `json:"verifierKid" validate:"required"`
+
+	// ID of the presentation definition to use for this request.
+	PresentationDefinitionID string `json:"presentationDefinitionId" validate:"required"`
+
+	// Expiration as defined in https://www.rfc-editor.org/rfc/rfc7519.html#section-4.1.4
+	// Optional. When not specified, the request will be valid for a default duration.
+	Expiration string `json:"expiration,omitempty"`
+
+	// When true, the authorization request references the request object via `request_uri`. Otherwise, the signed
+	// request object is passed by value in the `request` parameter.
+	ByReference bool `json:"byReference,omitempty"`
+}
+
+func (r CreateAuthorizationRequestRequest) toServiceRequest(defaultExpiration time.Duration) (*model.CreateAuthorizationRequestRequest, error) {
+	expiration := time.Now().Add(defaultExpiration)
+	if r.Expiration != "" {
+		var err error
+		expiration, err = time.Parse(time.RFC3339, r.Expiration)
+		if err != nil {
+			return nil, errors.Wrap(err, "parsing expiration")
+		}
+	}
+	return &model.CreateAuthorizationRequestRequest{
+		VerifierDID:              r.VerifierDID,
+		VerifierKID:              r.VerifierKID,
+		PresentationDefinitionID: r.PresentationDefinitionID,
+		Expiration:               expiration,
+		ByReference:              r.ByReference,
+	}, nil
+}
+
+type CreateAuthorizationRequestResponse struct {
+	AuthorizationRequest *model.AuthorizationRequest `json:"authorizationRequest"`
+}
+
+// CreateAuthorizationRequest godoc
+//
+//	@Summary		Create OpenID for Verifiable Presentations Authorization Request
+//	@Description	Create an authorization request, as defined in https://openid.net/specs/openid-4-verifiable-presentations-1_0.html,
+//	@Description	from an existing presentation definition. The request object is signed with the verifier's key, and
+//	@Description	wallets post their response to `/v1/presentations/oid4vp/responses`.
+//	@Tags			PresentationRequestAPI
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		CreateAuthorizationRequestRequest	true	"request body"
+//	@Success		201		{object}	CreateAuthorizationRequestResponse
+//	@Failure		400		{string}	string	"Bad request"
+//	@Failure		500		{string}	string	"Internal server error"
+//	@Router			/v1/presentations/oid4vp/requests [put]
+func (pr PresentationRouter) CreateAuthorizationRequest(c *gin.Context) {
+	var request CreateAuthorizationRequestRequest
+	errMsg := "invalid create authorization request request"
+	if err := framework.Decode(c.Request, &request); err != nil {
+		framework.LoggingRespondErrWithMsg(c, err, errMsg, http.StatusBadRequest)
+		return
+	}
+	if err := framework.ValidateRequest(request); err != nil {
+		framework.LoggingRespondErrWithMsg(c, err, errMsg, http.StatusBadRequest)
+		return
+	}
+
+	req, err := request.toServiceRequest(pr.service.Config().ExpirationDuration)
+	if err != nil {
+		framework.LoggingRespondErrWithMsg(c, err, errMsg, http.StatusBadRequest)
+		return
+	}
+
+	authorizationRequest, err := pr.service.CreateAuthorizationRequest(c, *req)
+	if err != nil {
+		framework.LoggingRespondErrWithMsg(c, err, "could not create authorization request", http.StatusInternalServerError)
+		return
+	}
+	framework.Respond(c, CreateAuthorizationRequestResponse{AuthorizationRequest: authorizationRequest}, http.StatusCreated)
+}
+
+// oauthAuthzReqJWTMediaType is the media type of signed request objects, as defined in
+// https://www.rfc-editor.org/rfc/rfc9101.html#section-10.2.1
+const oauthAuthzReqJWTMediaType = "application/oauth-authz-req+jwt"
+
+// GetRequestObject godoc
+//
+//	@Summary		Get Request Object
+//	@Description	Get the signed request object of an authorization request. This is the `request_uri` of requests
+//	@Description	created by reference. Requests that expired or were already answered are gone.
+//	@Tags			PresentationRequestAPI
+//	@Produce		application/oauth-authz-req+jwt
+//	@Param			id	path		string	true	"ID"
+//	@Success		200	{string}	string	"The signed request object"
+//	@Failure		400	{string}	string	"Bad request"
+//	@Failure		404	{string}	string	"Not found"
+//	@Failure		410	{string}	string	"The request has expired or already been answered"
+//	@Router			/v1/presentations/oid4vp/requests/{id} [get]
+func (pr PresentationRouter) GetRequestObject(c *gin.Context) {
+	id := framework.GetParam(c, IDParam)
+	if id == nil {
+		framework.LoggingRespondErrMsg(c, "cannot get request object without an ID", http.StatusBadRequest)
+		return
+	}
+
+	requestObject, err := pr.service.GetRequestObject(c, *id)
+	if err != nil {
+		status := http.StatusNotFound
+		if errors.Is(err, presentationstorage.ErrRequestAlreadyUsed) || errors.Is(err, presentationstorage.ErrRequestExpired) {
+			status = http.StatusGone
+		}
+		framework.LoggingRespondErrWithMsg(c, err, "could not get request object", status)
+		return
+	}
+	c.Data(http.StatusOK, oauthAuthzReqJWTMediaType, []byte(*requestObject))
+}
+
+// DirectPost godoc
+//
+//	@Summary		Authorization Response
+//	@Description	Response endpoint of OpenID for Verifiable Presentations authorization requests, which wallets post
+//	@Description	to with `response_mode=direct_post`. The `vp_token` is either a presentation JWT or a JSON encoded
+//	@Description	data integrity presentation, and `state` is the ID of the authorization request. The presentation
+//	@Description	is stored as a submission ready to be reviewed.
+//	@Tags			PresentationSubmissionAPI
+//	@Accept			x-www-form-urlencoded
+//	@Produce		json
+//	@Param			vp_token				formData	string		true	"The presentation"
+//	@Param			presentation_submission	formData	string		true	"JSON encoded presentation submission"
+//	@Param			state					formData	string		true	"ID of the authorization request"
+//	@Success		200						{object}	Operation	"The type of response is Submission once the operation has finished."
+//	@Failure		400						{string}	string		"Bad request"
+//	@Failure		500						{string}	string		"Internal server error"
+//	@Router			/v1/presentations/oid4vp/responses [post]
+func (pr PresentationRouter) DirectPost(c *gin.Context) {
+	errMsg := "invalid authorization response"
+	if err := c.Request.ParseForm(); err != nil {
+		framework.LoggingRespondErrWithMsg(c, err, errMsg, http.StatusBadRequest)
+		return
+	}
+
+	var submission exchange.PresentationSubmission
+	if err := json.Unmarshal([]byte(c.Request.PostForm.Get("presentation_submission")), &submission); err != nil {
+		framework.LoggingRespondErrWithMsg(c, err, "invalid presentation_submission", http.StatusBadRequest)
+		return
+	}
+	request := model.DirectPostRequest{
+		VPToken:                c.Request.PostForm.Get("vp_token"),
+		PresentationSubmission: submission,
+		State:                  c.Request.PostForm.Get("state"),
+	}
+	if err := request.IsValid(); err != nil {
+		framework.LoggingRespondErrWithMsg(c, err, errMsg, http.StatusBadRequest)
+		return
+	}
+
+	operation, err := pr.service.CreateDirectPostSubmission(c, request)
+	if err != nil {
+		framework.LoggingRespondErrWithMsg(c, err, "cannot create submission", http.StatusInternalServerError)
+		return
+	}
+
+	resp := Operation{ID: operation.ID}
+	framework.Respond(c, resp, http.StatusOK)
+}
@@ -0,0 +1,276 @@
+package router
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+
+	"github.com/tbd54566975/ssi-service/pkg/server/framework"
+	svcframework "github.com/tbd54566975/ssi-service/pkg/service/framework"
+	"github.com/tbd54566975/ssi-service/pkg/service/trust"
+)
+
+type TrustRouter struct {
+	service *trust.Service
+}
+
+func NewTrustRouter(s svcframework.Service) (*TrustRouter, error) {
+	if s == nil {
+		return nil, errors.New("service cannot be nil")
+	}
+	trustService, ok := s.(*trust.Service)
+	if !ok {
+		return nil, fmt.Errorf("could not create trust router with service type: %s", s.Type())
+	}
+	return &TrustRouter{service: trustService}, nil
+}
+
+type CreateTrustedIssuerRequest struct {
+	trust.TrustedIssuer
+}
+
+func (r CreateTrustedIssuerRequest) toServiceRequest() trust.CreateTrustedIssuerRequest {
+	return trust.CreateTrustedIssuerRequest{TrustedIssuer: r.TrustedIssuer}
+}
+
+type CreateTrustedIssuerResponse struct {
+	trust.TrustedIssuer
+}
+
+// CreateTrustedIssuer godoc
+//
+//	@Summary		Create Trusted Issuer
+//	@Description	Add an issuer to the trust registry, scoped to a set of schemas or credential types and an optional
+//	@Description	validity window.
+//	@Tags			TrustAPI
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		CreateTrustedIssuerRequest	true	"request body"
+//	@Success		201		{object}	CreateTrustedIssuerResponse
+//	@Failure		400		{string}	string	"Bad request"
+//	@Failure		500		{string}	string	"Internal server error"
+//	@Router			/v1/trust [put]
+func (tr TrustRouter) CreateTrustedIssuer(c *gin.Context) {
+	var request CreateTrustedIssuerRequest
+	invalidCreateTrustedIssuerRequest := "invalid create trusted issuer request"
+	if err := framework.Decode(c.Request, &request); err != nil {
+		framework.LoggingRespondErrWithMsg(c, err, invalidCreateTrustedIssuerRequest, http.StatusBadRequest)
+		return
+	}
+
+	if err := request.TrustedIssuer.IsValid(); err != nil {
+		framework.LoggingRespondErrWithMsg(c, err, invalidCreateTrustedIssuerRequest, http.StatusBadRequest)
+		return
+	}
+
+	created, err := tr.service.CreateTrustedIssuer(c, request.toServiceRequest())
+	if err != nil {
+		errMsg := fmt.Sprintf("could not create trusted issuer for issuer<%s>", request.IssuerDID)
+		framework.LoggingRespondErrWithMsg(c, err, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	resp := CreateTrustedIssuerResponse{TrustedIssuer: created.TrustedIssuer}
+	framework.Respond(c, resp, http.StatusCreated)
+}
+
+type GetTrustedIssuerResponse struct {
+	trust.TrustedIssuer
+}
+
+// GetTrustedIssuer godoc
+//
+//	@Summary		Get Trusted Issuer
+//	@Description	Get a trust registry entry by its ID
+//	@Tags			TrustAPI
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string	true	"ID"
+//	@Success		200	{object}	GetTrustedIssuerResponse
+//	@Failure		400	{string}	string	"Bad request"
+//	@Failure		404	{string}	string	"Not found"
+//	@Router			/v1/trust/{id} [get]
+func (tr TrustRouter) GetTrustedIssuer(c *gin.Context) {
+	id := framework.GetParam(c, IDParam)
+	if id == nil {
+		errMsg := "cannot get trusted issuer without ID parameter"
+		framework.LoggingRespondErrMsg(c, errMsg, http.StatusBadRequest)
+		return
+	}
+
+	gotIssuer, err := tr.service.GetTrustedIssuer(c, trust.GetTrustedIssuerRequest{ID: *id})
+	if err != nil {
+		errMsg := fmt.Sprintf("could not get trusted issuer with id: %s", *id)
+		framework.LoggingRespondErrWithMsg(c, err, errMsg, http.StatusNotFound)
+		return
+	}
+
+	resp := GetTrustedIssuerResponse{TrustedIssuer: gotIssuer.TrustedIssuer}
+	framework.Respond(c, resp, http.StatusOK)
+}
+
+type UpdateTrustedIssuerRequest struct {
+	trust.TrustedIssuer
+}
+
+type UpdateTrustedIssuerResponse struct {
+	trust.TrustedIssuer
+}
+
+// UpdateTrustedIssuer godoc
+//
+//	@Summary		Update Trusted Issuer
+//	@Description	Replace the issuer, scope and validity window of a trust registry entry
+//	@Tags			TrustAPI
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string						true	"ID"
+//	@Param			request	body		UpdateTrustedIssuerRequest	true	"request body"
+//	@Success		200		{object}	UpdateTrustedIssuerResponse
+//	@Failure		400		{string}	string	"Bad request"
+//	@Failure		500		{string}	string	"Internal server error"
+//	@Router			/v1/trust/{id} [put]
+func (tr TrustRouter) UpdateTrustedIssuer(c *gin.Context) {
+	id := framework.GetParam(c, IDParam)
+	if id == nil {
+		errMsg := "cannot update trusted issuer without ID parameter"
+		framework.LoggingRespondErrMsg(c, errMsg, http.StatusBadRequest)
+		return
+	}
+
+	var request UpdateTrustedIssuerRequest
+	invalidUpdateTrustedIssuerRequest := "invalid update trusted issuer request"
+	if err := framework.Decode(c.Request, &request); err != nil {
+		framework.LoggingRespondErrWithMsg(c, err, invalidUpdateTrustedIssuerRequest, http.StatusBadRequest)
+		return
+	}
+
+	if err := request.TrustedIssuer.IsValid(); err != nil {
+		framework.LoggingRespondErrWithMsg(c, err, invalidUpdateTrustedIssuerRequest, http.StatusBadRequest)
+		return
+	}
+
+	updated, err := tr.service.UpdateTrustedIssuer(c, trust.UpdateTrustedIssuerRequest{ID: *id, TrustedIssuer: request.TrustedIssuer})
+	if err != nil {
+		errMsg := fmt.Sprintf("could not update trusted issuer with id: %s", *id)
+		framework.LoggingRespondErrWithMsg(c, err, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	resp := UpdateTrustedIssuerResponse{TrustedIssuer: updated.TrustedIssuer}
+	framework.Respond(c, resp, http.StatusOK)
+}
+
+type ListTrustedIssuersResponse struct {
+	TrustedIssuers []trust.TrustedIssuer `json:"trustedIssuers"`
+}
+
+// ListTrustedIssuers godoc
+//
+//	@Summary		List Trusted Issuers
+//	@Description	List the entries of the trust registry, optionally filtered by issuer DID
+//	@Tags			TrustAPI
+//	@Accept			json
+//	@Produce		json
+//	@Param			issuer	query		string	false	"string issuer"
+//	@Success		200		{object}	ListTrustedIssuersResponse
+//	@Failure		500		{string}	string	"Internal server error"
+//	@Router			/v1/trust [get]
+func (tr TrustRouter) ListTrustedIssuers(c *gin.Context) {
+	var request trust.ListTrustedIssuersRequest
+	if issuer := framework.GetQueryValue(c, IssuerParam); issuer != nil {
+		request.IssuerDID = *issuer
+	}
+
+	gotIssuers, err := tr.service.ListTrustedIssuers(c, request)
+	if err != nil {
+		errMsg := "could not list trusted issuers"
+		framework.LoggingRespondErrWithMsg(c, err, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	resp := ListTrustedIssuersResponse{TrustedIssuers: gotIssuers.TrustedIssuers}
+	framework.Respond(c, resp, http.StatusOK)
+}
+
+// DeleteTrustedIssuer godoc
+//
+//	@Summary		Delete Trusted Issuer
+//	@Description	Remove an entry from the trust registry
+//	@Tags			TrustAPI
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string	true	"ID"
+//	@Success		204	{string}	string	"No Content"
+//	@Failure		400	{string}	string	"Bad request"
+//	@Failure		500	{string}	string	"Internal server error"
+//	@Router			/v1/trust/{id} [delete]
+func (tr TrustRouter) DeleteTrustedIssuer(c *gin.Context) {
+	id := framework.GetParam(c, IDParam)
+	if id == nil {
+		errMsg := "cannot delete trusted issuer without ID parameter"
+		framework.LoggingRespondErrMsg(c, errMsg, http.StatusBadRequest)
+		return
+	}
+
+	if err := tr.service.DeleteTrustedIssuer(c, trust.DeleteTrustedIssuerRequest{ID: *id}); err != nil {
+		errMsg := fmt.Sprintf("could not delete trusted issuer with id: %s", *id)
+		framework.LoggingRespondErrWithMsg(c, err, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	framework.Respond(c, nil, http.StatusNoContent)
+}
+
+type VerifyIssuerTrustRequest struct {
+	IssuerDID       string   `json:"issuerDid" validate:"required"`
+	SchemaID        string   `json:"schemaId,omitempty"`
+	CredentialTypes []string `json:"credentialTypes,omitempty"`
+}
+
+type VerifyIssuerTrustResponse struct {
+	Trusted bool   `json:"trusted"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// VerifyIssuerTrust godoc
+//
+//	@Summary		Verify Issuer Trust
+//	@Description	Checks whether the trust registry currently trusts an issuer for a schema or set of credential types
+//	@Tags			TrustAPI
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		VerifyIssuerTrustRequest	true	"request body"
+//	@Success		200		{object}	VerifyIssuerTrustResponse
+//	@Failure		400		{string}	string	"Bad request"
+//	@Failure		500		{string}	string	"Internal server error"
+//	@Router			/v1/trust/verification [put]
+func (tr TrustRouter) VerifyIssuerTrust(c *gin.Context) {
+	var request VerifyIssuerTrustRequest
+	invalidVerifyIssuerTrustRequest := "invalid verify issuer trust request"
+	if err := framework.Decode(c.Request, &request); err != nil {
+		framework.LoggingRespondErrWithMsg(c, err, invalidVerifyIssuerTrustRequest, http.StatusBadRequest)
+		return
+	}
+
+	if err := framework.ValidateRequest(request); err != nil {
+		framework.LoggingRespondErrWithMsg(c, err, invalidVerifyIssuerTrustRequest, http.StatusBadRequest)
+		return
+	}
+
+	result, err := tr.service.VerifyIssuerTrust(c, trust.VerifyIssuerTrustRequest{
+		IssuerDID:       request.IssuerDID,
+		SchemaID:        request.SchemaID,
+		CredentialTypes: request.CredentialTypes,
+	})
+	if err != nil {
+		errMsg := "could not verify issuer trust"
+		framework.LoggingRespondErrWithMsg(c, err, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	resp := VerifyIssuerTrustResponse{Trusted: result.Trusted, Reason: result.Reason}
+	framework.Respond(c, resp, http.StatusOK)
+}
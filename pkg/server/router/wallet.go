@@ -0,0 +1,410 @@
+package router
+
+import (
+	"fmt"
+	"net/http"
+
+	credsdk "github.com/TBD54566975/ssi-sdk/credential"
+	"github.com/TBD54566975/ssi-sdk/credential/exchange"
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+
+	"github.com/tbd54566975/ssi-service/internal/keyaccess"
+	"github.com/tbd54566975/ssi-service/pkg/server/framework"
+	svcframework "github.com/tbd54566975/ssi-service/pkg/service/framework"
+	"github.com/tbd54566975/ssi-service/pkg/service/wallet"
+)
+
+const (
+	HolderParam string = "holder"
+	TypeParam   string = "type"
+)
+
+type WalletRouter struct {
+	service *wallet.Service
+}
+
+func NewWalletRouter(s svcframework.Service) (*WalletRouter, error) {
+	if s == nil {
+		return nil, errors.New("service cannot be nil")
+	}
+	walletService, ok := s.(*wallet.Service)
+	if !ok {
+		return nil, fmt.Errorf("could not create wallet router with service type: %s", s.Type())
+	}
+	return &WalletRouter{service: walletService}, nil
+}
+
+type StoreHeldCredentialRequest struct {
+	// DID of the holder. Must be a DID managed by this service.
+	HolderDID string `json:"holderDid" validate:"required"`
+
+	// A credential secured via data integrity. Must have the "proof" property set.
+	DataIntegrityCredential *credsdk.VerifiableCredential `json:"credential,omitempty"`
+
+	// A JWT that encodes a credential.
+	CredentialJWT *keyaccess.JWT `json:"credentialJwt,omitempty"`
+}
+
+func (r StoreHeldCredentialRequest) toServiceRequest() wallet.StoreCredentialRequest {
+	return wallet.StoreCredentialRequest{
+		HolderDID:               r.HolderDID,
+		DataIntegrityCredential: r.DataIntegrityCredential,
+		CredentialJWT:           r.CredentialJWT,
+	}
+}
+
+type StoreHeldCredentialResponse struct {
+	wallet.HeldCredential
+}
+
+// StoreCredential godoc
+//
+//	@Summary		Store Held Credential
+//	@Description	Verifies a credential issued to a DID managed by this service and stores it in that DID's wallet.
+//	@Description	Exactly one of `credential` or `credentialJwt` must be present.
+//	@Tags			WalletAPI
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		StoreHeldCredentialRequest	true	"request body"
+//	@Success		201		{object}	StoreHeldCredentialResponse
+//	@Failure		400		{string}	string	"Bad request"
+//	@Failure		500		{string}	string	"Internal server error"
+//	@Router			/v1/wallet/credentials [put]
+func (wr WalletRouter) StoreCredential(c *gin.Context) {
+	var request StoreHeldCredentialRequest
+	invalidStoreCredentialRequest := "invalid store held credential request"
+	if err := framework.Decode(c.Request, &request); err != nil {
+		framework.LoggingRespondErrWithMsg(c, err, invalidStoreCredentialRequest, http.StatusBadRequest)
+		return
+	}
+
+	req := request.toServiceRequest()
+	if err := req.IsValid(); err != nil {
+		framework.LoggingRespondErrWithMsg(c, err, invalidStoreCredentialRequest, http.StatusBadRequest)
+		return
+	}
+
+	stored, err := wr.service.StoreCredential(c, req)
+	if err != nil {
+		errMsg := fmt.Sprintf("could not store credential for holder<%s>", request.HolderDID)
+		framework.LoggingRespondErrWithMsg(c, err, errMsg, http.StatusBadRequest)
+		return
+	}
+
+	framework.Respond(c, StoreHeldCredentialResponse{HeldCredential: stored.HeldCredential}, http.StatusCreated)
+}
+
+type GetHeldCredentialResponse struct {
+	wallet.HeldCredential
+}
+
+// GetCredential godoc
+//
+//	@Summary		Get Held Credential
+//	@Description	Get a held credential by its wallet ID
+//	@Tags			WalletAPI
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string	true	"ID"
+//	@Success		200	{object}	GetHeldCredentialResponse
+//	@Failure		400	{string}	string	"Bad request"
+//	@Failure		404	{string}	string	"Not found"
+//	@Router			/v1/wallet/credentials/{id} [get]
+func (wr WalletRouter) GetCredential(c *gin.Context) {
+	id := framework.GetParam(c, IDParam)
+	if id == nil {
+		errMsg := "cannot get held credential without ID parameter"
+		framework.LoggingRespondErrMsg(c, errMsg, http.StatusBadRequest)
+		return
+	}
+
+	gotCred, err := wr.service.GetCredential(c, wallet.GetCredentialRequest{ID: *id})
+	if err != nil {
+		errMsg := fmt.Sprintf("could not get held credential with id: %s", *id)
+		framework.LoggingRespondErrWithMsg(c, err, errMsg, http.StatusNotFound)
+		return
+	}
+
+	framework.Respond(c, GetHeldCredentialResponse{HeldCredential: gotCred.HeldCredential}, http.StatusOK)
+}
+
+type ListHeldCredentialsResponse struct {
+	Credentials []wallet.HeldCredential `json:"credentials"`
+}
+
+// ListCredentials godoc
+//
+//	@Summary		List Held Credentials
+//	@Description	List held credentials, optionally filtered by holder, issuer, schema and credential type
+//	@Tags			WalletAPI
+//	@Accept			json
+//	@Produce		json
+//	@Param			holder	query		string	false	"string holder"
+//	@Param			issuer	query		string	false	"string issuer"
+//	@Param			schema	query		string	false	"string schema"
+//	@Param			type	query		string	false	"string type"
+//	@Success		200		{object}	ListHeldCredentialsResponse
+//	@Failure		500		{string}	string	"Internal server error"
+//	@Router			/v1/wallet/credentials [get]
+func (wr WalletRouter) ListCredentials(c *gin.Context) {
+	var request wallet.ListCredentialsRequest
+	if holder := framework.GetQueryValue(c, HolderParam); holder != nil {
+		request.HolderDID = *holder
+	}
+	if issuer := framework.GetQueryValue(c, IssuerParam); issuer != nil {
+		request.Issuer = *issuer
+	}
+	if schema := framework.GetQueryValue(c, SchemaParam); schema != nil {
+		request.Schema = *schema
+	}
+	if credType := framework.GetQueryValue(c, TypeParam); credType != nil {
+		request.Type = *credType
+	}
+
+	gotCreds, err := wr.service.ListCredentials(c, request)
+	if err != nil {
+		errMsg := "could not list held credentials"
+		framework.LoggingRespondErrWithMsg(c, err, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	framework.Respond(c, ListHeldCredentialsResponse{Credentials: gotCreds.Credentials}, http.StatusOK)
+}
+
+// DeleteCredential godoc
+//
+//	@Summary		Delete Held Credential
+//	@Description	Remove a credential from the wallet
+//	@Tags			WalletAPI
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string	true	"ID"
+//	@Success		204	{string}	string	"No Content"
+//	@Failure		400	{string}	string	"Bad request"
+//	@Failure		500	{string}	string	"Internal server error"
+//	@Router			/v1/wallet/credentials/{id} [delete]
+func (wr WalletRouter) DeleteCredential(c *gin.Context) {
+	id := framework.GetParam(c, IDParam)
+	if id == nil {
+		errMsg := "cannot delete held credential without ID parameter"
+		framework.LoggingRespondErrMsg(c, errMsg, http.StatusBadRequest)
+		return
+	}
+
+	if err := wr.service.DeleteCredential(c, wallet.DeleteCredentialRequest{ID: *id}); err != nil {
+		errMsg := fmt.Sprintf("could not delete held credential with id: %s", *id)
+		framework.LoggingRespondErrWithMsg(c, err, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	framework.Respond(c, nil, http.StatusNoContent)
+}
+
+type StoreCredentialResponseRequest struct {
+	// DID of the holder. Must be a DID managed by this service.
+	HolderDID string `json:"holderDid" validate:"required"`
+
+	// The signed credential response, as returned by an issuer's `responseJwt`.
+	ResponseJWT keyaccess.JWT `json:"responseJwt" validate:"required"`
+}
+
+type StoreCredentialResponseResponse struct {
+	ResponseID  string                  `json:"responseId"`
+	Credentials []wallet.HeldCredential `json:"credentials"`
+}
+
+// StoreCredentialResponse godoc
+//
+//	@Summary		Store Credential Response
+//	@Description	Accepts a credential response from an issuer on behalf of a holder. The response's signature and each
+//	@Description	fulfilled credential are verified before the credentials are stored in the holder's wallet.
+//	@Tags			WalletAPI
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		StoreCredentialResponseRequest	true	"request body"
+//	@Success		201		{object}	StoreCredentialResponseResponse
+//	@Failure		400		{string}	string	"Bad request"
+//	@Router			/v1/wallet/responses [put]
+func (wr WalletRouter) StoreCredentialResponse(c *gin.Context) {
+	var request StoreCredentialResponseRequest
+	invalidStoreResponseRequest := "invalid store credential response request"
+	if err := framework.Decode(c.Request, &request); err != nil {
+		framework.LoggingRespondErrWithMsg(c, err, invalidStoreResponseRequest, http.StatusBadRequest)
+		return
+	}
+
+	if err := framework.ValidateRequest(request); err != nil {
+		framework.LoggingRespondErrWithMsg(c, err, invalidStoreResponseRequest, http.StatusBadRequest)
+		return
+	}
+
+	stored, err := wr.service.StoreCredentialResponse(c, wallet.StoreCredentialResponseRequest{
+		HolderDID:   request.HolderDID,
+		ResponseJWT: request.ResponseJWT,
+	})
+	if err != nil {
+		errMsg := fmt.Sprintf("could not store credential response for holder<%s>", request.HolderDID)
+		framework.LoggingRespondErrWithMsg(c, err, errMsg, http.StatusBadRequest)
+		return
+	}
+
+	resp := StoreCredentialResponseResponse{ResponseID: stored.ResponseID, Credentials: stored.Credentials}
+	framework.Respond(c, resp, http.StatusCreated)
+}
+
+type CreateHolderPresentationRequest struct {
+	// DID of the holder. Must be the controller of `holderKid`.
+	HolderDID string `json:"holderDid" validate:"required"`
+
+	// ID of the key in the keystore used to sign the presentation.
+	HolderKID string `json:"holderKid" validate:"required"`
+
+	// Wallet IDs of the held credentials to present.
+	CredentialIDs []string `json:"credentialIds" validate:"required,min=1"`
+
+	// Audience of the presentation.
+	Audience string `json:"audience,omitempty"`
+}
+
+func (r CreateHolderPresentationRequest) toServiceRequest() wallet.CreatePresentationRequest {
+	return wallet.CreatePresentationRequest{
+		HolderDID:     r.HolderDID,
+		HolderKID:     r.HolderKID,
+		CredentialIDs: r.CredentialIDs,
+		Audience:      r.Audience,
+	}
+}
+
+type CreateHolderPresentationResponse struct {
+	Presentation    credsdk.VerifiablePresentation `json:"presentation"`
+	PresentationJWT keyaccess.JWT                  `json:"presentationJwt"`
+}
+
+// CreatePresentation godoc
+//
+//	@Summary		Create Presentation
+//	@Description	Builds a verifiable presentation from held credentials and signs it with the holder's key
+//	@Tags			WalletAPI
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		CreateHolderPresentationRequest	true	"request body"
+//	@Success		201		{object}	CreateHolderPresentationResponse
+//	@Failure		400		{string}	string	"Bad request"
+//	@Failure		500		{string}	string	"Internal server error"
+//	@Router			/v1/wallet/presentations [put]
+func (wr WalletRouter) CreatePresentation(c *gin.Context) {
+	var request CreateHolderPresentationRequest
+	invalidCreatePresentationRequest := "invalid create presentation request"
+	if err := framework.Decode(c.Request, &request); err != nil {
+		framework.LoggingRespondErrWithMsg(c, err, invalidCreatePresentationRequest, http.StatusBadRequest)
+		return
+	}
+
+	if err := framework.ValidateRequest(request); err != nil {
+		framework.LoggingRespondErrWithMsg(c, err, invalidCreatePresentationRequest, http.StatusBadRequest)
+		return
+	}
+
+	created, err := wr.service.CreatePresentation(c, request.toServiceRequest())
+	if err != nil {
+		errMsg := fmt.Sprintf("could not create presentation for holder<%s>", request.HolderDID)
+		framework.LoggingRespondErrWithMsg(c, err, errMsg, http.StatusInternalServerError)
+		return
+	}
+
+	resp := CreateHolderPresentationResponse{Presentation: created.Presentation, PresentationJWT: created.PresentationJWT}
+	framework.Respond(c, resp, http.StatusCreated)
+}
+
+type CreateHolderSubmissionRequest struct {
+	// DID of the holder. Must be the controller of `holderKid`.
+	HolderDID string `json:"holderDid" validate:"required"`
+
+	// ID of the key in the keystore used to sign the presentation.
+	HolderKID string `json:"holderKid" validate:"required"`
+
+	// ID of a presentation definition stored in this service. Exactly one of `presentationDefinitionId` or
+	// `presentationRequestJwt` must be present.
+	PresentationDefinitionID string `json:"presentationDefinitionId,omitempty"`
+
+	// A presentation request JWT received from a verifier.
+	PresentationRequestJWT *keyaccess.JWT `json:"presentationRequestJwt,omitempty"`
+
+	// Audience of the presentation. Defaults to the issuer of `presentationRequestJwt` when one is given.
+	Audience string `json:"audience,omitempty"`
+
+	// When present, the signed submission is sent to this URL as the body of a create submission request.
+	SubmitURL string `json:"submitUrl,omitempty"`
+}
+
+func (r CreateHolderSubmissionRequest) toServiceRequest() wallet.CreatePresentationSubmissionRequest {
+	return wallet.CreatePresentationSubmissionRequest{
+		HolderDID:                r.HolderDID,
+		HolderKID:                r.HolderKID,
+		PresentationDefinitionID: r.PresentationDefinitionID,
+		PresentationRequestJWT:   r.PresentationRequestJWT,
+		Audience:                 r.Audience,
+		SubmitURL:                r.SubmitURL,
+	}
+}
+
+type CreateHolderSubmissionResponse struct {
+	PresentationSubmission exchange.PresentationSubmission `json:"presentationSubmission"`
+	Presentation           credsdk.VerifiablePresentation  `json:"presentation"`
+
+	// The presentation signed as a JWT. Can be used as the `submissionJwt` of a create submission request.
+	SubmissionJWT keyaccess.JWT `json:"submissionJwt"`
+
+	// ID of the presentation request the submission answers, when `presentationRequestJwt` was given. Must be sent
+	// as the `presentationRequestId` of the create submission request.
+	PresentationRequestID string `json:"presentationRequestId,omitempty"`
+
+	// What the verifier answered, when `submitUrl` was given.
+	SubmitResult *wallet.SubmitResult `json:"submitResult,omitempty"`
+}
+
+// CreateSubmission godoc
+//
+//	@Summary		Create Presentation Submission
+//	@Description	Selects credentials from the holder's wallet that fulfill a presentation definition, and builds a
+//	@Description	presentation submission signed by the holder. The definition is either one stored in this service or
+//	@Description	the one in a verifier's presentation request. When `submitUrl` is given, the submission is sent there.
+//	@Tags			WalletAPI
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		CreateHolderSubmissionRequest	true	"request body"
+//	@Success		201		{object}	CreateHolderSubmissionResponse
+//	@Failure		400		{string}	string	"Bad request"
+//	@Failure		500		{string}	string	"Internal server error"
+//	@Router			/v1/wallet/submissions [put]
+func (wr WalletRouter) CreateSubmission(c *gin.Context) {
+	var request CreateHolderSubmissionRequest
+	invalidCreateSubmissionRequest := "invalid create presentation submission request"
+	if err := framework.Decode(c.Request, &request); err != nil {
+		framework.LoggingRespondErrWithMsg(c, err, invalidCreateSubmissionRequest, http.StatusBadRequest)
+		return
+	}
+
+	req := request.toServiceRequest()
+	if err := req.IsValid(); err != nil {
+		framework.LoggingRespondErrWithMsg(c, err, invalidCreateSubmissionRequest, http.StatusBadRequest)
+		return
+	}
+
+	created, err := wr.service.CreatePresentationSubmission(c, req)
+	if err != nil {
+		errMsg := fmt.Sprintf("could not create presentation submission for holder<%s>", request.HolderDID)
+		framework.LoggingRespondErrWithMsg(c, err, errMsg, http.StatusBadRequest)
+		return
+	}
+
+	resp := CreateHolderSubmissionResponse{
+		PresentationSubmission: created.PresentationSubmission,
+		Presentation:           created.Presentation,
+		SubmissionJWT:          created.SubmissionJWT,
+		PresentationRequestID:  created.PresentationRequestID,
+		SubmitResult:           created.SubmitResult,
+	}
+	framework.Respond(c, resp, http.StatusCreated)
+}
@@ -3,7 +3,9 @@
 package server
 
 import (
+	"net/http"
 	"os"
+	"strings"
 
 	sdkutil "github.com/TBD54566975/ssi-sdk/util"
 	"github.com/gin-gonic/gin"
@@ -46,6 +48,9 @@ const (
 	KeyStorePrefix         = "/keys"
 	VerificationPath       = "/verification"
 	WebhookPrefix          = "/webhooks"
+	TrustPrefix            = "/trust"
+	WalletPrefix           = "/wallet"
+	OID4VPPrefix           = "/oid4vp"
 )
 
 // SSIServer exposes all dependencies needed to run a http server and all its services
@@ -77,6 +82,11 @@ func NewSSIServer(shutdown chan os.Signal, cfg config.SSIServiceConfig) (*SSISer
 	engine.StaticFile("swagger.yaml", "./doc/swagger.yaml")
 	engine.GET(SwaggerPrefix, ginswagger.WrapHandler(swaggerfiles.Handler, ginswagger.URL("/swagger.yaml")))
 
+	// did:web documents are served at the paths the method requires, outside of the versioned API
+	if err = WebDIDAPI(engine, ssi.DID); err != nil {
+		return nil, sdkutil.LoggingErrorMsg(err, "unable to instantiate did:web API")
+	}
+
 	// register all v1 routers
 	v1 := engine.Group(V1Prefix)
 	if err = DecentralizedIdentityAPI(v1, ssi.DID, ssi.Webhook); err != nil {
@@ -106,6 +116,12 @@ func NewSSIServer(shutdown chan os.Signal, cfg config.SSIServiceConfig) (*SSISer
 	if err = WebhookAPI(v1, ssi.Webhook); err != nil {
 		return nil, sdkutil.LoggingErrorMsg(err, "unable to instantiate Webhook API")
 	}
+	if err = TrustAPI(v1, ssi.Trust); err != nil {
+		return nil, sdkutil.LoggingErrorMsg(err, "unable to instantiate Trust API")
+	}
+	if err = WalletAPI(v1, ssi.Wallet); err != nil {
+		return nil, sdkutil.LoggingErrorMsg(err, "unable to instantiate Wallet API")
+	}
 
 	return &SSIServer{
 		Server:       httpServer,
@@ -153,13 +169,37 @@ func DecentralizedIdentityAPI(rg *gin.RouterGroup, service *didsvc.Service, webh
 	didAPI := rg.Group(DIDsPrefix)
 	didAPI.GET("", didRouter.ListDIDMethods)
 	didAPI.PUT("/:method", middleware.Webhook(webhookService, webhook.DID, webhook.Create), didRouter.CreateDIDByMethod)
+	didAPI.PUT("/:method/import", middleware.Webhook(webhookService, webhook.DID, webhook.Create), didRouter.ImportDIDByMethod)
 	didAPI.GET("/:method", didRouter.ListDIDsByMethod)
 	didAPI.GET("/:method/:id", didRouter.GetDIDByMethod)
+	didAPI.PATCH("/:method/:id", didRouter.UpdateDIDByMethod)
 	didAPI.DELETE("/:method/:id", didRouter.SoftDeleteDIDByMethod)
+	didAPI.POST("/:method/:id/rotate-key", didRouter.RotateDIDKey)
+	didAPI.POST("/ion/:id/recover", didRouter.RecoverIONDID)
+	didAPI.POST("/ion/:id/deactivate", didRouter.DeactivateIONDID)
+	didAPI.GET(ResolverPrefix+"/dereference", didRouter.DereferenceDIDURL)
 	didAPI.GET(ResolverPrefix+"/:id", didRouter.ResolveDID)
 	return
 }
 
+// WebDIDAPI registers the HTTP handlers hosting the documents of did:web DIDs. Documents of DIDs with a path can be
+// at any depth, so GET requests for a did.json that match no other route are served them. Any other request that
+// matches no route gets the default not found response.
+func WebDIDAPI(engine *gin.Engine, service *didsvc.Service) error {
+	didRouter, err := router.NewDIDRouter(service)
+	if err != nil {
+		return sdkutil.LoggingErrorMsg(err, "creating DID router")
+	}
+
+	engine.GET(router.WellKnownDIDPath, didRouter.GetWebDIDDocument)
+	engine.NoRoute(func(c *gin.Context) {
+		if c.Request.Method == http.MethodGet && strings.HasSuffix(c.Request.URL.Path, "/"+router.DIDDocumentFile) {
+			didRouter.GetWebDIDDocument(c)
+		}
+	})
+	return nil
+}
+
 // SchemaAPI registers all HTTP handlers for the Schema Service
 func SchemaAPI(rg *gin.RouterGroup, service svcframework.Service, webhookService *webhook.Service) (err error) {
 	schemaRouter, err := router.NewSchemaRouter(service)
@@ -221,6 +261,14 @@ func PresentationAPI(rg *gin.RouterGroup, service svcframework.Service, webhookS
 	presSubAPI.GET("/:id", presRouter.GetSubmission)
 	presSubAPI.GET("", presRouter.ListSubmissions)
 	presSubAPI.PUT("/:id/review", presRouter.ReviewSubmission)
+
+	presAPI := rg.Group(PresentationsPrefix)
+	presAPI.PUT(VerificationPath, presRouter.VerifyPresentation)
+
+	oid4vpAPI := rg.Group(PresentationsPrefix + OID4VPPrefix)
+	oid4vpAPI.PUT(RequestsPrefix, presRouter.CreateAuthorizationRequest)
+	oid4vpAPI.GET(RequestsPrefix+"/:id", presRouter.GetRequestObject)
+	oid4vpAPI.POST(ResponsesPrefix, middleware.Webhook(webhookService, webhook.Submission, webhook.Create), presRouter.DirectPost)
 	return
 }
 
@@ -313,3 +361,38 @@ func WebhookAPI(rg *gin.RouterGroup, service svcframework.Service) (err error) {
 	webhookAPI.GET("verbs", webhookRouter.GetSupportedVerbs)
 	return
 }
+
+// TrustAPI registers all HTTP handlers for the Trust Service
+func TrustAPI(rg *gin.RouterGroup, service svcframework.Service) (err error) {
+	trustRouter, err := router.NewTrustRouter(service)
+	if err != nil {
+		return sdkutil.LoggingErrorMsg(err, "creating trust router")
+	}
+
+	trustAPI := rg.Group(TrustPrefix)
+	trustAPI.PUT("", trustRouter.CreateTrustedIssuer)
+	trustAPI.GET("", trustRouter.ListTrustedIssuers)
+	trustAPI.PUT(VerificationPath, trustRouter.VerifyIssuerTrust)
+	trustAPI.GET("/:id", trustRouter.GetTrustedIssuer)
+	trustAPI.PUT("/:id", trustRouter.UpdateTrustedIssuer)
+	trustAPI.DELETE("/:id", trustRouter.DeleteTrustedIssuer)
+	return
+}
+
+// WalletAPI registers all HTTP handlers for the Wallet Service
+func WalletAPI(rg *gin.RouterGroup, service svcframework.Service) (err error) {
+	walletRouter, err := router.NewWalletRouter(service)
+	if err != nil {
+		return sdkutil.LoggingErrorMsg(err, "creating wallet router")
+	}
+
+	walletAPI := rg.Group(WalletPrefix)
+	walletAPI.PUT(CredentialsPrefix, walletRouter.StoreCredential)
+	walletAPI.GET(CredentialsPrefix, walletRouter.ListCredentials)
+	walletAPI.GET(CredentialsPrefix+"/:id", walletRouter.GetCredential)
+	walletAPI.DELETE(CredentialsPrefix+"/:id", walletRouter.DeleteCredential)
+	walletAPI.PUT(ResponsesPrefix, walletRouter.StoreCredentialResponse)
+	walletAPI.PUT(PresentationsPrefix, walletRouter.CreatePresentation)
+	walletAPI.PUT(SubmissionsPrefix, walletRouter.CreateSubmission)
+	return
+}
@@ -1,22 +1,35 @@
 package server
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/TBD54566975/ssi-sdk/crypto"
+	"github.com/TBD54566975/ssi-sdk/crypto/jwx"
+	"github.com/TBD54566975/ssi-sdk/cryptosuite"
 	didsdk "github.com/TBD54566975/ssi-sdk/did"
 	"github.com/TBD54566975/ssi-sdk/did/ion"
+	"github.com/TBD54566975/ssi-sdk/did/key"
+	"github.com/TBD54566975/ssi-sdk/did/web"
+	"github.com/gin-gonic/gin"
 	"github.com/goccy/go-json"
+	"github.com/mr-tron/base58"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"gopkg.in/h2non/gock.v1"
 
+	"github.com/tbd54566975/ssi-service/config"
 	"github.com/tbd54566975/ssi-service/internal/util"
 	"github.com/tbd54566975/ssi-service/pkg/server/router"
+	"github.com/tbd54566975/ssi-service/pkg/service/credential"
 	"github.com/tbd54566975/ssi-service/pkg/service/did"
+	"github.com/tbd54566975/ssi-service/pkg/service/keystore"
 )
 
 func TestDIDAPI(t *testing.T) {
@@ -565,4 +578,625 @@ func TestDIDAPI(t *testing.T) {
 		assert.NotEmpty(tt, resolutionResponse.DIDDocument)
 		assert.Equal(tt, "did:key:z6MkiTBz1ymuepAQ4HEHYSF1H8quG5GLVVQR3djdX3mDooWp", resolutionResponse.DIDDocument.ID)
 	})
+
+	t.Run("Test Resolve DID Metadata And Dereference DID URLs", func(tt *testing.T) {
+		bolt := setupTestDB(tt)
+		require.NotEmpty(tt, bolt)
+
+		_, keyStore := testKeyStore(tt, bolt)
+		didService := testDIDService(tt, bolt, keyStore, "web", "key")
+		didRouter, err := router.NewDIDRouter(didService)
+		require.NoError(tt, err)
+		engine := gin.New()
+		require.NoError(tt, DecentralizedIdentityAPI(engine.Group("/v1"), didService, testWebhookService(tt, bolt)))
+
+		created, err := didService.CreateDIDByMethod(context.Background(), did.CreateDIDRequest{
+			Method:  didsdk.WebMethod,
+			KeyType: crypto.Ed25519,
+			Options: did.CreateWebDIDOptions{DIDWebID: "did:web:example.com"},
+		})
+		require.NoError(tt, err)
+		_, err = didService.UpdateDIDByMethod(context.Background(), did.UpdateDIDRequest{
+			Method:        didsdk.WebMethod,
+			ID:            created.DID.ID,
+			ServicesToAdd: []didsdk.Service{{ID: "#files", Type: "LinkedDomains", ServiceEndpoint: "https://example.com/files/"}},
+		})
+		require.NoError(tt, err)
+
+		// managed DIDs are resolved with the metadata of their document
+		params := map[string]string{"id": created.DID.ID}
+		req := httptest.NewRequest(http.MethodGet, "https://ssi-service.com/v1/dids/resolver/"+created.DID.ID, nil)
+		w := httptest.NewRecorder()
+		didRouter.ResolveDID(newRequestContextWithParams(w, req, params))
+		require.Equal(tt, http.StatusOK, w.Code)
+		var resolutionResponse router.ResolveDIDResponse
+		require.NoError(tt, json.NewDecoder(w.Body).Decode(&resolutionResponse))
+		require.NotNil(tt, resolutionResponse.DIDDocumentMetadata)
+		assert.NotEmpty(tt, resolutionResponse.DIDDocumentMetadata.Created)
+		assert.NotEmpty(tt, resolutionResponse.DIDDocumentMetadata.Updated)
+		assert.Equal(tt, "2", resolutionResponse.DIDDocumentMetadata.VersionID)
+		assert.False(tt, resolutionResponse.DIDDocumentMetadata.Deactivated)
+
+		dereference := func(didURL string) (int, router.DereferenceDIDURLResponse) {
+			w := httptest.NewRecorder()
+			target := "https://ssi-service.com/v1/dids/resolver/dereference?" + url.Values{"didUrl": {didURL}}.Encode()
+			engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+			var resp router.DereferenceDIDURLResponse
+			if w.Code == http.StatusOK {
+				require.NoError(tt, json.NewDecoder(w.Body).Decode(&resp))
+			}
+			return w.Code, resp
+		}
+
+		// the document
+		code, resp := dereference(created.DID.ID)
+		require.Equal(tt, http.StatusOK, code)
+		assert.Equal(tt, "application/did+ld+json", resp.DereferencingMetadata.ContentType)
+		assert.Equal(tt, created.DID.ID, resp.ContentStream.(map[string]any)["id"])
+		assert.Equal(tt, "2", resp.ContentMetadata.VersionID)
+
+		// a verification method
+		keyID := created.DID.VerificationMethod[0].ID
+		code, resp = dereference(keyID)
+		require.Equal(tt, http.StatusOK, code)
+		assert.Equal(tt, keyID, resp.ContentStream.(map[string]any)["id"])
+
+		// a service endpoint, with a relative reference
+		code, resp = dereference(created.DID.ID + "?service=files&relativeRef=" + url.QueryEscape("report.pdf"))
+		require.Equal(tt, http.StatusOK, code)
+		assert.Equal(tt, "text/uri-list", resp.DereferencingMetadata.ContentType)
+		assert.Equal(tt, []any{"https://example.com/files/report.pdf"}, resp.ContentStream)
+
+		for _, didURL := range []string{
+			"",
+			"not-a-did",
+			created.DID.ID + "#unknown",
+			created.DID.ID + "?service=unknown",
+			created.DID.ID + "?versionId=1",
+			created.DID.ID + "/path",
+		} {
+			code, _ = dereference(didURL)
+			assert.Equal(tt, http.StatusBadRequest, code, didURL)
+		}
+
+		// soft deleted DIDs are deactivated
+		require.NoError(tt, didService.SoftDeleteDIDByMethod(context.Background(), did.DeleteDIDRequest{Method: didsdk.WebMethod, ID: created.DID.ID}))
+		resolved, err := didService.ResolveDID(did.ResolveDIDRequest{DID: created.DID.ID})
+		require.NoError(tt, err)
+		assert.True(tt, resolved.DIDDocumentMetadata.Deactivated)
+	})
+
+	t.Run("Test Host Web DID Documents", func(tt *testing.T) {
+		bolt := setupTestDB(tt)
+		require.NotEmpty(tt, bolt)
+
+		_, keyStore := testKeyStore(tt, bolt)
+		didService := testDIDService(tt, bolt, keyStore, "web")
+		engine := gin.New()
+		require.NoError(tt, WebDIDAPI(engine, didService))
+
+		// create a DID without a path, and one with a port and a path
+		createdIDs := make([]string, 0, 2)
+		for _, id := range []string{"did:web:example.com", "did:web:localhost%3A8080:users:alice"} {
+			created, err := didService.CreateDIDByMethod(context.Background(), did.CreateDIDRequest{
+				Method:  didsdk.WebMethod,
+				KeyType: crypto.Ed25519,
+				Options: did.CreateWebDIDOptions{DIDWebID: id},
+			})
+			require.NoError(tt, err)
+			createdIDs = append(createdIDs, created.DID.ID)
+		}
+
+		getDocument := func(url string) *httptest.ResponseRecorder {
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, url, nil))
+			return w
+		}
+
+		w := getDocument("https://example.com/.well-known/did.json")
+		assert.Equal(tt, http.StatusOK, w.Code)
+		var gotDoc didsdk.Document
+		require.NoError(tt, json.NewDecoder(w.Body).Decode(&gotDoc))
+		assert.Equal(tt, createdIDs[0], gotDoc.ID)
+		assert.NotEmpty(tt, gotDoc.VerificationMethod)
+
+		w = getDocument("http://localhost:8080/users/alice/did.json")
+		assert.Equal(tt, http.StatusOK, w.Code)
+		require.NoError(tt, json.NewDecoder(w.Body).Decode(&gotDoc))
+		assert.Equal(tt, createdIDs[1], gotDoc.ID)
+
+		// unknown hosts and paths are not found
+		for _, url := range []string{
+			"https://other.com/.well-known/did.json",
+			"https://example.com/users/alice/did.json",
+			"http://localhost:8080/.well-known/did.json",
+			"http://localhost:8080/users/alice",
+		} {
+			assert.Equal(tt, http.StatusNotFound, getDocument(url).Code, url)
+		}
+
+		// requests for anything else that matches no route get the default not found response
+		for _, req := range []*http.Request{
+			httptest.NewRequest(http.MethodGet, "https://example.com/v1/typo", nil),
+			httptest.NewRequest(http.MethodPost, "https://example.com/.well-known/did.json", nil),
+			httptest.NewRequest(http.MethodPut, "http://localhost:8080/users/alice/did.json", nil),
+		} {
+			w = httptest.NewRecorder()
+			engine.ServeHTTP(w, req)
+			assert.Equal(tt, http.StatusNotFound, w.Code, req.URL.String())
+			assert.Equal(tt, "404 page not found", w.Body.String(), req.URL.String())
+		}
+
+		// soft deleted DIDs are deactivated
+		require.NoError(tt, didService.SoftDeleteDIDByMethod(context.Background(), did.DeleteDIDRequest{Method: didsdk.WebMethod, ID: createdIDs[0]}))
+		w = getDocument("https://example.com/.well-known/did.json")
+		assert.Equal(tt, http.StatusGone, w.Code)
+		assert.Contains(tt, w.Body.String(), "DID has been deactivated")
+	})
+
+	t.Run("Test Update DID By Method: Web", func(tt *testing.T) {
+		bolt := setupTestDB(tt)
+		require.NotEmpty(tt, bolt)
+
+		_, keyStore := testKeyStore(tt, bolt)
+		didService := testDIDService(tt, bolt, keyStore, "web", "key")
+		didRouter, err := router.NewDIDRouter(didService)
+		require.NoError(tt, err)
+		engine := gin.New()
+		require.NoError(tt, WebDIDAPI(engine, didService))
+
+		created, err := didService.CreateDIDByMethod(context.Background(), did.CreateDIDRequest{
+			Method:  didsdk.WebMethod,
+			KeyType: crypto.Ed25519,
+			Options: did.CreateWebDIDOptions{DIDWebID: "did:web:example.com"},
+		})
+		require.NoError(tt, err)
+		params := map[string]string{"method": "web", "id": created.DID.ID}
+		updateDIDPath := fmt.Sprintf("https://ssi-service.com/v1/dids/web/%s", created.DID.ID)
+
+		// no changes
+		req := httptest.NewRequest(http.MethodPatch, updateDIDPath, newRequestValue(tt, router.UpdateDIDByMethodRequest{}))
+		w := httptest.NewRecorder()
+		didRouter.UpdateDIDByMethod(newRequestContextWithParams(w, req, params))
+		assert.Equal(tt, http.StatusBadRequest, w.Code)
+		assert.Contains(tt, w.Body.String(), "invalid update DID request: no changes")
+
+		// invalid purpose
+		updateRequest := router.UpdateDIDByMethodRequest{
+			VerificationMethodsToAdd: []did.VerificationMethodToAdd{{KeyType: crypto.Ed25519, Purposes: []ion.PublicKeyPurpose{"signing"}}},
+		}
+		req = httptest.NewRequest(http.MethodPatch, updateDIDPath, newRequestValue(tt, updateRequest))
+		w = httptest.NewRecorder()
+		didRouter.UpdateDIDByMethod(newRequestContextWithParams(w, req, params))
+		assert.Equal(tt, http.StatusBadRequest, w.Code)
+
+		// add a DIDComm service
+		updateRequest = router.UpdateDIDByMethodRequest{
+			ServicesToAdd: []didsdk.Service{{ID: "#didcomm", Type: "DIDCommMessaging", ServiceEndpoint: "https://example.com/didcomm"}},
+		}
+		req = httptest.NewRequest(http.MethodPatch, updateDIDPath, newRequestValue(tt, updateRequest))
+		w = httptest.NewRecorder()
+		didRouter.UpdateDIDByMethod(newRequestContextWithParams(w, req, params))
+		require.Equal(tt, http.StatusOK, w.Code)
+		var resp router.UpdateDIDByMethodResponse
+		require.NoError(tt, json.NewDecoder(w.Body).Decode(&resp))
+		require.Len(tt, resp.DID.Services, 1)
+		assert.Equal(tt, "DIDCommMessaging", resp.DID.Services[0].Type)
+
+		// the hosted document is updated immediately
+		w = httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "https://example.com/.well-known/did.json", nil))
+		require.Equal(tt, http.StatusOK, w.Code)
+		var hostedDoc didsdk.Document
+		require.NoError(tt, json.NewDecoder(w.Body).Decode(&hostedDoc))
+		assert.Equal(tt, resp.DID.Services, hostedDoc.Services)
+
+		// did:key DIDs can't be updated
+		createdKey, err := didService.CreateDIDByMethod(context.Background(), did.CreateDIDRequest{Method: didsdk.KeyMethod, KeyType: crypto.Ed25519})
+		require.NoError(tt, err)
+		req = httptest.NewRequest(http.MethodPatch, "https://ssi-service.com/v1/dids/key/"+createdKey.DID.ID, newRequestValue(tt, updateRequest))
+		w = httptest.NewRecorder()
+		didRouter.UpdateDIDByMethod(newRequestContextWithParams(w, req, map[string]string{"method": "key", "id": createdKey.DID.ID}))
+		assert.Contains(tt, w.Body.String(), "updates are not supported for method<key>")
+	})
+
+	t.Run("Test Rotate DID Key: Web", func(tt *testing.T) {
+		bolt := setupTestDB(tt)
+		require.NotEmpty(tt, bolt)
+
+		_, keyStore := testKeyStore(tt, bolt)
+		didService := testDIDService(tt, bolt, keyStore, "web", "key")
+		didRouter, err := router.NewDIDRouter(didService)
+		require.NoError(tt, err)
+		schemaService := testSchemaService(tt, bolt, keyStore, didService)
+		credentialService := testCredentialService(tt, bolt, keyStore, didService, schemaService)
+
+		created, err := didService.CreateDIDByMethod(context.Background(), did.CreateDIDRequest{
+			Method:  didsdk.WebMethod,
+			KeyType: crypto.Ed25519,
+			Options: did.CreateWebDIDOptions{DIDWebID: "did:web:example.com"},
+		})
+		require.NoError(tt, err)
+		oldKeyID := created.DID.VerificationMethod[0].ID
+
+		// credentials without an issuer kid are signed with the current key
+		createCredentialRequest := credential.CreateCredentialRequest{
+			Issuer:    created.DID.ID,
+			Subject:   "did:example:subject",
+			Data:      map[string]any{"name": "alice"},
+			Revocable: true,
+		}
+		oldCred, err := credentialService.CreateCredential(context.Background(), createCredentialRequest)
+		require.NoError(tt, err)
+		assert.Equal(tt, oldKeyID, oldCred.IssuerKID)
+
+		// a key type is required
+		params := map[string]string{"method": "web", "id": created.DID.ID}
+		rotateKeyPath := fmt.Sprintf("https://ssi-service.com/v1/dids/web/%s/rotate-key", created.DID.ID)
+		req := httptest.NewRequest(http.MethodPost, rotateKeyPath, newRequestValue(tt, router.RotateDIDKeyRequest{}))
+		w := httptest.NewRecorder()
+		didRouter.RotateDIDKey(newRequestContextWithParams(w, req, params))
+		assert.Equal(tt, http.StatusBadRequest, w.Code)
+		assert.Contains(tt, w.Body.String(), "invalid rotate DID key request")
+
+		// the old key can still sign when the DID can't be updated with the new key
+		req = httptest.NewRequest(http.MethodPost, rotateKeyPath, newRequestValue(tt, router.RotateDIDKeyRequest{KeyType: "unsupported"}))
+		w = httptest.NewRecorder()
+		didRouter.RotateDIDKey(newRequestContextWithParams(w, req, params))
+		assert.Equal(tt, http.StatusInternalServerError, w.Code)
+		gotKey, err := keyStore.GetKeyDetails(context.Background(), keystore.GetKeyDetailsRequest{ID: oldKeyID})
+		require.NoError(tt, err)
+		assert.False(tt, gotKey.Revoked)
+
+		req = httptest.NewRequest(http.MethodPost, rotateKeyPath, newRequestValue(tt, router.RotateDIDKeyRequest{KeyType: crypto.Ed25519}))
+		w = httptest.NewRecorder()
+		didRouter.RotateDIDKey(newRequestContextWithParams(w, req, params))
+		require.Equal(tt, http.StatusOK, w.Code)
+		var resp router.RotateDIDKeyResponse
+		require.NoError(tt, json.NewDecoder(w.Body).Decode(&resp))
+		require.NotEmpty(tt, resp.KeyID)
+		assert.NotEqual(tt, oldKeyID, resp.KeyID)
+
+		// the old key stays resolvable, and assertions move to the new key along with the old key's other relationships
+		require.Len(tt, resp.DID.VerificationMethod, 2)
+		assert.Equal(tt, oldKeyID, resp.DID.VerificationMethod[0].ID)
+		assert.Equal(tt, []didsdk.VerificationMethodSet{resp.KeyID}, resp.DID.AssertionMethod)
+		assert.Equal(tt, []didsdk.VerificationMethodSet{resp.KeyID}, resp.DID.Authentication)
+
+		// the old key is revoked for signing
+		gotKey, err = keyStore.GetKeyDetails(context.Background(), keystore.GetKeyDetailsRequest{ID: oldKeyID})
+		require.NoError(tt, err)
+		assert.True(tt, gotKey.Revoked)
+		createCredentialRequest.IssuerKID = oldKeyID
+		_, err = credentialService.CreateCredential(context.Background(), createCredentialRequest)
+		assert.ErrorContains(tt, err, "can no longer be used for signing")
+
+		createCredentialRequest.IssuerKID = ""
+		newCred, err := credentialService.CreateCredential(context.Background(), createCredentialRequest)
+		require.NoError(tt, err)
+		assert.Equal(tt, resp.KeyID, newCred.IssuerKID)
+
+		// credentials signed with the old key still verify
+		verified, err := credentialService.VerifyCredential(context.Background(), credential.VerifyCredentialRequest{CredentialJWT: oldCred.CredentialJWT})
+		require.NoError(tt, err)
+		assert.True(tt, verified.Verified, verified.Reason)
+
+		// the status of credentials signed with the old key can still be updated
+		_, err = credentialService.UpdateCredentialStatus(context.Background(), credential.UpdateCredentialStatusRequest{ID: oldCred.ID, Revoked: true})
+		require.NoError(tt, err)
+	})
+
+	t.Run("Test Import DID", func(tt *testing.T) {
+		bolt := setupTestDB(tt)
+		require.NotEmpty(tt, bolt)
+
+		_, keyStore := testKeyStore(tt, bolt)
+		didService := testDIDService(tt, bolt, keyStore, "web", "key")
+		didRouter, err := router.NewDIDRouter(didService)
+		require.NoError(tt, err)
+		schemaService := testSchemaService(tt, bolt, keyStore, didService)
+		credentialService := testCredentialService(tt, bolt, keyStore, didService, schemaService)
+
+		// a did:key DID with a base58 key, whose document is derived from the DID
+		privKey, didKey, err := key.GenerateDIDKey(crypto.Ed25519)
+		require.NoError(tt, err)
+		privKeyBytes, err := crypto.PrivKeyToBytes(privKey)
+		require.NoError(tt, err)
+		expanded, err := didKey.Expand()
+		require.NoError(tt, err)
+		kid := expanded.VerificationMethod[0].ID
+
+		importKeyRequest := router.ImportDIDByMethodRequest{
+			DID: didsdk.Document{ID: didKey.String()},
+			Keys: []did.ImportDIDKey{{
+				ID:               kid,
+				PrivateKeyBase58: base58.Encode(privKeyBytes),
+				KeyType:          crypto.Ed25519,
+			}},
+		}
+		params := map[string]string{"method": "key"}
+		req := httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/dids/key/import", newRequestValue(tt, importKeyRequest))
+		w := httptest.NewRecorder()
+		didRouter.ImportDIDByMethod(newRequestContextWithParams(w, req, params))
+		require.Equal(tt, http.StatusCreated, w.Code)
+		var resp router.ImportDIDByMethodResponse
+		require.NoError(tt, json.NewDecoder(w.Body).Decode(&resp))
+		assert.Equal(tt, expanded.ID, resp.DID.ID)
+		assert.Equal(tt, expanded.VerificationMethod, resp.DID.VerificationMethod)
+
+		// keys are stored under the absolute id of their verification method
+		gotKey, err := keyStore.GetKeyDetails(context.Background(), keystore.GetKeyDetailsRequest{ID: didKey.String() + kid})
+		require.NoError(tt, err)
+		assert.Equal(tt, didKey.String(), gotKey.Controller)
+
+		// imported DIDs can issue like the DIDs the service creates
+		cred, err := credentialService.CreateCredential(context.Background(), credential.CreateCredentialRequest{
+			Issuer:  didKey.String(),
+			Subject: "did:example:subject",
+			Data:    map[string]any{"name": "alice"},
+		})
+		require.NoError(tt, err)
+		assert.Equal(tt, didKey.String()+kid, cred.IssuerKID)
+		verified, err := credentialService.VerifyCredential(context.Background(), credential.VerifyCredentialRequest{CredentialJWT: cred.CredentialJWT})
+		require.NoError(tt, err)
+		assert.True(tt, verified.Verified, verified.Reason)
+
+		// a DID can't be imported twice
+		req = httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/dids/key/import", newRequestValue(tt, importKeyRequest))
+		w = httptest.NewRecorder()
+		didRouter.ImportDIDByMethod(newRequestContextWithParams(w, req, params))
+		assert.Equal(tt, http.StatusInternalServerError, w.Code)
+		assert.Contains(tt, w.Body.String(), "already exists")
+
+		// a did:web DID with a JWK key
+		pubKey, webPrivKey, err := crypto.GenerateKeyByKeyType(crypto.P256)
+		require.NoError(tt, err)
+		pubKeyBytes, err := crypto.PubKeyToBytes(pubKey)
+		require.NoError(tt, err)
+		webDoc, err := web.DIDWeb("did:web:example.org").CreateDoc(crypto.P256, pubKeyBytes)
+		require.NoError(tt, err)
+		_, webPrivKeyJWK, err := jwx.PrivateKeyToPrivateKeyJWK(webDoc.VerificationMethod[0].ID, webPrivKey)
+		require.NoError(tt, err)
+		otherPubKey, otherPrivKey, err := crypto.GenerateKeyByKeyType(crypto.P256)
+		require.NoError(tt, err)
+		_, otherPrivKeyJWK, err := jwx.PrivateKeyToPrivateKeyJWK(webDoc.VerificationMethod[0].ID, otherPrivKey)
+		require.NoError(tt, err)
+
+		// the document of a did:web DID is the one its domain serves
+		defer gock.Off()
+		params = map[string]string{"method": "web"}
+		importWebRequest := router.ImportDIDByMethodRequest{
+			DID:  *webDoc,
+			Keys: []did.ImportDIDKey{{ID: webDoc.VerificationMethod[0].ID, PrivateKeyJWK: webPrivKeyJWK}},
+		}
+		req = httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/dids/web/import", newRequestValue(tt, importWebRequest))
+		w = httptest.NewRecorder()
+		gock.New("https://example.org").Get("/.well-known/did.json").Reply(http.StatusNotFound)
+		didRouter.ImportDIDByMethod(newRequestContextWithParams(w, req, params))
+		assert.Equal(tt, http.StatusInternalServerError, w.Code)
+		assert.Contains(tt, w.Body.String(), "getting the document served for did:web DID")
+
+		otherPubKeyBytes, err := crypto.PubKeyToBytes(otherPubKey)
+		require.NoError(tt, err)
+		servedDoc, err := web.DIDWeb(webDoc.ID).CreateDoc(crypto.P256, otherPubKeyBytes)
+		require.NoError(tt, err)
+		req = httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/dids/web/import", newRequestValue(tt, importWebRequest))
+		w = httptest.NewRecorder()
+		gock.New("https://example.org").Get("/.well-known/did.json").Reply(http.StatusOK).JSON(map[string]any{"didDocument": servedDoc})
+		didRouter.ImportDIDByMethod(newRequestContextWithParams(w, req, params))
+		assert.Equal(tt, http.StatusInternalServerError, w.Code)
+		assert.Contains(tt, w.Body.String(), "key does not match the public key of verification method")
+
+		// keys must be those of the document's verification methods
+		for i := 0; i < 3; i++ {
+			gock.New("https://example.org").Get("/.well-known/did.json").Reply(http.StatusOK).JSON(map[string]any{"didDocument": webDoc})
+		}
+		importWebRequest.Keys = []did.ImportDIDKey{{ID: webDoc.VerificationMethod[0].ID, PrivateKeyJWK: otherPrivKeyJWK}}
+		req = httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/dids/web/import", newRequestValue(tt, importWebRequest))
+		w = httptest.NewRecorder()
+		didRouter.ImportDIDByMethod(newRequestContextWithParams(w, req, params))
+		assert.Equal(tt, http.StatusInternalServerError, w.Code)
+		assert.Contains(tt, w.Body.String(), "key does not match the public key of verification method")
+
+		importWebRequest.Keys = []did.ImportDIDKey{{ID: "#unknown", PrivateKeyJWK: webPrivKeyJWK}}
+		req = httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/dids/web/import", newRequestValue(tt, importWebRequest))
+		w = httptest.NewRecorder()
+		didRouter.ImportDIDByMethod(newRequestContextWithParams(w, req, params))
+		assert.Equal(tt, http.StatusInternalServerError, w.Code)
+		assert.Contains(tt, w.Body.String(), "not found in document")
+		exists, err := keyStore.KeyExists(context.Background(), webDoc.VerificationMethod[0].ID)
+		require.NoError(tt, err)
+		assert.False(tt, exists)
+
+		importWebRequest.Keys = []did.ImportDIDKey{{ID: webDoc.VerificationMethod[0].ID, PrivateKeyJWK: webPrivKeyJWK}}
+		req = httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/dids/web/import", newRequestValue(tt, importWebRequest))
+		w = httptest.NewRecorder()
+		didRouter.ImportDIDByMethod(newRequestContextWithParams(w, req, params))
+		require.Equal(tt, http.StatusCreated, w.Code)
+
+		// the document is hosted by the service
+		hosted, err := didService.GetWebDIDDocument(context.Background(), did.GetWebDIDDocumentRequest{ID: webDoc.ID})
+		require.NoError(tt, err)
+		assert.Equal(tt, webDoc.ID, hosted.DID.ID)
+		assert.Equal(tt, webDoc.VerificationMethod, hosted.DID.VerificationMethod)
+		gotKey, err = keyStore.GetKeyDetails(context.Background(), keystore.GetKeyDetailsRequest{ID: webDoc.VerificationMethod[0].ID})
+		require.NoError(tt, err)
+		assert.Equal(tt, webDoc.ID, gotKey.Controller)
+		assert.Equal(tt, crypto.P256, gotKey.Type)
+
+		// did:web DIDs whose verification methods have the same relative id each keep their key
+		relativeDocs := make([]didsdk.Document, 0, 2)
+		for _, id := range []string{"did:web:alice.example.org", "did:web:bob.example.org"} {
+			_, relativePrivKey, err := crypto.GenerateKeyByKeyType(crypto.P256)
+			require.NoError(tt, err)
+			relativePubKeyJWK, relativePrivKeyJWK, err := jwx.PrivateKeyToPrivateKeyJWK("#key-1", relativePrivKey)
+			require.NoError(tt, err)
+			relativeDoc := didsdk.Document{
+				Context: webDoc.Context,
+				ID:      id,
+				VerificationMethod: []didsdk.VerificationMethod{{
+					ID:           "#key-1",
+					Type:         cryptosuite.JSONWebKey2020Type,
+					Controller:   id,
+					PublicKeyJWK: relativePubKeyJWK,
+				}},
+				AssertionMethod: []didsdk.VerificationMethodSet{"#key-1"},
+			}
+			gock.New("https://" + strings.TrimPrefix(id, "did:web:")).Get("/.well-known/did.json").Reply(http.StatusOK).JSON(map[string]any{"didDocument": relativeDoc})
+			importRelativeRequest := router.ImportDIDByMethodRequest{
+				DID:  relativeDoc,
+				Keys: []did.ImportDIDKey{{ID: "#key-1", PrivateKeyJWK: relativePrivKeyJWK}},
+			}
+			req = httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/dids/web/import", newRequestValue(tt, importRelativeRequest))
+			w = httptest.NewRecorder()
+			didRouter.ImportDIDByMethod(newRequestContextWithParams(w, req, params))
+			require.Equal(tt, http.StatusCreated, w.Code, w.Body.String())
+			relativeDocs = append(relativeDocs, relativeDoc)
+		}
+		exists, err = keyStore.KeyExists(context.Background(), "#key-1")
+		require.NoError(tt, err)
+		assert.False(tt, exists)
+		for _, relativeDoc := range relativeDocs {
+			gotKey, err = keyStore.GetKeyDetails(context.Background(), keystore.GetKeyDetailsRequest{ID: relativeDoc.ID + "#key-1"})
+			require.NoError(tt, err)
+			assert.Equal(tt, relativeDoc.ID, gotKey.Controller)
+			assert.Equal(tt, relativeDoc.VerificationMethod[0].PublicKeyJWK.X, gotKey.PublicKeyJWK.X)
+			assertionKeyID, err := keyStore.GetAssertionKeyID(context.Background(), relativeDoc)
+			require.NoError(tt, err)
+			assert.Equal(tt, relativeDoc.ID+"#key-1", assertionKeyID)
+		}
+
+		// DIDs whose keys are already in the keystore aren't imported
+		_, existingPrivKey, err := crypto.GenerateKeyByKeyType(crypto.P256)
+		require.NoError(tt, err)
+		existingPubKeyJWK, existingPrivKeyJWK, err := jwx.PrivateKeyToPrivateKeyJWK("#key-1", existingPrivKey)
+		require.NoError(tt, err)
+		existingPrivKeyBytes, err := crypto.PrivKeyToBytes(existingPrivKey)
+		require.NoError(tt, err)
+		existingID := "did:web:carol.example.org"
+		require.NoError(tt, keyStore.StoreKey(context.Background(), keystore.StoreKeyRequest{
+			ID:               existingID + "#key-1",
+			Type:             crypto.P256,
+			Controller:       existingID,
+			PrivateKeyBase58: base58.Encode(existingPrivKeyBytes),
+		}))
+		importExistingRequest := router.ImportDIDByMethodRequest{
+			DID: didsdk.Document{
+				Context: webDoc.Context,
+				ID:      existingID,
+				VerificationMethod: []didsdk.VerificationMethod{{
+					ID:           "#key-1",
+					Type:         cryptosuite.JSONWebKey2020Type,
+					Controller:   existingID,
+					PublicKeyJWK: existingPubKeyJWK,
+				}},
+			},
+			Keys: []did.ImportDIDKey{{ID: "#key-1", PrivateKeyJWK: existingPrivKeyJWK}},
+		}
+		gock.New("https://carol.example.org").Get("/.well-known/did.json").Reply(http.StatusOK).JSON(map[string]any{"didDocument": importExistingRequest.DID})
+		req = httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/dids/web/import", newRequestValue(tt, importExistingRequest))
+		w = httptest.NewRecorder()
+		didRouter.ImportDIDByMethod(newRequestContextWithParams(w, req, params))
+		assert.Equal(tt, http.StatusInternalServerError, w.Code)
+		assert.Contains(tt, w.Body.String(), "key<did:web:carol.example.org#key-1> already exists")
+		_, err = didService.GetWebDIDDocument(context.Background(), did.GetWebDIDDocumentRequest{ID: existingID})
+		assert.Error(tt, err)
+
+		// DIDs of other methods can't be imported under a method
+		importKeyRequest.DID = didsdk.Document{ID: "did:key:z6MkfooBar"}
+		req = httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/dids/web/import", newRequestValue(tt, importKeyRequest))
+		w = httptest.NewRecorder()
+		didRouter.ImportDIDByMethod(newRequestContextWithParams(w, req, params))
+		assert.Equal(tt, http.StatusInternalServerError, w.Code)
+		assert.Contains(tt, w.Body.String(), "not a did:web DID")
+	})
+
+	t.Run("Test Resolution Cache Is Invalidated On Update", func(tt *testing.T) {
+		bolt := setupTestDB(tt)
+		require.NotEmpty(tt, bolt)
+
+		_, keyStore := testKeyStore(tt, bolt)
+		didService, err := did.NewDIDService(config.DIDServiceConfig{
+			BaseServiceConfig:      &config.BaseServiceConfig{Name: "test-did"},
+			Methods:                []string{"web"},
+			LocalResolutionMethods: []string{"web"},
+			ResolutionCache:        &config.DIDResolutionCacheConfig{Store: "storage", TTL: time.Hour},
+		}, bolt, keyStore)
+		require.NoError(tt, err)
+
+		created, err := didService.CreateDIDByMethod(context.Background(), did.CreateDIDRequest{
+			Method:  didsdk.WebMethod,
+			KeyType: crypto.Ed25519,
+			Options: did.CreateWebDIDOptions{DIDWebID: "did:web:example.com"},
+		})
+		require.NoError(tt, err)
+		resolved, err := didService.ResolveDID(did.ResolveDIDRequest{DID: created.DID.ID})
+		require.NoError(tt, err)
+		assert.Empty(tt, resolved.DIDDocument.Services)
+
+		_, err = didService.UpdateDIDByMethod(context.Background(), did.UpdateDIDRequest{
+			Method:        didsdk.WebMethod,
+			ID:            created.DID.ID,
+			ServicesToAdd: []didsdk.Service{{ID: "#linked-domain", Type: "LinkedDomains", ServiceEndpoint: "https://example.com"}},
+		})
+		require.NoError(tt, err)
+		resolved, err = didService.ResolveDID(did.ResolveDIDRequest{DID: created.DID.ID})
+		require.NoError(tt, err)
+		require.Len(tt, resolved.DIDDocument.Services, 1)
+		assert.Equal(tt, "LinkedDomains", resolved.DIDDocument.Services[0].Type)
+	})
+
+	t.Run("Test Recover And Deactivate ION DID", func(tt *testing.T) {
+		bolt := setupTestDB(tt)
+		require.NotEmpty(tt, bolt)
+
+		_, keyStore := testKeyStore(tt, bolt)
+		didService := testDIDService(tt, bolt, keyStore, "ion", "key")
+		didRouter, err := router.NewDIDRouter(didService)
+		require.NoError(tt, err)
+
+		gock.New(testIONResolverURL).
+			Post("/operations").
+			Times(3).
+			Reply(200)
+		defer gock.Off()
+		created, err := didService.CreateDIDByMethod(context.Background(), did.CreateDIDRequest{Method: didsdk.IONMethod, KeyType: crypto.Ed25519})
+		require.NoError(tt, err)
+		params := map[string]string{"id": created.DID.ID}
+
+		// missing verification methods
+		req := httptest.NewRequest(http.MethodPost, "https://ssi-service.com/v1/dids/ion/"+created.DID.ID+"/recover", newRequestValue(tt, router.RecoverIONDIDRequest{}))
+		w := httptest.NewRecorder()
+		didRouter.RecoverIONDID(newRequestContextWithParams(w, req, params))
+		assert.Equal(tt, http.StatusBadRequest, w.Code)
+		assert.Contains(tt, w.Body.String(), "invalid recover DID request")
+
+		recoverRequest := router.RecoverIONDIDRequest{
+			VerificationMethods: []did.VerificationMethodToAdd{{KeyType: crypto.Ed25519, Purposes: []ion.PublicKeyPurpose{ion.Authentication}}},
+		}
+		req = httptest.NewRequest(http.MethodPost, "https://ssi-service.com/v1/dids/ion/"+created.DID.ID+"/recover", newRequestValue(tt, recoverRequest))
+		w = httptest.NewRecorder()
+		didRouter.RecoverIONDID(newRequestContextWithParams(w, req, params))
+		require.Equal(tt, http.StatusOK, w.Code)
+		var recoverResp router.RecoverIONDIDResponse
+		require.NoError(tt, json.NewDecoder(w.Body).Decode(&recoverResp))
+		assert.NotEmpty(tt, recoverResp.OperationID)
+		require.Len(tt, recoverResp.DID.VerificationMethod, 1)
+		assert.NotEqual(tt, created.DID.VerificationMethod[0].ID, recoverResp.DID.VerificationMethod[0].ID)
+
+		req = httptest.NewRequest(http.MethodPost, "https://ssi-service.com/v1/dids/ion/"+created.DID.ID+"/deactivate", nil)
+		w = httptest.NewRecorder()
+		didRouter.DeactivateIONDID(newRequestContextWithParams(w, req, params))
+		require.Equal(tt, http.StatusOK, w.Code)
+		var deactivateResp router.DeactivateIONDIDResponse
+		require.NoError(tt, json.NewDecoder(w.Body).Decode(&deactivateResp))
+		assert.NotEmpty(tt, deactivateResp.OperationID)
+		assert.NotEqual(tt, recoverResp.OperationID, deactivateResp.OperationID)
+
+		// deactivation is permanent
+		w = httptest.NewRecorder()
+		didRouter.DeactivateIONDID(newRequestContextWithParams(w, req, params))
+		assert.Equal(tt, http.StatusInternalServerError, w.Code)
+		assert.Contains(tt, w.Body.String(), "has been deactivated")
+	})
 }
@@ -357,6 +357,10 @@ func TestIssuanceRouter(t *testing.T) {
 		var getIssuanceTemplate router.ListIssuanceTemplatesResponse
 		assert.NoError(tt, json.NewDecoder(w.Body).Decode(&getIssuanceTemplate))
 		assert.Len(tt, getIssuanceTemplate.IssuanceTemplates, 2)
+		for _, template := range getIssuanceTemplate.IssuanceTemplates {
+			assert.NotEmpty(tt, template.ID)
+			assert.Equal(tt, manifest.Manifest.ID, template.CredentialManifest)
+		}
 	})
 }
 
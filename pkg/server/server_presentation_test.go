@@ -5,7 +5,10 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/TBD54566975/ssi-sdk/credential"
 	"github.com/TBD54566975/ssi-sdk/credential/exchange"
@@ -17,6 +20,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/google/uuid"
+	"github.com/lestrrat-go/jwx/v2/jwt"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -257,6 +261,68 @@ func TestPresentationAPI(t *testing.T) {
 			assert.Zero(ttt, resp.Result)
 		})
 
+		tt.Run("Create submission without a presentation returns error", func(ttt *testing.T) {
+			s := setupTestDB(ttt)
+			pRouter, _ := setupPresentationRouter(ttt, s)
+
+			value := newRequestValue(ttt, router.CreateSubmissionRequest{})
+			req := httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/presentations/submissions", value)
+			w := httptest.NewRecorder()
+			c := newRequestContext(w, req)
+			pRouter.CreateSubmission(c)
+			assert.Equal(ttt, http.StatusBadRequest, w.Code)
+			assert.Contains(ttt, w.Body.String(), "exactly one of submissionJwt or submission must be provided")
+		})
+
+		tt.Run("Create data integrity submission with an invalid proof returns error", func(ttt *testing.T) {
+			s := setupTestDB(ttt)
+			pRouter, _ := setupPresentationRouter(ttt, s)
+
+			_, holderDID := getSigner(ttt)
+			definition := createPresentationDefinition(ttt, pRouter)
+			vp := credential.VerifiablePresentation{
+				Context: []string{credential.VerifiableCredentialsLinkedDataContext},
+				ID:      uuid.NewString(),
+				Holder:  holderDID.String(),
+				Type:    []string{credential.VerifiablePresentationType},
+				PresentationSubmission: exchange.PresentationSubmission{
+					ID:           uuid.NewString(),
+					DefinitionID: definition.PresentationDefinition.ID,
+					DescriptorMap: []exchange.SubmissionDescriptor{
+						{ID: "wa_driver_license", Format: string(exchange.LDPVP), Path: "$.verifiableCredential[0]"},
+					},
+				},
+				VerifiableCredential: []any{},
+			}
+
+			// no proof
+			value := newRequestValue(ttt, router.CreateSubmissionRequest{Submission: &vp})
+			req := httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/presentations/submissions", value)
+			w := httptest.NewRecorder()
+			c := newRequestContext(w, req)
+			pRouter.CreateSubmission(c)
+			assert.Equal(ttt, http.StatusBadRequest, w.Code)
+			assert.Contains(ttt, w.Body.String(), "submission must have a proof")
+
+			// a proof that was not made by the holder
+			var proof crypto.Proof = map[string]any{
+				"type":               "JsonWebSignature2020",
+				"created":            "2023-01-01T00:00:00Z",
+				"proofPurpose":       "authentication",
+				"challenge":          "challenge",
+				"verificationMethod": holderDID.String() + "#" + holderDID.String()[len("did:key:"):],
+				"jws":                "eyJhbGciOiJFUzI1NiJ9..c2lnbmF0dXJl",
+			}
+			vp.Proof = &proof
+			value = newRequestValue(ttt, router.CreateSubmissionRequest{Submission: &vp})
+			req = httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/presentations/submissions", value)
+			w = httptest.NewRecorder()
+			c = newRequestContext(w, req)
+			pRouter.CreateSubmission(c)
+			assert.Equal(ttt, http.StatusInternalServerError, w.Code)
+			assert.Contains(ttt, w.Body.String(), "verifying data integrity presentation from did")
+		})
+
 		tt.Run("Review submission returns approved submission", func(ttt *testing.T) {
 			s := setupTestDB(ttt)
 			pRouter, didService := setupPresentationRouter(ttt, s)
@@ -531,14 +597,530 @@ func TestPresentationAPI(t *testing.T) {
 		})
 	})
 
+	t.Run("Submissions bound to presentation requests", func(tt *testing.T) {
+		tt.Run("Submission answering a request uses it up", func(ttt *testing.T) {
+			s := setupTestDB(ttt)
+			pRouter, didService := setupPresentationRouter(ttt, s)
+			verifierDID := createDID(ttt, didService)
+
+			holderSigner, holderDID := getSigner(ttt)
+			definition := createPresentationDefinition(ttt, pRouter)
+			presentationRequest := createPresentationRequest(ttt, pRouter, definition.PresentationDefinition.ID, verifierDID, time.Now().Add(time.Hour).Format(time.RFC3339))
+			assert.NotEmpty(ttt, presentationRequest.Nonce)
+			assert.False(ttt, presentationRequest.Used)
+
+			request := createBoundSubmissionRequest(ttt, presentationRequest, presentationRequest.Nonce, VerifiableCredential(), holderSigner, holderDID)
+			value := newRequestValue(ttt, request)
+			req := httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/presentations/submissions", value)
+			w := httptest.NewRecorder()
+			c := newRequestContext(w, req)
+			pRouter.CreateSubmission(c)
+			assert.True(ttt, util.Is2xxResponse(w.Code), w.Body.String())
+
+			req = httptest.NewRequest(http.MethodGet, fmt.Sprintf("https://ssi-service.com/v1/presentations/requests/%s", presentationRequest.ID), nil)
+			w = httptest.NewRecorder()
+			c = newRequestContextWithParams(w, req, map[string]string{"id": presentationRequest.ID})
+			pRouter.GetRequest(c)
+			assert.True(ttt, util.Is2xxResponse(w.Code))
+
+			var getResp router.GetRequestResponse
+			assert.NoError(ttt, json.NewDecoder(w.Body).Decode(&getResp))
+			assert.True(ttt, getResp.Request.Used)
+
+			// replaying the request with another submission fails
+			replay := createBoundSubmissionRequest(ttt, presentationRequest, presentationRequest.Nonce, VerifiableCredential(), holderSigner, holderDID)
+			value = newRequestValue(ttt, replay)
+			req = httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/presentations/submissions", value)
+			w = httptest.NewRecorder()
+			c = newRequestContext(w, req)
+			pRouter.CreateSubmission(c)
+			assert.Equal(ttt, http.StatusInternalServerError, w.Code)
+			assert.Contains(ttt, w.Body.String(), "presentation request has already been used")
+		})
+
+		tt.Run("Submission with the wrong nonce returns error", func(ttt *testing.T) {
+			s := setupTestDB(ttt)
+			pRouter, didService := setupPresentationRouter(ttt, s)
+			verifierDID := createDID(ttt, didService)
+
+			holderSigner, holderDID := getSigner(ttt)
+			definition := createPresentationDefinition(ttt, pRouter)
+			presentationRequest := createPresentationRequest(ttt, pRouter, definition.PresentationDefinition.ID, verifierDID, time.Now().Add(time.Hour).Format(time.RFC3339))
+
+			request := createBoundSubmissionRequest(ttt, presentationRequest, "another-nonce", VerifiableCredential(), holderSigner, holderDID)
+			value := newRequestValue(ttt, request)
+			req := httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/presentations/submissions", value)
+			w := httptest.NewRecorder()
+			c := newRequestContext(w, req)
+			pRouter.CreateSubmission(c)
+			assert.Equal(ttt, http.StatusInternalServerError, w.Code)
+			assert.Contains(ttt, w.Body.String(), "is not bound to presentation request")
+
+			// a failed submission does not use up the request
+			request = createBoundSubmissionRequest(ttt, presentationRequest, presentationRequest.Nonce, VerifiableCredential(), holderSigner, holderDID)
+			value = newRequestValue(ttt, request)
+			req = httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/presentations/submissions", value)
+			w = httptest.NewRecorder()
+			c = newRequestContext(w, req)
+			pRouter.CreateSubmission(c)
+			assert.True(ttt, util.Is2xxResponse(w.Code), w.Body.String())
+		})
+
+		tt.Run("Submission answering an expired request returns error", func(ttt *testing.T) {
+			s := setupTestDB(ttt)
+			pRouter, didService := setupPresentationRouter(ttt, s)
+			verifierDID := createDID(ttt, didService)
+
+			holderSigner, holderDID := getSigner(ttt)
+			definition := createPresentationDefinition(ttt, pRouter)
+			expiration := time.Now().Add(-time.Minute).Format(time.RFC3339)
+			presentationRequest := createPresentationRequest(ttt, pRouter, definition.PresentationDefinition.ID, verifierDID, expiration)
+
+			request := createBoundSubmissionRequest(ttt, presentationRequest, presentationRequest.Nonce, VerifiableCredential(), holderSigner, holderDID)
+			value := newRequestValue(ttt, request)
+			req := httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/presentations/submissions", value)
+			w := httptest.NewRecorder()
+			c := newRequestContext(w, req)
+			pRouter.CreateSubmission(c)
+			assert.Equal(ttt, http.StatusInternalServerError, w.Code)
+			assert.Contains(ttt, w.Body.String(), "expired")
+		})
+
+		tt.Run("Submission for another definition returns error", func(ttt *testing.T) {
+			s := setupTestDB(ttt)
+			pRouter, didService := setupPresentationRouter(ttt, s)
+			verifierDID := createDID(ttt, didService)
+
+			holderSigner, holderDID := getSigner(ttt)
+			definition := createPresentationDefinition(ttt, pRouter)
+			otherDefinition := createPresentationDefinition(ttt, pRouter)
+			presentationRequest := createPresentationRequest(ttt, pRouter, definition.PresentationDefinition.ID, verifierDID, time.Now().Add(time.Hour).Format(time.RFC3339))
+
+			otherRequest := presentationRequest
+			otherRequest.PresentationDefinitionID = otherDefinition.PresentationDefinition.ID
+			request := createBoundSubmissionRequest(ttt, otherRequest, presentationRequest.Nonce, VerifiableCredential(), holderSigner, holderDID)
+			value := newRequestValue(ttt, request)
+			req := httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/presentations/submissions", value)
+			w := httptest.NewRecorder()
+			c := newRequestContext(w, req)
+			pRouter.CreateSubmission(c)
+			assert.Equal(ttt, http.StatusInternalServerError, w.Code)
+			assert.Contains(ttt, w.Body.String(), "does not match presentation request's definition")
+		})
+
+		tt.Run("Submission without a request returns error when requests are required", func(ttt *testing.T) {
+			s := setupTestDB(ttt)
+			pRouter, didService := setupPresentationRouterWithConfig(ttt, s, config.PresentationServiceConfig{RequirePresentationRequest: true})
+			authorDID := createDID(ttt, didService)
+
+			holderSigner, holderDID := getSigner(ttt)
+			definition := createPresentationDefinition(ttt, pRouter)
+			request := createSubmissionRequest(ttt, definition.PresentationDefinition.ID, authorDID.DID.ID, VerifiableCredential(), holderSigner, holderDID)
+			value := newRequestValue(ttt, request)
+			req := httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/presentations/submissions", value)
+			w := httptest.NewRecorder()
+			c := newRequestContext(w, req)
+			pRouter.CreateSubmission(c)
+			assert.Equal(ttt, http.StatusInternalServerError, w.Code)
+			assert.Contains(ttt, w.Body.String(), "submission must reference the presentation request it answers")
+		})
+	})
+
+	t.Run("OID4VP endpoints", func(tt *testing.T) {
+		tt.Run("Create authorization request by value", func(ttt *testing.T) {
+			s := setupTestDB(ttt)
+			pRouter, didService := setupPresentationRouter(ttt, s)
+			verifierDID := createDID(ttt, didService)
+			definition := createPresentationDefinition(ttt, pRouter)
+
+			authorizationRequest := createAuthorizationRequest(ttt, pRouter, definition.PresentationDefinition.ID, verifierDID, false)
+			assert.Empty(ttt, authorizationRequest.RequestURI)
+			assert.Equal(ttt, "http://localhost:8080/v1/presentations/oid4vp/responses", authorizationRequest.ResponseURI)
+
+			requestURI, err := url.Parse(authorizationRequest.AuthorizationRequestURI)
+			assert.NoError(ttt, err)
+			assert.Equal(ttt, "openid4vp", requestURI.Scheme)
+			assert.Equal(ttt, verifierDID.DID.ID, requestURI.Query().Get("client_id"))
+			assert.Equal(ttt, authorizationRequest.RequestObjectJWT.String(), requestURI.Query().Get("request"))
+
+			token, err := jwt.Parse([]byte(authorizationRequest.RequestObjectJWT), jwt.WithVerify(false))
+			require.NoError(ttt, err)
+			assert.NoError(ttt, err)
+			assert.Equal(ttt, verifierDID.DID.ID, token.Issuer())
+			claims := token.PrivateClaims()
+			assert.Equal(ttt, "vp_token", claims["response_type"])
+			assert.Equal(ttt, "direct_post", claims["response_mode"])
+			assert.Equal(ttt, authorizationRequest.ResponseURI, claims["response_uri"])
+			assert.Equal(ttt, verifierDID.DID.ID, claims["client_id"])
+			assert.Equal(ttt, authorizationRequest.Nonce, claims["nonce"])
+			assert.Equal(ttt, authorizationRequest.ID, claims["state"])
+			assert.Contains(ttt, claims, "presentation_definition")
+		})
+
+		tt.Run("Create authorization request by reference", func(ttt *testing.T) {
+			s := setupTestDB(ttt)
+			pRouter, didService := setupPresentationRouter(ttt, s)
+			verifierDID := createDID(ttt, didService)
+			definition := createPresentationDefinition(ttt, pRouter)
+
+			authorizationRequest := createAuthorizationRequest(ttt, pRouter, definition.PresentationDefinition.ID, verifierDID, true)
+			assert.Equal(ttt, "http://localhost:8080/v1/presentations/oid4vp/requests/"+authorizationRequest.ID, authorizationRequest.RequestURI)
+
+			requestURI, err := url.Parse(authorizationRequest.AuthorizationRequestURI)
+			assert.NoError(ttt, err)
+			assert.Equal(ttt, authorizationRequest.RequestURI, requestURI.Query().Get("request_uri"))
+			assert.Empty(ttt, requestURI.Query().Get("request"))
+
+			req := httptest.NewRequest(http.MethodGet, authorizationRequest.RequestURI, nil)
+			w := httptest.NewRecorder()
+			c := newRequestContextWithParams(w, req, map[string]string{"id": authorizationRequest.ID})
+			pRouter.GetRequestObject(c)
+			assert.Equal(ttt, http.StatusOK, w.Code)
+			assert.Equal(ttt, "application/oauth-authz-req+jwt", w.Header().Get("Content-Type"))
+			assert.Equal(ttt, authorizationRequest.RequestObjectJWT.String(), w.Body.String())
+
+			req = httptest.NewRequest(http.MethodGet, "https://ssi-service.com/v1/presentations/oid4vp/requests/bad", nil)
+			w = httptest.NewRecorder()
+			c = newRequestContextWithParams(w, req, map[string]string{"id": "bad"})
+			pRouter.GetRequestObject(c)
+			assert.Equal(ttt, http.StatusNotFound, w.Code)
+		})
+
+		tt.Run("Get request object of an expired authorization request returns error", func(ttt *testing.T) {
+			s := setupTestDB(ttt)
+			pRouter, didService := setupPresentationRouter(ttt, s)
+			verifierDID := createDID(ttt, didService)
+			definition := createPresentationDefinition(ttt, pRouter)
+
+			request := router.CreateAuthorizationRequestRequest{
+				VerifierDID:              verifierDID.DID.ID,
+				VerifierKID:              verifierDID.DID.VerificationMethod[0].ID,
+				PresentationDefinitionID: definition.PresentationDefinition.ID,
+				Expiration:               time.Now().Add(-time.Minute).Format(time.RFC3339),
+				ByReference:              true,
+			}
+			value := newRequestValue(ttt, request)
+			req := httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/presentations/oid4vp/requests", value)
+			w := httptest.NewRecorder()
+			c := newRequestContext(w, req)
+			pRouter.CreateAuthorizationRequest(c)
+			require.Equal(ttt, http.StatusCreated, w.Code, w.Body.String())
+
+			var resp router.CreateAuthorizationRequestResponse
+			require.NoError(ttt, json.NewDecoder(w.Body).Decode(&resp))
+
+			req = httptest.NewRequest(http.MethodGet, resp.AuthorizationRequest.RequestURI, nil)
+			w = httptest.NewRecorder()
+			c = newRequestContextWithParams(w, req, map[string]string{"id": resp.AuthorizationRequest.ID})
+			pRouter.GetRequestObject(c)
+			assert.Equal(ttt, http.StatusGone, w.Code)
+			assert.Contains(ttt, w.Body.String(), "presentation request has expired")
+		})
+
+		tt.Run("Direct post creates a submission", func(ttt *testing.T) {
+			s := setupTestDB(ttt)
+			pRouter, didService := setupPresentationRouter(ttt, s)
+			verifierDID := createDID(ttt, didService)
+			definition := createPresentationDefinition(ttt, pRouter)
+			authorizationRequest := createAuthorizationRequest(ttt, pRouter, definition.PresentationDefinition.ID, verifierDID, true)
+
+			holderSigner, holderDID := getSigner(ttt)
+			presentationRequest := model.Request{
+				ID:                       authorizationRequest.ID,
+				IssuerDID:                verifierDID.DID.ID,
+				PresentationDefinitionID: definition.PresentationDefinition.ID,
+			}
+			submissionRequest := createBoundSubmissionRequest(ttt, presentationRequest, authorizationRequest.Nonce, VerifiableCredential(), holderSigner, holderDID)
+			form := directPostForm(ttt, submissionRequest.SubmissionJWT, authorizationRequest.ID)
+
+			w := postDirectResponse(ttt, pRouter, form)
+			assert.Equal(ttt, http.StatusOK, w.Code, w.Body.String())
+
+			var op router.Operation
+			assert.NoError(ttt, json.NewDecoder(w.Body).Decode(&op))
+			assert.Contains(ttt, op.ID, "presentations/submissions/")
+			assert.False(ttt, op.Done)
+
+			req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("https://ssi-service.com/v1/presentations/submissions/%s", opstorage.StatusObjectID(op.ID)), nil)
+			w = httptest.NewRecorder()
+			c := newRequestContextWithParams(w, req, map[string]string{"id": opstorage.StatusObjectID(op.ID)})
+			pRouter.GetSubmission(c)
+			assert.True(ttt, util.Is2xxResponse(w.Code))
+
+			var resp router.GetSubmissionResponse
+			assert.NoError(ttt, json.NewDecoder(w.Body).Decode(&resp))
+			assert.Equal(ttt, "pending", resp.Submission.Status)
+			assert.Equal(ttt, definition.PresentationDefinition.ID, resp.GetSubmission().DefinitionID)
+
+			// the same authorization request can't be answered twice
+			w = postDirectResponse(ttt, pRouter, form)
+			assert.Equal(ttt, http.StatusInternalServerError, w.Code)
+			assert.Contains(ttt, w.Body.String(), "presentation request has already been used")
+
+			// nor can its request object be fetched anymore
+			req = httptest.NewRequest(http.MethodGet, authorizationRequest.RequestURI, nil)
+			w = httptest.NewRecorder()
+			c = newRequestContextWithParams(w, req, map[string]string{"id": authorizationRequest.ID})
+			pRouter.GetRequestObject(c)
+			assert.Equal(ttt, http.StatusGone, w.Code)
+			assert.Contains(ttt, w.Body.String(), "presentation request has already been used")
+		})
+
+		tt.Run("Direct post with a mismatched submission returns error", func(ttt *testing.T) {
+			s := setupTestDB(ttt)
+			pRouter, didService := setupPresentationRouter(ttt, s)
+			verifierDID := createDID(ttt, didService)
+			definition := createPresentationDefinition(ttt, pRouter)
+			authorizationRequest := createAuthorizationRequest(ttt, pRouter, definition.PresentationDefinition.ID, verifierDID, false)
+
+			holderSigner, holderDID := getSigner(ttt)
+			presentationRequest := model.Request{
+				ID:                       authorizationRequest.ID,
+				IssuerDID:                verifierDID.DID.ID,
+				PresentationDefinitionID: definition.PresentationDefinition.ID,
+			}
+			submissionRequest := createBoundSubmissionRequest(ttt, presentationRequest, authorizationRequest.Nonce, VerifiableCredential(), holderSigner, holderDID)
+			form := directPostForm(ttt, submissionRequest.SubmissionJWT, authorizationRequest.ID)
+
+			var submission exchange.PresentationSubmission
+			assert.NoError(ttt, json.Unmarshal([]byte(form.Get("presentation_submission")), &submission))
+			submission.ID = uuid.NewString()
+			submissionBytes, err := json.Marshal(submission)
+			assert.NoError(ttt, err)
+			form.Set("presentation_submission", string(submissionBytes))
+
+			w := postDirectResponse(ttt, pRouter, form)
+			assert.Equal(ttt, http.StatusInternalServerError, w.Code)
+			assert.Contains(ttt, w.Body.String(), "does not match presentation_submission")
+
+			// without state
+			form = directPostForm(ttt, submissionRequest.SubmissionJWT, "")
+			w = postDirectResponse(ttt, pRouter, form)
+			assert.Equal(ttt, http.StatusBadRequest, w.Code)
+			assert.Contains(ttt, w.Body.String(), "invalid authorization response")
+		})
+	})
+
+	t.Run("Verification endpoint", func(tt *testing.T) {
+		tt.Run("Verify without a presentation returns error", func(ttt *testing.T) {
+			s := setupTestDB(ttt)
+			pRouter, _ := setupPresentationRouter(ttt, s)
+
+			value := newRequestValue(ttt, router.VerifyPresentationRequest{})
+			req := httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/presentations/verification", value)
+			w := httptest.NewRecorder()
+			c := newRequestContext(w, req)
+			pRouter.VerifyPresentation(c)
+			assert.Equal(ttt, http.StatusBadRequest, w.Code)
+			assert.Contains(ttt, w.Body.String(), "request must contain either a Data Integrity Presentation or a JWT Presentation")
+		})
+
+		tt.Run("Verify presentation JWT against a definition", func(ttt *testing.T) {
+			s := setupTestDB(ttt)
+			pRouter, didService := setupPresentationRouter(ttt, s)
+			authorDID := createDID(ttt, didService)
+
+			holderSigner, holderDID := getSigner(ttt)
+			definition := createPresentationDefinition(ttt, pRouter)
+			submissionRequest := createSubmissionRequest(ttt, definition.PresentationDefinition.ID, authorDID.DID.ID, VerifiableCredential(), holderSigner, holderDID)
+
+			request := router.VerifyPresentationRequest{
+				PresentationJWT:        &submissionRequest.SubmissionJWT,
+				PresentationDefinition: &definition.PresentationDefinition,
+			}
+			value := newRequestValue(ttt, request)
+			req := httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/presentations/verification", value)
+			w := httptest.NewRecorder()
+			c := newRequestContext(w, req)
+			pRouter.VerifyPresentation(c)
+			assert.True(ttt, util.Is2xxResponse(w.Code))
+
+			var resp router.VerifyPresentationResponse
+			assert.NoError(ttt, json.NewDecoder(w.Body).Decode(&resp))
+			assert.True(ttt, resp.Verified, resp.Reason)
+			assert.True(ttt, resp.HolderVerified)
+			assert.Equal(ttt, holderDID.String(), resp.Holder)
+			assert.NotNil(ttt, resp.DefinitionVerified)
+			assert.True(ttt, *resp.DefinitionVerified)
+			assert.Len(ttt, resp.Credentials, 1)
+			assert.True(ttt, resp.Credentials[0].Verified)
+			assert.NotEmpty(ttt, resp.Credentials[0].Issuer)
+
+			// nothing is stored
+			listReq := httptest.NewRequest(http.MethodGet, "https://ssi-service.com/v1/presentations/submissions", newRequestValue(ttt, router.ListSubmissionRequest{}))
+			w = httptest.NewRecorder()
+			c = newRequestContext(w, listReq)
+			pRouter.ListSubmissions(c)
+			assert.True(ttt, util.Is2xxResponse(w.Code))
+
+			var listResp router.ListSubmissionResponse
+			assert.NoError(ttt, json.NewDecoder(w.Body).Decode(&listResp))
+			assert.Empty(ttt, listResp.Submissions)
+
+			// a definition the presentation does not fulfill
+			otherDefinition := createPresentationDefinition(ttt, pRouter)
+			request.PresentationDefinition = &otherDefinition.PresentationDefinition
+			value = newRequestValue(ttt, request)
+			req = httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/presentations/verification", value)
+			w = httptest.NewRecorder()
+			c = newRequestContext(w, req)
+			pRouter.VerifyPresentation(c)
+			assert.True(ttt, util.Is2xxResponse(w.Code))
+
+			resp = router.VerifyPresentationResponse{}
+			assert.NoError(ttt, json.NewDecoder(w.Body).Decode(&resp))
+			assert.False(ttt, resp.Verified)
+			assert.True(ttt, resp.HolderVerified)
+			assert.NotNil(ttt, resp.DefinitionVerified)
+			assert.False(ttt, *resp.DefinitionVerified)
+			assert.Contains(ttt, resp.Reason, "verifying presentation submission vp")
+		})
+
+		tt.Run("Verify presentation JWT bound to another domain", func(ttt *testing.T) {
+			s := setupTestDB(ttt)
+			pRouter, didService := setupPresentationRouter(ttt, s)
+			authorDID := createDID(ttt, didService)
+
+			holderSigner, holderDID := getSigner(ttt)
+			submissionRequest := createSubmissionRequest(ttt, "some-definition", authorDID.DID.ID, VerifiableCredential(), holderSigner, holderDID)
+
+			request := router.VerifyPresentationRequest{PresentationJWT: &submissionRequest.SubmissionJWT, Domain: authorDID.DID.ID}
+			value := newRequestValue(ttt, request)
+			req := httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/presentations/verification", value)
+			w := httptest.NewRecorder()
+			c := newRequestContext(w, req)
+			pRouter.VerifyPresentation(c)
+			assert.True(ttt, util.Is2xxResponse(w.Code))
+
+			var resp router.VerifyPresentationResponse
+			assert.NoError(ttt, json.NewDecoder(w.Body).Decode(&resp))
+			assert.True(ttt, resp.HolderVerified, resp.Reason)
+
+			request.Domain = "did:example:another-verifier"
+			value = newRequestValue(ttt, request)
+			req = httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/presentations/verification", value)
+			w = httptest.NewRecorder()
+			c = newRequestContext(w, req)
+			pRouter.VerifyPresentation(c)
+			assert.True(ttt, util.Is2xxResponse(w.Code))
+
+			resp = router.VerifyPresentationResponse{}
+			assert.NoError(ttt, json.NewDecoder(w.Body).Decode(&resp))
+			assert.False(ttt, resp.Verified)
+			assert.False(ttt, resp.HolderVerified)
+			assert.Contains(ttt, resp.Reason, "validating presentation JWT claims")
+		})
+
+		tt.Run("Verify presentation JWT signed by someone other than the holder", func(ttt *testing.T) {
+			s := setupTestDB(ttt)
+			pRouter, didService := setupPresentationRouter(ttt, s)
+			authorDID := createDID(ttt, didService)
+
+			otherSigner, _ := getSigner(ttt)
+			_, holderDID := getSigner(ttt)
+			submissionRequest := createSubmissionRequest(ttt, "some-definition", authorDID.DID.ID, VerifiableCredential(), otherSigner, holderDID)
+
+			value := newRequestValue(ttt, router.VerifyPresentationRequest{PresentationJWT: &submissionRequest.SubmissionJWT})
+			req := httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/presentations/verification", value)
+			w := httptest.NewRecorder()
+			c := newRequestContext(w, req)
+			pRouter.VerifyPresentation(c)
+			assert.True(ttt, util.Is2xxResponse(w.Code))
+
+			var resp router.VerifyPresentationResponse
+			assert.NoError(ttt, json.NewDecoder(w.Body).Decode(&resp))
+			assert.False(ttt, resp.Verified)
+			assert.False(ttt, resp.HolderVerified)
+			assert.Nil(ttt, resp.DefinitionVerified)
+			assert.Len(ttt, resp.Credentials, 1)
+			assert.True(ttt, resp.Credentials[0].Verified)
+		})
+
+		tt.Run("Verify presentation JWT whose holder is not its issuer", func(ttt *testing.T) {
+			s := setupTestDB(ttt)
+			pRouter, didService := setupPresentationRouter(ttt, s)
+			authorDID := createDID(ttt, didService)
+
+			// signed with the holder's key, but issued by another DID
+			private, holderDID, err := key.GenerateDIDKey(crypto.P256)
+			require.NoError(ttt, err)
+			expanded, err := holderDID.Expand()
+			require.NoError(ttt, err)
+			_, otherDID := getSigner(ttt)
+			kids := map[string]string{
+				"issuer": expanded.VerificationMethod[0].ID,
+				"kid":    otherDID.String() + expanded.VerificationMethod[0].ID,
+			}
+			issuers := map[string]string{
+				"issuer": otherDID.String(),
+				"kid":    holderDID.String(),
+			}
+			for name, kid := range kids {
+				signer, err := jwx.NewJWXSigner(issuers[name], kid, private)
+				require.NoError(ttt, err)
+				vp := createSubmissionPresentation(ttt, "some-definition", VerifiableCredential(), *holderDID)
+				signed, err := signer.SignWithDefaults(map[string]any{
+					"aud":                    []string{authorDID.DID.ID},
+					credential.VPJWTProperty: vp,
+				})
+				require.NoError(ttt, err)
+				presentationJWT := keyaccess.JWT(signed)
+
+				value := newRequestValue(ttt, router.VerifyPresentationRequest{PresentationJWT: &presentationJWT})
+				req := httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/presentations/verification", value)
+				w := httptest.NewRecorder()
+				c := newRequestContext(w, req)
+				pRouter.VerifyPresentation(c)
+				assert.True(ttt, util.Is2xxResponse(w.Code))
+
+				var resp router.VerifyPresentationResponse
+				assert.NoError(ttt, json.NewDecoder(w.Body).Decode(&resp))
+				assert.False(ttt, resp.Verified, name)
+				assert.False(ttt, resp.HolderVerified, name)
+				assert.Contains(ttt, resp.Reason, "does not match", name)
+			}
+		})
+
+		tt.Run("Verify data integrity presentation without proof", func(ttt *testing.T) {
+			s := setupTestDB(ttt)
+			pRouter, _ := setupPresentationRouter(ttt, s)
+
+			_, holderDID := getSigner(ttt)
+			vp := credential.VerifiablePresentation{
+				Context:              []string{credential.VerifiableCredentialsLinkedDataContext},
+				ID:                   uuid.NewString(),
+				Holder:               holderDID.String(),
+				Type:                 []string{credential.VerifiablePresentationType},
+				VerifiableCredential: []any{"not a credential"},
+			}
+			value := newRequestValue(ttt, router.VerifyPresentationRequest{DataIntegrityPresentation: &vp})
+			req := httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/presentations/verification", value)
+			w := httptest.NewRecorder()
+			c := newRequestContext(w, req)
+			pRouter.VerifyPresentation(c)
+			assert.True(ttt, util.Is2xxResponse(w.Code))
+
+			var resp router.VerifyPresentationResponse
+			assert.NoError(ttt, json.NewDecoder(w.Body).Decode(&resp))
+			assert.False(ttt, resp.Verified)
+			assert.False(ttt, resp.HolderVerified)
+			assert.Equal(ttt, "presentation has no proof", resp.Reason)
+			assert.Len(ttt, resp.Credentials, 1)
+			assert.False(ttt, resp.Credentials[0].Verified)
+		})
+	})
 }
 
 func setupPresentationRouter(t *testing.T, s storage.ServiceStorage) (*router.PresentationRouter, *did.Service) {
+	return setupPresentationRouterWithConfig(t, s, config.PresentationServiceConfig{})
+}
+
+func setupPresentationRouterWithConfig(t *testing.T, s storage.ServiceStorage, serviceConfig config.PresentationServiceConfig) (*router.PresentationRouter, *did.Service) {
 	keyStoreService := testKeyStoreService(t, s)
 	didService := testDIDService(t, s, keyStoreService)
 	schemaService := testSchemaService(t, s, keyStoreService, didService)
 
-	service, err := presentation.NewPresentationService(config.PresentationServiceConfig{}, s, didService.GetResolver(), schemaService, keyStoreService)
+	service, err := presentation.NewPresentationService(serviceConfig, s, didService.GetResolver(), schemaService, keyStoreService)
 	assert.NoError(t, err)
 
 	pRouter, err := router.NewPresentationRouter(service)
@@ -573,6 +1155,36 @@ func createSubmission(t *testing.T, pRouter *router.PresentationRouter, definiti
 
 func createSubmissionRequest(t *testing.T, definitionID, requesterDID string, vc credential.VerifiableCredential,
 	holderSigner jwx.Signer, holderDID key.DIDKey) router.CreateSubmissionRequest {
+	vp := createSubmissionPresentation(t, definitionID, vc, holderDID)
+
+	signed, err := credential.SignVerifiablePresentationJWT(holderSigner, credential.JWTVVPParameters{Audience: []string{requesterDID}}, vp)
+	require.NoError(t, err)
+
+	request := router.CreateSubmissionRequest{SubmissionJWT: keyaccess.JWT(signed)}
+	return request
+}
+
+// createBoundSubmissionRequest creates a submission answering the given presentation request, signed with the given
+// nonce.
+func createBoundSubmissionRequest(t *testing.T, presentationRequest model.Request, nonce string, vc credential.VerifiableCredential,
+	holderSigner jwx.Signer, holderDID key.DIDKey) router.CreateSubmissionRequest {
+	vp := createSubmissionPresentation(t, presentationRequest.PresentationDefinitionID, vc, holderDID)
+
+	signed, err := holderSigner.SignWithDefaults(map[string]any{
+		"aud":                    []string{presentationRequest.IssuerDID},
+		"jti":                    vp.ID,
+		"nonce":                  nonce,
+		credential.VPJWTProperty: vp,
+	})
+	require.NoError(t, err)
+
+	return router.CreateSubmissionRequest{
+		SubmissionJWT:         keyaccess.JWT(signed),
+		PresentationRequestID: presentationRequest.ID,
+	}
+}
+
+func createSubmissionPresentation(t *testing.T, definitionID string, vc credential.VerifiableCredential, holderDID key.DIDKey) credential.VerifiablePresentation {
 	issuerSigner, didKey := getSigner(t)
 	vc.Issuer = didKey.String()
 	vcData, err := credential.SignVerifiableCredentialJWT(issuerSigner, vc)
@@ -589,7 +1201,7 @@ func createSubmissionRequest(t *testing.T, definitionID, requesterDID string, vc
 		},
 	}
 
-	vp := credential.VerifiablePresentation{
+	return credential.VerifiablePresentation{
 		Context:                []string{credential.VerifiableCredentialsLinkedDataContext},
 		ID:                     uuid.NewString(),
 		Holder:                 holderDID.String(),
@@ -597,12 +1209,69 @@ func createSubmissionRequest(t *testing.T, definitionID, requesterDID string, vc
 		PresentationSubmission: ps,
 		VerifiableCredential:   []any{keyaccess.JWT(vcData)},
 	}
+}
+
+func createPresentationRequest(t *testing.T, pRouter *router.PresentationRouter, definitionID string, verifierDID *did.CreateDIDResponse, expiration string) model.Request {
+	request := router.CreateRequestRequest{
+		Expiration:               expiration,
+		IssuerDID:                verifierDID.DID.ID,
+		IssuerKID:                verifierDID.DID.VerificationMethod[0].ID,
+		PresentationDefinitionID: definitionID,
+	}
+	value := newRequestValue(t, request)
+	req := httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/presentations/requests", value)
+	w := httptest.NewRecorder()
+	c := newRequestContext(w, req)
+	pRouter.CreateRequest(c)
+	require.True(t, util.Is2xxResponse(w.Code), w.Body.String())
 
-	signed, err := credential.SignVerifiablePresentationJWT(holderSigner, credential.JWTVVPParameters{Audience: []string{requesterDID}}, vp)
+	var resp router.CreateRequestResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	return *resp.Request
+}
+
+func createAuthorizationRequest(t *testing.T, pRouter *router.PresentationRouter, definitionID string, verifierDID *did.CreateDIDResponse, byReference bool) model.AuthorizationRequest {
+	request := router.CreateAuthorizationRequestRequest{
+		VerifierDID:              verifierDID.DID.ID,
+		VerifierKID:              verifierDID.DID.VerificationMethod[0].ID,
+		PresentationDefinitionID: definitionID,
+		Expiration:               time.Now().Add(time.Hour).Format(time.RFC3339),
+		ByReference:              byReference,
+	}
+	value := newRequestValue(t, request)
+	req := httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/presentations/oid4vp/requests", value)
+	w := httptest.NewRecorder()
+	c := newRequestContext(w, req)
+	pRouter.CreateAuthorizationRequest(c)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	var resp router.CreateAuthorizationRequestResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	return *resp.AuthorizationRequest
+}
+
+// directPostForm creates an authorization response with the given vp_token, whose presentation_submission is the one
+// embedded in the token.
+func directPostForm(t *testing.T, vpToken keyaccess.JWT, state string) url.Values {
+	_, _, vp, err := credential.ParseVerifiablePresentationFromJWT(vpToken.String())
+	require.NoError(t, err)
+	submissionBytes, err := json.Marshal(vp.PresentationSubmission)
 	require.NoError(t, err)
 
-	request := router.CreateSubmissionRequest{SubmissionJWT: keyaccess.JWT(signed)}
-	return request
+	form := url.Values{}
+	form.Set("vp_token", vpToken.String())
+	form.Set("presentation_submission", string(submissionBytes))
+	form.Set("state", state)
+	return form
+}
+
+func postDirectResponse(t *testing.T, pRouter *router.PresentationRouter, form url.Values) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "https://ssi-service.com/v1/presentations/oid4vp/responses", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	c := newRequestContext(w, req)
+	pRouter.DirectPost(c)
+	return w
 }
 
 func VerifiableCredential(options ...VCOption) credential.VerifiableCredential {
@@ -34,6 +34,7 @@ import (
 	"github.com/tbd54566975/ssi-service/pkg/service/keystore"
 	"github.com/tbd54566975/ssi-service/pkg/service/manifest"
 	"github.com/tbd54566975/ssi-service/pkg/service/schema"
+	"github.com/tbd54566975/ssi-service/pkg/service/trust"
 	"github.com/tbd54566975/ssi-service/pkg/storage"
 )
 
@@ -335,3 +336,24 @@ func testWebhookRouter(t *testing.T, bolt storage.ServiceStorage) *router.Webhoo
 
 	return webhookRouter
 }
+
+func testTrustService(t *testing.T, bolt storage.ServiceStorage) *trust.Service {
+	serviceConfig := config.TrustServiceConfig{BaseServiceConfig: &config.BaseServiceConfig{Name: "trust"}}
+
+	// create a trust service
+	trustService, err := trust.NewTrustService(serviceConfig, bolt)
+	require.NoError(t, err)
+	require.NotEmpty(t, trustService)
+	return trustService
+}
+
+func testTrustRouter(t *testing.T, bolt storage.ServiceStorage) *router.TrustRouter {
+	trustService := testTrustService(t, bolt)
+
+	// create router for service
+	trustRouter, err := router.NewTrustRouter(trustService)
+	require.NoError(t, err)
+	require.NotEmpty(t, trustRouter)
+
+	return trustRouter
+}
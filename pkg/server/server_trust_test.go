@@ -0,0 +1,294 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/TBD54566975/ssi-sdk/crypto"
+	didsdk "github.com/TBD54566975/ssi-sdk/did"
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tbd54566975/ssi-service/internal/util"
+	"github.com/tbd54566975/ssi-service/pkg/server/router"
+	"github.com/tbd54566975/ssi-service/pkg/service/did"
+	"github.com/tbd54566975/ssi-service/pkg/service/trust"
+)
+
+func TestTrustAPI(t *testing.T) {
+	t.Run("Test Create Trusted Issuer", func(tt *testing.T) {
+		bolt := setupTestDB(tt)
+		require.NotEmpty(tt, bolt)
+
+		trustRouter := testTrustRouter(tt, bolt)
+
+		// missing issuer
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/trust", newRequestValue(tt, router.CreateTrustedIssuerRequest{}))
+		c := newRequestContext(w, req)
+		trustRouter.CreateTrustedIssuer(c)
+		assert.Contains(tt, w.Body.String(), "invalid create trusted issuer request")
+
+		// bad validity window
+		now := time.Now()
+		before := now.Add(-time.Hour)
+		w = httptest.NewRecorder()
+		badWindow := router.CreateTrustedIssuerRequest{TrustedIssuer: trust.TrustedIssuer{
+			IssuerDID:  "did:example:issuer",
+			ValidFrom:  &now,
+			ValidUntil: &before,
+		}}
+		req = httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/trust", newRequestValue(tt, badWindow))
+		c = newRequestContext(w, req)
+		trustRouter.CreateTrustedIssuer(c)
+		assert.Contains(tt, w.Body.String(), "validUntil must be after validFrom")
+
+		// good request
+		w = httptest.NewRecorder()
+		createRequest := router.CreateTrustedIssuerRequest{TrustedIssuer: trust.TrustedIssuer{
+			IssuerDID:       "did:example:issuer",
+			Name:            "Example Issuer",
+			CredentialTypes: []string{"DriversLicense"},
+		}}
+		req = httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/trust", newRequestValue(tt, createRequest))
+		c = newRequestContext(w, req)
+		trustRouter.CreateTrustedIssuer(c)
+		assert.True(tt, util.Is2xxResponse(w.Code))
+
+		var resp router.CreateTrustedIssuerResponse
+		err := json.NewDecoder(w.Body).Decode(&resp)
+		assert.NoError(tt, err)
+		assert.NotEmpty(tt, resp.ID)
+		assert.Equal(tt, "did:example:issuer", resp.IssuerDID)
+		assert.Equal(tt, []string{"DriversLicense"}, resp.CredentialTypes)
+	})
+
+	t.Run("Test Get, Update, List and Delete Trusted Issuers", func(tt *testing.T) {
+		bolt := setupTestDB(tt)
+		require.NotEmpty(tt, bolt)
+
+		trustRouter := testTrustRouter(tt, bolt)
+
+		// get a missing entry
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "https://ssi-service.com/v1/trust/bad", nil)
+		c := newRequestContextWithParams(w, req, map[string]string{"id": "bad"})
+		trustRouter.GetTrustedIssuer(c)
+		assert.Contains(tt, w.Body.String(), "could not get trusted issuer with id: bad")
+
+		// create two entries for different issuers
+		var ids []string
+		for _, issuer := range []string{"did:example:one", "did:example:two"} {
+			w = httptest.NewRecorder()
+			createRequest := router.CreateTrustedIssuerRequest{TrustedIssuer: trust.TrustedIssuer{IssuerDID: issuer}}
+			req = httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/trust", newRequestValue(tt, createRequest))
+			c = newRequestContext(w, req)
+			trustRouter.CreateTrustedIssuer(c)
+			assert.True(tt, util.Is2xxResponse(w.Code))
+
+			var resp router.CreateTrustedIssuerResponse
+			err := json.NewDecoder(w.Body).Decode(&resp)
+			assert.NoError(tt, err)
+			ids = append(ids, resp.ID)
+		}
+
+		// get one back
+		w = httptest.NewRecorder()
+		req = httptest.NewRequest(http.MethodGet, fmt.Sprintf("https://ssi-service.com/v1/trust/%s", ids[0]), nil)
+		c = newRequestContextWithParams(w, req, map[string]string{"id": ids[0]})
+		trustRouter.GetTrustedIssuer(c)
+		assert.True(tt, util.Is2xxResponse(w.Code))
+
+		var getResp router.GetTrustedIssuerResponse
+		err := json.NewDecoder(w.Body).Decode(&getResp)
+		assert.NoError(tt, err)
+		assert.Equal(tt, ids[0], getResp.ID)
+		assert.Equal(tt, "did:example:one", getResp.IssuerDID)
+
+		// update its scope
+		w = httptest.NewRecorder()
+		updateRequest := router.UpdateTrustedIssuerRequest{TrustedIssuer: trust.TrustedIssuer{
+			IssuerDID: "did:example:one",
+			Schemas:   []string{"https://example.com/schema"},
+		}}
+		req = httptest.NewRequest(http.MethodPut, fmt.Sprintf("https://ssi-service.com/v1/trust/%s", ids[0]), newRequestValue(tt, updateRequest))
+		c = newRequestContextWithParams(w, req, map[string]string{"id": ids[0]})
+		trustRouter.UpdateTrustedIssuer(c)
+		assert.True(tt, util.Is2xxResponse(w.Code))
+
+		var updateResp router.UpdateTrustedIssuerResponse
+		err = json.NewDecoder(w.Body).Decode(&updateResp)
+		assert.NoError(tt, err)
+		assert.Equal(tt, ids[0], updateResp.ID)
+		assert.Equal(tt, []string{"https://example.com/schema"}, updateResp.Schemas)
+
+		// list all
+		w = httptest.NewRecorder()
+		req = httptest.NewRequest(http.MethodGet, "https://ssi-service.com/v1/trust", nil)
+		c = newRequestContext(w, req)
+		trustRouter.ListTrustedIssuers(c)
+		assert.True(tt, util.Is2xxResponse(w.Code))
+
+		var listResp router.ListTrustedIssuersResponse
+		err = json.NewDecoder(w.Body).Decode(&listResp)
+		assert.NoError(tt, err)
+		assert.Len(tt, listResp.TrustedIssuers, 2)
+
+		// list filtered by issuer
+		w = httptest.NewRecorder()
+		req = httptest.NewRequest(http.MethodGet, "https://ssi-service.com/v1/trust?issuer=did:example:two", nil)
+		c = newRequestContext(w, req)
+		trustRouter.ListTrustedIssuers(c)
+		assert.True(tt, util.Is2xxResponse(w.Code))
+
+		err = json.NewDecoder(w.Body).Decode(&listResp)
+		assert.NoError(tt, err)
+		assert.Len(tt, listResp.TrustedIssuers, 1)
+		assert.Equal(tt, ids[1], listResp.TrustedIssuers[0].ID)
+
+		// delete one
+		w = httptest.NewRecorder()
+		req = httptest.NewRequest(http.MethodDelete, fmt.Sprintf("https://ssi-service.com/v1/trust/%s", ids[1]), nil)
+		c = newRequestContextWithParams(w, req, map[string]string{"id": ids[1]})
+		trustRouter.DeleteTrustedIssuer(c)
+		assert.True(tt, util.Is2xxResponse(w.Code))
+
+		w = httptest.NewRecorder()
+		req = httptest.NewRequest(http.MethodGet, fmt.Sprintf("https://ssi-service.com/v1/trust/%s", ids[1]), nil)
+		c = newRequestContextWithParams(w, req, map[string]string{"id": ids[1]})
+		trustRouter.GetTrustedIssuer(c)
+		assert.Contains(tt, w.Body.String(), fmt.Sprintf("could not get trusted issuer with id: %s", ids[1]))
+	})
+
+	t.Run("Test Verify Issuer Trust", func(tt *testing.T) {
+		bolt := setupTestDB(tt)
+		require.NotEmpty(tt, bolt)
+
+		trustService := testTrustService(tt, bolt)
+		trustRouter, err := router.NewTrustRouter(trustService)
+		require.NoError(tt, err)
+
+		expired := time.Now().Add(-time.Hour)
+		_, err = trustService.CreateTrustedIssuer(context.Background(), trust.CreateTrustedIssuerRequest{TrustedIssuer: trust.TrustedIssuer{
+			IssuerDID: "did:example:issuer",
+			Schemas:   []string{"https://example.com/schema"},
+		}})
+		require.NoError(tt, err)
+		_, err = trustService.CreateTrustedIssuer(context.Background(), trust.CreateTrustedIssuerRequest{TrustedIssuer: trust.TrustedIssuer{
+			IssuerDID:  "did:example:expired",
+			ValidUntil: &expired,
+		}})
+		require.NoError(tt, err)
+
+		tests := []struct {
+			name    string
+			request router.VerifyIssuerTrustRequest
+			trusted bool
+		}{
+			{
+				name:    "matching schema",
+				request: router.VerifyIssuerTrustRequest{IssuerDID: "did:example:issuer", SchemaID: "https://example.com/schema"},
+				trusted: true,
+			},
+			{
+				name:    "other schema",
+				request: router.VerifyIssuerTrustRequest{IssuerDID: "did:example:issuer", SchemaID: "https://example.com/other"},
+				trusted: false,
+			},
+			{
+				name:    "unknown issuer",
+				request: router.VerifyIssuerTrustRequest{IssuerDID: "did:example:unknown"},
+				trusted: false,
+			},
+			{
+				name:    "expired entry",
+				request: router.VerifyIssuerTrustRequest{IssuerDID: "did:example:expired"},
+				trusted: false,
+			},
+		}
+		for _, test := range tests {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/trust/verification", newRequestValue(tt, test.request))
+			c := newRequestContext(w, req)
+			trustRouter.VerifyIssuerTrust(c)
+			assert.True(tt, util.Is2xxResponse(w.Code), test.name)
+
+			var resp router.VerifyIssuerTrustResponse
+			err = json.NewDecoder(w.Body).Decode(&resp)
+			assert.NoError(tt, err)
+			assert.Equal(tt, test.trusted, resp.Trusted, test.name)
+			if !test.trusted {
+				assert.Contains(tt, resp.Reason, "issuer is not trusted", test.name)
+			}
+		}
+	})
+
+	t.Run("Test Verify Credential Requiring Trusted Issuer", func(tt *testing.T) {
+		bolt := setupTestDB(tt)
+		require.NotEmpty(tt, bolt)
+
+		keyStoreService := testKeyStoreService(tt, bolt)
+		didService := testDIDService(tt, bolt, keyStoreService)
+		schemaService := testSchemaService(tt, bolt, keyStoreService, didService)
+		credRouter := testCredentialRouter(tt, bolt, keyStoreService, didService, schemaService)
+		trustService := testTrustService(tt, bolt)
+
+		issuerDID, err := didService.CreateDIDByMethod(context.Background(), did.CreateDIDRequest{
+			Method:  didsdk.KeyMethod,
+			KeyType: crypto.Ed25519,
+		})
+		require.NoError(tt, err)
+
+		createCredRequest := router.CreateCredentialRequest{
+			Issuer:    issuerDID.DID.ID,
+			IssuerKID: issuerDID.DID.VerificationMethod[0].ID,
+			Subject:   "did:abc:456",
+			Data:      map[string]any{"firstName": "Jack"},
+			Expiry:    time.Now().Add(24 * time.Hour).Format(time.RFC3339),
+		}
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/credentials", newRequestValue(tt, createCredRequest))
+		c := newRequestContext(w, req)
+		credRouter.CreateCredential(c)
+		assert.True(tt, util.Is2xxResponse(w.Code))
+
+		var resp router.CreateCredentialResponse
+		err = json.NewDecoder(w.Body).Decode(&resp)
+		assert.NoError(tt, err)
+
+		// the issuer is not in the registry yet
+		verifyRequest := router.VerifyCredentialRequest{CredentialJWT: resp.CredentialJWT, RequireTrustedIssuer: true}
+		w = httptest.NewRecorder()
+		req = httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/credentials/verification", newRequestValue(tt, verifyRequest))
+		c = newRequestContext(w, req)
+		credRouter.VerifyCredential(c)
+		assert.True(tt, util.Is2xxResponse(w.Code))
+
+		var verifyResp router.VerifyCredentialResponse
+		err = json.NewDecoder(w.Body).Decode(&verifyResp)
+		assert.NoError(tt, err)
+		assert.False(tt, verifyResp.Verified)
+		assert.Contains(tt, verifyResp.Reason, "issuer is not trusted")
+
+		// trust the issuer and verify again
+		_, err = trustService.CreateTrustedIssuer(context.Background(), trust.CreateTrustedIssuerRequest{
+			TrustedIssuer: trust.TrustedIssuer{IssuerDID: issuerDID.DID.ID},
+		})
+		require.NoError(tt, err)
+
+		w = httptest.NewRecorder()
+		req = httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/credentials/verification", newRequestValue(tt, verifyRequest))
+		c = newRequestContext(w, req)
+		credRouter.VerifyCredential(c)
+		assert.True(tt, util.Is2xxResponse(w.Code))
+
+		err = json.NewDecoder(w.Body).Decode(&verifyResp)
+		assert.NoError(tt, err)
+		assert.True(tt, verifyResp.Verified)
+	})
+}
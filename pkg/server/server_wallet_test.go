@@ -0,0 +1,441 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	credsdk "github.com/TBD54566975/ssi-sdk/credential"
+	"github.com/TBD54566975/ssi-sdk/credential/exchange"
+	manifestsdk "github.com/TBD54566975/ssi-sdk/credential/manifest"
+	"github.com/TBD54566975/ssi-sdk/crypto"
+	didsdk "github.com/TBD54566975/ssi-sdk/did"
+	"github.com/goccy/go-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tbd54566975/ssi-service/config"
+	"github.com/tbd54566975/ssi-service/internal/keyaccess"
+	"github.com/tbd54566975/ssi-service/internal/util"
+	"github.com/tbd54566975/ssi-service/pkg/server/router"
+	"github.com/tbd54566975/ssi-service/pkg/service/did"
+	"github.com/tbd54566975/ssi-service/pkg/service/keystore"
+	"github.com/tbd54566975/ssi-service/pkg/service/manifest"
+	"github.com/tbd54566975/ssi-service/pkg/service/presentation"
+	"github.com/tbd54566975/ssi-service/pkg/service/presentation/model"
+	"github.com/tbd54566975/ssi-service/pkg/service/schema"
+	"github.com/tbd54566975/ssi-service/pkg/service/wallet"
+	"github.com/tbd54566975/ssi-service/pkg/storage"
+)
+
+func TestWalletAPI(t *testing.T) {
+	t.Run("Test Store, Get, List and Delete Held Credentials", func(tt *testing.T) {
+		bolt := setupTestDB(tt)
+		require.NotEmpty(tt, bolt)
+
+		keyStoreService := testKeyStoreService(tt, bolt)
+		didService := testDIDService(tt, bolt, keyStoreService)
+		schemaService := testSchemaService(tt, bolt, keyStoreService, didService)
+		credRouter := testCredentialRouter(tt, bolt, keyStoreService, didService, schemaService)
+		walletRouter := testWalletRouter(tt, bolt, keyStoreService, didService, schemaService)
+
+		issuerDID := createTestKeyDID(tt, didService)
+		holderDID := createTestKeyDID(tt, didService)
+		credJWT := createTestCredentialJWT(tt, credRouter, issuerDID, holderDID.DID.ID)
+
+		// bad request
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/wallet/credentials", newRequestValue(tt, router.StoreHeldCredentialRequest{HolderDID: holderDID.DID.ID}))
+		c := newRequestContext(w, req)
+		walletRouter.StoreCredential(c)
+		assert.Contains(tt, w.Body.String(), "invalid store held credential request")
+
+		// holder not managed by the service
+		w = httptest.NewRecorder()
+		storeRequest := router.StoreHeldCredentialRequest{HolderDID: "did:key:z6MkiTBz1ymuepAQ4HEHYSF1H8quG5GLVVQR3djdX3mDooWp", CredentialJWT: credJWT}
+		req = httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/wallet/credentials", newRequestValue(tt, storeRequest))
+		c = newRequestContext(w, req)
+		walletRouter.StoreCredential(c)
+		assert.Contains(tt, w.Body.String(), "is not a DID managed by this service")
+
+		// credential issued to another subject
+		w = httptest.NewRecorder()
+		storeRequest = router.StoreHeldCredentialRequest{HolderDID: issuerDID.DID.ID, CredentialJWT: credJWT}
+		req = httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/wallet/credentials", newRequestValue(tt, storeRequest))
+		c = newRequestContext(w, req)
+		walletRouter.StoreCredential(c)
+		assert.Contains(tt, w.Body.String(), "is not holder")
+
+		// good request
+		w = httptest.NewRecorder()
+		storeRequest = router.StoreHeldCredentialRequest{HolderDID: holderDID.DID.ID, CredentialJWT: credJWT}
+		req = httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/wallet/credentials", newRequestValue(tt, storeRequest))
+		c = newRequestContext(w, req)
+		walletRouter.StoreCredential(c)
+		assert.True(tt, util.Is2xxResponse(w.Code))
+
+		var storeResp router.StoreHeldCredentialResponse
+		err := json.NewDecoder(w.Body).Decode(&storeResp)
+		assert.NoError(tt, err)
+		assert.NotEmpty(tt, storeResp.ID)
+		assert.Equal(tt, holderDID.DID.ID, storeResp.HolderDID)
+		assert.Equal(tt, issuerDID.DID.ID, storeResp.Issuer)
+		assert.Equal(tt, *credJWT, *storeResp.CredentialJWT)
+
+		// get it back
+		w = httptest.NewRecorder()
+		req = httptest.NewRequest(http.MethodGet, fmt.Sprintf("https://ssi-service.com/v1/wallet/credentials/%s", storeResp.ID), nil)
+		c = newRequestContextWithParams(w, req, map[string]string{"id": storeResp.ID})
+		walletRouter.GetCredential(c)
+		assert.True(tt, util.Is2xxResponse(w.Code))
+
+		var getResp router.GetHeldCredentialResponse
+		err = json.NewDecoder(w.Body).Decode(&getResp)
+		assert.NoError(tt, err)
+		assert.Equal(tt, storeResp.ID, getResp.ID)
+
+		// list by holder and issuer
+		for _, query := range []string{"holder=" + holderDID.DID.ID, "issuer=" + issuerDID.DID.ID, "type=VerifiableCredential"} {
+			w = httptest.NewRecorder()
+			req = httptest.NewRequest(http.MethodGet, "https://ssi-service.com/v1/wallet/credentials?"+query, nil)
+			c = newRequestContext(w, req)
+			walletRouter.ListCredentials(c)
+			assert.True(tt, util.Is2xxResponse(w.Code))
+
+			var listResp router.ListHeldCredentialsResponse
+			err = json.NewDecoder(w.Body).Decode(&listResp)
+			assert.NoError(tt, err)
+			assert.Len(tt, listResp.Credentials, 1, query)
+		}
+
+		// list by another holder
+		w = httptest.NewRecorder()
+		req = httptest.NewRequest(http.MethodGet, "https://ssi-service.com/v1/wallet/credentials?holder="+issuerDID.DID.ID, nil)
+		c = newRequestContext(w, req)
+		walletRouter.ListCredentials(c)
+		assert.True(tt, util.Is2xxResponse(w.Code))
+
+		var listResp router.ListHeldCredentialsResponse
+		err = json.NewDecoder(w.Body).Decode(&listResp)
+		assert.NoError(tt, err)
+		assert.Empty(tt, listResp.Credentials)
+
+		// delete it
+		w = httptest.NewRecorder()
+		req = httptest.NewRequest(http.MethodDelete, fmt.Sprintf("https://ssi-service.com/v1/wallet/credentials/%s", storeResp.ID), nil)
+		c = newRequestContextWithParams(w, req, map[string]string{"id": storeResp.ID})
+		walletRouter.DeleteCredential(c)
+		assert.True(tt, util.Is2xxResponse(w.Code))
+
+		w = httptest.NewRecorder()
+		req = httptest.NewRequest(http.MethodGet, fmt.Sprintf("https://ssi-service.com/v1/wallet/credentials/%s", storeResp.ID), nil)
+		c = newRequestContextWithParams(w, req, map[string]string{"id": storeResp.ID})
+		walletRouter.GetCredential(c)
+		assert.Contains(tt, w.Body.String(), "could not get held credential with id")
+	})
+
+	t.Run("Test Store Credential Response", func(tt *testing.T) {
+		bolt := setupTestDB(tt)
+		require.NotEmpty(tt, bolt)
+
+		keyStoreService := testKeyStoreService(tt, bolt)
+		didService := testDIDService(tt, bolt, keyStoreService)
+		schemaService := testSchemaService(tt, bolt, keyStoreService, didService)
+		credRouter := testCredentialRouter(tt, bolt, keyStoreService, didService, schemaService)
+		walletRouter := testWalletRouter(tt, bolt, keyStoreService, didService, schemaService)
+
+		issuerDID := createTestKeyDID(tt, didService)
+		holderDID := createTestKeyDID(tt, didService)
+		credJWT := createTestCredentialJWT(tt, credRouter, issuerDID, holderDID.DID.ID)
+
+		// a denial is rejected
+		builder := manifestsdk.NewCredentialResponseBuilder("test-manifest")
+		require.NoError(tt, builder.SetApplicantID(holderDID.DID.ID))
+		require.NoError(tt, builder.SetDenial("not today"))
+		denial, err := builder.Build()
+		require.NoError(tt, err)
+		denialJWT := signTestCredentialResponse(tt, keyStoreService, issuerDID, manifest.CredentialResponseContainer{Response: *denial})
+
+		w := httptest.NewRecorder()
+		responseRequest := router.StoreCredentialResponseRequest{HolderDID: holderDID.DID.ID, ResponseJWT: *denialJWT}
+		req := httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/wallet/responses", newRequestValue(tt, responseRequest))
+		c := newRequestContext(w, req)
+		walletRouter.StoreCredentialResponse(c)
+		assert.Contains(tt, w.Body.String(), "is a denial: not today")
+
+		// a fulfillment is accepted
+		builder = manifestsdk.NewCredentialResponseBuilder("test-manifest")
+		require.NoError(tt, builder.SetApplicantID(holderDID.DID.ID))
+		require.NoError(tt, builder.SetFulfillment([]exchange.SubmissionDescriptor{{ID: "test-descriptor", Format: string(exchange.JWTVC), Path: "$.verifiableCredentials[0]"}}))
+		fulfillment, err := builder.Build()
+		require.NoError(tt, err)
+		fulfillmentJWT := signTestCredentialResponse(tt, keyStoreService, issuerDID, manifest.CredentialResponseContainer{
+			Response:    *fulfillment,
+			Credentials: []any{credJWT.String()},
+		})
+
+		w = httptest.NewRecorder()
+		responseRequest = router.StoreCredentialResponseRequest{HolderDID: holderDID.DID.ID, ResponseJWT: *fulfillmentJWT}
+		req = httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/wallet/responses", newRequestValue(tt, responseRequest))
+		c = newRequestContext(w, req)
+		walletRouter.StoreCredentialResponse(c)
+		assert.True(tt, util.Is2xxResponse(w.Code))
+
+		var resp router.StoreCredentialResponseResponse
+		err = json.NewDecoder(w.Body).Decode(&resp)
+		assert.NoError(tt, err)
+		assert.Equal(tt, fulfillment.ID, resp.ResponseID)
+		assert.Len(tt, resp.Credentials, 1)
+		assert.Equal(tt, fulfillment.ID, resp.Credentials[0].ResponseID)
+		assert.Equal(tt, *credJWT, *resp.Credentials[0].CredentialJWT)
+	})
+
+	t.Run("Test Create Presentation", func(tt *testing.T) {
+		bolt := setupTestDB(tt)
+		require.NotEmpty(tt, bolt)
+
+		keyStoreService := testKeyStoreService(tt, bolt)
+		didService := testDIDService(tt, bolt, keyStoreService)
+		schemaService := testSchemaService(tt, bolt, keyStoreService, didService)
+		credRouter := testCredentialRouter(tt, bolt, keyStoreService, didService, schemaService)
+		walletRouter := testWalletRouter(tt, bolt, keyStoreService, didService, schemaService)
+
+		issuerDID := createTestKeyDID(tt, didService)
+		holderDID := createTestKeyDID(tt, didService)
+		credJWT := createTestCredentialJWT(tt, credRouter, issuerDID, holderDID.DID.ID)
+
+		w := httptest.NewRecorder()
+		storeRequest := router.StoreHeldCredentialRequest{HolderDID: holderDID.DID.ID, CredentialJWT: credJWT}
+		req := httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/wallet/credentials", newRequestValue(tt, storeRequest))
+		c := newRequestContext(w, req)
+		walletRouter.StoreCredential(c)
+		assert.True(tt, util.Is2xxResponse(w.Code))
+
+		var storeResp router.StoreHeldCredentialResponse
+		err := json.NewDecoder(w.Body).Decode(&storeResp)
+		assert.NoError(tt, err)
+
+		// signing with a key the holder does not control
+		w = httptest.NewRecorder()
+		presentationRequest := router.CreateHolderPresentationRequest{
+			HolderDID:     holderDID.DID.ID,
+			HolderKID:     issuerDID.DID.VerificationMethod[0].ID,
+			CredentialIDs: []string{storeResp.ID},
+			Audience:      "did:example:verifier",
+		}
+		req = httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/wallet/presentations", newRequestValue(tt, presentationRequest))
+		c = newRequestContext(w, req)
+		walletRouter.CreatePresentation(c)
+		assert.Contains(tt, w.Body.String(), "does not match presentation holder")
+
+		// good request
+		w = httptest.NewRecorder()
+		presentationRequest.HolderKID = holderDID.DID.VerificationMethod[0].ID
+		req = httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/wallet/presentations", newRequestValue(tt, presentationRequest))
+		c = newRequestContext(w, req)
+		walletRouter.CreatePresentation(c)
+		assert.True(tt, util.Is2xxResponse(w.Code))
+
+		var resp router.CreateHolderPresentationResponse
+		err = json.NewDecoder(w.Body).Decode(&resp)
+		assert.NoError(tt, err)
+		assert.Equal(tt, holderDID.DID.ID, resp.Presentation.Holder)
+		assert.Len(tt, resp.Presentation.VerifiableCredential, 1)
+
+		_, token, vp, err := credsdk.ParseVerifiablePresentationFromJWT(resp.PresentationJWT.String())
+		assert.NoError(tt, err)
+		assert.Equal(tt, []string{"did:example:verifier"}, token.Audience())
+		assert.Equal(tt, holderDID.DID.ID, vp.Holder)
+		assert.Equal(tt, credJWT.String(), vp.VerifiableCredential[0])
+	})
+
+	t.Run("Test Create Presentation Submission", func(tt *testing.T) {
+		bolt := setupTestDB(tt)
+		require.NotEmpty(tt, bolt)
+
+		keyStoreService := testKeyStoreService(tt, bolt)
+		didService := testDIDService(tt, bolt, keyStoreService)
+		schemaService := testSchemaService(tt, bolt, keyStoreService, didService)
+		credRouter := testCredentialRouter(tt, bolt, keyStoreService, didService, schemaService)
+		walletRouter := testWalletRouter(tt, bolt, keyStoreService, didService, schemaService)
+
+		presentationService, err := presentation.NewPresentationService(config.PresentationServiceConfig{}, bolt, didService.GetResolver(), schemaService, keyStoreService)
+		require.NoError(tt, err)
+		pRouter, err := router.NewPresentationRouter(presentationService)
+		require.NoError(tt, err)
+
+		issuerDID := createTestKeyDID(tt, didService)
+		holderDID := createTestKeyDID(tt, didService)
+		verifierDID := createTestKeyDID(tt, didService)
+		credJWT := createTestCredentialJWT(tt, credRouter, issuerDID, holderDID.DID.ID)
+
+		w := httptest.NewRecorder()
+		storeRequest := router.StoreHeldCredentialRequest{HolderDID: holderDID.DID.ID, CredentialJWT: credJWT}
+		req := httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/wallet/credentials", newRequestValue(tt, storeRequest))
+		c := newRequestContext(w, req)
+		walletRouter.StoreCredential(c)
+		assert.True(tt, util.Is2xxResponse(w.Code))
+
+		definition := createPresentationDefinition(tt, pRouter, WithInputDescriptors([]exchange.InputDescriptor{
+			{
+				ID: "first_name",
+				Constraints: &exchange.Constraints{
+					Fields: []exchange.Field{{ID: "first_name", Path: []string{"$.vc.credentialSubject.firstName"}}},
+				},
+			},
+		}))
+
+		// the verifier receives the submission over http
+		verifier := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			pRouter.CreateSubmission(newRequestContext(w, req))
+		}))
+		defer verifier.Close()
+
+		// neither a definition nor a request
+		w = httptest.NewRecorder()
+		submissionRequest := router.CreateHolderSubmissionRequest{
+			HolderDID: holderDID.DID.ID,
+			HolderKID: holderDID.DID.VerificationMethod[0].ID,
+		}
+		req = httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/wallet/submissions", newRequestValue(tt, submissionRequest))
+		c = newRequestContext(w, req)
+		walletRouter.CreateSubmission(c)
+		assert.Contains(tt, w.Body.String(), "invalid create presentation submission request")
+
+		// a definition the holder cannot fulfill
+		unfulfillable := createPresentationDefinition(tt, pRouter)
+		w = httptest.NewRecorder()
+		submissionRequest.PresentationDefinitionID = unfulfillable.PresentationDefinition.ID
+		req = httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/wallet/submissions", newRequestValue(tt, submissionRequest))
+		c = newRequestContext(w, req)
+		walletRouter.CreateSubmission(c)
+		assert.Contains(tt, w.Body.String(), "could not create presentation submission")
+
+		// by definition ID, submitted to the verifier
+		w = httptest.NewRecorder()
+		submissionRequest.PresentationDefinitionID = definition.PresentationDefinition.ID
+		submissionRequest.SubmitURL = verifier.URL + "/v1/presentations/submissions"
+		req = httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/wallet/submissions", newRequestValue(tt, submissionRequest))
+		c = newRequestContext(w, req)
+		walletRouter.CreateSubmission(c)
+		assert.True(tt, util.Is2xxResponse(w.Code), w.Body.String())
+
+		var resp router.CreateHolderSubmissionResponse
+		err = json.NewDecoder(w.Body).Decode(&resp)
+		assert.NoError(tt, err)
+		assert.Equal(tt, definition.PresentationDefinition.ID, resp.PresentationSubmission.DefinitionID)
+		assert.Len(tt, resp.Presentation.VerifiableCredential, 1)
+		assert.NotEmpty(tt, resp.SubmissionJWT)
+		assert.NotNil(tt, resp.SubmitResult)
+		assert.True(tt, util.Is2xxResponse(resp.SubmitResult.StatusCode))
+
+		var op router.Operation
+		err = json.Unmarshal([]byte(resp.SubmitResult.Body), &op)
+		assert.NoError(tt, err)
+		assert.Contains(tt, op.ID, "presentations/submissions/")
+
+		// by presentation request, the audience is the requester and the submission is bound to the request
+		requestResp, err := presentationService.CreateRequest(context.Background(), model.CreateRequestRequest{
+			PresentationRequest: model.Request{
+				Expiration:               time.Now().Add(time.Hour),
+				IssuerDID:                verifierDID.DID.ID,
+				IssuerKID:                verifierDID.DID.VerificationMethod[0].ID,
+				PresentationDefinitionID: definition.PresentationDefinition.ID,
+			},
+		})
+		require.NoError(tt, err)
+
+		w = httptest.NewRecorder()
+		submissionRequest = router.CreateHolderSubmissionRequest{
+			HolderDID:              holderDID.DID.ID,
+			HolderKID:              holderDID.DID.VerificationMethod[0].ID,
+			PresentationRequestJWT: &requestResp.PresentationDefinitionJWT,
+			SubmitURL:              verifier.URL + "/v1/presentations/submissions",
+		}
+		req = httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/wallet/submissions", newRequestValue(tt, submissionRequest))
+		c = newRequestContext(w, req)
+		walletRouter.CreateSubmission(c)
+		assert.True(tt, util.Is2xxResponse(w.Code), w.Body.String())
+
+		var requestSubmissionResp router.CreateHolderSubmissionResponse
+		err = json.NewDecoder(w.Body).Decode(&requestSubmissionResp)
+		assert.NoError(tt, err)
+		assert.Equal(tt, requestResp.ID, requestSubmissionResp.PresentationRequestID)
+		assert.NotNil(tt, requestSubmissionResp.SubmitResult)
+		assert.True(tt, util.Is2xxResponse(requestSubmissionResp.SubmitResult.StatusCode))
+
+		_, token, vp, err := credsdk.ParseVerifiablePresentationFromJWT(requestSubmissionResp.SubmissionJWT.String())
+		assert.NoError(tt, err)
+		assert.Equal(tt, []string{verifierDID.DID.ID}, token.Audience())
+		nonce, ok := token.Get("nonce")
+		assert.True(tt, ok)
+		assert.Equal(tt, requestResp.Nonce, nonce)
+		assert.Equal(tt, holderDID.DID.ID, vp.Holder)
+		assert.Equal(tt, credJWT.String(), vp.VerifiableCredential[0])
+
+		// the verifier only accepts one submission per request
+		w = httptest.NewRecorder()
+		req = httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/wallet/submissions", newRequestValue(tt, submissionRequest))
+		c = newRequestContext(w, req)
+		walletRouter.CreateSubmission(c)
+		assert.Contains(tt, w.Body.String(), "has already been used")
+	})
+}
+
+func testWalletRouter(t *testing.T, bolt storage.ServiceStorage, keyStore *keystore.Service, did *did.Service, schema *schema.Service) *router.WalletRouter {
+	serviceConfig := config.WalletServiceConfig{BaseServiceConfig: &config.BaseServiceConfig{Name: "wallet"}}
+	walletService, err := wallet.NewWalletService(serviceConfig, bolt, keyStore, did.GetResolver(), schema)
+	require.NoError(t, err)
+	require.NotEmpty(t, walletService)
+
+	// create router for service
+	walletRouter, err := router.NewWalletRouter(walletService)
+	require.NoError(t, err)
+	require.NotEmpty(t, walletRouter)
+
+	return walletRouter
+}
+
+func createTestKeyDID(t *testing.T, didService *did.Service) *did.CreateDIDResponse {
+	created, err := didService.CreateDIDByMethod(context.Background(), did.CreateDIDRequest{
+		Method:  didsdk.KeyMethod,
+		KeyType: crypto.Ed25519,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, created)
+	return created
+}
+
+func createTestCredentialJWT(t *testing.T, credRouter *router.CredentialRouter, issuer *did.CreateDIDResponse, subject string) *keyaccess.JWT {
+	createCredRequest := router.CreateCredentialRequest{
+		Issuer:    issuer.DID.ID,
+		IssuerKID: issuer.DID.VerificationMethod[0].ID,
+		Subject:   subject,
+		Data:      map[string]any{"firstName": "Jack"},
+		Expiry:    time.Now().Add(24 * time.Hour).Format(time.RFC3339),
+	}
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPut, "https://ssi-service.com/v1/credentials", newRequestValue(t, createCredRequest))
+	c := newRequestContext(w, req)
+	credRouter.CreateCredential(c)
+	require.True(t, util.Is2xxResponse(w.Code))
+
+	var resp router.CreateCredentialResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.NotEmpty(t, resp.CredentialJWT)
+	return resp.CredentialJWT
+}
+
+func signTestCredentialResponse(t *testing.T, keyStore *keystore.Service, issuer *did.CreateDIDResponse, container manifest.CredentialResponseContainer) *keyaccess.JWT {
+	kid := issuer.DID.VerificationMethod[0].ID
+	gotKey, err := keyStore.GetKey(context.Background(), keystore.GetKeyRequest{ID: kid})
+	require.NoError(t, err)
+	keyAccess, err := keyaccess.NewJWKKeyAccess(gotKey.Controller, gotKey.ID, gotKey.Key)
+	require.NoError(t, err)
+	token, err := keyAccess.SignJSON(container)
+	require.NoError(t, err)
+	return token
+}
@@ -9,8 +9,9 @@ const (
 )
 
 type CreateCredentialRequest struct {
-	Issuer    string `json:"issuer" validate:"required"`
-	IssuerKID string `json:"issuerKid" validate:"required"`
+	Issuer string `json:"issuer" validate:"required"`
+	// The key to sign with. When empty, it's the issuer's current key, which follows key rotations.
+	IssuerKID string `json:"issuerKid,omitempty"`
 	Subject   string `json:"subject" validate:"required"`
 	// A context is optional. If not present, we'll apply default, required context values.
 	Context string `json:"context,omitempty"`
@@ -22,17 +22,20 @@ import (
 	"github.com/tbd54566975/ssi-service/pkg/service/framework"
 	"github.com/tbd54566975/ssi-service/pkg/service/keystore"
 	"github.com/tbd54566975/ssi-service/pkg/service/schema"
+	"github.com/tbd54566975/ssi-service/pkg/service/trust"
 	"github.com/tbd54566975/ssi-service/pkg/storage"
 )
 
 type Service struct {
-	storage  *Storage
-	config   config.CredentialServiceConfig
-	verifier *credint.Verifier
+	storage      *Storage
+	trustStorage *trust.Storage
+	config       config.CredentialServiceConfig
+	verifier     *credint.Verifier
 
 	// external dependencies
-	keyStore *keystore.Service
-	schema   *schema.Service
+	keyStore    *keystore.Service
+	didResolver resolution.Resolver
+	schema      *schema.Service
 }
 
 func (s Service) Type() framework.Type {
@@ -71,16 +74,22 @@ func NewCredentialService(config config.CredentialServiceConfig, s storage.Servi
 	if err != nil {
 		return nil, sdkutil.LoggingErrorMsg(err, "could not instantiate storage for the credential service")
 	}
+	trustStorage, err := trust.NewTrustStorage(s)
+	if err != nil {
+		return nil, sdkutil.LoggingErrorMsg(err, "could not instantiate trust storage for the credential service")
+	}
 	verifier, err := credint.NewCredentialVerifier(didResolver, schema)
 	if err != nil {
 		return nil, sdkutil.LoggingErrorMsg(err, "could not instantiate verifier for the credential service")
 	}
 	service := Service{
-		storage:  credentialStorage,
-		config:   config,
-		verifier: verifier,
-		keyStore: keyStore,
-		schema:   schema,
+		storage:      credentialStorage,
+		trustStorage: trustStorage,
+		config:       config,
+		verifier:     verifier,
+		keyStore:     keyStore,
+		didResolver:  didResolver,
+		schema:       schema,
 	}
 	if !service.Status().IsReady() {
 		return nil, errors.New(service.Status().Message)
@@ -89,6 +98,14 @@ func NewCredentialService(config config.CredentialServiceConfig, s storage.Servi
 }
 
 func (s Service) CreateCredential(ctx context.Context, request CreateCredentialRequest) (*CreateCredentialResponse, error) {
+	if request.IssuerKID == "" {
+		issuerKID, err := s.currentIssuerKID(ctx, request.Issuer)
+		if err != nil {
+			return nil, err
+		}
+		request.IssuerKID = issuerKID
+	}
+
 	watchKeys := make([]storage.WatchKey, 0)
 
 	var slcMetadata StatusListCredentialMetadata
@@ -166,6 +183,13 @@ func (s Service) createCredentialBusinessLogic(ctx context.Context, request Crea
 		}
 	}
 
+	// add any types beyond the default VerifiableCredential type
+	if len(request.Types) > 0 {
+		if err := builder.AddType(request.Types); err != nil {
+			return nil, sdkutil.LoggingErrorMsgf(err, "could not add types to credential: %v", request.Types)
+		}
+	}
+
 	// if a schema value exists, verify we can access it, validate the data against it, then set it
 	var knownSchema *schemalib.VCJSONSchema
 	if request.SchemaID != "" {
@@ -341,9 +365,22 @@ func createStatusListCredential(ctx context.Context, tx storage.Tx, s Service, s
 	return randomIndex, generatedStatusListCredential, nil
 }
 
+// currentIssuerKID returns the id of the key the issuer currently signs credentials with, which follows key rotations.
+func (s Service) currentIssuerKID(ctx context.Context, issuer string) (string, error) {
+	resolved, err := s.didResolver.Resolve(ctx, issuer)
+	if err != nil {
+		return "", sdkutil.LoggingErrorMsgf(err, "resolving issuer DID<%s>", issuer)
+	}
+	issuerKID, err := s.keyStore.GetAssertionKeyID(ctx, resolved.Document)
+	if err != nil {
+		return "", sdkutil.LoggingErrorMsgf(err, "getting current key of issuer<%s>", issuer)
+	}
+	return issuerKID, nil
+}
+
 // signCredentialJWT signs a credential and returns it as a vc-jwt
 func (s Service) signCredentialJWT(ctx context.Context, issuerKID string, cred credential.VerifiableCredential) (*keyaccess.JWT, error) {
-	gotKey, err := s.keyStore.GetKey(ctx, keystore.GetKeyRequest{ID: issuerKID})
+	gotKey, err := s.keyStore.GetSigningKey(ctx, keystore.GetKeyRequest{ID: issuerKID})
 	if err != nil {
 		return nil, sdkutil.LoggingErrorMsgf(err, "getting key for signing credential<%s>", issuerKID)
 	}
@@ -364,6 +401,9 @@ func (s Service) signCredentialJWT(ctx context.Context, issuerKID string, cred c
 type VerifyCredentialRequest struct {
 	DataIntegrityCredential *credential.VerifiableCredential `json:"credential,omitempty"`
 	CredentialJWT           *keyaccess.JWT                   `json:"credentialJwt,omitempty"`
+
+	// When true, the credential's issuer must be trusted for the credential's schema by the trust registry.
+	RequireTrustedIssuer bool `json:"requireTrustedIssuer,omitempty"`
 }
 
 // IsValid checks if the request is valid, meaning there is at least one data integrity (with proof)
@@ -388,6 +428,7 @@ type VerifyCredentialResponse struct {
 // 2. Makes sure the credential has is not expired
 // 3. Makes sure the credential complies with the VC Data Model
 // 4. If the credential has a schema, makes sure its data complies with the schema
+// 5. If requested, makes sure the issuer is trusted for the credential's schema by the trust registry
 // LATER: Makes sure the credential has not been revoked, other checks.
 // Note: https://github.com/TBD54566975/ssi-sdk/issues/213
 func (s Service) VerifyCredential(ctx context.Context, request VerifyCredentialRequest) (*VerifyCredentialResponse, error) {
@@ -397,17 +438,31 @@ func (s Service) VerifyCredential(ctx context.Context, request VerifyCredentialR
 		return nil, sdkutil.LoggingErrorMsg(err, "invalid verify credential request")
 	}
 
+	cred := request.DataIntegrityCredential
 	if request.CredentialJWT != nil {
 		err := s.verifier.VerifyJWTCredential(ctx, *request.CredentialJWT)
 		if err != nil {
 			return &VerifyCredentialResponse{Verified: false, Reason: err.Error()}, nil
 		}
+		_, _, cred, err = credential.ParseVerifiableCredentialFromJWT(request.CredentialJWT.String())
+		if err != nil {
+			return nil, sdkutil.LoggingErrorMsg(err, "parsing credential from JWT")
+		}
 	} else {
 		if err := s.verifier.VerifyDataIntegrityCredential(ctx, *request.DataIntegrityCredential); err != nil {
 			return &VerifyCredentialResponse{Verified: false, Reason: err.Error()}, nil
 		}
 	}
 
+	if request.RequireTrustedIssuer {
+		if err := trust.VerifyCredentialIssuer(ctx, s.trustStorage, *cred); err != nil {
+			if errors.Is(err, trust.ErrIssuerNotTrusted) {
+				return &VerifyCredentialResponse{Verified: false, Reason: err.Error()}, nil
+			}
+			return nil, sdkutil.LoggingErrorMsg(err, "checking issuer trust")
+		}
+	}
+
 	return &VerifyCredentialResponse{Verified: true}, nil
 }
 
@@ -674,7 +729,18 @@ func updateCredentialStatus(ctx context.Context, tx storage.Tx, s Service, gotCr
 
 	generatedStatusListCredential.CredentialSchema = gotCred.Credential.CredentialSchema
 
-	statusListCredJWT, err := s.signCredentialJWT(ctx, gotCred.IssuerKID, *generatedStatusListCredential)
+	// the status list is signed now, so when the key of the credential has since been rotated, use the current key
+	statusListIssuerKID := gotCred.IssuerKID
+	gotKey, err := s.keyStore.GetKeyDetails(ctx, keystore.GetKeyDetailsRequest{ID: statusListIssuerKID})
+	if err != nil {
+		return nil, sdkutil.LoggingErrorMsg(err, "getting key of credential")
+	}
+	if gotKey.Revoked {
+		if statusListIssuerKID, err = s.currentIssuerKID(ctx, gotCred.Issuer); err != nil {
+			return nil, err
+		}
+	}
+	statusListCredJWT, err := s.signCredentialJWT(ctx, statusListIssuerKID, *generatedStatusListCredential)
 	if err != nil {
 		return nil, sdkutil.LoggingErrorMsg(err, "could not sign status list credential")
 	}
@@ -682,7 +748,7 @@ func updateCredentialStatus(ctx context.Context, tx storage.Tx, s Service, gotCr
 	// store the status list credential
 	statusListContainer := credint.Container{
 		ID:            generatedStatusListCredential.ID,
-		IssuerKID:     gotCred.IssuerKID,
+		IssuerKID:     statusListIssuerKID,
 		Credential:    generatedStatusListCredential,
 		CredentialJWT: statusListCredJWT,
 	}
@@ -0,0 +1,141 @@
+package did
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	didsdk "github.com/TBD54566975/ssi-sdk/did"
+	"github.com/pkg/errors"
+)
+
+const (
+	didContentType     = "application/did+ld+json"
+	uriListContentType = "text/uri-list"
+
+	serviceParam     = "service"
+	relativeRefParam = "relativeRef"
+)
+
+// didURL is a DID URL split into the DID and the parts dereferencing it https://www.w3.org/TR/did-core/#did-url-syntax
+type didURL struct {
+	did      string
+	path     string
+	query    url.Values
+	fragment string
+}
+
+func parseDIDURL(rawURL string) (*didURL, error) {
+	var parsed didURL
+	rest := rawURL
+	if i := strings.IndexByte(rest, '#'); i >= 0 {
+		parsed.fragment = rest[i+1:]
+		rest = rest[:i]
+	}
+	if i := strings.IndexByte(rest, '?'); i >= 0 {
+		query, err := url.ParseQuery(rest[i+1:])
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing query of DID URL: %s", rawURL)
+		}
+		parsed.query = query
+		rest = rest[:i]
+	}
+	if i := strings.IndexByte(rest, '/'); i >= 0 {
+		parsed.path = rest[i:]
+		rest = rest[:i]
+	}
+	if !strings.HasPrefix(rest, "did:") {
+		return nil, fmt.Errorf("not a DID URL: %s", rawURL)
+	}
+	parsed.did = rest
+	return &parsed, nil
+}
+
+// dereferenceDocument dereferences the parts of a DID URL following the DID within the resolved document. A service
+// parameter selects the endpoints of a service, to which the relativeRef parameter and the fragment are applied. A
+// fragment on its own selects a verification method or service. The document is selected otherwise.
+func dereferenceDocument(doc didsdk.Document, parsed didURL) (content any, contentType string, err error) {
+	if parsed.path != "" {
+		return nil, "", fmt.Errorf("dereferencing paths of DID URLs is not supported: %s", parsed.path)
+	}
+	for param := range parsed.query {
+		if param != serviceParam && param != relativeRefParam {
+			return nil, "", fmt.Errorf("unsupported DID URL parameter: %s", param)
+		}
+	}
+
+	if len(parsed.query) > 0 {
+		serviceID := parsed.query.Get(serviceParam)
+		if serviceID == "" {
+			return nil, "", fmt.Errorf("the %s parameter requires the %s parameter", relativeRefParam, serviceParam)
+		}
+		i := findService(doc, serviceID)
+		if i < 0 {
+			return nil, "", fmt.Errorf("service<%s> not found in document", serviceID)
+		}
+		endpoints, err := serviceEndpointURLs(doc.Services[i])
+		if err != nil {
+			return nil, "", err
+		}
+		selected := make([]string, 0, len(endpoints))
+		for _, endpoint := range endpoints {
+			selectedEndpoint, err := selectServiceEndpoint(endpoint, parsed.query.Get(relativeRefParam), parsed.fragment)
+			if err != nil {
+				return nil, "", errors.Wrapf(err, "dereferencing endpoint of service<%s>", serviceID)
+			}
+			selected = append(selected, selectedEndpoint)
+		}
+		return selected, uriListContentType, nil
+	}
+
+	if parsed.fragment != "" {
+		if i := findVerificationMethod(doc, parsed.fragment); i >= 0 {
+			return doc.VerificationMethod[i], didContentType, nil
+		}
+		if i := findService(doc, parsed.fragment); i >= 0 {
+			return doc.Services[i], didContentType, nil
+		}
+		return nil, "", fmt.Errorf("no verification method or service with fragment<%s> in document", parsed.fragment)
+	}
+	return doc, didContentType, nil
+}
+
+// serviceEndpointURLs returns the URLs of the endpoint of a service, which is either a URL or a set of them. Maps
+// describing endpoints can't be dereferenced.
+func serviceEndpointURLs(service didsdk.Service) ([]string, error) {
+	switch endpoint := service.ServiceEndpoint.(type) {
+	case string:
+		return []string{endpoint}, nil
+	case []string:
+		return endpoint, nil
+	case []any:
+		endpoints := make([]string, 0, len(endpoint))
+		for _, e := range endpoint {
+			s, ok := e.(string)
+			if !ok {
+				return nil, fmt.Errorf("endpoint of service<%s> is not a set of URLs", service.ID)
+			}
+			endpoints = append(endpoints, s)
+		}
+		return endpoints, nil
+	}
+	return nil, fmt.Errorf("endpoint of service<%s> is not a URL", service.ID)
+}
+
+func selectServiceEndpoint(endpoint, relativeRef, fragment string) (string, error) {
+	endpointURL, err := url.Parse(endpoint)
+	if err != nil {
+		return "", errors.Wrapf(err, "parsing endpoint: %s", endpoint)
+	}
+	if relativeRef != "" {
+		ref, err := url.Parse(relativeRef)
+		if err != nil {
+			return "", errors.Wrapf(err, "parsing relative reference: %s", relativeRef)
+		}
+		endpointURL = endpointURL.ResolveReference(ref)
+	}
+	if fragment != "" {
+		endpointURL.Fragment = fragment
+	}
+	return endpointURL.String(), nil
+}
@@ -19,6 +19,7 @@ type MethodHandler interface {
 	GetDID(ctx context.Context, request GetDIDRequest) (*GetDIDResponse, error)
 	ListDIDs(ctx context.Context) (*ListDIDsResponse, error)
 	ListDeletedDIDs(ctx context.Context) (*ListDIDsResponse, error)
+	UpdateDID(ctx context.Context, request UpdateDIDRequest) (*UpdateDIDResponse, error)
 	SoftDeleteDID(ctx context.Context, request DeleteDIDRequest) error
 }
 
@@ -66,7 +67,11 @@ func (h handlerResolver) Resolve(ctx context.Context, did string, _ ...resolutio
 	if err != nil {
 		return nil, errors.Wrap(err, "getting DID from handler")
 	}
-	return &resolution.ResolutionResult{Document: gotDIDResponse.DID}, nil
+	resolved := resolution.ResolutionResult{Document: gotDIDResponse.DID}
+	if gotDIDResponse.DocumentMetadata != nil {
+		resolved.DocumentMetadata = *gotDIDResponse.DocumentMetadata
+	}
+	return &resolved, nil
 }
 
 func (h handlerResolver) Methods() []didsdk.Method {
@@ -0,0 +1,123 @@
+package did
+
+import (
+	gocrypto "crypto"
+	"fmt"
+	"strings"
+
+	"github.com/TBD54566975/ssi-sdk/crypto"
+	"github.com/TBD54566975/ssi-sdk/crypto/jwx"
+	"github.com/TBD54566975/ssi-sdk/did"
+	"github.com/mr-tron/base58"
+	"github.com/pkg/errors"
+
+	"github.com/tbd54566975/ssi-service/pkg/service/keystore"
+)
+
+// didImporter is implemented by the handlers of methods whose DIDs can be created outside the service and imported.
+type didImporter interface {
+	// importedDocument validates the document of a DID to import, and returns the document to store for it.
+	importedDocument(doc did.Document) (*did.Document, error)
+}
+
+// importKeys returns the requests storing the private keys of a DID to import, after checking that each of them is the
+// private key of a verification method of the document. Keys are stored under the absolute id of their verification
+// method, since relative ids aren't unique across DIDs.
+func importKeys(doc did.Document, keys []ImportDIDKey) ([]keystore.StoreKeyRequest, error) {
+	storeRequests := make([]keystore.StoreKeyRequest, 0, len(keys))
+	imported := make(map[string]bool, len(keys))
+	for _, importKey := range keys {
+		i := findVerificationMethod(doc, importKey.ID)
+		if i < 0 {
+			return nil, fmt.Errorf("verification method<%s> not found in document", importKey.ID)
+		}
+		kid := doc.VerificationMethod[i].ID
+		if imported[kid] {
+			return nil, fmt.Errorf("more than one key given for verification method<%s>", kid)
+		}
+		imported[kid] = true
+
+		privKey, keyType, err := parseImportedKey(importKey)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing key of verification method<%s>", kid)
+		}
+		if err = checkKeyMatchesVerificationMethod(doc, kid, privKey); err != nil {
+			return nil, err
+		}
+
+		privKeyBytes, err := crypto.PrivKeyToBytes(privKey)
+		if err != nil {
+			return nil, errors.Wrapf(err, "encoding key of verification method<%s>", kid)
+		}
+		storeRequests = append(storeRequests, keystore.StoreKeyRequest{
+			ID:               absoluteID(doc.ID, kid),
+			Type:             keyType,
+			Controller:       doc.ID,
+			PrivateKeyBase58: base58.Encode(privKeyBytes),
+		})
+	}
+	return storeRequests, nil
+}
+
+// absoluteID returns the id of a verification method of the document with the given ID, prefixed with the DID when
+// it's relative.
+func absoluteID(docID, id string) string {
+	if strings.HasPrefix(id, "#") {
+		return docID + id
+	}
+	return id
+}
+
+func parseImportedKey(importKey ImportDIDKey) (gocrypto.PrivateKey, crypto.KeyType, error) {
+	switch {
+	case importKey.PrivateKeyJWK != nil && importKey.PrivateKeyBase58 != "":
+		return nil, "", errors.New("only one of privateKeyJwk and privateKeyBase58 can be given")
+	case importKey.PrivateKeyJWK != nil:
+		privKey, err := importKey.PrivateKeyJWK.ToPrivateKey()
+		if err != nil {
+			return nil, "", errors.Wrap(err, "getting private key from JWK")
+		}
+		keyType, err := crypto.GetKeyTypeFromPrivateKey(privKey)
+		if err != nil {
+			return nil, "", errors.Wrap(err, "getting type of private key")
+		}
+		return privKey, keyType, nil
+	case importKey.PrivateKeyBase58 != "":
+		if importKey.KeyType == "" {
+			return nil, "", errors.New("keyType is required with privateKeyBase58")
+		}
+		privKeyBytes, err := base58.Decode(importKey.PrivateKeyBase58)
+		if err != nil {
+			return nil, "", errors.Wrap(err, "decoding base58 private key")
+		}
+		privKey, err := crypto.BytesToPrivKey(privKeyBytes, importKey.KeyType)
+		if err != nil {
+			return nil, "", errors.Wrap(err, "getting private key from bytes")
+		}
+		return privKey, importKey.KeyType, nil
+	}
+	return nil, "", errors.New("one of privateKeyJwk and privateKeyBase58 is required")
+}
+
+// checkKeyMatchesVerificationMethod compares the public key of the private key with the public key of the verification
+// method as JWKs, since verification methods express keys in several formats.
+func checkKeyMatchesVerificationMethod(doc did.Document, kid string, privKey gocrypto.PrivateKey) error {
+	pubKeyJWK, _, err := jwx.PrivateKeyToPrivateKeyJWK(kid, privKey)
+	if err != nil {
+		return errors.Wrapf(err, "getting public key of key of verification method<%s>", kid)
+	}
+	vmPubKey, err := did.GetKeyFromVerificationMethod(doc, kid)
+	if err != nil {
+		return errors.Wrapf(err, "getting public key of verification method<%s>", kid)
+	}
+	vmPubKeyJWK, err := jwx.PublicKeyToPublicKeyJWK(kid, vmPubKey)
+	if err != nil {
+		return errors.Wrapf(err, "getting public key of verification method<%s>", kid)
+	}
+	if pubKeyJWK.KTY != vmPubKeyJWK.KTY || pubKeyJWK.CRV != vmPubKeyJWK.CRV ||
+		pubKeyJWK.X != vmPubKeyJWK.X || pubKeyJWK.Y != vmPubKeyJWK.Y ||
+		pubKeyJWK.N != vmPubKeyJWK.N || pubKeyJWK.E != vmPubKeyJWK.E {
+		return fmt.Errorf("key does not match the public key of verification method<%s>", kid)
+	}
+	return nil
+}
@@ -3,24 +3,34 @@ package did
 import (
 	"context"
 	"fmt"
+	"io"
 	"net/http"
+	"strings"
 
 	"github.com/TBD54566975/ssi-sdk/crypto"
 	"github.com/TBD54566975/ssi-sdk/crypto/jwx"
 	"github.com/TBD54566975/ssi-sdk/did"
 	"github.com/TBD54566975/ssi-sdk/did/ion"
 	"github.com/TBD54566975/ssi-sdk/util"
+	"github.com/goccy/go-json"
 	"github.com/google/uuid"
 	"github.com/mr-tron/base58"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 
 	"github.com/tbd54566975/ssi-service/pkg/service/keystore"
+	"github.com/tbd54566975/ssi-service/pkg/service/operation"
+	opdid "github.com/tbd54566975/ssi-service/pkg/service/operation/did"
+	opstorage "github.com/tbd54566975/ssi-service/pkg/service/operation/storage"
+	"github.com/tbd54566975/ssi-service/pkg/storage"
 )
 
 const (
 	updateKeySuffix  string = "update"
 	recoverKeySuffix string = "recover"
+
+	// ionOperationNamespace maps the ids of operations submitted to the ION node to the DIDs they were submitted for
+	ionOperationNamespace = "ion_operation"
 )
 
 func NewIONHandler(baseURL string, s *Storage, ks *keystore.Service) (MethodHandler, error) {
@@ -37,14 +47,27 @@ func NewIONHandler(baseURL string, s *Storage, ks *keystore.Service) (MethodHand
 	if err != nil {
 		return nil, errors.Wrap(err, "creating ion resolver")
 	}
-	return &ionHandler{method: did.IONMethod, resolver: r, storage: s, keyStore: ks}, nil
+	opsStorage, err := operation.NewOperationStorage(s.db)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating operation storage")
+	}
+	return &ionHandler{
+		method:     did.IONMethod,
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		resolver:   r,
+		storage:    s,
+		opsStorage: opsStorage,
+		keyStore:   ks,
+	}, nil
 }
 
 type ionHandler struct {
-	method   did.Method
-	resolver *ion.Resolver
-	storage  *Storage
-	keyStore *keystore.Service
+	method     did.Method
+	baseURL    string
+	resolver   *ion.Resolver
+	storage    *Storage
+	opsStorage *operation.Storage
+	keyStore   *keystore.Service
 }
 
 type CreateIONDIDOptions struct {
@@ -70,6 +93,59 @@ type ionStoredDID struct {
 	SoftDeleted bool         `json:"softDeleted"`
 	LongFormDID string       `json:"longFormDID"`
 	Operations  []any        `json:"operations"`
+	// Public keys committed to for the next update and recovery, whose private keys are in the keystore
+	UpdatePublicKey   *jwx.PublicKeyJWK `json:"updatePublicKey,omitempty"`
+	RecoveryPublicKey *jwx.PublicKeyJWK `json:"recoveryPublicKey,omitempty"`
+	// Whether a deactivate operation has been submitted for the DID, after which no further operations are possible
+	Deactivated bool `json:"deactivated"`
+	// Operations submitted to the ION node which have not been seen anchored yet, in submission order
+	PendingOperations []pendingIONOperation `json:"pendingOperations,omitempty"`
+}
+
+// pendingIONOperation is an operation submitted to the ION node, along with the state of the DID once it is anchored.
+type pendingIONOperation struct {
+	// ID of the operation tracking the anchoring
+	ID                 string            `json:"id"`
+	Type               ion.OperationType `json:"type"`
+	UpdateCommitment   string            `json:"updateCommitment,omitempty"`
+	RecoveryCommitment string            `json:"recoveryCommitment,omitempty"`
+	DID                did.Document      `json:"did"`
+	// Ids the private keys of the update and recovery keys the operation commits to are stored under, until they
+	// replace the update and recovery keys of the DID once the operation is anchored. Empty when the operation doesn't
+	// commit to a new key.
+	UpdateKeyID   string `json:"updateKeyId,omitempty"`
+	RecoveryKeyID string `json:"recoveryKeyId,omitempty"`
+}
+
+// pendingKeyID returns the id a key the operation commits to is stored under until the operation is anchored.
+func (p pendingIONOperation) pendingKeyID(didID, suffix string) string {
+	return didID + "#" + suffix + "-" + opstorage.StatusObjectID(p.ID)
+}
+
+// ionCommitmentKey is a key an operation commits to for the next update or recovery, identified by the suffix of the
+// id it's stored under once the operation is anchored.
+type ionCommitmentKey struct {
+	suffix     string
+	publicKey  *jwx.PublicKeyJWK
+	privateKey *jwx.PrivateKeyJWK
+}
+
+// isAnchored is whether the state of the DID on the network shows the operation as anchored.
+func (p pendingIONOperation) isAnchored(state ionDIDState) bool {
+	if state.Deactivated {
+		return p.Type == ion.Deactivate
+	}
+	return state.Method.UpdateCommitment == p.UpdateCommitment && state.Method.RecoveryCommitment == p.RecoveryCommitment
+}
+
+// ionDIDState is the document metadata of a DID resolved by an ION node, which describes its state on the network.
+type ionDIDState struct {
+	Deactivated bool `json:"deactivated"`
+	Method      struct {
+		Published          bool   `json:"published"`
+		UpdateCommitment   string `json:"updateCommitment"`
+		RecoveryCommitment string `json:"recoveryCommitment"`
+	} `json:"method"`
 }
 
 func (i ionStoredDID) GetID() string {
@@ -84,6 +160,41 @@ func (i ionStoredDID) IsSoftDeleted() bool {
 	return i.SoftDeleted
 }
 
+// updateKeyID returns the id the private key of UpdatePublicKey is stored under, which is a pending id when a pending
+// operation committed to it.
+func (i ionStoredDID) updateKeyID() string {
+	for j := len(i.PendingOperations) - 1; j >= 0; j-- {
+		if keyID := i.PendingOperations[j].UpdateKeyID; keyID != "" {
+			return keyID
+		}
+	}
+	return i.ID + "#" + updateKeySuffix
+}
+
+// recoveryKeyID returns the id the private key of RecoveryPublicKey is stored under, which is a pending id when a
+// pending operation committed to it.
+func (i ionStoredDID) recoveryKeyID() string {
+	for j := len(i.PendingOperations) - 1; j >= 0; j-- {
+		if keyID := i.PendingOperations[j].RecoveryKeyID; keyID != "" {
+			return keyID
+		}
+	}
+	return i.ID + "#" + recoverKeySuffix
+}
+
+// commitmentKeyIDs returns the ids of all the update and recovery keys of the DID, including pending ones.
+func (i ionStoredDID) commitmentKeyIDs() []string {
+	keyIDs := []string{i.ID + "#" + updateKeySuffix, i.ID + "#" + recoverKeySuffix}
+	for _, pending := range i.PendingOperations {
+		for _, keyID := range []string{pending.UpdateKeyID, pending.RecoveryKeyID} {
+			if keyID != "" {
+				keyIDs = append(keyIDs, keyID)
+			}
+		}
+	}
+	return keyIDs
+}
+
 func (h *ionHandler) CreateDID(ctx context.Context, request CreateDIDRequest) (*CreateDIDResponse, error) {
 	// process options
 	var opts CreateIONDIDOptions
@@ -166,6 +277,10 @@ func (h *ionHandler) CreateDID(ctx context.Context, request CreateDIDRequest) (*
 		LongFormDID: ionDID.LongForm(),
 		Operations:  ionDID.Operations(),
 	}
+	updatePrivateKey, recoveryPrivateKey := ionDID.GetUpdatePrivateKey(), ionDID.GetRecoveryPrivateKey()
+	updatePublicKey, recoveryPublicKey := updatePrivateKey.ToPublicKeyJWK(), recoveryPrivateKey.ToPublicKeyJWK()
+	storedDID.UpdatePublicKey = &updatePublicKey
+	storedDID.RecoveryPublicKey = &recoveryPublicKey
 	if err = h.storage.StoreDID(ctx, storedDID); err != nil {
 		return nil, errors.Wrap(err, "storing ion did document")
 	}
@@ -234,7 +349,12 @@ func (h *ionHandler) GetDID(ctx context.Context, request GetDIDRequest) (*GetDID
 	gotDID := new(ionStoredDID)
 	err := h.storage.GetDID(ctx, id, gotDID)
 	if err == nil {
-		return &GetDIDResponse{DID: gotDID.DID}, nil
+		metadata, err := h.storage.GetDocumentMetadata(ctx, gotDID)
+		if err != nil {
+			return nil, err
+		}
+		metadata.Deactivated = metadata.Deactivated || gotDID.Deactivated
+		return &GetDIDResponse{DID: gotDID.DID, DocumentMetadata: metadata}, nil
 	}
 	logrus.WithError(err).Warnf("error getting DID from storage: %s", id)
 
@@ -243,7 +363,7 @@ func (h *ionHandler) GetDID(ctx context.Context, request GetDIDRequest) (*GetDID
 	if err != nil {
 		return nil, errors.Wrap(err, "resolving DID from network")
 	}
-	return &GetDIDResponse{DID: resolved.Document}, nil
+	return &GetDIDResponse{DID: resolved.Document, DocumentMetadata: &resolved.DocumentMetadata}, nil
 }
 
 // ListDIDs returns all DIDs we have in storage for ION, it is not feasible to get all DIDs from the network
@@ -280,6 +400,617 @@ func (h *ionHandler) ListDeletedDIDs(ctx context.Context) (*ListDIDsResponse, er
 	return &ListDIDsResponse{DIDs: dids}, nil
 }
 
+// UpdateDID submits a Sidetree update operation with the changes to the document, signed with the stored update key,
+// and stores the updated document. A new update key is committed to in the operation, which replaces the stored one
+// once the update is anchored. Anchoring the update is tracked by the returned operation.
+func (h *ionHandler) UpdateDID(ctx context.Context, request UpdateDIDRequest) (*UpdateDIDResponse, error) {
+	logrus.Debugf("updating DID: %+v", request)
+
+	id := request.ID
+	gotDID, err := h.getActiveDID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	added, keyStoreRequests, err := generateIONVerificationMethods(id, request.VerificationMethodsToAdd)
+	if err != nil {
+		return nil, err
+	}
+	updatedDoc, err := applyUpdate(gotDID.DID, request, added)
+	if err != nil {
+		return nil, errors.Wrapf(err, "updating DID: %s", id)
+	}
+	stateChange, err := toIONStateChange(*updatedDoc, request, added)
+	if err != nil {
+		return nil, errors.Wrapf(err, "building state change of DID: %s", id)
+	}
+
+	// sign the update with the current update key, committing to the next one
+	updatePrivateKey, err := h.getIONPrivateKey(ctx, gotDID.updateKeyID(), gotDID.UpdatePublicKey)
+	if err != nil {
+		return nil, err
+	}
+	signer, err := ion.NewBTCSignerVerifier(*updatePrivateKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating update key signer")
+	}
+	nextUpdateKey, err := generateIONCommitmentKey(updateKeySuffix)
+	if err != nil {
+		return nil, errors.Wrap(err, "generating next update key")
+	}
+	suffix, err := ion.ION(id).Suffix()
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting suffix of DID: %s", id)
+	}
+	updateOp, err := ion.NewUpdateRequest(suffix, *gotDID.UpdatePublicKey, *nextUpdateKey.publicKey, *signer, *stateChange)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating update operation")
+	}
+	pending, err := newPendingIONOperation(*updatedDoc, nextUpdateKey.publicKey, gotDID.RecoveryPublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	// submit the update operation to the ION service
+	opID, err := h.submitOperation(ctx, gotDID, updateOp, *pending, *nextUpdateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	// revoke removed keys before storing new ones, which may replace a removed key with the same id
+	if err = revokeRemovedKeys(ctx, h.keyStore, gotDID.DID, request.VerificationMethodIDsToRemove); err != nil {
+		return nil, errors.Wrapf(err, "revoking keys of DID: %s", id)
+	}
+	gotDID.DID = *updatedDoc
+	if err = h.storage.StoreDIDWith(ctx, *gotDID, h.storeKeys(keyStoreRequests)); err != nil {
+		return nil, errors.Wrap(err, "storing ion did document")
+	}
+	return &UpdateDIDResponse{DID: *updatedDoc, OperationID: opID}, nil
+}
+
+// RecoverDID submits a Sidetree recover operation, signed with the stored recovery key, which replaces the document with
+// one made of new keys and the requested services. New update and recovery keys are committed to in the operation,
+// which replace the stored ones once the recovery is anchored. The keys of the replaced document are revoked. Anchoring
+// the recovery is tracked by the returned operation.
+func (h *ionHandler) RecoverDID(ctx context.Context, request RecoverIONDIDRequest) (*RecoverIONDIDResponse, error) {
+	logrus.Debugf("recovering DID: %+v", request)
+
+	id := request.ID
+	gotDID, err := h.getActiveDID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// build the replacement document
+	added, keyStoreRequests, err := generateIONVerificationMethods(id, request.VerificationMethods)
+	if err != nil {
+		return nil, err
+	}
+	recoveredDoc := did.Document{ID: id}
+	var ionDoc ion.Document
+	for _, a := range added {
+		verificationMethod := a.verificationMethod
+		if findVerificationMethod(recoveredDoc, verificationMethod.ID) >= 0 {
+			return nil, fmt.Errorf("verification method<%s> is duplicated", verificationMethod.ID)
+		}
+		recoveredDoc.VerificationMethod = append(recoveredDoc.VerificationMethod, verificationMethod)
+		addVerificationRelationships(&recoveredDoc, verificationMethod.ID, a.purposes)
+		ionDoc.PublicKeys = append(ionDoc.PublicKeys, ion.PublicKey{
+			ID:           verificationMethod.ID,
+			Type:         string(verificationMethod.Type),
+			PublicKeyJWK: *verificationMethod.PublicKeyJWK,
+			Purposes:     a.purposes,
+		})
+	}
+	for _, service := range request.Services {
+		if findService(recoveredDoc, service.ID) >= 0 {
+			return nil, fmt.Errorf("service<%s> is duplicated", service.ID)
+		}
+		recoveredDoc.Services = append(recoveredDoc.Services, service)
+		ionDoc.Services = append(ionDoc.Services, ion.Service{
+			ID:              ionFragment(id, service.ID),
+			Type:            service.Type,
+			ServiceEndpoint: service.ServiceEndpoint,
+		})
+	}
+
+	// sign the recovery with the current recovery key, committing to the next update and recovery keys
+	recoveryPrivateKey, err := h.getIONPrivateKey(ctx, gotDID.recoveryKeyID(), gotDID.RecoveryPublicKey)
+	if err != nil {
+		return nil, err
+	}
+	signer, err := ion.NewBTCSignerVerifier(*recoveryPrivateKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating recovery key signer")
+	}
+	nextUpdateKey, err := generateIONCommitmentKey(updateKeySuffix)
+	if err != nil {
+		return nil, errors.Wrap(err, "generating next update key")
+	}
+	nextRecoveryKey, err := generateIONCommitmentKey(recoverKeySuffix)
+	if err != nil {
+		return nil, errors.Wrap(err, "generating next recovery key")
+	}
+	suffix, err := ion.ION(id).Suffix()
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting suffix of DID: %s", id)
+	}
+	recoverOp, err := ion.NewRecoverRequest(suffix, *gotDID.RecoveryPublicKey, *nextRecoveryKey.publicKey, *nextUpdateKey.publicKey, ionDoc, *signer)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating recover operation")
+	}
+	pending, err := newPendingIONOperation(recoveredDoc, nextUpdateKey.publicKey, nextRecoveryKey.publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	// submit the recover operation to the ION service
+	opID, err := h.submitOperation(ctx, gotDID, recoverOp, *pending, *nextUpdateKey, *nextRecoveryKey)
+	if err != nil {
+		return nil, err
+	}
+
+	// revoke the keys of the replaced document before storing new ones, which may have the same ids
+	if err = revokeRemovedKeys(ctx, h.keyStore, gotDID.DID, verificationMethodIDs(gotDID.DID)); err != nil {
+		return nil, errors.Wrapf(err, "revoking keys of DID: %s", id)
+	}
+	gotDID.DID = recoveredDoc
+	if err = h.storage.StoreDIDWith(ctx, *gotDID, h.storeKeys(keyStoreRequests)); err != nil {
+		return nil, errors.Wrap(err, "storing ion did document")
+	}
+	return &RecoverIONDIDResponse{DID: recoveredDoc, OperationID: opID}, nil
+}
+
+// DeactivateDID submits a Sidetree deactivate operation, signed with the stored recovery key. Deactivation is permanent,
+// so all keys of the DID are revoked. Anchoring the deactivation is tracked by the returned operation.
+func (h *ionHandler) DeactivateDID(ctx context.Context, request DeactivateIONDIDRequest) (*DeactivateIONDIDResponse, error) {
+	logrus.Debugf("deactivating DID: %+v", request)
+
+	id := request.ID
+	gotDID, err := h.getActiveDID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	recoveryPrivateKey, err := h.getIONPrivateKey(ctx, gotDID.recoveryKeyID(), gotDID.RecoveryPublicKey)
+	if err != nil {
+		return nil, err
+	}
+	signer, err := ion.NewBTCSignerVerifier(*recoveryPrivateKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating recovery key signer")
+	}
+	suffix, err := ion.ION(id).Suffix()
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting suffix of DID: %s", id)
+	}
+	deactivateOp, err := ion.NewDeactivateRequest(suffix, *gotDID.RecoveryPublicKey, *signer)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating deactivate operation")
+	}
+
+	// submit the deactivate operation to the ION service
+	opID, err := h.submitOperation(ctx, gotDID, deactivateOp, pendingIONOperation{ID: newIONOperationID(), DID: gotDID.DID})
+	if err != nil {
+		return nil, err
+	}
+
+	gotDID.Deactivated = true
+	if err = h.storage.StoreDID(ctx, *gotDID); err != nil {
+		return nil, errors.Wrap(err, "storing ion did document")
+	}
+	if err = revokeRemovedKeys(ctx, h.keyStore, gotDID.DID, verificationMethodIDs(gotDID.DID)); err != nil {
+		return nil, errors.Wrapf(err, "revoking keys of DID: %s", id)
+	}
+	for _, keyID := range gotDID.commitmentKeyIDs() {
+		if err = h.keyStore.RevokeKey(ctx, keystore.RevokeKeyRequest{ID: keyID}); err != nil {
+			return nil, errors.Wrapf(err, "revoking key: %s", keyID)
+		}
+	}
+	return &DeactivateIONDIDResponse{OperationID: opID}, nil
+}
+
+// getActiveDID returns the stored DID, as long as operations can still be submitted for it.
+func (h *ionHandler) getActiveDID(ctx context.Context, id string) (*ionStoredDID, error) {
+	gotDID := new(ionStoredDID)
+	if err := h.storage.GetDID(ctx, id, gotDID); err != nil {
+		return nil, errors.Wrapf(err, "getting DID: %s", id)
+	}
+	if gotDID.IsSoftDeleted() {
+		return nil, fmt.Errorf("did with id<%s> has been deleted", id)
+	}
+	if gotDID.Deactivated {
+		return nil, fmt.Errorf("did with id<%s> has been deactivated", id)
+	}
+	return gotDID, nil
+}
+
+// submitOperation submits an operation to the ION service. Before it's submitted, a pending operation tracking its
+// anchoring is added to the stored DID, and stored along with the private keys it commits to under pending ids, so
+// that they are kept whatever happens once the ION node accepts it. When the ION node rejects the operation, the stored
+// DID is restored. The caller must store further changes to the DID.
+func (h *ionHandler) submitOperation(ctx context.Context, storedDID *ionStoredDID, op ion.AnchorOperation, pending pendingIONOperation, commitmentKeys ...ionCommitmentKey) (string, error) {
+	previous := *storedDID
+	pending.Type = op.GetType()
+	keyStoreRequests := make([]keystore.StoreKeyRequest, 0, len(commitmentKeys))
+	for _, key := range commitmentKeys {
+		keyID := pending.pendingKeyID(storedDID.ID, key.suffix)
+		keyStoreRequest, err := keyToStoreRequest(keyID, *key.privateKey, storedDID.ID)
+		if err != nil {
+			return "", errors.Wrapf(err, "converting %s private key to store request", key.suffix)
+		}
+		keyStoreRequests = append(keyStoreRequests, *keyStoreRequest)
+		switch key.suffix {
+		case updateKeySuffix:
+			pending.UpdateKeyID = keyID
+			storedDID.UpdatePublicKey = key.publicKey
+		case recoverKeySuffix:
+			pending.RecoveryKeyID = keyID
+			storedDID.RecoveryPublicKey = key.publicKey
+		}
+	}
+	storedDID.Operations = append(storedDID.Operations, op)
+	storedDID.PendingOperations = append(storedDID.PendingOperations, pending)
+
+	storeKeys := h.storeKeys(keyStoreRequests)
+	err := h.storage.StoreDIDWith(ctx, *storedDID, func(ctx context.Context, tx storage.Tx) error {
+		if err := storeKeys(ctx, tx); err != nil {
+			return err
+		}
+		if err := h.opsStorage.StoreOperationTx(ctx, tx, opstorage.StoredOperation{ID: pending.ID}); err != nil {
+			return errors.Wrap(err, "storing operation")
+		}
+		return tx.Write(ctx, ionOperationNamespace, pending.ID, []byte(storedDID.ID))
+	})
+	if err != nil {
+		*storedDID = previous
+		return "", errors.Wrapf(err, "storing pending %s operation", op.GetType())
+	}
+
+	if err = h.resolver.Anchor(ctx, op); err != nil {
+		*storedDID = previous
+		h.abandonOperation(ctx, previous, pending)
+		return "", errors.Wrapf(err, "anchoring %s operation", op.GetType())
+	}
+	return pending.ID, nil
+}
+
+// abandonOperation restores the stored DID after the ION node rejected an operation, and removes what was stored for
+// the operation. The keys the operation committed to are revoked. Failures are logged, since the operation already
+// failed.
+func (h *ionHandler) abandonOperation(ctx context.Context, storedDID ionStoredDID, pending pendingIONOperation) {
+	if err := h.storage.StoreDID(ctx, storedDID); err != nil {
+		logrus.WithError(err).Errorf("could not restore DID<%s> after operation<%s> was rejected", storedDID.ID, pending.ID)
+	}
+	if err := h.opsStorage.DeleteOperation(ctx, pending.ID); err != nil {
+		logrus.WithError(err).Warnf("could not delete rejected operation: %s", pending.ID)
+	}
+	if err := h.storage.db.Delete(ctx, ionOperationNamespace, pending.ID); err != nil {
+		logrus.WithError(err).Warnf("could not delete DID of rejected operation: %s", pending.ID)
+	}
+	for _, keyID := range []string{pending.UpdateKeyID, pending.RecoveryKeyID} {
+		if keyID == "" {
+			continue
+		}
+		if err := h.keyStore.RevokeKey(ctx, keystore.RevokeKeyRequest{ID: keyID}); err != nil {
+			logrus.WithError(err).Warnf("could not revoke key of rejected operation: %s", keyID)
+		}
+	}
+}
+
+// storeKeys returns a function storing the keys within a transaction.
+func (h *ionHandler) storeKeys(keyStoreRequests []keystore.StoreKeyRequest) func(context.Context, storage.Tx) error {
+	return func(ctx context.Context, tx storage.Tx) error {
+		for _, keyStoreRequest := range keyStoreRequests {
+			if err := h.keyStore.StoreKeyTx(ctx, tx, keyStoreRequest); err != nil {
+				return errors.Wrap(err, "could not store did:ion private key")
+			}
+		}
+		return nil
+	}
+}
+
+// SettleOperation settles the pending operations of the DID the operation with the given ID was submitted for, so
+// that the operation is done once the ION node shows it anchored. Other operations are ignored.
+func (h *ionHandler) SettleOperation(ctx context.Context, id string) error {
+	if !strings.HasPrefix(id, opdid.ParentResource+"/") {
+		return nil
+	}
+	didID, err := h.storage.db.Read(ctx, ionOperationNamespace, id)
+	if err != nil {
+		return errors.Wrapf(err, "reading DID of operation: %s", id)
+	}
+	if len(didID) == 0 {
+		return nil
+	}
+	gotDID := new(ionStoredDID)
+	if err = h.storage.GetDID(ctx, string(didID), gotDID); err != nil {
+		return errors.Wrapf(err, "getting DID: %s", didID)
+	}
+	return h.settleOperations(ctx, gotDID)
+}
+
+// settleOperations marks the pending operations of the DID as done once the ION node shows them anchored. Operations
+// are anchored in submission order, so all operations up to the last anchored one are done. The update and recovery
+// keys the done operations committed to replace the stored ones, and are stored along with the DID and the done
+// operations.
+func (h *ionHandler) settleOperations(ctx context.Context, storedDID *ionStoredDID) error {
+	if len(storedDID.PendingOperations) == 0 {
+		return nil
+	}
+	state, err := h.resolveDIDState(ctx, storedDID.ID)
+	if err != nil {
+		return err
+	}
+
+	anchored := -1
+	for i := len(storedDID.PendingOperations) - 1; i >= 0; i-- {
+		if storedDID.PendingOperations[i].isAnchored(*state) {
+			anchored = i
+			break
+		}
+	}
+	if anchored < 0 {
+		return nil
+	}
+	settled := storedDID.PendingOperations[:anchored+1]
+
+	// the last keys committed to by the settled operations are the update and recovery keys of the DID
+	var updateKeyID, recoveryKeyID string
+	doneOps := make([]opstorage.StoredOperation, 0, len(settled))
+	for _, pending := range settled {
+		if pending.UpdateKeyID != "" {
+			updateKeyID = pending.UpdateKeyID
+		}
+		if pending.RecoveryKeyID != "" {
+			recoveryKeyID = pending.RecoveryKeyID
+		}
+		docBytes, err := json.Marshal(pending.DID)
+		if err != nil {
+			return errors.Wrapf(err, "marshalling document of operation: %s", pending.ID)
+		}
+		doneOps = append(doneOps, opstorage.StoredOperation{ID: pending.ID, Done: true, Response: docBytes})
+	}
+	keyStoreRequests := make([]keystore.StoreKeyRequest, 0, 2)
+	for keyID, suffix := range map[string]string{updateKeyID: updateKeySuffix, recoveryKeyID: recoverKeySuffix} {
+		if keyID == "" {
+			continue
+		}
+		keyStoreRequest, err := h.promotedKeyRequest(ctx, keyID, storedDID.ID+"#"+suffix, storedDID.ID)
+		if err != nil {
+			return err
+		}
+		keyStoreRequests = append(keyStoreRequests, *keyStoreRequest)
+	}
+
+	storedDID.PendingOperations = storedDID.PendingOperations[anchored+1:]
+	storeKeys := h.storeKeys(keyStoreRequests)
+	err = h.storage.StoreDIDWith(ctx, *storedDID, func(ctx context.Context, tx storage.Tx) error {
+		if err := storeKeys(ctx, tx); err != nil {
+			return err
+		}
+		for _, op := range doneOps {
+			if err := h.opsStorage.StoreOperationTx(ctx, tx, op); err != nil {
+				return errors.Wrap(err, "storing operation")
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.Wrapf(err, "storing settled operations of DID: %s", storedDID.ID)
+	}
+
+	// the keys are no longer needed under their pending ids
+	for _, pending := range settled {
+		for _, keyID := range []string{pending.UpdateKeyID, pending.RecoveryKeyID} {
+			if keyID == "" {
+				continue
+			}
+			if err = h.keyStore.RevokeKey(ctx, keystore.RevokeKeyRequest{ID: keyID}); err != nil {
+				logrus.WithError(err).Warnf("could not revoke pending key: %s", keyID)
+			}
+		}
+	}
+	return nil
+}
+
+// promotedKeyRequest returns the request storing the key stored under a pending id under the given id.
+func (h *ionHandler) promotedKeyRequest(ctx context.Context, pendingKeyID, keyID, controller string) (*keystore.StoreKeyRequest, error) {
+	gotKey, err := h.keyStore.GetKey(ctx, keystore.GetKeyRequest{ID: pendingKeyID})
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting key: %s", pendingKeyID)
+	}
+	privateKeyBytes, err := crypto.PrivKeyToBytes(gotKey.Key)
+	if err != nil {
+		return nil, errors.Wrapf(err, "encoding key: %s", pendingKeyID)
+	}
+	return &keystore.StoreKeyRequest{
+		ID:               keyID,
+		Type:             gotKey.Type,
+		Controller:       controller,
+		PrivateKeyBase58: base58.Encode(privateKeyBytes),
+	}, nil
+}
+
+// resolveDIDState resolves the DID from the ION node, returning its state on the network. The resolver of the SDK
+// does not expose the method metadata, which holds the commitments of the last anchored operation.
+func (h *ionHandler) resolveDIDState(ctx context.Context, id string) (*ionDIDState, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.Join([]string{h.baseURL, "identifiers", id}, "/"), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating request")
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "resolving DID: %s", id)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading resolution of DID: %s", id)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("could not resolve DID<%s>: %s", id, string(body))
+	}
+	var result struct {
+		DIDDocumentMetadata ionDIDState `json:"didDocumentMetadata"`
+	}
+	if err = json.Unmarshal(body, &result); err != nil {
+		return nil, errors.Wrapf(err, "unmarshalling resolution of DID: %s", id)
+	}
+	return &result.DIDDocumentMetadata, nil
+}
+
+// newIONOperationID returns the id of a new operation tracking the anchoring of an operation submitted to the ION node.
+func newIONOperationID() string {
+	return opdid.IDFromOperationID(uuid.NewString())
+}
+
+// newPendingIONOperation returns a pending operation resulting in the document, and committing to the keys.
+func newPendingIONOperation(doc did.Document, updatePublicKey, recoveryPublicKey *jwx.PublicKeyJWK) (*pendingIONOperation, error) {
+	if updatePublicKey == nil || recoveryPublicKey == nil {
+		return nil, errors.New("update and recovery public keys are required")
+	}
+	_, updateCommitment, err := ion.Commit(*updatePublicKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "committing to update key")
+	}
+	_, recoveryCommitment, err := ion.Commit(*recoveryPublicKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "committing to recovery key")
+	}
+	return &pendingIONOperation{
+		ID:                 newIONOperationID(),
+		UpdateCommitment:   updateCommitment,
+		RecoveryCommitment: recoveryCommitment,
+		DID:                doc,
+	}, nil
+}
+
+// generateIONVerificationMethods generates the keys of verification methods to add to a document, returning the
+// verification methods along with the requests storing their private keys.
+func generateIONVerificationMethods(id string, toAdd []VerificationMethodToAdd) ([]addedVerificationMethod, []keystore.StoreKeyRequest, error) {
+	added := make([]addedVerificationMethod, 0, len(toAdd))
+	keyStoreRequests := make([]keystore.StoreKeyRequest, 0, len(toAdd))
+	for _, a := range toAdd {
+		keyID := strings.TrimPrefix(a.ID, "#")
+		if keyID == "" {
+			keyID = uuid.NewString()
+		}
+		_, privKey, err := crypto.GenerateKeyByKeyType(a.KeyType)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "could not generate key for ion DID")
+		}
+		pubKeyJWK, privKeyJWK, err := jwx.PrivateKeyToPrivateKeyJWK(uuid.NewString(), privKey)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "could not convert key to JWK")
+		}
+		ldKeyType, err := did.KeyTypeToLDKeyType(a.KeyType)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "converting key type to LD key type")
+		}
+		added = append(added, addedVerificationMethod{
+			verificationMethod: did.VerificationMethod{
+				ID:           keyID,
+				Type:         ldKeyType,
+				Controller:   id,
+				PublicKeyJWK: pubKeyJWK,
+			},
+			purposes: a.Purposes,
+		})
+		keyStoreRequest, err := keyToStoreRequest(keyID, *privKeyJWK, id)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "converting private key to store request")
+		}
+		keyStoreRequests = append(keyStoreRequests, *keyStoreRequest)
+	}
+	return added, keyStoreRequests, nil
+}
+
+// generateIONCommitmentKey generates an update or recovery key to commit to, identified by the suffix of the id it's
+// stored under.
+func generateIONCommitmentKey(suffix string) (*ionCommitmentKey, error) {
+	_, privateKey, err := crypto.GenerateSECP256k1Key()
+	if err != nil {
+		return nil, errors.Wrap(err, "generating key")
+	}
+	publicKeyJWK, privateKeyJWK, err := jwx.PrivateKeyToPrivateKeyJWK(uuid.NewString(), privateKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "converting key to JWK")
+	}
+	return &ionCommitmentKey{suffix: suffix, publicKey: publicKeyJWK, privateKey: privateKeyJWK}, nil
+}
+
+// getIONPrivateKey returns the stored private key of an update or recovery key as the JWK that was committed to, which
+// has the key id of the stored public key.
+func (h *ionHandler) getIONPrivateKey(ctx context.Context, keyID string, publicKey *jwx.PublicKeyJWK) (*jwx.PrivateKeyJWK, error) {
+	if publicKey == nil {
+		return nil, fmt.Errorf("public key of key<%s> is unknown, which is the case for DIDs created before updates were supported", keyID)
+	}
+	gotKey, err := h.keyStore.GetKey(ctx, keystore.GetKeyRequest{ID: keyID})
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting key: %s", keyID)
+	}
+	_, privateKeyJWK, err := jwx.PrivateKeyToPrivateKeyJWK(publicKey.KID, gotKey.Key)
+	if err != nil {
+		return nil, errors.Wrapf(err, "converting key<%s> to JWK", keyID)
+	}
+	return privateKeyJWK, nil
+}
+
+// toIONStateChange converts an update to the patches of a Sidetree update operation. Sidetree ids are fragments, and
+// public keys added with the id of an existing key replace it, which is how verification relationships are changed.
+func toIONStateChange(updatedDoc did.Document, request UpdateDIDRequest, added []addedVerificationMethod) (*ion.StateChange, error) {
+	var stateChange ion.StateChange
+	for _, service := range request.ServicesToAdd {
+		stateChange.ServicesToAdd = append(stateChange.ServicesToAdd, ion.Service{
+			ID:              ionFragment(updatedDoc.ID, service.ID),
+			Type:            service.Type,
+			ServiceEndpoint: service.ServiceEndpoint,
+		})
+	}
+	for _, id := range request.ServiceIDsToRemove {
+		stateChange.ServiceIDsToRemove = append(stateChange.ServiceIDsToRemove, ionFragment(updatedDoc.ID, id))
+	}
+	for _, id := range request.VerificationMethodIDsToRemove {
+		stateChange.PublicKeyIDsToRemove = append(stateChange.PublicKeyIDsToRemove, ionFragment(updatedDoc.ID, id))
+	}
+
+	toAdd := make([]VerificationRelationships, 0, len(added)+len(request.VerificationRelationshipsToSet))
+	for _, a := range added {
+		toAdd = append(toAdd, VerificationRelationships{ID: a.verificationMethod.ID, Purposes: a.purposes})
+	}
+	toAdd = append(toAdd, request.VerificationRelationshipsToSet...)
+	publicKeys := make(map[string]int, len(toAdd))
+	for _, relationships := range toAdd {
+		i := findVerificationMethod(updatedDoc, relationships.ID)
+		if i < 0 {
+			return nil, fmt.Errorf("verification method<%s> not found in document", relationships.ID)
+		}
+		verificationMethod := updatedDoc.VerificationMethod[i]
+		if verificationMethod.PublicKeyJWK == nil {
+			return nil, fmt.Errorf("verification method<%s> has no JWK", verificationMethod.ID)
+		}
+		publicKey := ion.PublicKey{
+			ID:           ionFragment(updatedDoc.ID, verificationMethod.ID),
+			Type:         string(verificationMethod.Type),
+			PublicKeyJWK: *verificationMethod.PublicKeyJWK,
+			Purposes:     relationships.Purposes,
+		}
+		// the last change to a key wins
+		if j, ok := publicKeys[publicKey.ID]; ok {
+			stateChange.PublicKeysToAdd[j] = publicKey
+			continue
+		}
+		publicKeys[publicKey.ID] = len(stateChange.PublicKeysToAdd)
+		stateChange.PublicKeysToAdd = append(stateChange.PublicKeysToAdd, publicKey)
+	}
+	return &stateChange, nil
+}
+
+func ionFragment(docID, id string) string {
+	return strings.TrimPrefix(strings.TrimPrefix(id, docID), "#")
+}
+
 // SoftDeleteDID soft deletes a DID from storage but has no effect on the DID's state on the network
 func (h *ionHandler) SoftDeleteDID(ctx context.Context, request DeleteDIDRequest) error {
 	logrus.Debugf("soft deleting DID: %+v", request)
@@ -2,17 +2,27 @@ package did
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/TBD54566975/ssi-sdk/crypto"
 	"github.com/TBD54566975/ssi-sdk/did"
+	"github.com/TBD54566975/ssi-sdk/did/ion"
+	"github.com/goccy/go-json"
+	"github.com/lestrrat-go/jwx/v2/jws"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"gopkg.in/h2non/gock.v1"
 
 	"github.com/tbd54566975/ssi-service/config"
 	"github.com/tbd54566975/ssi-service/pkg/service/keystore"
+	"github.com/tbd54566975/ssi-service/pkg/service/operation"
+	opstorage "github.com/tbd54566975/ssi-service/pkg/service/operation/storage"
 	"github.com/tbd54566975/ssi-service/pkg/storage"
 )
 
@@ -221,6 +231,413 @@ func TestIONHandler(t *testing.T) {
 		assert.NotEmpty(tt, gotDID)
 		assert.Equal(tt, "did:ion:test", gotDID.DID.ID)
 	})
+	t.Run("Test Update DID", func(tt *testing.T) {
+		s := setupTestDB(tt)
+		keystoreService := testKeyStoreService(tt, s)
+		didStorage, err := NewDIDStorage(s)
+		require.NoError(tt, err)
+		handler, err := NewIONHandler("https://test-ion-resolver.com", didStorage, keystoreService)
+		require.NoError(tt, err)
+
+		// record the operations submitted to the ION node
+		var operations []map[string]any
+		gock.New("https://test-ion-resolver.com").
+			Post("/operations").
+			Times(3).
+			AddMatcher(func(req *http.Request, _ *gock.Request) (bool, error) {
+				var op map[string]any
+				if err := json.NewDecoder(req.Body).Decode(&op); err != nil {
+					return false, err
+				}
+				operations = append(operations, op)
+				return true, nil
+			}).
+			Reply(200)
+		defer gock.Off()
+
+		created, err := handler.CreateDID(context.Background(), CreateDIDRequest{Method: did.IONMethod, KeyType: crypto.Ed25519})
+		require.NoError(tt, err)
+		createdKeyID := created.DID.VerificationMethod[0].ID
+
+		// add a key and a service, and change the relationships of the created key
+		updated, err := handler.UpdateDID(context.Background(), UpdateDIDRequest{
+			Method: did.IONMethod,
+			ID:     created.DID.ID,
+			VerificationMethodsToAdd: []VerificationMethodToAdd{
+				{ID: "key-2", KeyType: crypto.SECP256k1, Purposes: []ion.PublicKeyPurpose{ion.AssertionMethod}},
+			},
+			VerificationRelationshipsToSet: []VerificationRelationships{
+				{ID: createdKeyID, Purposes: []ion.PublicKeyPurpose{ion.Authentication}},
+			},
+			ServicesToAdd: []did.Service{{ID: "linked-domain", Type: "LinkedDomains", ServiceEndpoint: "https://example.com"}},
+		})
+		require.NoError(tt, err)
+		require.Len(tt, updated.DID.VerificationMethod, 2)
+		assert.Equal(tt, "key-2", updated.DID.VerificationMethod[1].ID)
+		assert.Equal(tt, []did.VerificationMethodSet{"key-2"}, updated.DID.AssertionMethod)
+		assert.Equal(tt, []did.VerificationMethodSet{createdKeyID}, updated.DID.Authentication)
+		require.Len(tt, updated.DID.Services, 1)
+
+		gotKey, err := keystoreService.GetKey(context.Background(), keystore.GetKeyRequest{ID: "key-2"})
+		require.NoError(tt, err)
+		assert.Equal(tt, created.DID.ID, gotKey.Controller)
+
+		// remove the created key, which revokes it
+		updated, err = handler.UpdateDID(context.Background(), UpdateDIDRequest{
+			Method:                        did.IONMethod,
+			ID:                            created.DID.ID,
+			VerificationMethodIDsToRemove: []string{createdKeyID},
+		})
+		require.NoError(tt, err)
+		require.Len(tt, updated.DID.VerificationMethod, 1)
+		assert.Empty(tt, updated.DID.Authentication)
+		gotKey, err = keystoreService.GetKey(context.Background(), keystore.GetKeyRequest{ID: createdKeyID})
+		require.NoError(tt, err)
+		assert.True(tt, gotKey.Revoked)
+
+		// the stored document is updated
+		gotDID, err := handler.GetDID(context.Background(), GetDIDRequest{Method: did.IONMethod, ID: created.DID.ID})
+		require.NoError(tt, err)
+		assert.Equal(tt, updated.DID, gotDID.DID)
+
+		// each update reveals the key committed to by the previous operation
+		require.Len(tt, operations, 3)
+		suffix, err := ion.ION(created.DID.ID).Suffix()
+		require.NoError(tt, err)
+		for i, op := range operations[1:] {
+			assert.Equal(tt, string(ion.Update), op["type"])
+			assert.Equal(tt, suffix, op["didSuffix"])
+
+			payload, err := jws.Parse([]byte(op["signedData"].(string)))
+			require.NoError(tt, err)
+			var signedData ion.UpdateSignedDataObject
+			require.NoError(tt, json.Unmarshal(payload.Payload(), &signedData))
+			reveal, commitment, err := ion.Commit(signedData.UpdateKey)
+			require.NoError(tt, err)
+			assert.Equal(tt, reveal, op["revealValue"])
+			previousDelta := operations[i]["delta"].(map[string]any)
+			assert.Equal(tt, previousDelta["updateCommitment"], commitment)
+		}
+		patches := operations[1]["delta"].(map[string]any)["patches"].([]any)
+		require.Len(tt, patches, 2)
+		assert.Equal(tt, string(ion.AddServices), patches[0].(map[string]any)["action"])
+		assert.Equal(tt, string(ion.AddPublicKeys), patches[1].(map[string]any)["action"])
+		assert.Len(tt, patches[1].(map[string]any)["publicKeys"], 2)
+	})
+
+	t.Run("Test Update DID Errors", func(tt *testing.T) {
+		s := setupTestDB(tt)
+		keystoreService := testKeyStoreService(tt, s)
+		didStorage, err := NewDIDStorage(s)
+		require.NoError(tt, err)
+		handler, err := NewIONHandler("https://test-ion-resolver.com", didStorage, keystoreService)
+		require.NoError(tt, err)
+
+		_, err = handler.UpdateDID(context.Background(), UpdateDIDRequest{Method: did.IONMethod, ID: "did:ion:unknown", ServiceIDsToRemove: []string{"service"}})
+		assert.ErrorContains(tt, err, "getting DID: did:ion:unknown")
+
+		gock.New("https://test-ion-resolver.com").
+			Post("/operations").
+			Reply(200)
+		defer gock.Off()
+		created, err := handler.CreateDID(context.Background(), CreateDIDRequest{Method: did.IONMethod, KeyType: crypto.Ed25519})
+		require.NoError(tt, err)
+
+		_, err = handler.UpdateDID(context.Background(), UpdateDIDRequest{Method: did.IONMethod, ID: created.DID.ID, ServiceIDsToRemove: []string{"service"}})
+		assert.ErrorContains(tt, err, "service<service> not found in document")
+
+		// the ION node rejecting the operation leaves the DID as it was
+		gock.New("https://test-ion-resolver.com").
+			Post("/operations").
+			Reply(400).
+			BodyString("invalid operation")
+		_, err = handler.UpdateDID(context.Background(), UpdateDIDRequest{
+			Method:        did.IONMethod,
+			ID:            created.DID.ID,
+			ServicesToAdd: []did.Service{{ID: "service", Type: "LinkedDomains", ServiceEndpoint: "https://example.com"}},
+		})
+		assert.ErrorContains(tt, err, "anchoring update operation")
+		gotDID, err := handler.GetDID(context.Background(), GetDIDRequest{Method: did.IONMethod, ID: created.DID.ID})
+		require.NoError(tt, err)
+		assert.Equal(tt, created.DID, gotDID.DID)
+	})
+}
+
+func TestIONHandlerOperations(t *testing.T) {
+	node := newFakeIONNode(t)
+	s := setupTestDB(t)
+	keystoreService := testKeyStoreService(t, s)
+	didStorage, err := NewDIDStorage(s)
+	require.NoError(t, err)
+	handler, err := NewIONHandler(node.URL, didStorage, keystoreService)
+	require.NoError(t, err)
+	ih := handler.(*ionHandler)
+	operationService, err := operation.NewOperationService(s, ih)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	created, err := handler.CreateDID(ctx, CreateDIDRequest{Method: did.IONMethod, KeyType: crypto.Ed25519})
+	require.NoError(t, err)
+	id := created.DID.ID
+	createdKeyID := created.DID.VerificationMethod[0].ID
+	node.anchor()
+
+	getOperation := func(t *testing.T, opID string) *operation.Operation {
+		op, err := operationService.GetOperation(ctx, operation.GetOperationRequest{ID: opID})
+		require.NoError(t, err)
+		return op
+	}
+
+	t.Run("Test Update", func(tt *testing.T) {
+		updated, err := handler.UpdateDID(ctx, UpdateDIDRequest{
+			Method:        did.IONMethod,
+			ID:            id,
+			ServicesToAdd: []did.Service{{ID: "linked-domain", Type: "LinkedDomains", ServiceEndpoint: "https://example.com"}},
+		})
+		require.NoError(tt, err)
+		require.NotEmpty(tt, updated.OperationID)
+		assert.True(tt, strings.HasPrefix(updated.OperationID, "dids/operations/"))
+
+		// the operation is pending until the update is anchored, and the update key it commits to is pending too
+		updateKeyID := id + "#" + updateKeySuffix
+		before, err := keystoreService.GetKey(ctx, keystore.GetKeyRequest{ID: updateKeyID})
+		require.NoError(tt, err)
+		op := getOperation(tt, updated.OperationID)
+		assert.False(tt, op.Done)
+		pending, err := keystoreService.GetKey(ctx, keystore.GetKeyRequest{ID: updateKeyID + "-" + opstorage.StatusObjectID(updated.OperationID)})
+		require.NoError(tt, err)
+		gotKey, err := keystoreService.GetKey(ctx, keystore.GetKeyRequest{ID: updateKeyID})
+		require.NoError(tt, err)
+		assert.Equal(tt, before.Key, gotKey.Key)
+
+		// once anchored, the pending update key replaces the update key
+		node.anchor()
+		op = getOperation(tt, updated.OperationID)
+		assert.True(tt, op.Done)
+		assert.Empty(tt, op.Result.Error)
+		assert.Equal(tt, updated.DID, op.Result.Response)
+		gotKey, err = keystoreService.GetKey(ctx, keystore.GetKeyRequest{ID: updateKeyID})
+		require.NoError(tt, err)
+		assert.Equal(tt, pending.Key, gotKey.Key)
+		assert.False(tt, gotKey.Revoked)
+	})
+
+	t.Run("Test Recover", func(tt *testing.T) {
+		before := new(ionStoredDID)
+		require.NoError(tt, didStorage.GetDID(ctx, id, before))
+
+		recovered, err := ih.RecoverDID(ctx, RecoverIONDIDRequest{
+			ID: id,
+			VerificationMethods: []VerificationMethodToAdd{
+				{ID: "recovered", KeyType: crypto.SECP256k1, Purposes: []ion.PublicKeyPurpose{ion.Authentication}},
+			},
+			Services: []did.Service{{ID: "#hub", Type: "IdentityHub", ServiceEndpoint: "https://hub.example.com"}},
+		})
+		require.NoError(tt, err)
+		require.Len(tt, recovered.DID.VerificationMethod, 1)
+		assert.Equal(tt, "recovered", recovered.DID.VerificationMethod[0].ID)
+		assert.Equal(tt, []did.VerificationMethodSet{"recovered"}, recovered.DID.Authentication)
+		assert.Empty(tt, recovered.DID.AssertionMethod)
+		require.Len(tt, recovered.DID.Services, 1)
+		assert.Equal(tt, "IdentityHub", recovered.DID.Services[0].Type)
+
+		// the recover operation replaces the document, and commits to new keys
+		recoverOp := node.lastOperation()
+		assert.Equal(tt, ion.Recover, recoverOp.Type)
+		require.Len(tt, recoverOp.Delta.Patches, 1)
+		replace, err := json.Marshal(recoverOp.Delta.Patches[0])
+		require.NoError(tt, err)
+		assert.Contains(tt, string(replace), `"action":"replace"`)
+		assert.Contains(tt, string(replace), `"id":"recovered"`)
+		assert.Contains(tt, string(replace), `"id":"hub"`)
+		after := new(ionStoredDID)
+		require.NoError(tt, didStorage.GetDID(ctx, id, after))
+		assert.Equal(tt, recovered.DID, after.DID)
+		assert.NotEqual(tt, before.UpdatePublicKey, after.UpdatePublicKey)
+		assert.NotEqual(tt, before.RecoveryPublicKey, after.RecoveryPublicKey)
+
+		// the keys of the replaced document are revoked
+		gotKey, err := keystoreService.GetKey(ctx, keystore.GetKeyRequest{ID: createdKeyID})
+		require.NoError(tt, err)
+		assert.True(tt, gotKey.Revoked)
+		gotKey, err = keystoreService.GetKey(ctx, keystore.GetKeyRequest{ID: "recovered"})
+		require.NoError(tt, err)
+		assert.False(tt, gotKey.Revoked)
+
+		// updates submitted before the recovery is anchored use the new update key
+		updated, err := handler.UpdateDID(ctx, UpdateDIDRequest{Method: did.IONMethod, ID: id, ServiceIDsToRemove: []string{"hub"}})
+		require.NoError(tt, err)
+		assert.False(tt, getOperation(tt, recovered.OperationID).Done)
+		assert.False(tt, getOperation(tt, updated.OperationID).Done)
+
+		// anchoring the update means the recovery before it is anchored too
+		node.anchor()
+		op := getOperation(tt, recovered.OperationID)
+		assert.True(tt, op.Done)
+		assert.Equal(tt, recovered.DID, op.Result.Response)
+		op = getOperation(tt, updated.OperationID)
+		assert.True(tt, op.Done)
+		assert.Equal(tt, updated.DID, op.Result.Response)
+	})
+
+	t.Run("Test Deactivate", func(tt *testing.T) {
+		deactivated, err := ih.DeactivateDID(ctx, DeactivateIONDIDRequest{ID: id})
+		require.NoError(tt, err)
+		assert.Equal(tt, ion.Deactivate, node.lastOperation().Type)
+		assert.False(tt, getOperation(tt, deactivated.OperationID).Done)
+
+		// all keys are revoked
+		for _, keyID := range []string{"recovered", id + "#" + updateKeySuffix, id + "#" + recoverKeySuffix} {
+			gotKey, err := keystoreService.GetKey(ctx, keystore.GetKeyRequest{ID: keyID})
+			require.NoError(tt, err)
+			assert.True(tt, gotKey.Revoked, keyID)
+		}
+
+		// no operations can follow a deactivation
+		_, err = handler.UpdateDID(ctx, UpdateDIDRequest{Method: did.IONMethod, ID: id, ServiceIDsToRemove: []string{"hub"}})
+		assert.ErrorContains(tt, err, "has been deactivated")
+		_, err = ih.RecoverDID(ctx, RecoverIONDIDRequest{ID: id, VerificationMethods: []VerificationMethodToAdd{{KeyType: crypto.Ed25519}}})
+		assert.ErrorContains(tt, err, "has been deactivated")
+		_, err = ih.DeactivateDID(ctx, DeactivateIONDIDRequest{ID: id})
+		assert.ErrorContains(tt, err, "has been deactivated")
+
+		node.anchor()
+		assert.True(tt, getOperation(tt, deactivated.OperationID).Done)
+	})
+
+	t.Run("Test Rejected Operations", func(tt *testing.T) {
+		other, err := handler.CreateDID(ctx, CreateDIDRequest{Method: did.IONMethod, KeyType: crypto.Ed25519})
+		require.NoError(tt, err)
+		node.anchor()
+
+		node.reject = true
+		defer func() { node.reject = false }()
+		_, err = ih.RecoverDID(ctx, RecoverIONDIDRequest{
+			ID:                  other.DID.ID,
+			VerificationMethods: []VerificationMethodToAdd{{KeyType: crypto.Ed25519, Purposes: []ion.PublicKeyPurpose{ion.Authentication}}},
+		})
+		assert.ErrorContains(tt, err, "anchoring recover operation")
+		_, err = ih.DeactivateDID(ctx, DeactivateIONDIDRequest{ID: other.DID.ID})
+		assert.ErrorContains(tt, err, "anchoring deactivate operation")
+
+		// the DID is left as it was
+		gotDID := new(ionStoredDID)
+		require.NoError(tt, didStorage.GetDID(ctx, other.DID.ID, gotDID))
+		assert.Equal(tt, other.DID, gotDID.DID)
+		assert.False(tt, gotDID.Deactivated)
+		assert.Empty(tt, gotDID.PendingOperations)
+		gotKey, err := keystoreService.GetKey(ctx, keystore.GetKeyRequest{ID: other.DID.VerificationMethod[0].ID})
+		require.NoError(tt, err)
+		assert.False(tt, gotKey.Revoked)
+	})
+}
+
+// fakeIONNode is a local ION node, which accepts operations and anchors them when asked to.
+type fakeIONNode struct {
+	*httptest.Server
+	t *testing.T
+
+	mu         sync.Mutex
+	operations []fakeIONOperation
+	anchored   int
+	states     map[string]*ionDIDState
+	// whether operations are rejected
+	reject bool
+}
+
+type fakeIONOperation struct {
+	Type       ion.OperationType `json:"type"`
+	DIDSuffix  string            `json:"didSuffix"`
+	SuffixData ion.SuffixData    `json:"suffixData"`
+	Delta      ion.Delta         `json:"delta"`
+	SignedData string            `json:"signedData"`
+}
+
+func newFakeIONNode(t *testing.T) *fakeIONNode {
+	node := &fakeIONNode{t: t, states: make(map[string]*ionDIDState)}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/operations", node.submit)
+	mux.HandleFunc("/identifiers/", node.resolve)
+	node.Server = httptest.NewTLSServer(mux)
+	t.Cleanup(node.Close)
+
+	// ION resolution URLs must use https, so the handler's client must trust the node
+	transport := http.DefaultClient.Transport
+	http.DefaultClient.Transport = node.Client().Transport
+	t.Cleanup(func() { http.DefaultClient.Transport = transport })
+	return node
+}
+
+func (n *fakeIONNode) submit(w http.ResponseWriter, r *http.Request) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if r.Method != http.MethodPost || n.reject {
+		http.Error(w, "invalid operation", http.StatusBadRequest)
+		return
+	}
+	var op fakeIONOperation
+	if err := json.NewDecoder(r.Body).Decode(&op); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	n.operations = append(n.operations, op)
+}
+
+func (n *fakeIONNode) resolve(w http.ResponseWriter, r *http.Request) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	id := strings.TrimPrefix(r.URL.Path, "/identifiers/")
+	suffix, err := ion.ION(id).Suffix()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	state, ok := n.states[suffix]
+	if !ok {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	resolution := map[string]any{"didDocument": map[string]any{"id": id}, "didDocumentMetadata": state}
+	if err = json.NewEncoder(w).Encode(resolution); err != nil {
+		n.t.Errorf("encoding resolution: %v", err)
+	}
+}
+
+// anchor applies the operations submitted since the last call to the state of their DIDs.
+func (n *fakeIONNode) anchor() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for _, op := range n.operations[n.anchored:] {
+		switch op.Type {
+		case ion.Create:
+			shortFormDID, err := ion.CreateShortFormDID(op.SuffixData)
+			require.NoError(n.t, err)
+			state := new(ionDIDState)
+			state.Method.Published = true
+			state.Method.UpdateCommitment = op.Delta.UpdateCommitment
+			state.Method.RecoveryCommitment = op.SuffixData.RecoveryCommitment
+			n.states[strings.TrimPrefix(shortFormDID, "did:ion:")] = state
+		case ion.Update:
+			n.states[op.DIDSuffix].Method.UpdateCommitment = op.Delta.UpdateCommitment
+		case ion.Recover:
+			var signedData ion.RecoverySignedDataObject
+			payload, err := base64.RawURLEncoding.DecodeString(strings.Split(op.SignedData, ".")[1])
+			require.NoError(n.t, err)
+			require.NoError(n.t, json.Unmarshal(payload, &signedData))
+			n.states[op.DIDSuffix].Method.UpdateCommitment = op.Delta.UpdateCommitment
+			n.states[op.DIDSuffix].Method.RecoveryCommitment = signedData.RecoveryCommitment
+		case ion.Deactivate:
+			n.states[op.DIDSuffix] = &ionDIDState{Deactivated: true}
+		}
+	}
+	n.anchored = len(n.operations)
+}
+
+func (n *fakeIONNode) lastOperation() fakeIONOperation {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	require.NotEmpty(n.t, n.operations)
+	return n.operations[len(n.operations)-1]
 }
 
 func testKeyStoreService(t *testing.T, db storage.ServiceStorage) *keystore.Service {
@@ -0,0 +1,117 @@
+package did
+
+import (
+	"context"
+	"testing"
+
+	"github.com/TBD54566975/ssi-sdk/crypto"
+	"github.com/TBD54566975/ssi-sdk/did"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tbd54566975/ssi-service/pkg/service/keystore"
+)
+
+func TestJWKHandler(t *testing.T) {
+	t.Run("Test Create JWK Handler", func(tt *testing.T) {
+		s := setupTestDB(tt)
+		keystoreService := testKeyStoreService(tt, s)
+		didStorage, err := NewDIDStorage(s)
+		require.NoError(tt, err)
+
+		handler, err := NewJWKHandler(nil, keystoreService)
+		assert.Empty(tt, handler)
+		assert.ErrorContains(tt, err, "storage cannot be empty")
+
+		handler, err = NewJWKHandler(didStorage, nil)
+		assert.Empty(tt, handler)
+		assert.ErrorContains(tt, err, "keystore cannot be empty")
+
+		handler, err = NewJWKHandler(didStorage, keystoreService)
+		assert.NoError(tt, err)
+		assert.Equal(tt, did.JWKMethod, handler.GetMethod())
+	})
+
+	t.Run("Test Create DID For Every Key Type", func(tt *testing.T) {
+		handler, keystoreService := testJWKHandler(tt)
+		for _, keyType := range crypto.GetSupportedKeyTypes() {
+			if keyType == crypto.P224 {
+				continue
+			}
+			created, err := handler.CreateDID(context.Background(), CreateDIDRequest{Method: did.JWKMethod, KeyType: keyType})
+			require.NoError(tt, err, "key type %s", keyType)
+			assert.Contains(tt, created.DID.ID, "did:jwk:")
+			require.Len(tt, created.DID.VerificationMethod, 1)
+			assert.Equal(tt, created.DID.ID+"#0", created.DID.VerificationMethod[0].ID)
+
+			// the private key is kept in the keystore
+			gotKey, err := keystoreService.GetKey(context.Background(), keystore.GetKeyRequest{ID: created.DID.VerificationMethod[0].ID})
+			require.NoError(tt, err)
+			assert.Equal(tt, keyType, gotKey.Type)
+			assert.Equal(tt, created.DID.ID, gotKey.Controller)
+		}
+	})
+
+	t.Run("Test Create DID With Unsupported Key Type", func(tt *testing.T) {
+		handler, _ := testJWKHandler(tt)
+		for _, keyType := range []crypto.KeyType{crypto.P224, crypto.Dilithium2} {
+			_, err := handler.CreateDID(context.Background(), CreateDIDRequest{Method: did.JWKMethod, KeyType: keyType})
+			assert.ErrorContains(tt, err, "is not supported for did:jwk")
+		}
+	})
+
+	t.Run("Test Get, List and Soft Delete DIDs", func(tt *testing.T) {
+		handler, _ := testJWKHandler(tt)
+		ctx := context.Background()
+
+		created, err := handler.CreateDID(ctx, CreateDIDRequest{Method: did.JWKMethod, KeyType: crypto.P256})
+		require.NoError(tt, err)
+		other, err := handler.CreateDID(ctx, CreateDIDRequest{Method: did.JWKMethod, KeyType: crypto.Ed25519})
+		require.NoError(tt, err)
+
+		got, err := handler.GetDID(ctx, GetDIDRequest{Method: did.JWKMethod, ID: created.DID.ID})
+		require.NoError(tt, err)
+		assert.Equal(tt, created.DID.ID, got.DID.ID)
+		assert.Equal(tt, created.DID.VerificationMethod, got.DID.VerificationMethod)
+
+		listed, err := handler.ListDIDs(ctx)
+		require.NoError(tt, err)
+		assert.Len(tt, listed.DIDs, 2)
+
+		require.NoError(tt, handler.SoftDeleteDID(ctx, DeleteDIDRequest{Method: did.JWKMethod, ID: created.DID.ID}))
+		listed, err = handler.ListDIDs(ctx)
+		require.NoError(tt, err)
+		require.Len(tt, listed.DIDs, 1)
+		assert.Equal(tt, other.DID.ID, listed.DIDs[0].ID)
+		deleted, err := handler.ListDeletedDIDs(ctx)
+		require.NoError(tt, err)
+		require.Len(tt, deleted.DIDs, 1)
+		assert.Equal(tt, created.DID.ID, deleted.DIDs[0].ID)
+
+		err = handler.SoftDeleteDID(ctx, DeleteDIDRequest{Method: did.JWKMethod, ID: "did:jwk:unknown"})
+		assert.ErrorContains(tt, err, "did:jwk:unknown")
+	})
+
+	t.Run("Test Resolve DID", func(tt *testing.T) {
+		handler, _ := testJWKHandler(tt)
+		created, err := handler.CreateDID(context.Background(), CreateDIDRequest{Method: did.JWKMethod, KeyType: crypto.SECP256k1})
+		require.NoError(tt, err)
+
+		resolver, err := NewHandlerResolver(map[did.Method]MethodHandler{did.JWKMethod: handler})
+		require.NoError(tt, err)
+		resolved, err := resolver.Resolve(context.Background(), created.DID.ID)
+		require.NoError(tt, err)
+		assert.Equal(tt, created.DID.ID, resolved.Document.ID)
+		assert.Equal(tt, created.DID.VerificationMethod, resolved.Document.VerificationMethod)
+	})
+}
+
+func testJWKHandler(t *testing.T) (MethodHandler, *keystore.Service) {
+	s := setupTestDB(t)
+	keystoreService := testKeyStoreService(t, s)
+	didStorage, err := NewDIDStorage(s)
+	require.NoError(t, err)
+	handler, err := NewJWKHandler(didStorage, keystoreService)
+	require.NoError(t, err)
+	return handler, keystoreService
+}
@@ -3,6 +3,7 @@ package did
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/TBD54566975/ssi-sdk/crypto"
 	"github.com/TBD54566975/ssi-sdk/did"
@@ -81,6 +82,19 @@ func (h *keyHandler) CreateDID(ctx context.Context, request CreateDIDRequest) (*
 	return &CreateDIDResponse{DID: storedDID.DID}, nil
 }
 
+// importedDocument returns the document the did:key DID expands to, which is the only document it can have, so keys are
+// checked against it rather than the document given.
+func (h *keyHandler) importedDocument(doc did.Document) (*did.Document, error) {
+	if !strings.HasPrefix(doc.ID, key.Prefix+":") {
+		return nil, fmt.Errorf("not a did:key DID: %s", doc.ID)
+	}
+	expanded, err := key.DIDKey(doc.ID).Expand()
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid did:key DID: %s", doc.ID)
+	}
+	return expanded, nil
+}
+
 func (h *keyHandler) GetDID(ctx context.Context, request GetDIDRequest) (*GetDIDResponse, error) {
 	logrus.Debugf("getting DID: %+v", request)
 
@@ -92,7 +106,11 @@ func (h *keyHandler) GetDID(ctx context.Context, request GetDIDRequest) (*GetDID
 	if gotDID == nil {
 		return nil, fmt.Errorf("did with id<%s> could not be found", id)
 	}
-	return &GetDIDResponse{DID: gotDID.DID}, nil
+	metadata, err := h.storage.GetDocumentMetadata(ctx, gotDID)
+	if err != nil {
+		return nil, err
+	}
+	return &GetDIDResponse{DID: gotDID.DID, DocumentMetadata: metadata}, nil
 }
 
 func (h *keyHandler) ListDIDs(ctx context.Context) (*ListDIDsResponse, error) {
@@ -128,6 +146,11 @@ func (h *keyHandler) ListDeletedDIDs(ctx context.Context) (*ListDIDsResponse, er
 	return &ListDIDsResponse{DIDs: dids}, nil
 }
 
+// UpdateDID is not supported, since the documents of did:key DIDs are derived from their key
+func (h *keyHandler) UpdateDID(_ context.Context, request UpdateDIDRequest) (*UpdateDIDResponse, error) {
+	return nil, fmt.Errorf("could not update DID<%s>: updates are not supported for method<%s>", request.ID, h.method)
+}
+
 func (h *keyHandler) SoftDeleteDID(ctx context.Context, request DeleteDIDRequest) error {
 	logrus.Debugf("soft deleting DID: %+v", request)
 
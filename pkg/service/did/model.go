@@ -4,7 +4,9 @@ import (
 	gocrypto "crypto"
 
 	"github.com/TBD54566975/ssi-sdk/crypto"
+	"github.com/TBD54566975/ssi-sdk/crypto/jwx"
 	didsdk "github.com/TBD54566975/ssi-sdk/did"
+	"github.com/TBD54566975/ssi-sdk/did/ion"
 	"github.com/TBD54566975/ssi-sdk/did/resolution"
 )
 
@@ -22,6 +24,24 @@ type ResolveDIDResponse struct {
 	DIDDocumentMetadata *resolution.DocumentMetadata   `json:"didDocumentMetadata,omitempty"`
 }
 
+type DereferenceDIDURLRequest struct {
+	DIDURL string `json:"didUrl" validate:"required"`
+}
+
+// DereferenceDIDURLResponse is the result of dereferencing a DID URL https://w3c-ccg.github.io/did-resolution/#dereferencing
+type DereferenceDIDURLResponse struct {
+	DereferencingMetadata DereferencingMetadata `json:"dereferencingMetadata"`
+	// The resource the DID URL identifies, which is a document, verification method, service, or URLs of service
+	// endpoints.
+	ContentStream any `json:"contentStream"`
+	// Metadata of the document of the DID.
+	ContentMetadata *resolution.DocumentMetadata `json:"contentMetadata,omitempty"`
+}
+
+type DereferencingMetadata struct {
+	ContentType string `json:"contentType"`
+}
+
 type CreateDIDRequestOptions interface {
 	Method() didsdk.Method
 }
@@ -38,6 +58,29 @@ type CreateDIDResponse struct {
 	DID didsdk.Document `json:"did"`
 }
 
+// ImportDIDRequest registers a DID created outside the service, along with the private keys of its verification
+// methods.
+type ImportDIDRequest struct {
+	Method didsdk.Method   `json:"method" validate:"required"`
+	DID    didsdk.Document `json:"did"`
+	Keys   []ImportDIDKey  `json:"keys" validate:"required,min=1,dive"`
+}
+
+// ImportDIDKey is the private key of a verification method, given either as a JWK or as base58 along with its type.
+type ImportDIDKey struct {
+	// ID of the verification method, or its fragment.
+	ID               string             `json:"id" validate:"required"`
+	PrivateKeyJWK    *jwx.PrivateKeyJWK `json:"privateKeyJwk,omitempty"`
+	PrivateKeyBase58 string             `json:"privateKeyBase58,omitempty"`
+	// Type of the base58 private key.
+	KeyType crypto.KeyType `json:"keyType,omitempty"`
+}
+
+// ImportDIDResponse is the JSON-serializable response for importing a DID
+type ImportDIDResponse struct {
+	DID didsdk.Document `json:"did"`
+}
+
 type GetDIDRequest struct {
 	Method didsdk.Method `json:"method" validate:"required"`
 	ID     string        `json:"id" validate:"required"`
@@ -46,6 +89,8 @@ type GetDIDRequest struct {
 // GetDIDResponse is the JSON-serializable response for getting a DID
 type GetDIDResponse struct {
 	DID didsdk.Document `json:"did"`
+	// Metadata of the document, such as when it was created and last updated, and whether the DID is deactivated.
+	DocumentMetadata *resolution.DocumentMetadata `json:"didDocumentMetadata,omitempty"`
 }
 
 type GetKeyFromDIDRequest struct {
@@ -68,7 +113,105 @@ type ListDIDsResponse struct {
 	DIDs []didsdk.Document `json:"dids"`
 }
 
+// UpdateDIDRequest is a patch to the document of a DID. Services and verification methods are removed first, then
+// added, and the verification relationships to set are applied last.
+type UpdateDIDRequest struct {
+	Method didsdk.Method `json:"method" validate:"required"`
+	ID     string        `json:"id" validate:"required"`
+
+	// Verification methods to add, whose keys are generated by the service and stored in the keystore.
+	VerificationMethodsToAdd []VerificationMethodToAdd `json:"verificationMethodsToAdd,omitempty" validate:"dive"`
+	// IDs of the verification methods to remove, along with their verification relationships.
+	VerificationMethodIDsToRemove []string `json:"verificationMethodIdsToRemove,omitempty"`
+	// Verification relationships replacing those of verification methods in the document.
+	VerificationRelationshipsToSet []VerificationRelationships `json:"verificationRelationshipsToSet,omitempty" validate:"dive"`
+	ServicesToAdd                  []didsdk.Service            `json:"servicesToAdd,omitempty" validate:"dive"`
+	ServiceIDsToRemove             []string                    `json:"serviceIdsToRemove,omitempty"`
+}
+
+// IsEmpty is whether the update has no changes.
+func (r UpdateDIDRequest) IsEmpty() bool {
+	return len(r.VerificationMethodsToAdd) == 0 &&
+		len(r.VerificationMethodIDsToRemove) == 0 &&
+		len(r.VerificationRelationshipsToSet) == 0 &&
+		len(r.ServicesToAdd) == 0 &&
+		len(r.ServiceIDsToRemove) == 0
+}
+
+type VerificationMethodToAdd struct {
+	// ID of the verification method, which is generated when empty.
+	ID      string         `json:"id,omitempty"`
+	KeyType crypto.KeyType `json:"keyType" validate:"required"`
+	// Verification relationships of the verification method.
+	Purposes []ion.PublicKeyPurpose `json:"purposes" validate:"required,dive,oneof=authentication assertionMethod capabilityInvocation capabilityDelegation keyAgreement"`
+}
+
+type VerificationRelationships struct {
+	// ID of the verification method.
+	ID       string                 `json:"id" validate:"required"`
+	Purposes []ion.PublicKeyPurpose `json:"purposes" validate:"dive,oneof=authentication assertionMethod capabilityInvocation capabilityDelegation keyAgreement"`
+}
+
+// UpdateDIDResponse is the JSON-serializable response for updating a DID
+type UpdateDIDResponse struct {
+	DID didsdk.Document `json:"did"`
+	// ID of the operation tracking the anchoring of the update, for methods anchoring updates on a network.
+	OperationID string `json:"operationId,omitempty"`
+}
+
+// RotateDIDKeyRequest replaces a key of a DID with a new one. The new key takes over the verification relationships of
+// the replaced key, and always becomes an assertion method.
+type RotateDIDKeyRequest struct {
+	Method didsdk.Method `json:"method" validate:"required"`
+	ID     string        `json:"id" validate:"required"`
+	// ID of the verification method of the key to replace. When empty, it's the key currently used to sign assertions.
+	KeyID   string         `json:"keyId,omitempty"`
+	KeyType crypto.KeyType `json:"keyType" validate:"required"`
+}
+
+// RotateDIDKeyResponse is the JSON-serializable response for rotating a key of a DID
+type RotateDIDKeyResponse struct {
+	DID didsdk.Document `json:"did"`
+	// ID of the verification method of the new key.
+	KeyID string `json:"keyId"`
+	// ID of the operation tracking the anchoring of the update, for methods anchoring updates on a network.
+	OperationID string `json:"operationId,omitempty"`
+}
+
+// RecoverIONDIDRequest replaces the document of a did:ion DID with a Sidetree recover operation.
+type RecoverIONDIDRequest struct {
+	ID string `json:"id" validate:"required"`
+	// Verification methods of the new document, whose keys are generated by the service and stored in the keystore.
+	VerificationMethods []VerificationMethodToAdd `json:"verificationMethods" validate:"required,dive"`
+	Services            []didsdk.Service          `json:"services,omitempty" validate:"dive"`
+}
+
+type RecoverIONDIDResponse struct {
+	DID didsdk.Document `json:"did"`
+	// ID of the operation tracking the anchoring of the recovery.
+	OperationID string `json:"operationId"`
+}
+
+type DeactivateIONDIDRequest struct {
+	ID string `json:"id" validate:"required"`
+}
+
+type DeactivateIONDIDResponse struct {
+	// ID of the operation tracking the anchoring of the deactivation.
+	OperationID string `json:"operationId"`
+}
+
 type DeleteDIDRequest struct {
 	Method didsdk.Method `json:"method" validate:"required"`
 	ID     string        `json:"id" validate:"required"`
 }
+
+type GetWebDIDDocumentRequest struct {
+	ID string `json:"id" validate:"required"`
+}
+
+// GetWebDIDDocumentResponse is the stored document of a did:web DID, and whether the DID has been deactivated
+type GetWebDIDDocumentResponse struct {
+	DID         didsdk.Document `json:"did"`
+	Deactivated bool            `json:"deactivated"`
+}
@@ -0,0 +1,310 @@
+package did
+
+import (
+	"context"
+	gocrypto "crypto"
+	"fmt"
+	"strings"
+
+	"github.com/TBD54566975/ssi-sdk/crypto"
+	"github.com/TBD54566975/ssi-sdk/did"
+	"github.com/TBD54566975/ssi-sdk/did/peer"
+	"github.com/TBD54566975/ssi-sdk/util"
+	"github.com/mr-tron/base58"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	didint "github.com/tbd54566975/ssi-service/internal/did"
+	"github.com/tbd54566975/ssi-service/pkg/service/keystore"
+)
+
+func NewPeerHandler(s *Storage, ks *keystore.Service) (MethodHandler, error) {
+	if s == nil {
+		return nil, errors.New("storage cannot be empty")
+	}
+	if ks == nil {
+		return nil, errors.New("keystore cannot be empty")
+	}
+	return &peerHandler{method: did.PeerMethod, storage: s, keyStore: ks}, nil
+}
+
+type peerHandler struct {
+	method   did.Method
+	storage  *Storage
+	keyStore *keystore.Service
+}
+
+// CreatePeerDIDOptions are the options for creating a did:peer. Without options, a numalgo 0 DID is created from a key
+// of the request's key type.
+type CreatePeerDIDOptions struct {
+	// NumAlgo is the algorithm used to generate the DID, either 0 (a single inception key) or 2 (multiple keys and
+	// services).
+	NumAlgo int `json:"numalgo" validate:"oneof=0 2"`
+	// Keys to generate in addition to the key of the request's key type, which is used for authentication and
+	// assertion. Only supported with numalgo 2.
+	Keys []CreatePeerDIDKey `json:"keys,omitempty" validate:"omitempty,dive"`
+	// Services of the DID, with string service endpoints. Only supported with numalgo 2.
+	Services []did.Service `json:"services,omitempty" validate:"omitempty,dive"`
+}
+
+func (c CreatePeerDIDOptions) Method() did.Method {
+	return did.PeerMethod
+}
+
+// CreatePeerDIDKey is a key to generate for a numalgo 2 did:peer.
+type CreatePeerDIDKey struct {
+	KeyType crypto.KeyType `json:"keyType" validate:"required"`
+	// Purposes of the key, each of which is one of A (assertion), E (key agreement), V (authentication),
+	// I (capability invocation) or D (capability delegation).
+	Purposes []peer.PurposeType `json:"purposes" validate:"required,dive,oneof=A E V I D"`
+}
+
+// peerKey is a key generated for a did:peer, with its multibase encoding and purposes.
+type peerKey struct {
+	keyType  crypto.KeyType
+	privKey  gocrypto.PrivateKey
+	encoded  string
+	purposes []peer.PurposeType
+}
+
+func (h *peerHandler) GetMethod() did.Method {
+	return h.method
+}
+
+func (h *peerHandler) CreateDID(ctx context.Context, request CreateDIDRequest) (*CreateDIDResponse, error) {
+	logrus.Debugf("creating DID: %+v", request)
+
+	// process options
+	var opts CreatePeerDIDOptions
+	if request.Options != nil {
+		var ok bool
+		opts, ok = request.Options.(CreatePeerDIDOptions)
+		if !ok || request.Options.Method() != did.PeerMethod {
+			return nil, fmt.Errorf("invalid options for method, expected %s, got %s", did.PeerMethod, request.Options.Method())
+		}
+		if err := util.IsValidStruct(opts); err != nil {
+			return nil, errors.Wrap(err, "processing options")
+		}
+	}
+
+	var keys []peerKey
+	var expanded *did.Document
+	switch opts.NumAlgo {
+	case 0:
+		if len(opts.Keys) > 0 || len(opts.Services) > 0 {
+			return nil, errors.New("keys and services are only supported for did:peer numalgo 2")
+		}
+		// the inception key has the verification relationships it's given when resolved by the sdk
+		key, err := generatePeerKey(request.KeyType, []peer.PurposeType{
+			peer.PurposeVerificationCode,
+			peer.PurposeAssertionCode,
+			peer.PurposeEncryptionCode,
+			peer.PurposeCapabilityDelegationCode,
+		})
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, *key)
+		if expanded, err = expandPeerDIDNumAlgo0(*key); err != nil {
+			return nil, errors.Wrap(err, "error generating did:peer document")
+		}
+	case 2:
+		key, err := generatePeerKey(request.KeyType, []peer.PurposeType{peer.PurposeAssertionCode, peer.PurposeVerificationCode})
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, *key)
+		for _, k := range opts.Keys {
+			key, err = generatePeerKey(k.KeyType, k.Purposes)
+			if err != nil {
+				return nil, err
+			}
+			keys = append(keys, *key)
+		}
+		id, err := buildPeerDIDNumAlgo2(keys, opts.Services)
+		if err != nil {
+			return nil, err
+		}
+		// the document is the one the DID resolves to, so it's the same wherever it's resolved
+		if expanded, err = didint.ExpandPeerDIDNumAlgo2(id); err != nil {
+			return nil, errors.Wrap(err, "error generating did:peer document")
+		}
+	}
+	id := expanded.ID
+
+	// store metadata in DID storage
+	storedDID := DefaultStoredDID{
+		ID:          id,
+		DID:         *expanded,
+		SoftDeleted: false,
+	}
+	if err := h.storage.StoreDID(ctx, storedDID); err != nil {
+		return nil, errors.Wrap(err, "could not store did:peer value")
+	}
+
+	// store private keys in key storage, under the id of their verification method
+	for _, key := range keys {
+		privKeyBytes, err := crypto.PrivKeyToBytes(key.privKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not encode private key as base58")
+		}
+		keyStoreRequest := keystore.StoreKeyRequest{
+			ID:               id + "#" + key.encoded,
+			Type:             key.keyType,
+			Controller:       id,
+			PrivateKeyBase58: base58.Encode(privKeyBytes),
+		}
+		if err = h.keyStore.StoreKey(ctx, keyStoreRequest); err != nil {
+			return nil, errors.Wrap(err, "could not store did:peer private key")
+		}
+	}
+	return &CreateDIDResponse{DID: storedDID.DID}, nil
+}
+
+// generatePeerKey generates a key of the given type for a did:peer, encoded as in the DID.
+func generatePeerKey(kt crypto.KeyType, purposes []peer.PurposeType) (*peerKey, error) {
+	if !peer.IsSupportedDIDPeerType(kt) {
+		return nil, fmt.Errorf("key type <%s> is not supported for did:peer", kt)
+	}
+	pubKey, privKey, err := crypto.GenerateKeyByKeyType(kt)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not generate key for did:peer")
+	}
+	// a numalgo 0 DID's suffix is the multibase encoding of its key, which is how keys are encoded in numalgo 2 too
+	didPeer, err := peer.Method0{}.Generate(kt, pubKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not encode key for did:peer")
+	}
+	encoded, err := didPeer.Suffix()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not encode key for did:peer")
+	}
+	return &peerKey{keyType: kt, privKey: privKey, encoded: encoded, purposes: purposes}, nil
+}
+
+// buildPeerDIDNumAlgo2 builds a numalgo 2 did:peer, with an entry for each purpose of each key followed by an entry
+// for each service, according to https://identity.foundation/peer-did-method-spec/#method-2-multiple-inception-key-without-doc
+func buildPeerDIDNumAlgo2(keys []peerKey, services []did.Service) (string, error) {
+	var sb strings.Builder
+	sb.WriteString(peer.DIDPeerPrefix + ":2")
+	for _, key := range keys {
+		for _, purpose := range key.purposes {
+			sb.WriteString("." + string(purpose) + key.encoded)
+		}
+	}
+	for _, service := range services {
+		encoded, err := didint.EncodePeerService(service)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString("." + string(peer.PurposeCapabilityServiceCode) + encoded)
+	}
+	return sb.String(), nil
+}
+
+// expandPeerDIDNumAlgo0 builds the document of a numalgo 0 did:peer, with a verification method for its inception
+// key, referenced by the verification relationships of its purposes. The verification method id is absolute, so that
+// the key in the keystore can be found in the document.
+func expandPeerDIDNumAlgo0(key peerKey) (*did.Document, error) {
+	id := peer.DIDPeerPrefix + ":0" + key.encoded
+	doc := did.Document{
+		Context: did.KnownDIDContext,
+		ID:      id,
+	}
+	pubKeyBytes, ldKeyType, cryptoKeyType, err := did.DecodeMultibaseEncodedKey(key.encoded)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not decode did:peer key")
+	}
+	vmID := id + "#" + key.encoded
+	verificationMethod, err := did.ConstructJWKVerificationMethod(id, vmID, pubKeyBytes, ldKeyType, cryptoKeyType)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not construct verification method")
+	}
+	doc.VerificationMethod = append(doc.VerificationMethod, *verificationMethod)
+	for _, purpose := range key.purposes {
+		switch purpose {
+		case peer.PurposeAssertionCode:
+			doc.AssertionMethod = append(doc.AssertionMethod, vmID)
+		case peer.PurposeEncryptionCode:
+			doc.KeyAgreement = append(doc.KeyAgreement, vmID)
+		case peer.PurposeVerificationCode:
+			doc.Authentication = append(doc.Authentication, vmID)
+		case peer.PurposeCapabilityDelegationCode:
+			doc.CapabilityDelegation = append(doc.CapabilityDelegation, vmID)
+		}
+	}
+	return &doc, nil
+}
+
+func (h *peerHandler) GetDID(ctx context.Context, request GetDIDRequest) (*GetDIDResponse, error) {
+	logrus.Debugf("getting DID: %+v", request)
+
+	id := request.ID
+	gotDID, err := h.storage.GetDIDDefault(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("error getting DID: %s", id)
+	}
+	if gotDID == nil {
+		return nil, fmt.Errorf("did with id<%s> could not be found", id)
+	}
+	metadata, err := h.storage.GetDocumentMetadata(ctx, gotDID)
+	if err != nil {
+		return nil, err
+	}
+	return &GetDIDResponse{DID: gotDID.DID, DocumentMetadata: metadata}, nil
+}
+
+func (h *peerHandler) ListDIDs(ctx context.Context) (*ListDIDsResponse, error) {
+	logrus.Debug("getting did:peer DIDs")
+
+	gotDIDs, err := h.storage.ListDIDsDefault(ctx, did.PeerMethod.String())
+	if err != nil {
+		return nil, fmt.Errorf("error getting did:peer DIDs")
+	}
+	dids := make([]did.Document, 0, len(gotDIDs))
+	for _, gotDID := range gotDIDs {
+		if !gotDID.IsSoftDeleted() {
+			dids = append(dids, gotDID.GetDocument())
+		}
+	}
+	return &ListDIDsResponse{DIDs: dids}, nil
+}
+
+// ListDeletedDIDs returns only DIDs we have in storage for peer with SoftDeleted flag set to true
+func (h *peerHandler) ListDeletedDIDs(ctx context.Context) (*ListDIDsResponse, error) {
+	logrus.Debug("listing did:peer DIDs")
+
+	gotDIDs, err := h.storage.ListDIDsDefault(ctx, did.PeerMethod.String())
+	if err != nil {
+		return nil, fmt.Errorf("error getting did:peer DIDs")
+	}
+	dids := make([]did.Document, 0, len(gotDIDs))
+	for _, gotDID := range gotDIDs {
+		if gotDID.IsSoftDeleted() {
+			dids = append(dids, gotDID.GetDocument())
+		}
+	}
+	return &ListDIDsResponse{DIDs: dids}, nil
+}
+
+// UpdateDID is not supported, since the documents of did:peer DIDs are derived from their keys and services
+func (h *peerHandler) UpdateDID(_ context.Context, request UpdateDIDRequest) (*UpdateDIDResponse, error) {
+	return nil, fmt.Errorf("could not update DID<%s>: updates are not supported for method<%s>", request.ID, h.method)
+}
+
+func (h *peerHandler) SoftDeleteDID(ctx context.Context, request DeleteDIDRequest) error {
+	logrus.Debugf("soft deleting DID: %+v", request)
+
+	id := request.ID
+	gotStoredDID, err := h.storage.GetDIDDefault(ctx, id)
+	if err != nil {
+		return fmt.Errorf("error getting DID: %s", id)
+	}
+	if gotStoredDID == nil {
+		return fmt.Errorf("did with id<%s> could not be found", id)
+	}
+
+	gotStoredDID.SoftDeleted = true
+
+	return h.storage.StoreDID(ctx, *gotStoredDID)
+}
@@ -0,0 +1,241 @@
+package did
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/TBD54566975/ssi-sdk/crypto"
+	"github.com/TBD54566975/ssi-sdk/crypto/jwx"
+	"github.com/TBD54566975/ssi-sdk/did"
+	"github.com/TBD54566975/ssi-sdk/did/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	didint "github.com/tbd54566975/ssi-service/internal/did"
+	"github.com/tbd54566975/ssi-service/pkg/service/keystore"
+)
+
+func TestPeerHandler(t *testing.T) {
+	t.Run("Test Create Peer Handler", func(tt *testing.T) {
+		s := setupTestDB(tt)
+		keystoreService := testKeyStoreService(tt, s)
+		didStorage, err := NewDIDStorage(s)
+		require.NoError(tt, err)
+
+		handler, err := NewPeerHandler(nil, keystoreService)
+		assert.Empty(tt, handler)
+		assert.ErrorContains(tt, err, "storage cannot be empty")
+
+		handler, err = NewPeerHandler(didStorage, nil)
+		assert.Empty(tt, handler)
+		assert.ErrorContains(tt, err, "keystore cannot be empty")
+
+		handler, err = NewPeerHandler(didStorage, keystoreService)
+		assert.NoError(tt, err)
+		assert.Equal(tt, did.PeerMethod, handler.GetMethod())
+	})
+
+	t.Run("Test Create NumAlgo 0 DID For Every Key Type", func(tt *testing.T) {
+		handler, keystoreService := testPeerHandler(tt)
+		for _, keyType := range peer.GetSupportedDIDPeerTypes() {
+			created, err := handler.CreateDID(context.Background(), CreateDIDRequest{Method: did.PeerMethod, KeyType: keyType})
+			require.NoError(tt, err, "key type %s", keyType)
+			assert.True(tt, strings.HasPrefix(created.DID.ID, "did:peer:0z"))
+			require.Len(tt, created.DID.VerificationMethod, 1)
+			vmID := created.DID.VerificationMethod[0].ID
+			assert.Equal(tt, created.DID.ID+"#"+strings.TrimPrefix(created.DID.ID, "did:peer:0"), vmID)
+			assert.Equal(tt, []did.VerificationMethodSet{vmID}, created.DID.AssertionMethod)
+			assert.Equal(tt, []did.VerificationMethodSet{vmID}, created.DID.Authentication)
+
+			// the private key is kept in the keystore
+			gotKey, err := keystoreService.GetKey(context.Background(), keystore.GetKeyRequest{ID: vmID})
+			require.NoError(tt, err)
+			assert.Equal(tt, keyType, gotKey.Type)
+			assert.Equal(tt, created.DID.ID, gotKey.Controller)
+		}
+	})
+
+	t.Run("Test Create NumAlgo 2 DID With Keys And Services", func(tt *testing.T) {
+		handler, keystoreService := testPeerHandler(tt)
+		service := did.Service{
+			ID:              "#didcomm",
+			Type:            peer.DIDCommMessaging,
+			ServiceEndpoint: "https://example.com/didcomm",
+			Accept:          []string{"didcomm/v2"},
+		}
+		created, err := handler.CreateDID(context.Background(), CreateDIDRequest{
+			Method:  did.PeerMethod,
+			KeyType: crypto.Ed25519,
+			Options: CreatePeerDIDOptions{
+				NumAlgo: 2,
+				Keys: []CreatePeerDIDKey{
+					{KeyType: crypto.X25519, Purposes: []peer.PurposeType{peer.PurposeEncryptionCode}},
+					{KeyType: crypto.P256, Purposes: []peer.PurposeType{peer.PurposeCapabilityInvocationCode}},
+				},
+				Services: []did.Service{service},
+			},
+		})
+		require.NoError(tt, err)
+		assert.True(tt, strings.HasPrefix(created.DID.ID, "did:peer:2.Az"))
+		require.Len(tt, created.DID.VerificationMethod, 3)
+		signingKeyID := created.DID.VerificationMethod[0].ID
+		assert.Equal(tt, []did.VerificationMethodSet{signingKeyID}, created.DID.AssertionMethod)
+		assert.Equal(tt, []did.VerificationMethodSet{signingKeyID}, created.DID.Authentication)
+		assert.Equal(tt, []did.VerificationMethodSet{created.DID.VerificationMethod[1].ID}, created.DID.KeyAgreement)
+		assert.Equal(tt, []did.VerificationMethodSet{created.DID.VerificationMethod[2].ID}, created.DID.CapabilityInvocation)
+		assert.Equal(tt, []did.Service{service}, created.DID.Services)
+
+		for i, keyType := range []crypto.KeyType{crypto.Ed25519, crypto.X25519, crypto.P256} {
+			gotKey, err := keystoreService.GetKey(context.Background(), keystore.GetKeyRequest{ID: created.DID.VerificationMethod[i].ID})
+			require.NoError(tt, err)
+			assert.Equal(tt, keyType, gotKey.Type)
+			assert.Equal(tt, created.DID.ID, gotKey.Controller)
+		}
+
+		// the service is encoded in the DID, and the DID has an entry for each purpose of each key
+		entries := strings.Split(strings.TrimPrefix(created.DID.ID, "did:peer:2."), ".")
+		require.Len(tt, entries, 5)
+		assert.True(tt, strings.HasPrefix(entries[1], "Vz"))
+		assert.True(tt, strings.HasPrefix(entries[2], "Ez"))
+		assert.True(tt, strings.HasPrefix(entries[3], "Iz"))
+		assert.True(tt, strings.HasPrefix(entries[4], "S"))
+
+		// the DID resolves to the same document without the service
+		resolver, err := didint.BuildMultiMethodResolver([]string{did.PeerMethod.String()})
+		require.NoError(tt, err)
+		resolved, err := resolver.Resolve(context.Background(), created.DID.ID)
+		require.NoError(tt, err)
+		assert.Equal(tt, created.DID, resolved.Document)
+	})
+
+	t.Run("Test Create DID With Invalid Options", func(tt *testing.T) {
+		handler, _ := testPeerHandler(tt)
+		testCases := []struct {
+			name      string
+			keyType   crypto.KeyType
+			options   CreateDIDRequestOptions
+			wantError string
+		}{
+			{
+				name:      "unsupported key type",
+				keyType:   crypto.P224,
+				wantError: "is not supported for did:peer",
+			},
+			{
+				name:      "unsupported numalgo",
+				keyType:   crypto.Ed25519,
+				options:   CreatePeerDIDOptions{NumAlgo: 1},
+				wantError: "processing options",
+			},
+			{
+				name:      "options of another method",
+				keyType:   crypto.Ed25519,
+				options:   CreateWebDIDOptions{DIDWebID: "did:web:example.com"},
+				wantError: "invalid options for method",
+			},
+			{
+				name:    "keys with numalgo 0",
+				keyType: crypto.Ed25519,
+				options: CreatePeerDIDOptions{Keys: []CreatePeerDIDKey{
+					{KeyType: crypto.X25519, Purposes: []peer.PurposeType{peer.PurposeEncryptionCode}},
+				}},
+				wantError: "only supported for did:peer numalgo 2",
+			},
+			{
+				name:    "service purpose for a key",
+				keyType: crypto.Ed25519,
+				options: CreatePeerDIDOptions{NumAlgo: 2, Keys: []CreatePeerDIDKey{
+					{KeyType: crypto.X25519, Purposes: []peer.PurposeType{peer.PurposeCapabilityServiceCode}},
+				}},
+				wantError: "processing options",
+			},
+			{
+				name:    "service with an endpoint that isn't a string",
+				keyType: crypto.Ed25519,
+				options: CreatePeerDIDOptions{NumAlgo: 2, Services: []did.Service{
+					{ID: "#service", Type: "LinkedDomains", ServiceEndpoint: []string{"https://example.com"}},
+				}},
+				wantError: "endpoint must be a string",
+			},
+		}
+		for _, tc := range testCases {
+			tt.Run(tc.name, func(ttt *testing.T) {
+				_, err := handler.CreateDID(context.Background(), CreateDIDRequest{Method: did.PeerMethod, KeyType: tc.keyType, Options: tc.options})
+				assert.ErrorContains(ttt, err, tc.wantError)
+			})
+		}
+	})
+
+	t.Run("Test Get, List and Soft Delete DIDs", func(tt *testing.T) {
+		handler, _ := testPeerHandler(tt)
+		ctx := context.Background()
+
+		created, err := handler.CreateDID(ctx, CreateDIDRequest{Method: did.PeerMethod, KeyType: crypto.Ed25519})
+		require.NoError(tt, err)
+		other, err := handler.CreateDID(ctx, CreateDIDRequest{Method: did.PeerMethod, KeyType: crypto.Ed25519, Options: CreatePeerDIDOptions{NumAlgo: 2}})
+		require.NoError(tt, err)
+
+		got, err := handler.GetDID(ctx, GetDIDRequest{Method: did.PeerMethod, ID: created.DID.ID})
+		require.NoError(tt, err)
+		assert.Equal(tt, created.DID.ID, got.DID.ID)
+		assert.Equal(tt, created.DID.VerificationMethod, got.DID.VerificationMethod)
+
+		listed, err := handler.ListDIDs(ctx)
+		require.NoError(tt, err)
+		assert.Len(tt, listed.DIDs, 2)
+
+		require.NoError(tt, handler.SoftDeleteDID(ctx, DeleteDIDRequest{Method: did.PeerMethod, ID: created.DID.ID}))
+		listed, err = handler.ListDIDs(ctx)
+		require.NoError(tt, err)
+		require.Len(tt, listed.DIDs, 1)
+		assert.Equal(tt, other.DID.ID, listed.DIDs[0].ID)
+		deleted, err := handler.ListDeletedDIDs(ctx)
+		require.NoError(tt, err)
+		require.Len(tt, deleted.DIDs, 1)
+		assert.Equal(tt, created.DID.ID, deleted.DIDs[0].ID)
+
+		err = handler.SoftDeleteDID(ctx, DeleteDIDRequest{Method: did.PeerMethod, ID: "did:peer:0unknown"})
+		assert.ErrorContains(tt, err, "did:peer:0unknown")
+	})
+
+	t.Run("Test Sign With A Stored Key And Verify With The Resolved DID", func(tt *testing.T) {
+		handler, keystoreService := testPeerHandler(tt)
+		for _, numAlgo := range []int{0, 2} {
+			created, err := handler.CreateDID(context.Background(), CreateDIDRequest{
+				Method:  did.PeerMethod,
+				KeyType: crypto.SECP256k1,
+				Options: CreatePeerDIDOptions{NumAlgo: numAlgo},
+			})
+			require.NoError(tt, err)
+			kid := created.DID.VerificationMethod[0].ID
+
+			gotKey, err := keystoreService.GetKey(context.Background(), keystore.GetKeyRequest{ID: kid})
+			require.NoError(tt, err)
+			signer, err := jwx.NewJWXSigner(created.DID.ID, kid, gotKey.Key)
+			require.NoError(tt, err)
+			token, err := signer.SignWithDefaults(map[string]any{"numalgo": numAlgo})
+			require.NoError(tt, err)
+
+			resolver, err := NewHandlerResolver(map[did.Method]MethodHandler{did.PeerMethod: handler})
+			require.NoError(tt, err)
+			resolved, err := resolver.Resolve(context.Background(), created.DID.ID)
+			require.NoError(tt, err)
+			pubKey, err := did.GetKeyFromVerificationMethod(resolved.Document, kid)
+			require.NoError(tt, err)
+			verifier, err := jwx.NewJWXVerifier(created.DID.ID, kid, pubKey)
+			require.NoError(tt, err)
+			assert.NoError(tt, verifier.Verify(string(token)))
+		}
+	})
+}
+
+func testPeerHandler(t *testing.T) (MethodHandler, *keystore.Service) {
+	s := setupTestDB(t)
+	keystoreService := testKeyStoreService(t, s)
+	didStorage, err := NewDIDStorage(s)
+	require.NoError(t, err)
+	handler, err := NewPeerHandler(didStorage, keystoreService)
+	require.NoError(t, err)
+	return handler, keystoreService
+}
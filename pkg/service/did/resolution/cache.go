@@ -0,0 +1,125 @@
+package resolution
+
+import (
+	"context"
+	"time"
+
+	"github.com/TBD54566975/ssi-sdk/did"
+	"github.com/TBD54566975/ssi-sdk/did/resolution"
+	"github.com/benbjohnson/clock"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	utilint "github.com/tbd54566975/ssi-service/internal/util"
+)
+
+// CachedResolution is the outcome of resolving a DID, kept until it expires. DIDs that weren't found are cached too,
+// so that resolving them again doesn't go back to the network until they expire.
+type CachedResolution struct {
+	Result    *resolution.ResolutionResult `json:"result,omitempty"`
+	NotFound  bool                         `json:"notFound,omitempty"`
+	ExpiresAt time.Time                    `json:"expiresAt"`
+}
+
+// CacheStore stores cached resolutions by DID.
+type CacheStore interface {
+	// Get returns the cached resolution of the DID, or nil when there is none.
+	Get(ctx context.Context, did string) (*CachedResolution, error)
+	Set(ctx context.Context, did string, cached CachedResolution) error
+	Delete(ctx context.Context, did string) error
+}
+
+// CacheOptions are the TTLs of cached resolutions. A TTL of zero disables caching.
+type CacheOptions struct {
+	// TTL of resolutions of methods without a TTL of their own.
+	TTL        time.Duration
+	MethodTTLs map[did.Method]time.Duration
+	// TTL of DIDs that weren't found.
+	NotFoundTTL time.Duration
+}
+
+// CachingResolver caches the resolutions of another resolver. Cached resolutions of DIDs that change must be
+// invalidated.
+type CachingResolver struct {
+	resolver resolution.Resolver
+	store    CacheStore
+	options  CacheOptions
+
+	Clock clock.Clock
+}
+
+var _ resolution.Resolver = (*CachingResolver)(nil)
+
+// NewCachingResolver creates a resolver caching the resolutions of resolver in store.
+func NewCachingResolver(resolver resolution.Resolver, store CacheStore, options CacheOptions) (*CachingResolver, error) {
+	if resolver == nil {
+		return nil, errors.New("resolver cannot be empty")
+	}
+	if store == nil {
+		return nil, errors.New("cache store cannot be empty")
+	}
+	return &CachingResolver{resolver: resolver, store: store, options: options, Clock: clock.New()}, nil
+}
+
+// Resolve returns the cached resolution of the DID when it hasn't expired, and otherwise resolves and caches it.
+// Expired resolutions are evicted when read. Failing to read or write the cache doesn't fail the resolution.
+func (cr *CachingResolver) Resolve(ctx context.Context, did string, opts ...resolution.ResolutionOption) (*resolution.ResolutionResult, error) {
+	cached, err := cr.store.Get(ctx, did)
+	if err != nil {
+		logrus.WithError(err).Warnf("getting cached resolution of DID: %s", did)
+	}
+	if cached != nil {
+		if cr.Clock.Now().Before(cached.ExpiresAt) {
+			if cached.NotFound {
+				return nil, errors.Wrapf(ErrNotFound, "unable to resolve DID %s", did)
+			}
+			return cached.Result, nil
+		}
+		if err = cr.store.Delete(ctx, did); err != nil {
+			logrus.WithError(err).Warnf("evicting expired resolution of DID: %s", did)
+		}
+	}
+
+	resolved, err := cr.resolver.Resolve(ctx, did, opts...)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			cr.cache(ctx, did, CachedResolution{NotFound: true}, cr.options.NotFoundTTL)
+		}
+		return nil, err
+	}
+	cr.cache(ctx, did, CachedResolution{Result: resolved}, cr.ttl(did))
+	return resolved, nil
+}
+
+// Invalidate removes the cached resolution of the DID, which is resolved again the next time.
+func (cr *CachingResolver) Invalidate(ctx context.Context, did string) error {
+	if err := cr.store.Delete(ctx, did); err != nil {
+		return errors.Wrapf(err, "invalidating cached resolution of DID: %s", did)
+	}
+	return nil
+}
+
+func (cr *CachingResolver) Methods() []did.Method {
+	return cr.resolver.Methods()
+}
+
+func (cr *CachingResolver) ttl(did string) time.Duration {
+	method, err := utilint.GetMethodForDID(did)
+	if err != nil {
+		return cr.options.TTL
+	}
+	if ttl, ok := cr.options.MethodTTLs[method]; ok {
+		return ttl
+	}
+	return cr.options.TTL
+}
+
+func (cr *CachingResolver) cache(ctx context.Context, did string, cached CachedResolution, ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+	cached.ExpiresAt = cr.Clock.Now().Add(ttl)
+	if err := cr.store.Set(ctx, did, cached); err != nil {
+		logrus.WithError(err).Warnf("caching resolution of DID: %s", did)
+	}
+}
@@ -0,0 +1,205 @@
+package resolution
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	didsdk "github.com/TBD54566975/ssi-sdk/did"
+	"github.com/TBD54566975/ssi-sdk/did/resolution"
+	"github.com/benbjohnson/clock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tbd54566975/ssi-service/pkg/storage"
+)
+
+// countingResolver resolves the DIDs it knows, and counts how many times it was asked to.
+type countingResolver struct {
+	docs        map[string]didsdk.Document
+	resolutions int
+}
+
+func (r *countingResolver) Resolve(_ context.Context, did string, _ ...resolution.ResolutionOption) (*resolution.ResolutionResult, error) {
+	r.resolutions++
+	doc, ok := r.docs[did]
+	if !ok {
+		return nil, errors.Wrapf(ErrNotFound, "resolving DID %s", did)
+	}
+	return &resolution.ResolutionResult{Document: doc}, nil
+}
+
+func (r *countingResolver) Methods() []didsdk.Method {
+	return []didsdk.Method{"example", "ion"}
+}
+
+func TestCachingResolver(t *testing.T) {
+	stores := map[string]func(t *testing.T) CacheStore{
+		"memory": func(_ *testing.T) CacheStore {
+			return NewMemoryCacheStore(0)
+		},
+		"storage": func(t *testing.T) CacheStore {
+			store, err := NewStorageCacheStore(setupTestDB(t))
+			require.NoError(t, err)
+			return store
+		},
+	}
+	for name, newStore := range stores {
+		t.Run(name, func(tt *testing.T) {
+			ctx := context.Background()
+			resolver := &countingResolver{docs: map[string]didsdk.Document{
+				"did:example:123": {ID: "did:example:123"},
+				"did:ion:123":     {ID: "did:ion:123"},
+			}}
+			cachingResolver, err := NewCachingResolver(resolver, newStore(tt), CacheOptions{
+				TTL:         time.Hour,
+				MethodTTLs:  map[didsdk.Method]time.Duration{"ion": time.Minute},
+				NotFoundTTL: time.Second,
+			})
+			require.NoError(tt, err)
+			mockClock := clock.NewMock()
+			mockClock.Set(time.Now())
+			cachingResolver.Clock = mockClock
+
+			// resolutions are cached until they expire
+			resolved, err := cachingResolver.Resolve(ctx, "did:example:123")
+			require.NoError(tt, err)
+			assert.Equal(tt, "did:example:123", resolved.Document.ID)
+			_, err = cachingResolver.Resolve(ctx, "did:ion:123")
+			require.NoError(tt, err)
+			assert.Equal(tt, 2, resolver.resolutions)
+
+			mockClock.Add(30 * time.Second)
+			resolved, err = cachingResolver.Resolve(ctx, "did:example:123")
+			require.NoError(tt, err)
+			assert.Equal(tt, "did:example:123", resolved.Document.ID)
+			_, err = cachingResolver.Resolve(ctx, "did:ion:123")
+			require.NoError(tt, err)
+			assert.Equal(tt, 2, resolver.resolutions)
+
+			// the method's TTL applies to it
+			mockClock.Add(time.Minute)
+			_, err = cachingResolver.Resolve(ctx, "did:example:123")
+			require.NoError(tt, err)
+			_, err = cachingResolver.Resolve(ctx, "did:ion:123")
+			require.NoError(tt, err)
+			assert.Equal(tt, 3, resolver.resolutions)
+
+			// invalidated resolutions are resolved again
+			resolver.docs["did:example:123"] = didsdk.Document{ID: "did:example:123", Controller: "did:example:456"}
+			require.NoError(tt, cachingResolver.Invalidate(ctx, "did:example:123"))
+			resolved, err = cachingResolver.Resolve(ctx, "did:example:123")
+			require.NoError(tt, err)
+			assert.Equal(tt, "did:example:456", resolved.Document.Controller)
+			assert.Equal(tt, 4, resolver.resolutions)
+
+			// DIDs that weren't found are cached for their own TTL
+			_, err = cachingResolver.Resolve(ctx, "did:example:unknown")
+			assert.ErrorIs(tt, err, ErrNotFound)
+			_, err = cachingResolver.Resolve(ctx, "did:example:unknown")
+			assert.ErrorIs(tt, err, ErrNotFound)
+			assert.Equal(tt, 5, resolver.resolutions)
+
+			mockClock.Add(2 * time.Second)
+			_, err = cachingResolver.Resolve(ctx, "did:example:unknown")
+			assert.ErrorIs(tt, err, ErrNotFound)
+			assert.Equal(tt, 6, resolver.resolutions)
+
+			// invalidating a DID that isn't cached is a no-op
+			assert.NoError(tt, cachingResolver.Invalidate(ctx, "did:example:uncached"))
+		})
+	}
+
+	t.Run("zero TTLs disable caching", func(tt *testing.T) {
+		resolver := &countingResolver{docs: map[string]didsdk.Document{"did:example:123": {ID: "did:example:123"}}}
+		cachingResolver, err := NewCachingResolver(resolver, NewMemoryCacheStore(0), CacheOptions{})
+		require.NoError(tt, err)
+
+		for i := 0; i < 2; i++ {
+			_, err = cachingResolver.Resolve(context.Background(), "did:example:123")
+			require.NoError(tt, err)
+			_, err = cachingResolver.Resolve(context.Background(), "did:example:unknown")
+			assert.ErrorIs(tt, err, ErrNotFound)
+		}
+		assert.Equal(tt, 4, resolver.resolutions)
+	})
+}
+
+func TestCachingResolverEvictsExpiredResolutions(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryCacheStore(0)
+	resolver := &countingResolver{docs: map[string]didsdk.Document{"did:example:123": {ID: "did:example:123"}}}
+	cachingResolver, err := NewCachingResolver(resolver, store, CacheOptions{TTL: time.Minute})
+	require.NoError(t, err)
+	mockClock := clock.NewMock()
+	mockClock.Set(time.Now())
+	cachingResolver.Clock = mockClock
+
+	_, err = cachingResolver.Resolve(ctx, "did:example:123")
+	require.NoError(t, err)
+	cached, err := store.Get(ctx, "did:example:123")
+	require.NoError(t, err)
+	assert.NotNil(t, cached)
+
+	// the expired resolution is evicted even though resolving the DID again fails
+	delete(resolver.docs, "did:example:123")
+	mockClock.Add(2 * time.Minute)
+	_, err = cachingResolver.Resolve(ctx, "did:example:123")
+	assert.ErrorIs(t, err, ErrNotFound)
+	cached, err = store.Get(ctx, "did:example:123")
+	require.NoError(t, err)
+	assert.Nil(t, cached)
+}
+
+func TestMemoryCacheStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryCacheStore(2)
+	for _, did := range []string{"did:example:1", "did:example:2"} {
+		require.NoError(t, store.Set(ctx, did, CachedResolution{NotFound: true}))
+	}
+
+	// the least recently used resolution is evicted beyond the maximum number of entries
+	cached, err := store.Get(ctx, "did:example:1")
+	require.NoError(t, err)
+	assert.NotNil(t, cached)
+	require.NoError(t, store.Set(ctx, "did:example:3", CachedResolution{NotFound: true}))
+	for did, kept := range map[string]bool{"did:example:1": true, "did:example:2": false, "did:example:3": true} {
+		cached, err = store.Get(ctx, did)
+		require.NoError(t, err)
+		assert.Equal(t, kept, cached != nil, did)
+	}
+
+	// replacing a resolution doesn't evict another one
+	require.NoError(t, store.Set(ctx, "did:example:3", CachedResolution{}))
+	cached, err = store.Get(ctx, "did:example:1")
+	require.NoError(t, err)
+	assert.NotNil(t, cached)
+	cached, err = store.Get(ctx, "did:example:3")
+	require.NoError(t, err)
+	require.NotNil(t, cached)
+	assert.False(t, cached.NotFound)
+
+	require.NoError(t, store.Delete(ctx, "did:example:3"))
+	cached, err = store.Get(ctx, "did:example:3")
+	require.NoError(t, err)
+	assert.Nil(t, cached)
+}
+
+func setupTestDB(t *testing.T) storage.ServiceStorage {
+	file, err := os.CreateTemp("", "bolt")
+	require.NoError(t, err)
+	name := file.Name()
+	s, err := storage.NewStorage(storage.Bolt, storage.Option{
+		ID:     storage.BoltDBFilePathOption,
+		Option: name,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = s.Close()
+		_ = file.Close()
+		_ = os.Remove(name)
+	})
+	return s
+}
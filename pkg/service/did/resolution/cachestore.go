@@ -0,0 +1,138 @@
+package resolution
+
+import (
+	"container/list"
+	"context"
+	"sync"
+
+	"github.com/goccy/go-json"
+	"github.com/pkg/errors"
+
+	"github.com/tbd54566975/ssi-service/pkg/storage"
+)
+
+const (
+	cacheNamespace = "did_resolution_cache"
+
+	// DefaultMemoryCacheMaxEntries is how many resolutions a memory CacheStore keeps when not told otherwise.
+	DefaultMemoryCacheMaxEntries = 10000
+)
+
+// memoryCacheStore is a CacheStore keeping cached resolutions in memory, for a single instance of the service. Once it
+// holds maxEntries resolutions, the least recently used one is evicted to make room for a new one.
+type memoryCacheStore struct {
+	mu         sync.Mutex
+	maxEntries int
+	// most recently used entries are at the front
+	entries *list.List
+	cached  map[string]*list.Element
+}
+
+type memoryCacheEntry struct {
+	did    string
+	cached CachedResolution
+}
+
+var _ CacheStore = (*memoryCacheStore)(nil)
+
+// NewMemoryCacheStore creates a CacheStore keeping up to maxEntries cached resolutions in memory, or
+// DefaultMemoryCacheMaxEntries when maxEntries isn't positive.
+func NewMemoryCacheStore(maxEntries int) CacheStore {
+	if maxEntries <= 0 {
+		maxEntries = DefaultMemoryCacheMaxEntries
+	}
+	return &memoryCacheStore{
+		maxEntries: maxEntries,
+		entries:    list.New(),
+		cached:     make(map[string]*list.Element),
+	}
+}
+
+func (m *memoryCacheStore) Get(_ context.Context, did string) (*CachedResolution, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	element, ok := m.cached[did]
+	if !ok {
+		return nil, nil
+	}
+	m.entries.MoveToFront(element)
+	cached := element.Value.(*memoryCacheEntry).cached
+	return &cached, nil
+}
+
+func (m *memoryCacheStore) Set(_ context.Context, did string, cached CachedResolution) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if element, ok := m.cached[did]; ok {
+		element.Value.(*memoryCacheEntry).cached = cached
+		m.entries.MoveToFront(element)
+		return nil
+	}
+	m.cached[did] = m.entries.PushFront(&memoryCacheEntry{did: did, cached: cached})
+	for m.entries.Len() > m.maxEntries {
+		oldest := m.entries.Back()
+		m.entries.Remove(oldest)
+		delete(m.cached, oldest.Value.(*memoryCacheEntry).did)
+	}
+	return nil
+}
+
+func (m *memoryCacheStore) Delete(_ context.Context, did string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if element, ok := m.cached[did]; ok {
+		m.entries.Remove(element)
+		delete(m.cached, did)
+	}
+	return nil
+}
+
+// storageCacheStore is a CacheStore keeping cached resolutions in the service's storage, shared by all instances of the
+// service using it.
+type storageCacheStore struct {
+	db storage.ServiceStorage
+}
+
+var _ CacheStore = (*storageCacheStore)(nil)
+
+// NewStorageCacheStore creates a CacheStore keeping cached resolutions in db.
+func NewStorageCacheStore(db storage.ServiceStorage) (CacheStore, error) {
+	if db == nil {
+		return nil, errors.New("db reference is nil")
+	}
+	return &storageCacheStore{db: db}, nil
+}
+
+func (s *storageCacheStore) Get(ctx context.Context, did string) (*CachedResolution, error) {
+	cachedBytes, err := s.db.Read(ctx, cacheNamespace, did)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading cached resolution of DID: %s", did)
+	}
+	if len(cachedBytes) == 0 {
+		return nil, nil
+	}
+	var cached CachedResolution
+	if err = json.Unmarshal(cachedBytes, &cached); err != nil {
+		return nil, errors.Wrapf(err, "unmarshalling cached resolution of DID: %s", did)
+	}
+	return &cached, nil
+}
+
+func (s *storageCacheStore) Set(ctx context.Context, did string, cached CachedResolution) error {
+	cachedBytes, err := json.Marshal(cached)
+	if err != nil {
+		return errors.Wrapf(err, "marshalling cached resolution of DID: %s", did)
+	}
+	return s.db.Write(ctx, cacheNamespace, did, cachedBytes)
+}
+
+func (s *storageCacheStore) Delete(ctx context.Context, did string) error {
+	exists, err := s.db.Exists(ctx, cacheNamespace, did)
+	if err != nil {
+		return errors.Wrapf(err, "checking cached resolution of DID: %s", did)
+	}
+	if !exists {
+		return nil
+	}
+	return s.db.Delete(ctx, cacheNamespace, did)
+}
@@ -13,6 +13,9 @@ import (
 	utilint "github.com/tbd54566975/ssi-service/internal/util"
 )
 
+// ErrNotFound is returned when a resolver found that a DID doesn't exist.
+var ErrNotFound = errors.New("DID not found")
+
 // ServiceResolver is a resolver that can resolve DIDs using a combination of local and universal resolvers.
 type ServiceResolver struct {
 	resolutionMethods []string
@@ -90,6 +93,11 @@ func (sr *ServiceResolver) Resolve(ctx context.Context, did string, opts ...reso
 
 		}
 		logrus.WithError(err).Error("error resolving DID with universal resolver")
+
+		// the universal resolver is the last resort, so when it didn't find the DID no one can resolve it
+		if errors.Is(err, ErrNotFound) {
+			return nil, errors.Wrapf(err, "unable to resolve DID %s", did)
+		}
 	}
 
 	return nil, fmt.Errorf("unable to resolve DID %s", did)
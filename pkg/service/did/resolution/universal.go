@@ -12,6 +12,8 @@ import (
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+
+	utilint "github.com/tbd54566975/ssi-service/internal/util"
 )
 
 // universalResolver is a struct that implements the Resolver interface. It calls the universal resolver endpoint
@@ -46,6 +48,15 @@ func (ur *universalResolver) Resolve(ctx context.Context, did string, _ ...resol
 	if err != nil {
 		return nil, errors.Wrap(err, "performing http get")
 	}
+	defer resp.Body.Close()
+
+	// the universal resolver responds with a not found status for DIDs that don't exist
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, errors.Wrapf(ErrNotFound, "resolving DID %s", did)
+	}
+	if !utilint.Is2xxResponse(resp.StatusCode) {
+		return nil, errors.Errorf("resolving DID %s: universal resolver responded with status %d", did, resp.StatusCode)
+	}
 
 	respBody, err := io.ReadAll(bufio.NewReader(resp.Body))
 	if err != nil {
@@ -3,13 +3,18 @@ package did
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	didsdk "github.com/TBD54566975/ssi-sdk/did"
+	"github.com/TBD54566975/ssi-sdk/did/ion"
 	didresolution "github.com/TBD54566975/ssi-sdk/did/resolution"
+	"github.com/TBD54566975/ssi-sdk/did/web"
 	sdkutil "github.com/TBD54566975/ssi-sdk/util"
 	"github.com/pkg/errors"
 
 	"github.com/tbd54566975/ssi-service/config"
+	didint "github.com/tbd54566975/ssi-service/internal/did"
 	"github.com/tbd54566975/ssi-service/pkg/service/did/resolution"
 	"github.com/tbd54566975/ssi-service/pkg/service/framework"
 	"github.com/tbd54566975/ssi-service/pkg/service/keystore"
@@ -24,7 +29,9 @@ type Service struct {
 	handlers map[didsdk.Method]MethodHandler
 
 	// resolver for DID methods
-	resolver *resolution.ServiceResolver
+	resolver didresolution.Resolver
+	// cache of the resolver, which is nil when resolutions aren't cached
+	resolutionCache *resolution.CachingResolver
 
 	// external dependencies
 	keyStore *keystore.Service
@@ -99,6 +106,16 @@ func NewDIDService(config config.DIDServiceConfig, s storage.ServiceStorage, key
 	}
 	service.resolver = resolver
 
+	// cache resolutions when configured, which are invalidated as the DIDs of the service change
+	if config.ResolutionCache != nil {
+		cache, err := newResolutionCache(*config.ResolutionCache, s, resolver)
+		if err != nil {
+			return nil, errors.Wrap(err, "instantiating DID resolution cache")
+		}
+		service.resolver = cache
+		service.resolutionCache = cache
+	}
+
 	if !service.Status().IsReady() {
 		return nil, errors.New(service.Status().Message)
 	}
@@ -127,6 +144,18 @@ func (s *Service) instantiateHandlerForMethod(method didsdk.Method) error {
 			return errors.Wrap(err, "instantiating ion handler")
 		}
 		s.handlers[method] = ih
+	case didsdk.JWKMethod:
+		jh, err := NewJWKHandler(s.storage, s.keyStore)
+		if err != nil {
+			return errors.Wrap(err, "instantiating jwk handler")
+		}
+		s.handlers[method] = jh
+	case didsdk.PeerMethod:
+		ph, err := NewPeerHandler(s.storage, s.keyStore)
+		if err != nil {
+			return errors.Wrap(err, "instantiating peer handler")
+		}
+		s.handlers[method] = ph
 	default:
 		return sdkutil.LoggingNewErrorf("unsupported DID method: %s", method)
 	}
@@ -148,6 +177,31 @@ func (s *Service) ResolveDID(request ResolveDIDRequest) (*ResolveDIDResponse, er
 	}, nil
 }
 
+// DereferenceDIDURL resolves the DID of a DID URL, and selects the resource the rest of the URL identifies within its
+// document.
+func (s *Service) DereferenceDIDURL(ctx context.Context, request DereferenceDIDURLRequest) (*DereferenceDIDURLResponse, error) {
+	if err := sdkutil.IsValidStruct(request); err != nil {
+		return nil, sdkutil.LoggingErrorMsg(err, "invalid dereference DID URL request")
+	}
+	parsed, err := parseDIDURL(request.DIDURL)
+	if err != nil {
+		return nil, sdkutil.LoggingErrorMsg(err, "could not parse DID URL")
+	}
+	resolved, err := s.Resolve(ctx, parsed.did)
+	if err != nil {
+		return nil, err
+	}
+	content, contentType, err := dereferenceDocument(resolved.Document, *parsed)
+	if err != nil {
+		return nil, sdkutil.LoggingErrorMsgf(err, "could not dereference DID URL: %s", request.DIDURL)
+	}
+	return &DereferenceDIDURLResponse{
+		DereferencingMetadata: DereferencingMetadata{ContentType: contentType},
+		ContentStream:         content,
+		ContentMetadata:       &resolved.DocumentMetadata,
+	}, nil
+}
+
 func (s *Service) Resolve(ctx context.Context, did string, opts ...didresolution.ResolutionOption) (*didresolution.ResolutionResult, error) {
 	return s.resolver.Resolve(ctx, did, opts)
 }
@@ -165,7 +219,77 @@ func (s *Service) CreateDIDByMethod(ctx context.Context, request CreateDIDReques
 	if err != nil {
 		return nil, sdkutil.LoggingErrorMsgf(err, "could not get handler for method<%s>", request.Method)
 	}
-	return handler.CreateDID(ctx, request)
+	created, err := handler.CreateDID(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	// the DID may have been cached as not found
+	if err = s.invalidateResolution(ctx, created.DID.ID); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// ImportDID registers a DID created outside the service, so that it can be used like the DIDs the service creates. The
+// given private keys are checked against the verification methods of the document before anything is stored, and are
+// stored with the DID as their controller.
+func (s *Service) ImportDID(ctx context.Context, request ImportDIDRequest) (*ImportDIDResponse, error) {
+	if err := sdkutil.IsValidStruct(request); err != nil {
+		return nil, sdkutil.LoggingErrorMsg(err, "invalid import DID request")
+	}
+	handler, err := s.getHandler(request.Method)
+	if err != nil {
+		return nil, sdkutil.LoggingErrorMsgf(err, "could not get handler for method<%s>", request.Method)
+	}
+	importer, ok := handler.(didImporter)
+	if !ok {
+		return nil, sdkutil.LoggingNewErrorf("importing DIDs is not supported for method<%s>", request.Method)
+	}
+	doc, err := importer.importedDocument(request.DID)
+	if err != nil {
+		return nil, sdkutil.LoggingErrorMsg(err, "invalid DID to import")
+	}
+
+	exists, err := s.storage.DIDExists(ctx, doc.ID)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, sdkutil.LoggingNewErrorf("DID<%s> already exists", doc.ID)
+	}
+	storeKeyRequests, err := importKeys(*doc, request.Keys)
+	if err != nil {
+		return nil, sdkutil.LoggingErrorMsgf(err, "invalid keys for DID<%s>", doc.ID)
+	}
+	for _, storeKeyRequest := range storeKeyRequests {
+		keyExists, err := s.keyStore.KeyExists(ctx, storeKeyRequest.ID)
+		if err != nil {
+			return nil, sdkutil.LoggingErrorMsgf(err, "checking whether key exists: %s", storeKeyRequest.ID)
+		}
+		if keyExists {
+			return nil, sdkutil.LoggingNewErrorf("key<%s> already exists", storeKeyRequest.ID)
+		}
+	}
+
+	// the keys and the DID are stored together, so that a failed import leaves no keys behind
+	storeKeys := func(ctx context.Context, tx storage.Tx) error {
+		for _, storeKeyRequest := range storeKeyRequests {
+			if err := s.keyStore.StoreKeyTx(ctx, tx, storeKeyRequest); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err = s.storage.StoreDIDWith(ctx, DefaultStoredDID{ID: doc.ID, DID: *doc}, storeKeys); err != nil {
+		return nil, sdkutil.LoggingErrorMsgf(err, "storing DID: %s", doc.ID)
+	}
+
+	// the DID may have been resolved before it was imported
+	if err = s.invalidateResolution(ctx, doc.ID); err != nil {
+		return nil, err
+	}
+	return &ImportDIDResponse{DID: *doc}, nil
 }
 
 func (s *Service) GetDIDByMethod(ctx context.Context, request GetDIDRequest) (*GetDIDResponse, error) {
@@ -176,6 +300,32 @@ func (s *Service) GetDIDByMethod(ctx context.Context, request GetDIDRequest) (*G
 	return handler.GetDID(ctx, request)
 }
 
+// SettleOperation settles the operation with the given ID when it was submitted to the ION node for a DID. Other
+// operations are ignored.
+func (s *Service) SettleOperation(ctx context.Context, id string) error {
+	ih, ok := s.handlers[didsdk.IONMethod].(*ionHandler)
+	if !ok {
+		return nil
+	}
+	return ih.SettleOperation(ctx, id)
+}
+
+// GetWebDIDDocument gets the stored document of a did:web DID, so it can be hosted at the URL the DID resolves to.
+// Soft deleted DIDs are reported as deactivated.
+func (s *Service) GetWebDIDDocument(ctx context.Context, request GetWebDIDDocumentRequest) (*GetWebDIDDocumentResponse, error) {
+	if _, err := s.getHandler(didsdk.WebMethod); err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(request.ID, web.WebPrefix+":") {
+		return nil, sdkutil.LoggingNewErrorf("not a did:web DID: %s", request.ID)
+	}
+	gotDID, err := s.storage.GetDIDDefault(ctx, request.ID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting DID: %s", request.ID)
+	}
+	return &GetWebDIDDocumentResponse{DID: gotDID.GetDocument(), Deactivated: gotDID.IsSoftDeleted()}, nil
+}
+
 func (s *Service) GetKeyFromDID(ctx context.Context, request GetKeyFromDIDRequest) (*GetKeyFromDIDResponse, error) {
 	resolved, err := s.Resolve(ctx, request.ID)
 	if err != nil {
@@ -183,7 +333,7 @@ func (s *Service) GetKeyFromDID(ctx context.Context, request GetKeyFromDIDReques
 	}
 
 	// next, get the verification information (key) from the did document
-	pubKey, err := didsdk.GetKeyFromVerificationMethod(resolved.Document, request.KeyID)
+	pubKey, err := didint.KeyFromVerificationMethod(resolved.Document, request.KeyID)
 	if err != nil {
 		return nil, errors.Wrapf(err, "getting verification information from the did document: %s", request.ID)
 	}
@@ -205,12 +355,177 @@ func (s *Service) ListDIDsByMethod(ctx context.Context, request ListDIDsRequest)
 	return handler.ListDIDs(ctx)
 }
 
+func (s *Service) UpdateDIDByMethod(ctx context.Context, request UpdateDIDRequest) (*UpdateDIDResponse, error) {
+	if err := sdkutil.IsValidStruct(request); err != nil {
+		return nil, sdkutil.LoggingErrorMsg(err, "invalid update DID request")
+	}
+	if request.IsEmpty() {
+		return nil, sdkutil.LoggingNewError("update DID request has no changes")
+	}
+	handler, err := s.getHandler(request.Method)
+	if err != nil {
+		return nil, sdkutil.LoggingErrorMsgf(err, "could not get handler for method<%s>", request.Method)
+	}
+	updated, err := handler.UpdateDID(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.invalidateResolution(ctx, request.ID); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// RotateDIDKey adds a new key to a DID with the method's update mechanism, moving the verification relationships of the
+// replaced key to it. The replaced key stays in the document so that what it signed can still be verified, but it's
+// revoked in the keystore so that it can no longer sign. The key is revoked before the DID is updated, so that it can't
+// sign once the new key is in the document, and is reinstated when the update fails.
+func (s *Service) RotateDIDKey(ctx context.Context, request RotateDIDKeyRequest) (*RotateDIDKeyResponse, error) {
+	if err := sdkutil.IsValidStruct(request); err != nil {
+		return nil, sdkutil.LoggingErrorMsg(err, "invalid rotate DID key request")
+	}
+	handler, err := s.getHandler(request.Method)
+	if err != nil {
+		return nil, sdkutil.LoggingErrorMsgf(err, "could not get handler for method<%s>", request.Method)
+	}
+	gotDID, err := handler.GetDID(ctx, GetDIDRequest{Method: request.Method, ID: request.ID})
+	if err != nil {
+		return nil, sdkutil.LoggingErrorMsgf(err, "getting DID: %s", request.ID)
+	}
+	doc := gotDID.DID
+
+	keyID := request.KeyID
+	if keyID == "" {
+		if keyID, err = s.keyStore.GetAssertionKeyID(ctx, doc); err != nil {
+			return nil, sdkutil.LoggingErrorMsgf(err, "getting current key of DID: %s", request.ID)
+		}
+	}
+	i := findVerificationMethod(doc, keyID)
+	if i < 0 {
+		return nil, sdkutil.LoggingNewErrorf("verification method<%s> not found in document", keyID)
+	}
+	keyID = doc.VerificationMethod[i].ID
+
+	purposes := []ion.PublicKeyPurpose{ion.AssertionMethod}
+	for _, purpose := range verificationRelationshipPurposes(doc, keyID) {
+		if purpose != ion.AssertionMethod {
+			purposes = append(purposes, purpose)
+		}
+	}
+	revokedKeyIDs, err := revokeVerificationMethodKey(ctx, s.keyStore, doc.ID, keyID)
+	if err != nil {
+		reinstateKeys(ctx, s.keyStore, revokedKeyIDs)
+		return nil, sdkutil.LoggingErrorMsgf(err, "revoking key of DID: %s", request.ID)
+	}
+	updated, err := handler.UpdateDID(ctx, UpdateDIDRequest{
+		Method:                         request.Method,
+		ID:                             request.ID,
+		VerificationMethodsToAdd:       []VerificationMethodToAdd{{KeyType: request.KeyType, Purposes: purposes}},
+		VerificationRelationshipsToSet: []VerificationRelationships{{ID: keyID}},
+	})
+	if err != nil {
+		reinstateKeys(ctx, s.keyStore, revokedKeyIDs)
+		return nil, sdkutil.LoggingErrorMsgf(err, "adding new key to DID: %s", request.ID)
+	}
+	if err = s.invalidateResolution(ctx, request.ID); err != nil {
+		return nil, err
+	}
+
+	// the new key is the only verification method the update added
+	newKeyID := ""
+	for _, id := range verificationMethodIDs(updated.DID) {
+		if findVerificationMethod(doc, id) < 0 {
+			newKeyID = id
+		}
+	}
+	return &RotateDIDKeyResponse{DID: updated.DID, KeyID: newKeyID, OperationID: updated.OperationID}, nil
+}
+
+// RecoverIONDID replaces the document of a did:ion DID. Anchoring the recovery is tracked by the returned operation.
+func (s *Service) RecoverIONDID(ctx context.Context, request RecoverIONDIDRequest) (*RecoverIONDIDResponse, error) {
+	if err := sdkutil.IsValidStruct(request); err != nil {
+		return nil, sdkutil.LoggingErrorMsg(err, "invalid recover DID request")
+	}
+	handler, err := s.getIONHandler()
+	if err != nil {
+		return nil, err
+	}
+	recovered, err := handler.RecoverDID(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.invalidateResolution(ctx, request.ID); err != nil {
+		return nil, err
+	}
+	return recovered, nil
+}
+
+// DeactivateIONDID permanently deactivates a did:ion DID. Anchoring the deactivation is tracked by the returned
+// operation.
+func (s *Service) DeactivateIONDID(ctx context.Context, request DeactivateIONDIDRequest) (*DeactivateIONDIDResponse, error) {
+	if err := sdkutil.IsValidStruct(request); err != nil {
+		return nil, sdkutil.LoggingErrorMsg(err, "invalid deactivate DID request")
+	}
+	handler, err := s.getIONHandler()
+	if err != nil {
+		return nil, err
+	}
+	deactivated, err := handler.DeactivateDID(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.invalidateResolution(ctx, request.ID); err != nil {
+		return nil, err
+	}
+	return deactivated, nil
+}
+
 func (s *Service) SoftDeleteDIDByMethod(ctx context.Context, request DeleteDIDRequest) error {
 	handler, err := s.getHandler(request.Method)
 	if err != nil {
 		return sdkutil.LoggingErrorMsgf(err, "could not get handler for method<%s>", request.Method)
 	}
-	return handler.SoftDeleteDID(ctx, request)
+	if err = handler.SoftDeleteDID(ctx, request); err != nil {
+		return err
+	}
+	return s.invalidateResolution(ctx, request.ID)
+}
+
+// invalidateResolution removes the cached resolution of a DID of the service that changed.
+func (s *Service) invalidateResolution(ctx context.Context, id string) error {
+	if s.resolutionCache == nil {
+		return nil
+	}
+	if err := s.resolutionCache.Invalidate(ctx, id); err != nil {
+		return sdkutil.LoggingErrorMsgf(err, "invalidating resolution of DID: %s", id)
+	}
+	return nil
+}
+
+func newResolutionCache(cacheConfig config.DIDResolutionCacheConfig, db storage.ServiceStorage, resolver didresolution.Resolver) (*resolution.CachingResolver, error) {
+	var store resolution.CacheStore
+	switch cacheConfig.Store {
+	case "", "memory":
+		store = resolution.NewMemoryCacheStore(cacheConfig.MaxEntries)
+	case "storage":
+		storageStore, err := resolution.NewStorageCacheStore(db)
+		if err != nil {
+			return nil, err
+		}
+		store = storageStore
+	default:
+		return nil, fmt.Errorf("unsupported resolution cache store: %s", cacheConfig.Store)
+	}
+
+	methodTTLs := make(map[didsdk.Method]time.Duration, len(cacheConfig.MethodTTLs))
+	for method, ttl := range cacheConfig.MethodTTLs {
+		methodTTLs[didsdk.Method(method)] = ttl
+	}
+	return resolution.NewCachingResolver(resolver, store, resolution.CacheOptions{
+		TTL:         cacheConfig.TTL,
+		MethodTTLs:  methodTTLs,
+		NotFoundTTL: cacheConfig.NotFoundTTL,
+	})
 }
 
 func (s *Service) getHandler(method didsdk.Method) (MethodHandler, error) {
@@ -220,3 +535,15 @@ func (s *Service) getHandler(method didsdk.Method) (MethodHandler, error) {
 	}
 	return handler, nil
 }
+
+func (s *Service) getIONHandler() (*ionHandler, error) {
+	handler, err := s.getHandler(didsdk.IONMethod)
+	if err != nil {
+		return nil, err
+	}
+	ih, ok := handler.(*ionHandler)
+	if !ok {
+		return nil, sdkutil.LoggingNewErrorf("unexpected handler for DID method: %s", didsdk.IONMethod)
+	}
+	return ih, nil
+}
@@ -2,10 +2,15 @@ package did
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"reflect"
+	"strconv"
+	"time"
 
 	"github.com/TBD54566975/ssi-sdk/did"
+	"github.com/TBD54566975/ssi-sdk/did/resolution"
 	sdkutil "github.com/TBD54566975/ssi-sdk/util"
 	"github.com/goccy/go-json"
 	"github.com/pkg/errors"
@@ -16,17 +21,22 @@ import (
 )
 
 const (
-	namespace    = "did"
-	keyNamespace = "key"
-	webNamespace = "web"
-	ionNamespace = "ion"
+	namespace         = "did"
+	metadataNamespace = "did_metadata"
+	keyNamespace      = "key"
+	webNamespace      = "web"
+	ionNamespace      = "ion"
+	jwkNamespace      = "jwk"
+	peerNamespace     = "peer"
 )
 
 var (
 	didMethodToNamespace = map[string]string{
-		keyNamespace: storage.MakeNamespace(namespace, keyNamespace),
-		webNamespace: storage.MakeNamespace(namespace, webNamespace),
-		ionNamespace: storage.MakeNamespace(namespace, ionNamespace),
+		keyNamespace:  storage.MakeNamespace(namespace, keyNamespace),
+		webNamespace:  storage.MakeNamespace(namespace, webNamespace),
+		ionNamespace:  storage.MakeNamespace(namespace, ionNamespace),
+		jwkNamespace:  storage.MakeNamespace(namespace, jwkNamespace),
+		peerNamespace: storage.MakeNamespace(namespace, peerNamespace),
 	}
 )
 
@@ -57,6 +67,15 @@ func (d DefaultStoredDID) IsSoftDeleted() bool {
 	return d.SoftDeleted
 }
 
+// storedDocumentMetadata tracks the versions of the document of a stored DID. The hash of the document tells whether
+// storing the DID changes its document.
+type storedDocumentMetadata struct {
+	Created      string `json:"created"`
+	Updated      string `json:"updated,omitempty"`
+	VersionID    int    `json:"versionId"`
+	DocumentHash string `json:"documentHash"`
+}
+
 type Storage struct {
 	db storage.ServiceStorage
 }
@@ -70,15 +89,124 @@ func NewDIDStorage(db storage.ServiceStorage) (*Storage, error) {
 
 func (ds *Storage) StoreDID(ctx context.Context, did StoredDID) error {
 	couldNotStoreDIDErr := fmt.Sprintf("could not store DID: %s", did.GetID())
-	ns, err := getNamespaceForDID(did.GetID())
+	namespaces, values, err := ds.didRecords(ctx, did)
 	if err != nil {
 		return sdkutil.LoggingErrorMsg(err, couldNotStoreDIDErr)
 	}
-	didBytes, err := json.Marshal(did)
+	keys := make([]string, len(namespaces))
+	for i := range keys {
+		keys[i] = did.GetID()
+	}
+	return ds.db.WriteMany(ctx, namespaces, keys, values)
+}
+
+// StoreDIDWith stores a DID in the same transaction as the changes store makes, so that either the DID and all the
+// changes are stored, or none of them are.
+func (ds *Storage) StoreDIDWith(ctx context.Context, did StoredDID, store func(context.Context, storage.Tx) error) error {
+	couldNotStoreDIDErr := fmt.Sprintf("could not store DID: %s", did.GetID())
+	namespaces, values, err := ds.didRecords(ctx, did)
 	if err != nil {
 		return sdkutil.LoggingErrorMsg(err, couldNotStoreDIDErr)
 	}
-	return ds.db.Write(ctx, ns, did.GetID(), didBytes)
+	_, err = ds.db.Execute(ctx, func(ctx context.Context, tx storage.Tx) (any, error) {
+		if err := store(ctx, tx); err != nil {
+			return nil, err
+		}
+		for i, ns := range namespaces {
+			if err := tx.Write(ctx, ns, did.GetID(), values[i]); err != nil {
+				return nil, errors.Wrapf(err, "writing DID: %s", did.GetID())
+			}
+		}
+		return nil, nil
+	}, []storage.WatchKey{{Namespace: namespaces[0], Key: did.GetID()}})
+	if err != nil {
+		return sdkutil.LoggingErrorMsg(err, couldNotStoreDIDErr)
+	}
+	return nil
+}
+
+// didRecords returns the namespaces and values to write when storing a DID. A new version of the metadata of its
+// document is written when the document changes.
+func (ds *Storage) didRecords(ctx context.Context, did StoredDID) ([]string, [][]byte, error) {
+	ns, err := getNamespaceForDID(did.GetID())
+	if err != nil {
+		return nil, nil, err
+	}
+	didBytes, err := json.Marshal(did)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	metadata, changed, err := ds.nextDocumentMetadata(ctx, did)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !changed {
+		return []string{ns}, [][]byte{didBytes}, nil
+	}
+	metadataBytes, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, nil, err
+	}
+	return []string{ns, metadataNamespace}, [][]byte{didBytes, metadataBytes}, nil
+}
+
+// nextDocumentMetadata returns the metadata of the document of the DID once stored, and whether storing it changes the
+// document.
+func (ds *Storage) nextDocumentMetadata(ctx context.Context, did StoredDID) (*storedDocumentMetadata, bool, error) {
+	docBytes, err := json.Marshal(did.GetDocument())
+	if err != nil {
+		return nil, false, errors.Wrap(err, "marshalling document")
+	}
+	docHash := sha256.Sum256(docBytes)
+	documentHash := hex.EncodeToString(docHash[:])
+
+	metadata, err := ds.getDocumentMetadata(ctx, did.GetID())
+	if err != nil {
+		return nil, false, err
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+	if metadata == nil {
+		return &storedDocumentMetadata{Created: now, VersionID: 1, DocumentHash: documentHash}, true, nil
+	}
+	if metadata.DocumentHash == documentHash {
+		return metadata, false, nil
+	}
+	metadata.Updated = now
+	metadata.VersionID++
+	metadata.DocumentHash = documentHash
+	return metadata, true, nil
+}
+
+// GetDocumentMetadata returns the metadata of the document of a stored DID. Soft deleted DIDs are deactivated. DIDs
+// stored before their versions were tracked have no creation or update times, nor version.
+func (ds *Storage) GetDocumentMetadata(ctx context.Context, did StoredDID) (*resolution.DocumentMetadata, error) {
+	metadata, err := ds.getDocumentMetadata(ctx, did.GetID())
+	if err != nil {
+		return nil, sdkutil.LoggingErrorMsgf(err, "could not get metadata of DID: %s", did.GetID())
+	}
+	documentMetadata := resolution.DocumentMetadata{Deactivated: did.IsSoftDeleted()}
+	if metadata != nil {
+		documentMetadata.Created = metadata.Created
+		documentMetadata.Updated = metadata.Updated
+		documentMetadata.VersionID = strconv.Itoa(metadata.VersionID)
+	}
+	return &documentMetadata, nil
+}
+
+func (ds *Storage) getDocumentMetadata(ctx context.Context, id string) (*storedDocumentMetadata, error) {
+	metadataBytes, err := ds.db.Read(ctx, metadataNamespace, id)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading metadata of DID: %s", id)
+	}
+	if len(metadataBytes) == 0 {
+		return nil, nil
+	}
+	var metadata storedDocumentMetadata
+	if err = json.Unmarshal(metadataBytes, &metadata); err != nil {
+		return nil, errors.Wrapf(err, "unmarshalling metadata of DID: %s", id)
+	}
+	return &metadata, nil
 }
 
 // GetDID attempts to get a DID from the database. It will return an error if it cannot.
@@ -115,6 +243,15 @@ func (ds *Storage) GetDIDDefault(ctx context.Context, id string) (*DefaultStored
 	return outType, nil
 }
 
+// DIDExists returns whether a DID is stored, whether it was soft deleted or not.
+func (ds *Storage) DIDExists(ctx context.Context, id string) (bool, error) {
+	ns, err := getNamespaceForDID(id)
+	if err != nil {
+		return false, sdkutil.LoggingErrorMsgf(err, "could not check whether DID exists: %s", id)
+	}
+	return ds.db.Exists(ctx, ns, id)
+}
+
 // ListDIDs attempts to get all DIDs for a given method. It will return those it can even if it has trouble with some.
 // The out parameter must be a pointer to a struct for a type that implement the StoredDID interface.
 // The result is a slice of the type of the out parameter (an array of pointers to the type of the out parameter).)
@@ -167,6 +304,15 @@ func (ds *Storage) DeleteDID(ctx context.Context, id string) error {
 	if err = ds.db.Delete(ctx, ns, id); err != nil {
 		return sdkutil.LoggingErrorMsgf(err, "could not delete DID: %s", id)
 	}
+	hasMetadata, err := ds.db.Exists(ctx, metadataNamespace, id)
+	if err != nil {
+		return sdkutil.LoggingErrorMsgf(err, "could not delete metadata of DID: %s", id)
+	}
+	if hasMetadata {
+		if err = ds.db.Delete(ctx, metadataNamespace, id); err != nil {
+			return sdkutil.LoggingErrorMsgf(err, "could not delete metadata of DID: %s", id)
+		}
+	}
 	return nil
 }
 
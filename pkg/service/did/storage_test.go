@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	didsdk "github.com/TBD54566975/ssi-sdk/did"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -207,6 +208,88 @@ func TestStorage(t *testing.T) {
 		assert.Len(tt, gotDIDs, 1)
 		assert.Contains(tt, gotDIDs, toStore2)
 	})
+
+	t.Run("Document metadata tracks versions", func(tt *testing.T) {
+		ds, err := NewDIDStorage(setupTestDB(tt))
+		assert.NoError(tt, err)
+		assert.NotEmpty(tt, ds)
+
+		toStore := DefaultStoredDID{
+			ID:  "did:web:example.com",
+			DID: didsdk.Document{ID: "did:web:example.com"},
+		}
+		require.NoError(tt, ds.StoreDID(context.Background(), toStore))
+		metadata, err := ds.GetDocumentMetadata(context.Background(), toStore)
+		require.NoError(tt, err)
+		assert.NotEmpty(tt, metadata.Created)
+		assert.Empty(tt, metadata.Updated)
+		assert.Equal(tt, "1", metadata.VersionID)
+		assert.False(tt, metadata.Deactivated)
+
+		// storing the same document is not a new version
+		require.NoError(tt, ds.StoreDID(context.Background(), toStore))
+		metadata, err = ds.GetDocumentMetadata(context.Background(), toStore)
+		require.NoError(tt, err)
+		assert.Equal(tt, "1", metadata.VersionID)
+
+		toStore.DID.Services = []didsdk.Service{{ID: "#linked-domain", Type: "LinkedDomains", ServiceEndpoint: "https://example.com"}}
+		require.NoError(tt, ds.StoreDID(context.Background(), toStore))
+		updated, err := ds.GetDocumentMetadata(context.Background(), toStore)
+		require.NoError(tt, err)
+		assert.Equal(tt, metadata.Created, updated.Created)
+		assert.NotEmpty(tt, updated.Updated)
+		assert.Equal(tt, "2", updated.VersionID)
+
+		// soft deleted DIDs are deactivated
+		toStore.SoftDeleted = true
+		require.NoError(tt, ds.StoreDID(context.Background(), toStore))
+		metadata, err = ds.GetDocumentMetadata(context.Background(), toStore)
+		require.NoError(tt, err)
+		assert.True(tt, metadata.Deactivated)
+		assert.Equal(tt, "2", metadata.VersionID)
+	})
+
+	t.Run("Store DID with other changes", func(tt *testing.T) {
+		db := setupTestDB(tt)
+		ds, err := NewDIDStorage(db)
+		assert.NoError(tt, err)
+		assert.NotEmpty(tt, ds)
+
+		toStore := DefaultStoredDID{
+			ID:  "did:web:example.com",
+			DID: didsdk.Document{ID: "did:web:example.com"},
+		}
+
+		// nothing is stored when the other changes fail
+		err = ds.StoreDIDWith(context.Background(), toStore, func(ctx context.Context, tx storage.Tx) error {
+			if err := tx.Write(ctx, "other", "key", []byte("value")); err != nil {
+				return err
+			}
+			return errors.New("bad change")
+		})
+		assert.Error(tt, err)
+		assert.Contains(tt, err.Error(), "bad change")
+		exists, err := ds.DIDExists(context.Background(), toStore.ID)
+		require.NoError(tt, err)
+		assert.False(tt, exists)
+		exists, err = db.Exists(context.Background(), "other", "key")
+		require.NoError(tt, err)
+		assert.False(tt, exists)
+
+		err = ds.StoreDIDWith(context.Background(), toStore, func(ctx context.Context, tx storage.Tx) error {
+			return tx.Write(ctx, "other", "key", []byte("value"))
+		})
+		require.NoError(tt, err)
+		gotDID, err := ds.GetDIDDefault(context.Background(), toStore.ID)
+		require.NoError(tt, err)
+		assert.Equal(tt, toStore, *gotDID)
+		exists, err = db.Exists(context.Background(), "other", "key")
+		require.NoError(tt, err)
+		assert.True(tt, exists)
+		metadata, err := ds.GetDocumentMetadata(context.Background(), toStore)
+		require.NoError(tt, err)
+		assert.Equal(tt, "1", metadata.VersionID)
+	})
 }
 
 func setupTestDB(t *testing.T) storage.ServiceStorage {
@@ -0,0 +1,294 @@
+package did
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/TBD54566975/ssi-sdk/did"
+	"github.com/TBD54566975/ssi-sdk/did/ion"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/tbd54566975/ssi-service/pkg/service/keystore"
+)
+
+// addedVerificationMethod is a verification method generated for an update, along with its verification relationships.
+type addedVerificationMethod struct {
+	verificationMethod did.VerificationMethod
+	purposes           []ion.PublicKeyPurpose
+}
+
+// applyUpdate returns a copy of the document with the update applied. The verification methods to add are generated
+// by the handler, since the ids and keys of verification methods depend on the method.
+func applyUpdate(doc did.Document, request UpdateDIDRequest, added []addedVerificationMethod) (*did.Document, error) {
+	updated := doc
+	updated.VerificationMethod = append([]did.VerificationMethod(nil), doc.VerificationMethod...)
+	updated.Services = append([]did.Service(nil), doc.Services...)
+	for _, relationship := range verificationRelationships(&updated) {
+		*relationship = append([]did.VerificationMethodSet(nil), *relationship...)
+	}
+
+	for _, id := range request.ServiceIDsToRemove {
+		i := findService(updated, id)
+		if i < 0 {
+			return nil, fmt.Errorf("service<%s> not found in document", id)
+		}
+		updated.Services = append(updated.Services[:i], updated.Services[i+1:]...)
+	}
+	for _, id := range request.VerificationMethodIDsToRemove {
+		i := findVerificationMethod(updated, id)
+		if i < 0 {
+			return nil, fmt.Errorf("verification method<%s> not found in document", id)
+		}
+		removeVerificationRelationships(&updated, updated.VerificationMethod[i].ID)
+		updated.VerificationMethod = append(updated.VerificationMethod[:i], updated.VerificationMethod[i+1:]...)
+	}
+
+	for _, service := range request.ServicesToAdd {
+		if findService(updated, service.ID) >= 0 {
+			return nil, fmt.Errorf("service<%s> already in document", service.ID)
+		}
+		updated.Services = append(updated.Services, service)
+	}
+	for _, a := range added {
+		if findVerificationMethod(updated, a.verificationMethod.ID) >= 0 {
+			return nil, fmt.Errorf("verification method<%s> already in document", a.verificationMethod.ID)
+		}
+		updated.VerificationMethod = append(updated.VerificationMethod, a.verificationMethod)
+		addVerificationRelationships(&updated, a.verificationMethod.ID, a.purposes)
+	}
+
+	for _, relationships := range request.VerificationRelationshipsToSet {
+		i := findVerificationMethod(updated, relationships.ID)
+		if i < 0 {
+			return nil, fmt.Errorf("verification method<%s> not found in document", relationships.ID)
+		}
+		id := updated.VerificationMethod[i].ID
+		removeVerificationRelationships(&updated, id)
+		addVerificationRelationships(&updated, id, relationships.Purposes)
+	}
+
+	// removing all entries leaves them unset, as they are in a document that never had any
+	if len(updated.VerificationMethod) == 0 {
+		updated.VerificationMethod = nil
+	}
+	if len(updated.Services) == 0 {
+		updated.Services = nil
+	}
+	return &updated, nil
+}
+
+// revokeRemovedKeys revokes the stored keys of the verification methods removed from the document, which may no
+// longer be used to sign on behalf of the DID.
+func revokeRemovedKeys(ctx context.Context, keyStore *keystore.Service, previousDoc did.Document, removedIDs []string) error {
+	for _, id := range removedIDs {
+		i := findVerificationMethod(previousDoc, id)
+		if i < 0 {
+			continue
+		}
+		if _, err := revokeVerificationMethodKey(ctx, keyStore, previousDoc.ID, previousDoc.VerificationMethod[i].ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// revokeVerificationMethodKey revokes the stored key of a verification method, if any. Keys are stored under the id
+// of their verification method as written in the document, or under its absolute id for imported DIDs. Since relative
+// ids aren't unique across DIDs, keys stored under one are only revoked when the DID controls them. The ids of the
+// keys it revoked are returned, even on error, leaving out keys that were already revoked.
+func revokeVerificationMethodKey(ctx context.Context, keyStore *keystore.Service, docID, vmID string) ([]string, error) {
+	keyIDs := []string{vmID}
+	if absolute := absoluteID(docID, vmID); absolute != vmID {
+		keyIDs = append(keyIDs, absolute)
+	}
+	var revoked []string
+	for _, keyID := range keyIDs {
+		exists, err := keyStore.KeyExists(ctx, keyID)
+		if err != nil {
+			return revoked, errors.Wrapf(err, "checking whether key exists: %s", keyID)
+		}
+		if !exists {
+			continue
+		}
+		details, err := keyStore.GetKeyDetails(ctx, keystore.GetKeyDetailsRequest{ID: keyID})
+		if err != nil {
+			return revoked, errors.Wrapf(err, "getting key: %s", keyID)
+		}
+		if details.Revoked || (strings.HasPrefix(keyID, "#") && details.Controller != docID) {
+			continue
+		}
+		if err = keyStore.RevokeKey(ctx, keystore.RevokeKeyRequest{ID: keyID}); err != nil {
+			return revoked, errors.Wrapf(err, "revoking key: %s", keyID)
+		}
+		revoked = append(revoked, keyID)
+	}
+	return revoked, nil
+}
+
+// reinstateKeys undoes the revocation of the keys, logging failures since it's only done to roll back a failed
+// change.
+func reinstateKeys(ctx context.Context, keyStore *keystore.Service, keyIDs []string) {
+	for _, keyID := range keyIDs {
+		if err := keyStore.ReinstateKey(ctx, keystore.ReinstateKeyRequest{ID: keyID}); err != nil {
+			logrus.WithError(err).Errorf("could not reinstate key: %s", keyID)
+		}
+	}
+}
+
+// verificationMethodIDs returns the ids of all verification methods of the document.
+func verificationMethodIDs(doc did.Document) []string {
+	ids := make([]string, 0, len(doc.VerificationMethod))
+	for _, vm := range doc.VerificationMethod {
+		ids = append(ids, vm.ID)
+	}
+	return ids
+}
+
+// findVerificationMethod returns the index of the verification method with the id in the document, or -1. The id may
+// be the full id of the verification method, or its fragment.
+func findVerificationMethod(doc did.Document, id string) int {
+	for i, vm := range doc.VerificationMethod {
+		if matchesDocumentID(doc.ID, vm.ID, id) {
+			return i
+		}
+	}
+	return -1
+}
+
+// findService returns the index of the service with the id in the document, or -1. The id may be the full id of the
+// service, or its fragment.
+func findService(doc did.Document, id string) int {
+	for i, service := range doc.Services {
+		if matchesDocumentID(doc.ID, service.ID, id) {
+			return i
+		}
+	}
+	return -1
+}
+
+func matchesDocumentID(docID, entryID, id string) bool {
+	return entryID == id || strings.TrimPrefix(entryID, docID) == "#"+strings.TrimPrefix(id, "#")
+}
+
+func verificationRelationships(doc *did.Document) map[ion.PublicKeyPurpose]*[]did.VerificationMethodSet {
+	return map[ion.PublicKeyPurpose]*[]did.VerificationMethodSet{
+		ion.Authentication:       &doc.Authentication,
+		ion.AssertionMethod:      &doc.AssertionMethod,
+		ion.KeyAgreement:         &doc.KeyAgreement,
+		ion.CapabilityInvocation: &doc.CapabilityInvocation,
+		ion.CapabilityDelegation: &doc.CapabilityDelegation,
+	}
+}
+
+func addVerificationRelationships(doc *did.Document, id string, purposes []ion.PublicKeyPurpose) {
+	relationships := verificationRelationships(doc)
+	for _, purpose := range purposes {
+		relationship := relationships[purpose]
+		*relationship = append(*relationship, id)
+	}
+}
+
+// verificationRelationshipPurposes returns the verification relationships of the document referencing the verification
+// method with the id.
+func verificationRelationshipPurposes(doc did.Document, id string) []ion.PublicKeyPurpose {
+	var purposes []ion.PublicKeyPurpose
+	for _, purpose := range []ion.PublicKeyPurpose{
+		ion.Authentication, ion.AssertionMethod, ion.KeyAgreement, ion.CapabilityInvocation, ion.CapabilityDelegation,
+	} {
+		for _, entry := range *verificationRelationships(&doc)[purpose] {
+			if referencesVerificationMethod(entry, id) {
+				purposes = append(purposes, purpose)
+				break
+			}
+		}
+	}
+	return purposes
+}
+
+// referencesVerificationMethod is whether the entry of a verification relationship references the verification method
+// with the id.
+func referencesVerificationMethod(entry did.VerificationMethodSet, id string) bool {
+	switch e := entry.(type) {
+	case string:
+		return e == id
+	case did.VerificationMethod:
+		return e.ID == id
+	case map[string]any:
+		return e["id"] == id
+	case []string:
+		for _, ref := range e {
+			if ref == id {
+				return true
+			}
+		}
+	case []any:
+		for _, item := range e {
+			if referencesVerificationMethod(item, id) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// removeVerificationRelationships removes the references to the verification method with the id from all verification
+// relationships of the document.
+func removeVerificationRelationships(doc *did.Document, id string) {
+	for _, relationship := range verificationRelationships(doc) {
+		kept := make([]did.VerificationMethodSet, 0, len(*relationship))
+		for _, entry := range *relationship {
+			if entry = withoutVerificationMethod(entry, id); entry != nil {
+				kept = append(kept, entry)
+			}
+		}
+		if len(kept) == 0 {
+			kept = nil
+		}
+		*relationship = kept
+	}
+}
+
+// withoutVerificationMethod returns the entry of a verification relationship without references to the verification
+// method with the id, or nil if nothing is left. Entries are references, embedded verification methods, or lists of
+// either.
+func withoutVerificationMethod(entry did.VerificationMethodSet, id string) did.VerificationMethodSet {
+	switch e := entry.(type) {
+	case string:
+		if e == id {
+			return nil
+		}
+	case did.VerificationMethod:
+		if e.ID == id {
+			return nil
+		}
+	case map[string]any:
+		if e["id"] == id {
+			return nil
+		}
+	case []string:
+		kept := make([]string, 0, len(e))
+		for _, ref := range e {
+			if ref != id {
+				kept = append(kept, ref)
+			}
+		}
+		if len(kept) == 0 {
+			return nil
+		}
+		return kept
+	case []any:
+		kept := make([]any, 0, len(e))
+		for _, item := range e {
+			if item = withoutVerificationMethod(item, id); item != nil {
+				kept = append(kept, item)
+			}
+		}
+		if len(kept) == 0 {
+			return nil
+		}
+		return kept
+	}
+	return entry
+}
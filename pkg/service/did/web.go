@@ -3,11 +3,13 @@ package did
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/TBD54566975/ssi-sdk/crypto"
 	"github.com/TBD54566975/ssi-sdk/did"
 	"github.com/TBD54566975/ssi-sdk/did/web"
 	"github.com/TBD54566975/ssi-sdk/util"
+	"github.com/google/uuid"
 	"github.com/mr-tron/base58"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
@@ -61,8 +63,9 @@ func (h *webHandler) CreateDID(ctx context.Context, request CreateDIDRequest) (*
 
 	didWeb := web.DIDWeb(opts.DIDWebID)
 
-	if !didWeb.IsValid() {
-		return nil, fmt.Errorf("could not resolve did:web DID: %s", didWeb)
+	// the document is hosted by the service once stored, so the DID only needs to be well-formed
+	if _, err := didWeb.GetDocURL(); err != nil {
+		return nil, errors.Wrapf(err, "invalid did:web DID: %s", didWeb)
 	}
 
 	pubKey, privKey, err := crypto.GenerateKeyByKeyType(request.KeyType)
@@ -112,6 +115,20 @@ func (h *webHandler) CreateDID(ctx context.Context, request CreateDIDRequest) (*
 	return &CreateDIDResponse{DID: storedDID.DID}, nil
 }
 
+// importedDocument returns the document the domain of the did:web DID serves, which is hosted and resolved by the
+// service once stored, so keys are checked against it rather than the document given. Importing a DID therefore
+// requires control of its domain, and can't shadow the document the domain serves with another one.
+func (h *webHandler) importedDocument(doc did.Document) (*did.Document, error) {
+	if !strings.HasPrefix(doc.ID, web.WebPrefix+":") {
+		return nil, fmt.Errorf("not a did:web DID: %s", doc.ID)
+	}
+	served, err := web.DIDWeb(doc.ID).Resolve()
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting the document served for did:web DID: %s", doc.ID)
+	}
+	return served, nil
+}
+
 func (h *webHandler) GetDID(ctx context.Context, request GetDIDRequest) (*GetDIDResponse, error) {
 	logrus.Debugf("getting DID: %+v", request)
 
@@ -123,7 +140,11 @@ func (h *webHandler) GetDID(ctx context.Context, request GetDIDRequest) (*GetDID
 	if gotDID == nil {
 		return nil, fmt.Errorf("did with id<%s> could not be found", id)
 	}
-	return &GetDIDResponse{DID: gotDID.GetDocument()}, nil
+	metadata, err := h.storage.GetDocumentMetadata(ctx, gotDID)
+	if err != nil {
+		return nil, err
+	}
+	return &GetDIDResponse{DID: gotDID.GetDocument(), DocumentMetadata: metadata}, nil
 }
 
 func (h *webHandler) ListDIDs(ctx context.Context) (*ListDIDsResponse, error) {
@@ -158,6 +179,79 @@ func (h *webHandler) ListDeletedDIDs(ctx context.Context) (*ListDIDsResponse, er
 	return &ListDIDsResponse{DIDs: dids}, nil
 }
 
+// UpdateDID applies the update to the stored document, which is published as soon as it's stored since the service
+// hosts did:web documents. Keys of added verification methods are stored, and keys of removed ones are revoked.
+func (h *webHandler) UpdateDID(ctx context.Context, request UpdateDIDRequest) (*UpdateDIDResponse, error) {
+	logrus.Debugf("updating DID: %+v", request)
+
+	id := request.ID
+	gotStoredDID, err := h.storage.GetDIDDefault(ctx, id)
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting DID: %s", id)
+	}
+	if gotStoredDID.IsSoftDeleted() {
+		return nil, fmt.Errorf("did with id<%s> has been deleted", id)
+	}
+
+	added := make([]addedVerificationMethod, 0, len(request.VerificationMethodsToAdd))
+	keyStoreRequests := make([]keystore.StoreKeyRequest, 0, len(request.VerificationMethodsToAdd))
+	for _, toAdd := range request.VerificationMethodsToAdd {
+		fragment := strings.TrimPrefix(toAdd.ID, "#")
+		if fragment == "" {
+			fragment = uuid.NewString()
+		}
+		pubKey, privKey, err := crypto.GenerateKeyByKeyType(toAdd.KeyType)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not generate key for did:web")
+		}
+		pubKeyBytes, err := crypto.PubKeyToBytes(pubKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not convert public key to byte")
+		}
+		ldKeyType, err := did.KeyTypeToLDKeyType(toAdd.KeyType)
+		if err != nil {
+			return nil, errors.Wrap(err, "converting key type to LD key type")
+		}
+		verificationMethod, err := did.ConstructJWKVerificationMethod(id, id+"#"+fragment, pubKeyBytes, ldKeyType, toAdd.KeyType)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not construct verification method")
+		}
+		added = append(added, addedVerificationMethod{verificationMethod: *verificationMethod, purposes: toAdd.Purposes})
+
+		privKeyBytes, err := crypto.PrivKeyToBytes(privKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not encode private key as base58")
+		}
+		keyStoreRequests = append(keyStoreRequests, keystore.StoreKeyRequest{
+			ID:               verificationMethod.ID,
+			Type:             toAdd.KeyType,
+			Controller:       id,
+			PrivateKeyBase58: base58.Encode(privKeyBytes),
+		})
+	}
+
+	updatedDoc, err := applyUpdate(gotStoredDID.DID, request, added)
+	if err != nil {
+		return nil, errors.Wrapf(err, "updating DID: %s", id)
+	}
+
+	// revoke removed keys before storing new ones, which may replace a removed key with the same id, and store the
+	// keys before the document, so that the document never references keys we don't have
+	if err = revokeRemovedKeys(ctx, h.keyStore, gotStoredDID.DID, request.VerificationMethodIDsToRemove); err != nil {
+		return nil, errors.Wrapf(err, "revoking keys of DID: %s", id)
+	}
+	for _, keyStoreRequest := range keyStoreRequests {
+		if err = h.keyStore.StoreKey(ctx, keyStoreRequest); err != nil {
+			return nil, errors.Wrap(err, "could not store did:web private key")
+		}
+	}
+	gotStoredDID.DID = *updatedDoc
+	if err = h.storage.StoreDID(ctx, *gotStoredDID); err != nil {
+		return nil, errors.Wrap(err, "could not store did:web value")
+	}
+	return &UpdateDIDResponse{DID: *updatedDoc}, nil
+}
+
 func (h *webHandler) SoftDeleteDID(ctx context.Context, request DeleteDIDRequest) error {
 	logrus.Debugf("soft deleting DID: %+v", request)
 
@@ -0,0 +1,208 @@
+package did
+
+import (
+	"context"
+	"testing"
+
+	"github.com/TBD54566975/ssi-sdk/crypto"
+	"github.com/TBD54566975/ssi-sdk/did"
+	"github.com/TBD54566975/ssi-sdk/did/ion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tbd54566975/ssi-service/pkg/service/keystore"
+)
+
+func TestWebHandlerUpdateDID(t *testing.T) {
+	t.Run("Test Add Key And Service And Change Relationships", func(tt *testing.T) {
+		handler, keystoreService := testWebHandler(tt)
+		created := createTestWebDID(tt, handler, "did:web:example.com")
+		ownerKeyID := created.VerificationMethod[0].ID
+
+		updated, err := handler.UpdateDID(context.Background(), UpdateDIDRequest{
+			Method: did.WebMethod,
+			ID:     created.ID,
+			VerificationMethodsToAdd: []VerificationMethodToAdd{
+				{ID: "key-agreement", KeyType: crypto.X25519, Purposes: []ion.PublicKeyPurpose{ion.KeyAgreement}},
+			},
+			VerificationRelationshipsToSet: []VerificationRelationships{
+				{ID: "owner", Purposes: []ion.PublicKeyPurpose{ion.AssertionMethod, ion.CapabilityInvocation}},
+			},
+			ServicesToAdd: []did.Service{
+				{ID: "#linked-domain", Type: "LinkedDomains", ServiceEndpoint: "https://example.com"},
+			},
+		})
+		require.NoError(tt, err)
+
+		keyAgreementID := created.ID + "#key-agreement"
+		require.Len(tt, updated.DID.VerificationMethod, 2)
+		assert.Equal(tt, keyAgreementID, updated.DID.VerificationMethod[1].ID)
+		assert.Equal(tt, created.ID, updated.DID.VerificationMethod[1].Controller)
+		assert.Equal(tt, []did.VerificationMethodSet{keyAgreementID}, updated.DID.KeyAgreement)
+		assert.Empty(tt, updated.DID.Authentication)
+		assert.Equal(tt, []did.VerificationMethodSet{ownerKeyID}, updated.DID.AssertionMethod)
+		assert.Equal(tt, []did.VerificationMethodSet{ownerKeyID}, updated.DID.CapabilityInvocation)
+		require.Len(tt, updated.DID.Services, 1)
+		assert.Equal(tt, "LinkedDomains", updated.DID.Services[0].Type)
+
+		gotKey, err := keystoreService.GetKey(context.Background(), keystore.GetKeyRequest{ID: keyAgreementID})
+		require.NoError(tt, err)
+		assert.Equal(tt, crypto.X25519, gotKey.Type)
+		assert.Equal(tt, created.ID, gotKey.Controller)
+
+		// the update is stored
+		gotDID, err := handler.GetDID(context.Background(), GetDIDRequest{Method: did.WebMethod, ID: created.ID})
+		require.NoError(tt, err)
+		assert.Equal(tt, updated.DID, gotDID.DID)
+	})
+
+	t.Run("Test Rotate Key", func(tt *testing.T) {
+		handler, keystoreService := testWebHandler(tt)
+		created := createTestWebDID(tt, handler, "did:web:example.com:users:alice")
+		ownerKeyID := created.VerificationMethod[0].ID
+
+		updated, err := handler.UpdateDID(context.Background(), UpdateDIDRequest{
+			Method:                        did.WebMethod,
+			ID:                            created.ID,
+			VerificationMethodIDsToRemove: []string{ownerKeyID},
+			VerificationMethodsToAdd: []VerificationMethodToAdd{
+				{KeyType: crypto.Ed25519, Purposes: []ion.PublicKeyPurpose{ion.Authentication, ion.AssertionMethod}},
+			},
+		})
+		require.NoError(tt, err)
+		require.Len(tt, updated.DID.VerificationMethod, 1)
+		newKeyID := updated.DID.VerificationMethod[0].ID
+		assert.NotEqual(tt, ownerKeyID, newKeyID)
+		assert.Equal(tt, []did.VerificationMethodSet{newKeyID}, updated.DID.Authentication)
+		assert.Equal(tt, []did.VerificationMethodSet{newKeyID}, updated.DID.AssertionMethod)
+
+		// the replaced key is revoked
+		gotKey, err := keystoreService.GetKey(context.Background(), keystore.GetKeyRequest{ID: ownerKeyID})
+		require.NoError(tt, err)
+		assert.True(tt, gotKey.Revoked)
+		gotKey, err = keystoreService.GetKey(context.Background(), keystore.GetKeyRequest{ID: newKeyID})
+		require.NoError(tt, err)
+		assert.False(tt, gotKey.Revoked)
+	})
+
+	t.Run("Test Replace Key With Same ID", func(tt *testing.T) {
+		handler, keystoreService := testWebHandler(tt)
+		created := createTestWebDID(tt, handler, "did:web:example.com")
+		ownerKeyID := created.VerificationMethod[0].ID
+		ownerKey, err := keystoreService.GetKey(context.Background(), keystore.GetKeyRequest{ID: ownerKeyID})
+		require.NoError(tt, err)
+
+		updated, err := handler.UpdateDID(context.Background(), UpdateDIDRequest{
+			Method:                        did.WebMethod,
+			ID:                            created.ID,
+			VerificationMethodIDsToRemove: []string{"owner"},
+			VerificationMethodsToAdd: []VerificationMethodToAdd{
+				{ID: "owner", KeyType: crypto.Ed25519, Purposes: []ion.PublicKeyPurpose{ion.Authentication}},
+			},
+		})
+		require.NoError(tt, err)
+		require.Len(tt, updated.DID.VerificationMethod, 1)
+		assert.Equal(tt, ownerKeyID, updated.DID.VerificationMethod[0].ID)
+
+		// the replacement key is usable
+		gotKey, err := keystoreService.GetKey(context.Background(), keystore.GetKeyRequest{ID: ownerKeyID})
+		require.NoError(tt, err)
+		assert.False(tt, gotKey.Revoked)
+		assert.NotEqual(tt, ownerKey.Key, gotKey.Key)
+	})
+
+	t.Run("Test Invalid Updates", func(tt *testing.T) {
+		handler, _ := testWebHandler(tt)
+		created := createTestWebDID(tt, handler, "did:web:example.com")
+
+		testCases := []struct {
+			name      string
+			request   UpdateDIDRequest
+			wantError string
+		}{
+			{
+				name:      "unknown DID",
+				request:   UpdateDIDRequest{ID: "did:web:unknown.com", ServiceIDsToRemove: []string{"service"}},
+				wantError: "getting DID: did:web:unknown.com",
+			},
+			{
+				name:      "unknown service",
+				request:   UpdateDIDRequest{ID: created.ID, ServiceIDsToRemove: []string{"service"}},
+				wantError: "service<service> not found in document",
+			},
+			{
+				name:      "unknown verification method",
+				request:   UpdateDIDRequest{ID: created.ID, VerificationMethodIDsToRemove: []string{"unknown"}},
+				wantError: "verification method<unknown> not found in document",
+			},
+			{
+				name: "existing verification method",
+				request: UpdateDIDRequest{ID: created.ID, VerificationMethodsToAdd: []VerificationMethodToAdd{
+					{ID: "owner", KeyType: crypto.Ed25519, Purposes: []ion.PublicKeyPurpose{ion.Authentication}},
+				}},
+				wantError: "already in document",
+			},
+		}
+		for _, tc := range testCases {
+			tt.Run(tc.name, func(ttt *testing.T) {
+				tc.request.Method = did.WebMethod
+				_, err := handler.UpdateDID(context.Background(), tc.request)
+				assert.ErrorContains(ttt, err, tc.wantError)
+			})
+		}
+
+		// nothing was changed
+		gotDID, err := handler.GetDID(context.Background(), GetDIDRequest{Method: did.WebMethod, ID: created.ID})
+		require.NoError(tt, err)
+		assert.Equal(tt, created.VerificationMethod, gotDID.DID.VerificationMethod)
+		assert.Len(tt, gotDID.DID.Authentication, 1)
+		assert.Empty(tt, gotDID.DID.Services)
+
+		// deleted DIDs can't be updated
+		require.NoError(tt, handler.SoftDeleteDID(context.Background(), DeleteDIDRequest{Method: did.WebMethod, ID: created.ID}))
+		_, err = handler.UpdateDID(context.Background(), UpdateDIDRequest{
+			Method:        did.WebMethod,
+			ID:            created.ID,
+			ServicesToAdd: []did.Service{{ID: "#service", Type: "LinkedDomains", ServiceEndpoint: "https://example.com"}},
+		})
+		assert.ErrorContains(tt, err, "has been deleted")
+	})
+
+	t.Run("Test Methods Without Updates", func(tt *testing.T) {
+		s := setupTestDB(tt)
+		keystoreService := testKeyStoreService(tt, s)
+		didStorage, err := NewDIDStorage(s)
+		require.NoError(tt, err)
+		keyHandler, err := NewKeyHandler(didStorage, keystoreService)
+		require.NoError(tt, err)
+		jwkHandler, err := NewJWKHandler(didStorage, keystoreService)
+		require.NoError(tt, err)
+		peerHandler, err := NewPeerHandler(didStorage, keystoreService)
+		require.NoError(tt, err)
+
+		for _, handler := range []MethodHandler{keyHandler, jwkHandler, peerHandler} {
+			_, err = handler.UpdateDID(context.Background(), UpdateDIDRequest{Method: handler.GetMethod(), ID: "did:example:123"})
+			assert.ErrorContains(tt, err, "updates are not supported for method<"+handler.GetMethod().String()+">")
+		}
+	})
+}
+
+func testWebHandler(t *testing.T) (MethodHandler, *keystore.Service) {
+	s := setupTestDB(t)
+	keystoreService := testKeyStoreService(t, s)
+	didStorage, err := NewDIDStorage(s)
+	require.NoError(t, err)
+	handler, err := NewWebHandler(didStorage, keystoreService)
+	require.NoError(t, err)
+	return handler, keystoreService
+}
+
+func createTestWebDID(t *testing.T, handler MethodHandler, id string) did.Document {
+	created, err := handler.CreateDID(context.Background(), CreateDIDRequest{
+		Method:  did.WebMethod,
+		KeyType: crypto.Ed25519,
+		Options: CreateWebDIDOptions{DIDWebID: id},
+	})
+	require.NoError(t, err)
+	return created.DID
+}
@@ -17,6 +17,8 @@ const (
 	Presentation Type = "presentation"
 	Operation    Type = "operation"
 	Webhook      Type = "webhook"
+	Trust        Type = "trust"
+	Wallet       Type = "wallet"
 
 	StatusReady    StatusState = "ready"
 	StatusNotReady StatusState = "not_ready"
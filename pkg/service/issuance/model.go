@@ -47,6 +47,11 @@ type CredentialTemplate struct {
 
 	// Whether the credentials created should be revocable.
 	Revocable bool `json:"revocable"`
+
+	// Optional.
+	// Types of the credentials created, in addition to VerifiableCredential. They are what OpenID4VCI clients request
+	// credentials by.
+	Types []string `json:"types,omitempty"`
 }
 
 // Template is a template for issuing credentials.
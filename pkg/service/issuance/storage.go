@@ -70,13 +70,13 @@ func (s Storage) ListIssuanceTemplates(ctx context.Context) ([]Template, error)
 	if err != nil {
 		return nil, errors.Wrap(err, "reading all")
 	}
-	ts := make([]Template, len(m))
-	i := 0
+	ts := make([]Template, 0, len(m))
 	for k, v := range m {
-		if err = json.Unmarshal(v, &ts[i]); err != nil {
+		var st StoredIssuanceTemplate
+		if err = json.Unmarshal(v, &st); err != nil {
 			return nil, errors.Wrapf(err, "unmarshalling template with key <%s>", k)
 		}
-		i++
+		ts = append(ts, st.IssuanceTemplate)
 	}
 	return ts, nil
 }
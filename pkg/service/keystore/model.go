@@ -45,3 +45,7 @@ type GetKeyDetailsResponse struct {
 type RevokeKeyRequest struct {
 	ID string
 }
+
+type ReinstateKeyRequest struct {
+	ID string
+}
@@ -3,9 +3,11 @@ package keystore
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/TBD54566975/ssi-sdk/crypto"
+	didsdk "github.com/TBD54566975/ssi-sdk/did"
 	sdkutil "github.com/TBD54566975/ssi-sdk/util"
 	"github.com/mr-tron/base58"
 	"github.com/pkg/errors"
@@ -15,6 +17,7 @@ import (
 	"github.com/tbd54566975/ssi-service/internal/keyaccess"
 
 	"github.com/tbd54566975/ssi-service/config"
+	didint "github.com/tbd54566975/ssi-service/internal/did"
 	"github.com/tbd54566975/ssi-service/internal/util"
 	"github.com/tbd54566975/ssi-service/pkg/service/framework"
 	"github.com/tbd54566975/ssi-service/pkg/storage"
@@ -72,23 +75,43 @@ func NewKeyStoreService(config config.KeyStoreServiceConfig, s storage.ServiceSt
 func (s Service) StoreKey(ctx context.Context, request StoreKeyRequest) error {
 	logrus.Debugf("storing key: %+v", request)
 
+	key, err := storedKey(request)
+	if err != nil {
+		return err
+	}
+	if err = s.storage.StoreKey(ctx, *key); err != nil {
+		return sdkutil.LoggingErrorMsgf(err, "storing key: %s", request.ID)
+	}
+	return nil
+}
+
+// StoreKeyTx stores a key within tx, for keys stored along with other changes in the same storage as the keystore.
+func (s Service) StoreKeyTx(ctx context.Context, tx storage.Tx, request StoreKeyRequest) error {
+	logrus.Debugf("storing key: %+v", request)
+
+	key, err := storedKey(request)
+	if err != nil {
+		return err
+	}
+	if err = s.storage.StoreKeyTx(ctx, tx, *key); err != nil {
+		return sdkutil.LoggingErrorMsgf(err, "storing key: %s", request.ID)
+	}
+	return nil
+}
+
+func storedKey(request StoreKeyRequest) (*StoredKey, error) {
 	// check if the provided key type is supported. support entails being able to serialize/deserialize, in addition
 	// to facilitating signing/verification and encryption/decryption support.
 	if !crypto.IsSupportedKeyType(request.Type) {
-		return sdkutil.LoggingNewErrorf("unsupported key type: %s", request.Type)
+		return nil, sdkutil.LoggingNewErrorf("unsupported key type: %s", request.Type)
 	}
-
-	key := StoredKey{
+	return &StoredKey{
 		ID:         request.ID,
 		Controller: request.Controller,
 		KeyType:    request.Type,
 		Base58Key:  request.PrivateKeyBase58,
 		CreatedAt:  time.Now().Format(time.RFC3339),
-	}
-	if err := s.storage.StoreKey(ctx, key); err != nil {
-		return sdkutil.LoggingErrorMsgf(err, "storing key: %s", request.ID)
-	}
-	return nil
+	}, nil
 }
 
 func (s Service) GetKey(ctx context.Context, request GetKeyRequest) (*GetKeyResponse, error) {
@@ -124,6 +147,59 @@ func (s Service) GetKey(ctx context.Context, request GetKeyRequest) (*GetKeyResp
 	}, nil
 }
 
+// GetSigningKey gets a key to sign with, which must not have been revoked.
+func (s Service) GetSigningKey(ctx context.Context, request GetKeyRequest) (*GetKeyResponse, error) {
+	gotKey, err := s.GetKey(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	if gotKey.Revoked {
+		return nil, sdkutil.LoggingNewErrorf("key<%s> was revoked at %s and can no longer be used for signing", gotKey.ID, gotKey.RevokedAt)
+	}
+	return gotKey, nil
+}
+
+// GetAssertionKeyID returns the id of the key a DID currently signs assertions, such as credentials, with. It is the
+// first key referenced by the assertion methods of the DID's document which is in the keystore and has not been
+// revoked, so after a key rotation it's the new key. Keys of verification methods with relative ids, such as those of
+// did:key DIDs, are stored under the relative id, which is then returned. Since relative ids aren't unique across DIDs,
+// only keys controlled by the DID are returned.
+func (s Service) GetAssertionKeyID(ctx context.Context, doc didsdk.Document) (string, error) {
+	for _, id := range didint.AssertionMethodIDs(doc) {
+		keyIDs := []string{id}
+		if relativeID := strings.TrimPrefix(id, doc.ID); relativeID != id {
+			keyIDs = append(keyIDs, relativeID)
+		}
+		for _, keyID := range keyIDs {
+			canSign, err := s.canSign(ctx, keyID, doc.ID)
+			if err != nil {
+				return "", err
+			}
+			if canSign {
+				return keyID, nil
+			}
+		}
+	}
+	return "", sdkutil.LoggingNewErrorf("no key to sign assertions with for DID<%s>", doc.ID)
+}
+
+// canSign is whether a key is in the keystore, is controlled by the controller, and has not been revoked.
+func (s Service) canSign(ctx context.Context, id, controller string) (bool, error) {
+	exists, err := s.storage.KeyExists(ctx, id)
+	if err != nil {
+		return false, sdkutil.LoggingErrorMsgf(err, "checking whether key<%s> exists", id)
+	}
+	if !exists {
+		return false, nil
+	}
+	gotKey, err := s.storage.GetKey(ctx, id)
+	if err != nil {
+		return false, sdkutil.LoggingErrorMsgf(err, "getting key: %s", id)
+	}
+	return gotKey.Controller == controller && !gotKey.Revoked, nil
+}
+
+// RevokeKey marks a key as revoked, after which it can no longer be used for signing.
 // TODO(gabe): expose this endpoint https://github.com/TBD54566975/ssi-service/issues/451
 func (s Service) RevokeKey(ctx context.Context, request RevokeKeyRequest) error {
 	logrus.Debugf("revoking key: %+v", request)
@@ -135,6 +211,22 @@ func (s Service) RevokeKey(ctx context.Context, request RevokeKeyRequest) error
 	return nil
 }
 
+// ReinstateKey undoes the revocation of a key, after which it can be used for signing again.
+func (s Service) ReinstateKey(ctx context.Context, request ReinstateKeyRequest) error {
+	logrus.Debugf("reinstating key: %+v", request)
+
+	id := request.ID
+	if err := s.storage.ReinstateKey(ctx, id); err != nil {
+		return sdkutil.LoggingErrorMsgf(err, "could not reinstate key: %s", id)
+	}
+	return nil
+}
+
+// KeyExists returns whether a key with the given ID is in the keystore, whether it was revoked or not.
+func (s Service) KeyExists(ctx context.Context, id string) (bool, error) {
+	return s.storage.KeyExists(ctx, id)
+}
+
 func (s Service) GetKeyDetails(ctx context.Context, request GetKeyDetailsRequest) (*GetKeyDetailsResponse, error) {
 	logrus.Debugf("getting key: %+v", request)
 
@@ -197,7 +289,7 @@ func DecryptKey(serviceKey, encryptedKey []byte) ([]byte, error) {
 
 // Sign fetches the key in the store, and uses it to sign data. Data should be json or json-serializable.
 func (s Service) Sign(ctx context.Context, keyID string, data any) (*keyaccess.JWT, error) {
-	gotKey, err := s.GetKey(ctx, GetKeyRequest{ID: keyID})
+	gotKey, err := s.GetSigningKey(ctx, GetKeyRequest{ID: keyID})
 	if err != nil {
 		return nil, sdkutil.LoggingErrorMsgf(err, "getting key with keyID<%s>", keyID)
 	}
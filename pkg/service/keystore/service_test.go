@@ -7,6 +7,7 @@ import (
 
 	"github.com/TBD54566975/ssi-sdk/crypto"
 	"github.com/TBD54566975/ssi-sdk/crypto/jwx"
+	"github.com/TBD54566975/ssi-sdk/did"
 	"github.com/mr-tron/base58"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -144,3 +145,92 @@ func TestStoreAndGetKey(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotEmpty(t, signer)
 }
+
+func TestRevokedKeysCannotSign(t *testing.T) {
+	file, err := os.CreateTemp("", "bolt")
+	require.NoError(t, err)
+	name := file.Name()
+	assert.NoError(t, file.Close())
+	s, err := storage.NewStorage(storage.Bolt, storage.Option{
+		ID:     storage.BoltDBFilePathOption,
+		Option: name,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = s.Close()
+		_ = os.Remove(s.URI())
+	})
+
+	keyStore, err := NewKeyStoreService(
+		config.KeyStoreServiceConfig{
+			BaseServiceConfig: &config.BaseServiceConfig{
+				Name: "test-keyStore",
+			},
+			MasterKeyPassword: "test-password",
+		},
+		s)
+	require.NoError(t, err)
+
+	for _, id := range []string{"did:example:123#old", "did:example:123#new"} {
+		_, privKey, err := crypto.GenerateEd25519Key()
+		require.NoError(t, err)
+		err = keyStore.StoreKey(context.Background(), StoreKeyRequest{
+			ID:               id,
+			Type:             crypto.Ed25519,
+			Controller:       "did:example:123",
+			PrivateKeyBase58: base58.Encode(privKey),
+		})
+		require.NoError(t, err)
+	}
+	doc := did.Document{
+		ID:              "did:example:123",
+		AssertionMethod: []did.VerificationMethodSet{"#old", "did:example:123#new"},
+	}
+
+	assertionKeyID, err := keyStore.GetAssertionKeyID(context.Background(), doc)
+	require.NoError(t, err)
+	assert.Equal(t, "did:example:123#old", assertionKeyID)
+
+	require.NoError(t, keyStore.RevokeKey(context.Background(), RevokeKeyRequest{ID: "did:example:123#old"}))
+
+	// the revoked key can still be read, but not used for signing
+	gotKey, err := keyStore.GetKey(context.Background(), GetKeyRequest{ID: "did:example:123#old"})
+	require.NoError(t, err)
+	assert.True(t, gotKey.Revoked)
+	_, err = keyStore.GetSigningKey(context.Background(), GetKeyRequest{ID: "did:example:123#old"})
+	assert.ErrorContains(t, err, "can no longer be used for signing")
+
+	assertionKeyID, err = keyStore.GetAssertionKeyID(context.Background(), doc)
+	require.NoError(t, err)
+	assert.Equal(t, "did:example:123#new", assertionKeyID)
+
+	// reinstated keys can sign again
+	require.NoError(t, keyStore.ReinstateKey(context.Background(), ReinstateKeyRequest{ID: "did:example:123#old"}))
+	_, err = keyStore.GetSigningKey(context.Background(), GetKeyRequest{ID: "did:example:123#old"})
+	assert.NoError(t, err)
+	require.NoError(t, keyStore.RevokeKey(context.Background(), RevokeKeyRequest{ID: "did:example:123#old"}))
+
+	require.NoError(t, keyStore.RevokeKey(context.Background(), RevokeKeyRequest{ID: "did:example:123#new"}))
+	_, err = keyStore.GetAssertionKeyID(context.Background(), doc)
+	assert.ErrorContains(t, err, "no key to sign assertions with")
+
+	// keys of verification methods with relative ids are stored under the relative id
+	_, privKey, err := crypto.GenerateEd25519Key()
+	require.NoError(t, err)
+	err = keyStore.StoreKey(context.Background(), StoreKeyRequest{
+		ID:               "#relative",
+		Type:             crypto.Ed25519,
+		Controller:       "did:example:123",
+		PrivateKeyBase58: base58.Encode(privKey),
+	})
+	require.NoError(t, err)
+	doc.AssertionMethod = append(doc.AssertionMethod, "#relative")
+	assertionKeyID, err = keyStore.GetAssertionKeyID(context.Background(), doc)
+	require.NoError(t, err)
+	assert.Equal(t, "#relative", assertionKeyID)
+
+	// but only for the DID controlling them
+	otherDoc := did.Document{ID: "did:example:456", AssertionMethod: []did.VerificationMethodSet{"#relative"}}
+	_, err = keyStore.GetAssertionKeyID(context.Background(), otherDoc)
+	assert.ErrorContains(t, err, "no key to sign assertions with")
+}
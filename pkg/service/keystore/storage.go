@@ -31,9 +31,11 @@ type StoredKey struct {
 	Controller string         `json:"controller"`
 	KeyType    crypto.KeyType `json:"keyType"`
 	Base58Key  string         `json:"key"`
-	Revoked    bool           `json:"revoked"`
-	RevokedAt  string         `json:"revokedAt"`
-	CreatedAt  string         `json:"createdAt"`
+	// Revoked keys can no longer be used for signing. They are kept, along with the verification methods referencing
+	// them, so that what they signed before being revoked can still be verified.
+	Revoked   bool   `json:"revoked"`
+	RevokedAt string `json:"revokedAt"`
+	CreatedAt string `json:"createdAt"`
 }
 
 // KeyDetails represents a common data model to get information about a key, without revealing the key itself
@@ -236,47 +238,65 @@ func (d decrypter) Decrypt(ctx context.Context, ciphertext, _ []byte) ([]byte, e
 
 func (kss *Storage) StoreKey(ctx context.Context, key StoredKey) error {
 	// TODO(gabe): conflict checking on key id
-	id := key.ID
-	if id == "" {
-		return sdkutil.LoggingNewError("could not store key without an ID")
+	publicBytes, encryptedKey, err := kss.keyRecords(ctx, key)
+	if err != nil {
+		return err
+	}
+	if err = kss.db.Write(ctx, namespace+publicNamespaceSuffix, key.ID, publicBytes); err != nil {
+		return sdkutil.LoggingErrorMsgf(err, "writing public key")
+	}
+	return kss.db.Write(ctx, namespace, key.ID, encryptedKey)
+}
+
+// StoreKeyTx stores a key within tx, for keys stored along with other changes.
+func (kss *Storage) StoreKeyTx(ctx context.Context, tx storage.Tx, key StoredKey) error {
+	publicBytes, encryptedKey, err := kss.keyRecords(ctx, key)
+	if err != nil {
+		return err
+	}
+	if err = tx.Write(ctx, namespace+publicNamespaceSuffix, key.ID, publicBytes); err != nil {
+		return sdkutil.LoggingErrorMsgf(err, "writing public key")
+	}
+	return tx.Write(ctx, namespace, key.ID, encryptedKey)
+}
+
+// keyRecords returns what's stored for a key, which are its public JWK and the encrypted key.
+func (kss *Storage) keyRecords(ctx context.Context, key StoredKey) (publicBytes, encryptedKey []byte, err error) {
+	if key.ID == "" {
+		return nil, nil, sdkutil.LoggingNewError("could not store key without an ID")
 	}
 
 	keyBytes, err := json.Marshal(key)
 	if err != nil {
-		return sdkutil.LoggingErrorMsg(err, "deserializing key from base58")
+		return nil, nil, sdkutil.LoggingErrorMsg(err, "deserializing key from base58")
 	}
 
 	skBytes, err := base58.Decode(key.Base58Key)
 	if err != nil {
-		return sdkutil.LoggingErrorMsg(err, "deserializing key from base58")
+		return nil, nil, sdkutil.LoggingErrorMsg(err, "deserializing key from base58")
 	}
 
 	secretKey, err := crypto.BytesToPrivKey(skBytes, key.KeyType)
 	if err != nil {
-		return sdkutil.LoggingErrorMsg(err, "reconstructing private key from input")
+		return nil, nil, sdkutil.LoggingErrorMsg(err, "reconstructing private key from input")
 	}
 
 	publicJWK, _, err := jwx.PrivateKeyToPrivateKeyJWK(key.ID, secretKey)
 	if err != nil {
-		return sdkutil.LoggingErrorMsg(err, "reconstructing JWK")
+		return nil, nil, sdkutil.LoggingErrorMsg(err, "reconstructing JWK")
 	}
 
-	publicBytes, err := json.Marshal(publicJWK)
+	publicBytes, err = json.Marshal(publicJWK)
 	if err != nil {
-		return sdkutil.LoggingErrorMsg(err, "marshalling JWK")
-	}
-
-	if err := kss.db.Write(ctx, namespace+publicNamespaceSuffix, id, publicBytes); err != nil {
-		return sdkutil.LoggingErrorMsgf(err, "writing public key")
+		return nil, nil, sdkutil.LoggingErrorMsg(err, "marshalling JWK")
 	}
 
 	// encrypt key before storing
-	encryptedKey, err := kss.encrypter.Encrypt(ctx, keyBytes, nil)
+	encryptedKey, err = kss.encrypter.Encrypt(ctx, keyBytes, nil)
 	if err != nil {
-		return sdkutil.LoggingErrorMsgf(err, "could not encrypt key: %s", key.ID)
+		return nil, nil, sdkutil.LoggingErrorMsgf(err, "could not encrypt key: %s", key.ID)
 	}
-
-	return kss.db.Write(ctx, namespace, id, encryptedKey)
+	return publicBytes, encryptedKey, nil
 }
 
 // RevokeKey revokes a key by setting the revoked flag to true.
@@ -294,6 +314,21 @@ func (kss *Storage) RevokeKey(ctx context.Context, id string) error {
 	return kss.StoreKey(ctx, *key)
 }
 
+// ReinstateKey undoes the revocation of a key by setting the revoked flag back to false.
+func (kss *Storage) ReinstateKey(ctx context.Context, id string) error {
+	key, err := kss.GetKey(ctx, id)
+	if err != nil {
+		return err
+	}
+	if key == nil {
+		return sdkutil.LoggingNewErrorf("key not found: %s", id)
+	}
+
+	key.Revoked = false
+	key.RevokedAt = ""
+	return kss.StoreKey(ctx, *key)
+}
+
 func (kss *Storage) GetKey(ctx context.Context, id string) (*StoredKey, error) {
 	storedKeyBytes, err := kss.db.Read(ctx, namespace, id)
 	if err != nil {
@@ -316,6 +351,15 @@ func (kss *Storage) GetKey(ctx context.Context, id string) (*StoredKey, error) {
 	return &stored, nil
 }
 
+// KeyExists returns whether a key with the given id was stored.
+func (kss *Storage) KeyExists(ctx context.Context, id string) (bool, error) {
+	exists, err := kss.db.Exists(ctx, namespace, id)
+	if err != nil {
+		return false, sdkutil.LoggingErrorMsgf(err, "checking whether key exists: %s", id)
+	}
+	return exists, nil
+}
+
 func (kss *Storage) GetKeyDetails(ctx context.Context, id string) (*KeyDetails, error) {
 	stored, err := kss.GetKey(ctx, id)
 	if err != nil {
@@ -7,6 +7,7 @@ import (
 
 	sdkutil "github.com/TBD54566975/ssi-sdk/util"
 	"github.com/lestrrat-go/jwx/v2/jws"
+	"github.com/pkg/errors"
 
 	didint "github.com/tbd54566975/ssi-service/internal/did"
 	"github.com/tbd54566975/ssi-service/internal/keyaccess"
@@ -16,6 +17,7 @@ import (
 	errresp "github.com/TBD54566975/ssi-sdk/error"
 
 	"github.com/tbd54566975/ssi-service/pkg/service/credential"
+	"github.com/tbd54566975/ssi-service/pkg/service/trust"
 )
 
 // validateCredentialApplication validates the credential application's signature(s) in addition to making sure it
@@ -92,6 +94,17 @@ func (s Service) validateCredentialApplication(ctx context.Context, credManifest
 			err = sdkutil.LoggingNewErrorf("submitted credential<%s> is not valid: %s", credentialContainer.Credential.ID, verificationResult.Reason)
 			return
 		}
+
+		if s.config.RequireTrustedIssuers {
+			if trustErr := trust.VerifyCredentialIssuer(ctx, s.trustStorage, *credentialContainer.Credential); trustErr != nil {
+				if errors.Is(trustErr, trust.ErrIssuerNotTrusted) {
+					err = errresp.NewErrorResponsef(DenialResponse, "submitted credential<%s> has an untrusted issuer: %s", credentialContainer.Credential.ID, trustErr.Error())
+					return
+				}
+				err = sdkutil.LoggingErrorMsgf(trustErr, "could not check issuer trust for credential: %s", credentialContainer.Credential.ID)
+				return
+			}
+		}
 	}
 	return
 }
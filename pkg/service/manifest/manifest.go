@@ -15,7 +15,7 @@ import (
 )
 
 func (s Service) signManifestJWT(ctx context.Context, keyID string, m CredentialManifestContainer) (*keyaccess.JWT, error) {
-	gotKey, err := s.keyStore.GetKey(ctx, keystore.GetKeyRequest{ID: keyID})
+	gotKey, err := s.keyStore.GetSigningKey(ctx, keystore.GetKeyRequest{ID: keyID})
 	if err != nil {
 		return nil, sdkutil.LoggingErrorMsgf(err, "could not get key for signing manifest with key<%s>", keyID)
 	}
@@ -17,7 +17,7 @@ type CreateManifestRequest struct {
 	Name                   *string                          `json:"name,omitempty"`
 	Description            *string                          `json:"description,omitempty"`
 	IssuerDID              string                           `json:"issuerDid" validate:"required"`
-	IssuerKID              string                           `json:"issuerKid" validate:"required"`
+	IssuerKID              string                           `json:"issuerKid,omitempty"`
 	IssuerName             *string                          `json:"issuerName,omitempty"`
 	OutputDescriptors      []manifestsdk.OutputDescriptor   `json:"outputDescriptors" validate:"required,dive"`
 	ClaimFormat            *exchange.ClaimFormat            `json:"format" validate:"required,dive"`
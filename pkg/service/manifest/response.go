@@ -30,7 +30,7 @@ const (
 )
 
 func (s Service) signCredentialResponse(ctx context.Context, issuerKID string, r CredentialResponseContainer) (*keyaccess.JWT, error) {
-	gotKey, err := s.keyStore.GetKey(ctx, keystore.GetKeyRequest{ID: issuerKID})
+	gotKey, err := s.keyStore.GetSigningKey(ctx, keystore.GetKeyRequest{ID: issuerKID})
 	if err != nil {
 		return nil, sdkutil.LoggingErrorMsgf(err, "getting key for signing response with key<%s>", issuerKID)
 	}
@@ -190,6 +190,7 @@ func (s Service) applyIssuanceTemplate(credentialRequest credential.CreateCreden
 	}
 
 	credentialRequest.Revocable = ct.Revocable
+	credentialRequest.Types = ct.Types
 	return &credentialRequest, nil
 }
 
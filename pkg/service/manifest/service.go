@@ -24,6 +24,7 @@ import (
 	"github.com/tbd54566975/ssi-service/pkg/service/operation"
 	opcredential "github.com/tbd54566975/ssi-service/pkg/service/operation/credential"
 	opstorage "github.com/tbd54566975/ssi-service/pkg/service/operation/storage"
+	"github.com/tbd54566975/ssi-service/pkg/service/trust"
 	"github.com/tbd54566975/ssi-service/pkg/storage"
 )
 
@@ -31,6 +32,7 @@ type Service struct {
 	storage                 *manifeststg.Storage
 	opsStorage              *operation.Storage
 	issuanceTemplateStorage *issuance.Storage
+	trustStorage            *trust.Storage
 	config                  config.ManifestServiceConfig
 
 	// external dependencies
@@ -86,10 +88,15 @@ func NewManifestService(config config.ManifestServiceConfig, s storage.ServiceSt
 	if err != nil {
 		return nil, sdkutil.LoggingErrorMsg(err, "could not instantiate storage for issuance templates")
 	}
+	trustStorage, err := trust.NewTrustStorage(s)
+	if err != nil {
+		return nil, sdkutil.LoggingErrorMsg(err, "could not instantiate storage for the trust registry")
+	}
 	return &Service{
 		storage:                 manifestStorage,
 		opsStorage:              opsStorage,
 		issuanceTemplateStorage: issuanceStorage,
+		trustStorage:            trustStorage,
 		config:                  config,
 		keyStore:                keyStore,
 		didResolver:             didResolver,
@@ -166,7 +173,11 @@ func (s Service) CreateManifest(ctx context.Context, request model.CreateManifes
 	}
 
 	// sign the manifest
-	manifestJWT, err := s.signManifestJWT(ctx, request.IssuerKID, CredentialManifestContainer{Manifest: *m})
+	issuerKID, err := s.signingKID(ctx, request.IssuerDID, request.IssuerKID)
+	if err != nil {
+		return nil, err
+	}
+	manifestJWT, err := s.signManifestJWT(ctx, issuerKID, CredentialManifestContainer{Manifest: *m})
 	if err != nil {
 		return nil, sdkutil.LoggingErrorMsg(err, "could not sign manifest")
 	}
@@ -189,6 +200,23 @@ func (s Service) CreateManifest(ctx context.Context, request model.CreateManifes
 	return &response, nil
 }
 
+// signingKID returns the key to sign with on behalf of the issuer. Manifests created without a key are signed with the
+// issuer's current key, which follows key rotations.
+func (s Service) signingKID(ctx context.Context, issuerDID, issuerKID string) (string, error) {
+	if issuerKID != "" {
+		return issuerKID, nil
+	}
+	resolved, err := s.didResolver.Resolve(ctx, issuerDID)
+	if err != nil {
+		return "", sdkutil.LoggingErrorMsgf(err, "resolving issuer DID<%s>", issuerDID)
+	}
+	currentKID, err := s.keyStore.GetAssertionKeyID(ctx, resolved.Document)
+	if err != nil {
+		return "", sdkutil.LoggingErrorMsgf(err, "getting current key of issuer<%s>", issuerDID)
+	}
+	return currentKID, nil
+}
+
 // VerifyManifest verifies a manifest's signature and makes sure the manifest is compliant with the specification
 func (s Service) VerifyManifest(ctx context.Context, request model.VerifyManifestRequest) (*model.VerifyManifestResponse, error) {
 	m, err := s.verifyManifestJWT(ctx, request.ManifestJWT)
@@ -354,7 +382,11 @@ func (s Service) attemptAutomaticIssuance(ctx context.Context, request model.Sub
 		return nil, err
 	}
 
-	responseJWT, err := s.signCredentialResponse(ctx, gotManifest.IssuerKID, CredentialResponseContainer{
+	issuerKID, err := s.signingKID(ctx, gotManifest.IssuerDID, gotManifest.IssuerKID)
+	if err != nil {
+		return nil, err
+	}
+	responseJWT, err := s.signCredentialResponse(ctx, issuerKID, CredentialResponseContainer{
 		Response:    *credResp,
 		Credentials: credint.ContainersToInterface(creds),
 	})
@@ -423,7 +455,11 @@ func (s Service) ReviewApplication(ctx context.Context, request model.ReviewAppl
 	}
 
 	// sign the response before returning
-	responseJWT, err := s.signCredentialResponse(ctx, gotManifest.IssuerKID, responseContainer)
+	issuerKID, err := s.signingKID(ctx, gotManifest.IssuerDID, gotManifest.IssuerKID)
+	if err != nil {
+		return nil, err
+	}
+	responseJWT, err := s.signCredentialResponse(ctx, issuerKID, responseContainer)
 	if err != nil {
 		return nil, sdkutil.LoggingErrorMsg(err, "could not sign credential response")
 	}
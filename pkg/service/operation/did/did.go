@@ -0,0 +1,13 @@
+package did
+
+import "fmt"
+
+const (
+	// ParentResource is the prefix of the DID operation parent resource.
+	ParentResource = "dids/operations"
+)
+
+// IDFromOperationID returns an operation ID from the ID of an operation submitted for a DID.
+func IDFromOperationID(id string) string {
+	return fmt.Sprintf("%s/%s", ParentResource, id)
+}
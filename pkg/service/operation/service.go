@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	didsdk "github.com/TBD54566975/ssi-sdk/did"
 	sdkutil "github.com/TBD54566975/ssi-sdk/util"
 	"github.com/goccy/go-json"
 	"github.com/pkg/errors"
@@ -14,6 +15,7 @@ import (
 	manifestmodel "github.com/tbd54566975/ssi-service/pkg/service/manifest/model"
 	manifeststg "github.com/tbd54566975/ssi-service/pkg/service/manifest/storage"
 	"github.com/tbd54566975/ssi-service/pkg/service/operation/credential"
+	opdid "github.com/tbd54566975/ssi-service/pkg/service/operation/did"
 	opstorage "github.com/tbd54566975/ssi-service/pkg/service/operation/storage"
 	"github.com/tbd54566975/ssi-service/pkg/service/operation/submission"
 	"github.com/tbd54566975/ssi-service/pkg/service/presentation/model"
@@ -23,6 +25,17 @@ import (
 
 type Service struct {
 	storage *Storage
+
+	// settlers bring operations up to date before they are looked up
+	settlers []Settler
+}
+
+// Settler settles operations which are done once something outside the service happens, such as an ION node anchoring
+// a DID operation, so that they are up to date when they are looked up.
+type Settler interface {
+	// SettleOperation marks the operation with the given ID as done when it is. Operations the settler doesn't track
+	// are ignored.
+	SettleOperation(ctx context.Context, id string) error
 }
 
 func (s Service) Type() framework.Type {
@@ -93,6 +106,12 @@ func ServiceModel(op opstorage.StoredOperation) (*Operation, error) {
 				return nil, errors.Wrap(err, "unmarshalling cred response")
 			}
 			newOp.Result.Response = manifestmodel.ServiceModel(&s)
+		case strings.HasPrefix(op.ID, opdid.ParentResource):
+			var doc didsdk.Document
+			if err := json.Unmarshal(op.Response, &doc); err != nil {
+				return nil, errors.Wrap(err, "unmarshalling did document")
+			}
+			newOp.Result.Response = doc
 		default:
 			return nil, errors.New("unknown response type")
 		}
@@ -106,6 +125,7 @@ func (s Service) GetOperation(ctx context.Context, request GetOperationRequest)
 		return nil, errors.Wrap(err, "invalid request")
 	}
 
+	s.settle(ctx, request.ID)
 	storedOp, err := s.storage.GetOperation(ctx, request.ID)
 	if err != nil {
 		return nil, errors.Wrap(err, "fetching from storage")
@@ -113,6 +133,16 @@ func (s Service) GetOperation(ctx context.Context, request GetOperationRequest)
 	return ServiceModel(storedOp)
 }
 
+// settle brings the operation up to date. Failing to settle it doesn't fail the lookup, which returns the operation as
+// it was last stored.
+func (s Service) settle(ctx context.Context, id string) {
+	for _, settler := range s.settlers {
+		if err := settler.SettleOperation(ctx, id); err != nil {
+			logrus.WithError(err).Warnf("could not settle operation: %s", id)
+		}
+	}
+}
+
 func (s Service) CancelOperation(ctx context.Context, request CancelOperationRequest) (*Operation, error) {
 	if err := request.Validate(); err != nil {
 		return nil, errors.Wrap(err, "invalid request")
@@ -125,12 +155,13 @@ func (s Service) CancelOperation(ctx context.Context, request CancelOperationReq
 	return ServiceModel(*storedOp)
 }
 
-func NewOperationService(s storage.ServiceStorage) (*Service, error) {
+// NewOperationService creates the operation service. Operations are settled by the settlers before they are looked up.
+func NewOperationService(s storage.ServiceStorage, settlers ...Settler) (*Service, error) {
 	opStorage, err := NewOperationStorage(s)
 	if err != nil {
 		return nil, sdkutil.LoggingErrorMsg(err, "creating operation storage")
 	}
-	service := &Service{storage: opStorage}
+	service := &Service{storage: opStorage, settlers: settlers}
 	if !service.Status().IsReady() {
 		return nil, errors.New(service.Status().Message)
 	}
@@ -83,6 +83,22 @@ func (s Storage) StoreOperation(ctx context.Context, op opstorage.StoredOperatio
 	return nil
 }
 
+// StoreOperationTx stores an operation within tx, for operations stored along with other changes in the same storage.
+func (s Storage) StoreOperationTx(ctx context.Context, tx storage.Tx, op opstorage.StoredOperation) error {
+	id := op.ID
+	if id == "" {
+		return sdkutil.LoggingNewError("ID is required for storing operations")
+	}
+	jsonBytes, err := json.Marshal(op)
+	if err != nil {
+		return sdkutil.LoggingErrorMsgf(err, "marshalling operation with id: %s", id)
+	}
+	if err = tx.Write(ctx, namespace.FromID(id), id, jsonBytes); err != nil {
+		return sdkutil.LoggingErrorMsg(err, "writing to db")
+	}
+	return nil
+}
+
 func (s Storage) GetOperation(ctx context.Context, id string) (opstorage.StoredOperation, error) {
 	var stored opstorage.StoredOperation
 	operationID := namespace.FromID(id)
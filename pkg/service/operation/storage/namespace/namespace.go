@@ -4,12 +4,14 @@ import (
 	"strings"
 
 	"github.com/tbd54566975/ssi-service/pkg/service/operation/credential"
+	"github.com/tbd54566975/ssi-service/pkg/service/operation/did"
 	"github.com/tbd54566975/ssi-service/pkg/service/operation/submission"
 )
 
 const (
 	namespace                   = "operation_submission"
 	credentialResponseNamespace = "operation_credential_response"
+	didNamespace                = "operation_did"
 )
 
 // FromID returns a namespace from a given operation ID. An empty string is returned when the namespace cannot
@@ -30,6 +32,8 @@ func FromParent(parent string) string {
 		return namespace
 	case credential.ParentResource:
 		return credentialResponseNamespace
+	case did.ParentResource:
+		return didNamespace
 	default:
 		return ""
 	}
@@ -39,14 +39,77 @@ type DeletePresentationDefinitionRequest struct {
 }
 
 type CreateSubmissionRequest struct {
-	Presentation  credsdk.VerifiablePresentation  `json:"presentation" validate:"required"`
-	SubmissionJWT keyaccess.JWT                   `json:"submissionJwt,omitempty" validate:"required"`
+	Presentation credsdk.VerifiablePresentation `json:"presentation" validate:"required"`
+
+	// ID of the presentation request this submission answers. When present, the presentation must be bound to the
+	// request's nonce and issuer, and the request must not be expired or already answered.
+	PresentationRequestID string `json:"presentationRequestId,omitempty"`
+
+	// Set when the presentation was submitted as a JWT. Otherwise, Presentation must have a data integrity proof.
+	SubmissionJWT keyaccess.JWT                   `json:"submissionJwt,omitempty"`
 	Submission    exchange.PresentationSubmission `json:"submission" validate:"required"`
 	Credentials   []credential.Container          `json:"credentials,omitempty"`
 }
 
 func (csr CreateSubmissionRequest) IsValid() bool {
-	return util.IsValidStruct(csr) == nil
+	return util.IsValidStruct(csr) == nil && (csr.SubmissionJWT != "" || csr.Presentation.Proof != nil)
+}
+
+// VerifyPresentationRequest verifies a presentation without storing anything. Exactly one of PresentationJWT or
+// DataIntegrityPresentation must be present.
+type VerifyPresentationRequest struct {
+	// A JWT that encodes a verifiable presentation, signed by its holder.
+	PresentationJWT *keyaccess.JWT `json:"presentationJwt,omitempty"`
+
+	// A presentation secured via data integrity. Must have the "proof" property set.
+	DataIntegrityPresentation *credsdk.VerifiablePresentation `json:"presentation,omitempty"`
+
+	// When present, the presentation must be a presentation submission that fulfills this definition.
+	PresentationDefinition *exchange.PresentationDefinition `json:"presentationDefinition,omitempty"`
+
+	// When present, the presentation must be bound to this challenge. It's the proof's `challenge` for data integrity
+	// presentations, and the `nonce` claim for JWTs.
+	Challenge string `json:"challenge,omitempty"`
+
+	// When present, the presentation must be bound to this domain. It's the proof's `domain` for data integrity
+	// presentations, and one of the `aud` values for JWTs.
+	Domain string `json:"domain,omitempty"`
+}
+
+func (vpr VerifyPresentationRequest) IsValid() bool {
+	return (vpr.PresentationJWT != nil) != (vpr.DataIntegrityPresentation != nil)
+}
+
+type VerifyPresentationResponse struct {
+	// Whether the holder's signature, every credential and, when requested, the presentation definition were verified.
+	Verified bool `json:"verified"`
+
+	// The reason why the presentation couldn't be verified.
+	Reason string `json:"reason,omitempty"`
+
+	// DID of the holder of the presentation.
+	Holder string `json:"holder,omitempty"`
+
+	// Whether the presentation was signed by its holder.
+	HolderVerified bool `json:"holderVerified"`
+
+	// Whether the presentation fulfills the requested definition. Only present when a definition was given.
+	DefinitionVerified *bool `json:"definitionVerified,omitempty"`
+
+	// The result of verifying each credential in the presentation, in the order they appear.
+	Credentials []CredentialVerificationResult `json:"credentials"`
+}
+
+type CredentialVerificationResult struct {
+	// Index of the credential within the presentation's verifiableCredential property.
+	Index int `json:"index"`
+
+	// ID and issuer of the credential, when it could be parsed.
+	ID     string `json:"id,omitempty"`
+	Issuer string `json:"issuer,omitempty"`
+
+	Verified bool   `json:"verified"`
+	Reason   string `json:"reason,omitempty"`
 }
 
 type CreateSubmissionResponse struct {
@@ -106,6 +169,10 @@ type ReviewSubmissionRequest struct {
 	ID       string `json:"id" validate:"required"`
 	Approved bool   `json:"approved"`
 	Reason   string `json:"reason"`
+
+	// When true, an approval is turned into a denial if any credential in the submission was issued by an issuer
+	// that is not trusted for it in the trust registry.
+	RequireTrustedIssuers bool `json:"requireTrustedIssuers"`
 }
 
 // Validate runs validation on the request struct and returns errors when it's invalid.
@@ -167,4 +234,68 @@ type Request struct {
 	// value of the field named "presentation_definition.id" matches PresentationDefinitionID.
 	// This is an output only field.
 	PresentationDefinitionJWT keyaccess.JWT `json:"presentationRequestJwt"`
+
+	// Nonce that a submission answering this request must be bound to. It matches the "nonce" claim in the JWT.
+	// This is an output only field.
+	Nonce string `json:"nonce,omitempty"`
+
+	// Whether a submission already answered this request. Requests can only be answered once.
+	// This is an output only field.
+	Used bool `json:"used,omitempty"`
+}
+
+// CreateAuthorizationRequestRequest asks for an OpenID for Verifiable Presentations authorization request that asks
+// wallets for a presentation fulfilling a stored presentation definition.
+type CreateAuthorizationRequestRequest struct {
+	// DID of the verifier. It's the request's `client_id`, and wallets must set it as the `aud` of the vp_token.
+	VerifierDID string `json:"verifierDid" validate:"required"`
+
+	// ID of the key in the keystore used to sign the request object. Its controller must be VerifierDID.
+	VerifierKID string `json:"verifierKid" validate:"required"`
+
+	// ID of the presentation definition used for this request.
+	PresentationDefinitionID string `json:"presentationDefinitionId" validate:"required"`
+
+	// Expiration of the request object.
+	Expiration time.Time `json:"expiration" validate:"required"`
+
+	// When true, the authorization request references the request object via `request_uri`. Otherwise, the signed
+	// request object is passed by value in the `request` parameter.
+	ByReference bool `json:"byReference,omitempty"`
+}
+
+type AuthorizationRequest struct {
+	// ID of the request. It's the `state` wallets send back to the response endpoint.
+	ID string `json:"id"`
+
+	// The signed request object.
+	RequestObjectJWT keyaccess.JWT `json:"requestObjectJwt"`
+
+	// URL from which wallets fetch RequestObjectJWT. Only set when the request is passed by reference.
+	RequestURI string `json:"requestUri,omitempty"`
+
+	// The authorization request to hand to a wallet, e.g. via a QR code.
+	AuthorizationRequestURI string `json:"authorizationRequestUri"`
+
+	// URL to which wallets post their response.
+	ResponseURI string `json:"responseUri"`
+
+	Nonce      string    `json:"nonce"`
+	Expiration time.Time `json:"expiration"`
+}
+
+// DirectPostRequest is an authorization response sent with `response_mode=direct_post`, as described in
+// https://openid.net/specs/openid-4-verifiable-presentations-1_0.html#name-response-mode-direct_post.
+type DirectPostRequest struct {
+	// Either a JWT that encodes a presentation, or a JSON encoded data integrity presentation.
+	VPToken string `json:"vp_token" validate:"required"`
+
+	PresentationSubmission exchange.PresentationSubmission `json:"presentation_submission" validate:"required"`
+
+	// ID of the authorization request the response answers.
+	State string `json:"state" validate:"required"`
+}
+
+func (r DirectPostRequest) IsValid() error {
+	return util.IsValidStruct(r)
 }
@@ -0,0 +1,211 @@
+package presentation
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	credsdk "github.com/TBD54566975/ssi-sdk/credential"
+	"github.com/TBD54566975/ssi-sdk/credential/exchange"
+	sdkutil "github.com/TBD54566975/ssi-sdk/util"
+	"github.com/goccy/go-json"
+	"github.com/google/uuid"
+	"github.com/lestrrat-go/jwx/jwt"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/tbd54566975/ssi-service/config"
+	"github.com/tbd54566975/ssi-service/internal/credential"
+	"github.com/tbd54566975/ssi-service/internal/keyaccess"
+	"github.com/tbd54566975/ssi-service/pkg/service/operation"
+	"github.com/tbd54566975/ssi-service/pkg/service/presentation/model"
+	presentationstorage "github.com/tbd54566975/ssi-service/pkg/service/presentation/storage"
+)
+
+// Values of the request object defined in https://openid.net/specs/openid-4-verifiable-presentations-1_0.html
+const (
+	oid4vpScheme             = "openid4vp://"
+	selfIssuedAudience       = "https://self-issued.me/v2"
+	vpTokenResponseType      = "vp_token"
+	directPostResponseMode   = "direct_post"
+	didClientIDScheme        = "did"
+	oid4vpRequestsPath       = "/v1/presentations/oid4vp/requests"
+	oid4vpResponsesPath      = "/v1/presentations/oid4vp/responses"
+	responseTypeClaim        = "response_type"
+	responseModeClaim        = "response_mode"
+	responseURIClaim         = "response_uri"
+	clientIDClaim            = "client_id"
+	clientIDSchemeClaim      = "client_id_scheme"
+	stateClaim               = "state"
+	requestParam             = "request"
+	requestURIParam          = "request_uri"
+	presentationSubmissionVP = "presentation_submission"
+)
+
+// CreateAuthorizationRequest creates an OpenID for Verifiable Presentations authorization request for a stored
+// presentation definition. The request object is signed with the verifier's key, and asks wallets to post their
+// response to this service's direct_post endpoint. It's stored as a presentation request, so that the response is bound
+// to it and can only be sent once.
+func (s Service) CreateAuthorizationRequest(ctx context.Context, request model.CreateAuthorizationRequestRequest) (*model.AuthorizationRequest, error) {
+	if err := sdkutil.IsValidStruct(request); err != nil {
+		return nil, errors.Wrap(err, "invalid create authorization request request")
+	}
+
+	pd, err := s.storage.GetDefinition(ctx, request.PresentationDefinitionID)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting presentation definition")
+	}
+	if pd == nil {
+		return nil, errors.Errorf("presentation definition %q is nil", request.PresentationDefinitionID)
+	}
+
+	requestID := uuid.NewString()
+	nonce := uuid.NewString()
+	responseURI := s.serviceEndpoint() + oid4vpResponsesPath
+	token, err := jwt.NewBuilder().
+		Claim(responseTypeClaim, vpTokenResponseType).
+		Claim(responseModeClaim, directPostResponseMode).
+		Claim(responseURIClaim, responseURI).
+		Claim(clientIDClaim, request.VerifierDID).
+		Claim(clientIDSchemeClaim, didClientIDScheme).
+		Claim(nonceClaim, nonce).
+		Claim(stateClaim, requestID).
+		Claim("presentation_definition", pd.PresentationDefinition).
+		Audience([]string{selfIssuedAudience}).
+		Expiration(request.Expiration).
+		Issuer(request.VerifierDID).
+		IssuedAt(time.Now()).
+		JwtID(requestID).
+		Build()
+	if err != nil {
+		return nil, errors.Wrap(err, "building request object")
+	}
+	signedToken, err := s.keystore.Sign(ctx, request.VerifierKID, token)
+	if err != nil {
+		return nil, errors.Wrapf(err, "signing request object with KID %q", request.VerifierKID)
+	}
+
+	stored := presentationstorage.StoredRequest{
+		ID:                        requestID,
+		Audience:                  []string{selfIssuedAudience},
+		Expiration:                request.Expiration.Format(time.RFC3339),
+		IssuerDID:                 request.VerifierDID,
+		IssuerKID:                 request.VerifierKID,
+		PresentationDefinitionID:  request.PresentationDefinitionID,
+		PresentationDefinitionJWT: signedToken.String(),
+		Nonce:                     nonce,
+	}
+	if err = s.storage.StoreRequest(ctx, stored); err != nil {
+		return nil, errors.Wrap(err, "storing authorization request")
+	}
+
+	params := url.Values{}
+	params.Set(clientIDClaim, request.VerifierDID)
+	authorizationRequest := model.AuthorizationRequest{
+		ID:               requestID,
+		RequestObjectJWT: *signedToken,
+		ResponseURI:      responseURI,
+		Nonce:            nonce,
+		Expiration:       request.Expiration,
+	}
+	if request.ByReference {
+		authorizationRequest.RequestURI = fmt.Sprintf("%s%s/%s", s.serviceEndpoint(), oid4vpRequestsPath, requestID)
+		params.Set(requestURIParam, authorizationRequest.RequestURI)
+	} else {
+		params.Set(requestParam, signedToken.String())
+	}
+	authorizationRequest.AuthorizationRequestURI = oid4vpScheme + "?" + params.Encode()
+	return &authorizationRequest, nil
+}
+
+// GetRequestObject returns the signed request object of an authorization request, which is what wallets fetch from
+// the request's `request_uri`.
+func (s Service) GetRequestObject(ctx context.Context, id string) (*keyaccess.JWT, error) {
+	storedRequest, err := s.usableRequest(ctx, id)
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting authorization request<%s>", id)
+	}
+	requestObject := keyaccess.JWT(storedRequest.PresentationDefinitionJWT)
+	return &requestObject, nil
+}
+
+// CreateDirectPostSubmission creates a submission from an authorization response sent to the direct_post endpoint.
+// The response goes through the same checks and workflow as any other submission answering a presentation request.
+func (s Service) CreateDirectPostSubmission(ctx context.Context, request model.DirectPostRequest) (*operation.Operation, error) {
+	logrus.Debugf("creating submission from authorization response to request<%s>", request.State)
+
+	if err := request.IsValid(); err != nil {
+		return nil, errors.Wrap(err, "invalid authorization response")
+	}
+	if err := request.PresentationSubmission.IsValid(); err != nil {
+		return nil, errors.Wrap(err, "invalid presentation_submission")
+	}
+
+	var vp *credsdk.VerifiablePresentation
+	var submissionJWT keyaccess.JWT
+	vpToken := strings.TrimSpace(request.VPToken)
+	if strings.HasPrefix(vpToken, "{") {
+		if err := json.Unmarshal([]byte(vpToken), &vp); err != nil {
+			return nil, errors.Wrap(err, "unmarshalling data integrity vp_token")
+		}
+		// the submission is covered by the proof, so it can't be added after the fact
+		if vp.PresentationSubmission == nil {
+			return nil, errors.Errorf("data integrity vp_token must contain its %s", presentationSubmissionVP)
+		}
+	} else {
+		_, _, parsed, err := keyaccess.ParseVerifiablePresentationFromJWT(keyaccess.JWT(vpToken))
+		if err != nil {
+			return nil, errors.Wrap(err, "parsing vp_token")
+		}
+		vp = parsed
+		submissionJWT = keyaccess.JWT(vpToken)
+	}
+
+	if vp.PresentationSubmission != nil {
+		embedded, err := toPresentationSubmission(vp.PresentationSubmission)
+		if err != nil {
+			return nil, err
+		}
+		if embedded.ID != request.PresentationSubmission.ID {
+			return nil, errors.Errorf("vp_token's %s<%s> does not match presentation_submission<%s>", presentationSubmissionVP, embedded.ID, request.PresentationSubmission.ID)
+		}
+	}
+	vp.PresentationSubmission = request.PresentationSubmission
+	if err := vp.IsValid(); err != nil {
+		return nil, errors.Wrap(err, "verifying vp validity")
+	}
+
+	credContainers, err := credential.NewCredentialContainerFromArray(vp.VerifiableCredential)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing verifiable credential array")
+	}
+	return s.CreateSubmission(ctx, model.CreateSubmissionRequest{
+		Presentation:          *vp,
+		PresentationRequestID: request.State,
+		SubmissionJWT:         submissionJWT,
+		Submission:            request.PresentationSubmission,
+		Credentials:           credContainers,
+	})
+}
+
+// serviceEndpoint is the base URL wallets use to reach this service.
+func (s Service) serviceEndpoint() string {
+	if s.config.BaseServiceConfig == nil || s.config.ServiceEndpoint == "" {
+		return config.DefaultServiceEndpoint
+	}
+	return s.config.ServiceEndpoint
+}
+
+func toPresentationSubmission(submission any) (*exchange.PresentationSubmission, error) {
+	submissionData, err := json.Marshal(submission)
+	if err != nil {
+		return nil, errors.Wrapf(err, "marshalling %s", presentationSubmissionVP)
+	}
+	var s exchange.PresentationSubmission
+	if err = json.Unmarshal(submissionData, &s); err != nil {
+		return nil, errors.Wrapf(err, "unmarshalling %s", presentationSubmissionVP)
+	}
+	return &s, nil
+}
@@ -5,13 +5,13 @@ import (
 	"fmt"
 	"time"
 
-	credsdk "github.com/TBD54566975/ssi-sdk/credential"
 	"github.com/TBD54566975/ssi-sdk/credential/exchange"
 	"github.com/TBD54566975/ssi-sdk/did/resolution"
 	sdkutil "github.com/TBD54566975/ssi-sdk/util"
 	"github.com/google/uuid"
 	"github.com/lestrrat-go/jwx/jws"
 	"github.com/lestrrat-go/jwx/jwt"
+	jwtv2 "github.com/lestrrat-go/jwx/v2/jwt"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/tbd54566975/ssi-service/config"
@@ -26,17 +26,19 @@ import (
 	"github.com/tbd54566975/ssi-service/pkg/service/presentation/model"
 	presentationstorage "github.com/tbd54566975/ssi-service/pkg/service/presentation/storage"
 	"github.com/tbd54566975/ssi-service/pkg/service/schema"
+	"github.com/tbd54566975/ssi-service/pkg/service/trust"
 	"github.com/tbd54566975/ssi-service/pkg/storage"
 )
 
 type Service struct {
-	storage    presentationstorage.Storage
-	keystore   *keystore.Service
-	opsStorage *operation.Storage
-	config     config.PresentationServiceConfig
-	resolver   resolution.Resolver
-	schema     *schema.Service
-	verifier   *credential.Verifier
+	storage      presentationstorage.Storage
+	keystore     *keystore.Service
+	opsStorage   *operation.Storage
+	trustStorage *trust.Storage
+	config       config.PresentationServiceConfig
+	resolver     resolution.Resolver
+	schema       *schema.Service
+	verifier     *credential.Verifier
 }
 
 func (s Service) Type() framework.Type {
@@ -71,18 +73,23 @@ func NewPresentationService(config config.PresentationServiceConfig, s storage.S
 	if err != nil {
 		return nil, sdkutil.LoggingErrorMsg(err, "could not instantiate storage for the operations")
 	}
+	trustStorage, err := trust.NewTrustStorage(s)
+	if err != nil {
+		return nil, sdkutil.LoggingErrorMsg(err, "could not instantiate trust storage for the presentation service")
+	}
 	verifier, err := credential.NewCredentialVerifier(resolver, schema)
 	if err != nil {
 		return nil, sdkutil.LoggingErrorMsg(err, "could not instantiate verifier")
 	}
 	service := Service{
-		storage:    presentationStorage,
-		keystore:   keystore,
-		opsStorage: opsStorage,
-		config:     config,
-		resolver:   resolver,
-		schema:     schema,
-		verifier:   verifier,
+		storage:      presentationStorage,
+		keystore:     keystore,
+		opsStorage:   opsStorage,
+		trustStorage: trustStorage,
+		config:       config,
+		resolver:     resolver,
+		schema:       schema,
+		verifier:     verifier,
 	}
 	if !service.Status().IsReady() {
 		return nil, errors.New(service.Status().Message)
@@ -155,25 +162,55 @@ func (s Service) CreateSubmission(ctx context.Context, request model.CreateSubmi
 		return nil, errors.Wrap(err, "provided value is not a valid presentation submission")
 	}
 
-	headers, _, vp, err := credsdk.ParseVerifiablePresentationFromJWT(request.SubmissionJWT.String())
-	if err != nil {
-		return nil, errors.Wrap(err, "parsing vp from jwt")
+	if s.config.RequirePresentationRequest && request.PresentationRequestID == "" {
+		return nil, errors.New("submission must reference the presentation request it answers")
 	}
-
-	gotKID, ok := headers.Get(jws.KeyIDKey)
-	if !ok {
-		return nil, errors.New("kid not found in token headers")
-	}
-	kid, ok := gotKID.(string)
-	if !ok {
-		return nil, errors.New("kid not a string")
+	var presentationRequest *presentationstorage.StoredRequest
+	if request.PresentationRequestID != "" {
+		gotRequest, err := s.validPresentationRequest(ctx, request.PresentationRequestID, request.Submission.DefinitionID)
+		if err != nil {
+			return nil, err
+		}
+		presentationRequest = gotRequest
 	}
 
-	// verify the token with the did by first resolving the did and getting the public key and next verifying the token
-	if err = didint.VerifyTokenFromDID(ctx, s.resolver, vp.Holder, kid, request.SubmissionJWT); err != nil {
-		return nil, errors.Wrapf(err, "verifying token from did<%s> with kid<%s>", vp.Holder, kid)
+	if request.SubmissionJWT != "" {
+		headers, token, vp, err := keyaccess.ParseVerifiablePresentationFromJWT(request.SubmissionJWT)
+		if err != nil {
+			return nil, errors.Wrap(err, "parsing vp from jwt")
+		}
+
+		gotKID, ok := headers.Get(jws.KeyIDKey)
+		if !ok {
+			return nil, errors.New("kid not found in token headers")
+		}
+		kid, ok := gotKID.(string)
+		if !ok {
+			return nil, errors.New("kid not a string")
+		}
+
+		// verify the token with the did by first resolving the did and getting the public key and next verifying the token
+		if err = didint.VerifyTokenFromDID(ctx, s.resolver, vp.Holder, kid, request.SubmissionJWT); err != nil {
+			return nil, errors.Wrapf(err, "verifying token from did<%s> with kid<%s>", vp.Holder, kid)
+		}
+
+		if presentationRequest != nil {
+			if err = jwtv2.Validate(token, jwtv2.WithClaimValue(nonceClaim, presentationRequest.Nonce), jwtv2.WithAudience(presentationRequest.IssuerDID)); err != nil {
+				return nil, errors.Wrapf(err, "submission is not bound to presentation request<%s>", presentationRequest.ID)
+			}
+		}
+	} else {
+		// verify the data integrity proof against the holder's did
+		var opts keyaccess.PresentationProofOptions
+		if presentationRequest != nil {
+			opts = keyaccess.PresentationProofOptions{Challenge: presentationRequest.Nonce, Domain: presentationRequest.IssuerDID}
+		}
+		if err := s.verifyDataIntegrityPresentation(ctx, request.Presentation, opts); err != nil {
+			return nil, errors.Wrapf(err, "verifying data integrity presentation from did<%s>", request.Presentation.Holder)
+		}
 	}
 
+	var err error
 	if _, err = s.storage.GetSubmission(ctx, request.Submission.ID); !errors.Is(err, presentationstorage.ErrSubmissionNotFound) {
 		return nil, errors.Errorf("submission with id %s already present", request.Submission.ID)
 	}
@@ -205,6 +242,13 @@ func (s Service) CreateSubmission(ctx context.Context, request model.CreateSubmi
 		return nil, errors.Wrap(err, "verifying presentation submission vp")
 	}
 
+	// the request is consumed last, so that a submission that fails verification doesn't use it up
+	if presentationRequest != nil {
+		if _, err = s.storage.UseRequest(ctx, presentationRequest.ID, request.Submission.ID); err != nil {
+			return nil, errors.Wrapf(err, "using presentation request<%s>", presentationRequest.ID)
+		}
+	}
+
 	storedSubmission := presentationstorage.StoredSubmission{
 		Status:                 submission.StatusPending,
 		VerifiablePresentation: request.Presentation,
@@ -234,6 +278,39 @@ func (s Service) CreateSubmission(ctx context.Context, request model.CreateSubmi
 	}, nil
 }
 
+// validPresentationRequest returns the presentation request with the given ID, as long as it can still be answered by
+// a submission for the given definition.
+func (s Service) validPresentationRequest(ctx context.Context, id, definitionID string) (*presentationstorage.StoredRequest, error) {
+	storedRequest, err := s.usableRequest(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if storedRequest.PresentationDefinitionID != definitionID {
+		return nil, errors.Errorf("submission's definition<%s> does not match presentation request's definition<%s>", definitionID, storedRequest.PresentationDefinitionID)
+	}
+	return storedRequest, nil
+}
+
+// usableRequest returns the presentation request with the given id when it can still be answered, which is when it
+// has neither been used nor expired.
+func (s Service) usableRequest(ctx context.Context, id string) (*presentationstorage.StoredRequest, error) {
+	storedRequest, err := s.storage.GetRequest(ctx, id)
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting presentation request<%s>", id)
+	}
+	if storedRequest.Used {
+		return nil, errors.Wrapf(presentationstorage.ErrRequestAlreadyUsed, "presentation request<%s>", id)
+	}
+	expiration, err := time.Parse(time.RFC3339, storedRequest.Expiration)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing expiration time")
+	}
+	if time.Now().After(expiration) {
+		return nil, errors.Wrapf(presentationstorage.ErrRequestExpired, "presentation request<%s> expired at %s", id, storedRequest.Expiration)
+	}
+	return storedRequest, nil
+}
+
 func (s Service) GetSubmission(ctx context.Context, request model.GetSubmissionRequest) (*model.GetSubmissionResponse, error) {
 	logrus.Debugf("getting presentation submission: %s", request.ID)
 
@@ -268,7 +345,18 @@ func (s Service) ReviewSubmission(ctx context.Context, request model.ReviewSubmi
 		return nil, errors.Wrap(err, "invalid request")
 	}
 
-	updatedSubmission, _, err := s.storage.UpdateSubmission(ctx, request.ID, request.Approved, request.Reason,
+	approved, reason := request.Approved, request.Reason
+	if approved && request.RequireTrustedIssuers {
+		untrustedReason, err := s.checkSubmissionIssuers(ctx, request.ID)
+		if err != nil {
+			return nil, errors.Wrap(err, "checking submission issuers")
+		}
+		if untrustedReason != "" {
+			approved, reason = false, untrustedReason
+		}
+	}
+
+	updatedSubmission, _, err := s.storage.UpdateSubmission(ctx, request.ID, approved, reason,
 		submission.IDFromSubmissionID(request.ID))
 	if err != nil {
 		return nil, errors.Wrap(err, "updating submission")
@@ -278,6 +366,28 @@ func (s Service) ReviewSubmission(ctx context.Context, request model.ReviewSubmi
 	return &m, nil
 }
 
+// checkSubmissionIssuers returns a non-empty reason when any credential in the submission was issued by an issuer
+// that the trust registry does not trust for that credential.
+func (s Service) checkSubmissionIssuers(ctx context.Context, id string) (string, error) {
+	storedSubmission, err := s.storage.GetSubmission(ctx, id)
+	if err != nil {
+		return "", errors.Wrap(err, "fetching submission")
+	}
+	credContainers, err := credential.NewCredentialContainerFromArray(storedSubmission.VerifiablePresentation.VerifiableCredential)
+	if err != nil {
+		return "", errors.Wrap(err, "parsing credentials in submission")
+	}
+	for _, container := range credContainers {
+		if err = trust.VerifyCredentialIssuer(ctx, s.trustStorage, *container.Credential); err != nil {
+			if errors.Is(err, trust.ErrIssuerNotTrusted) {
+				return fmt.Sprintf("credential<%s>: %s", container.ID, err.Error()), nil
+			}
+			return "", err
+		}
+	}
+	return "", nil
+}
+
 func (s Service) ListDefinitions(ctx context.Context) (*model.ListDefinitionsResponse, error) {
 	logrus.Debug("listing presentation definitions")
 
@@ -311,7 +421,9 @@ func (s Service) CreateRequest(ctx context.Context, req model.CreateRequestReque
 	}
 
 	requestID := uuid.NewString()
+	nonce := uuid.NewString()
 	token, err := jwt.NewBuilder().Claim("presentation_definition", pd.PresentationDefinition).
+		Claim(nonceClaim, nonce).
 		Audience(request.Audience).
 		Expiration(request.Expiration).
 		Issuer(request.IssuerDID).
@@ -334,6 +446,7 @@ func (s Service) CreateRequest(ctx context.Context, req model.CreateRequestReque
 		IssuerKID:                 request.IssuerKID,
 		PresentationDefinitionID:  request.PresentationDefinitionID,
 		PresentationDefinitionJWT: signedToken.String(),
+		Nonce:                     nonce,
 	}
 	if err := s.storage.StoreRequest(ctx, stored); err != nil {
 		return nil, errors.Wrap(err, "storing signed document")
@@ -378,5 +491,7 @@ func serviceModel(storedRequest *presentationstorage.StoredRequest) (*model.Requ
 		IssuerKID:                 storedRequest.IssuerKID,
 		PresentationDefinitionID:  storedRequest.PresentationDefinitionID,
 		PresentationDefinitionJWT: keyaccess.JWT(storedRequest.PresentationDefinitionJWT),
+		Nonce:                     storedRequest.Nonce,
+		Used:                      storedRequest.Used,
 	}, nil
 }
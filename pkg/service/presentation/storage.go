@@ -61,6 +61,43 @@ func (ps *Storage) DeleteRequest(ctx context.Context, id string) error {
 	return nil
 }
 
+func (ps *Storage) UseRequest(ctx context.Context, id string, submissionID string) (*prestorage.StoredRequest, error) {
+	watchKeys := []storage.WatchKey{{Namespace: presentationRequestNamespace, Key: id}}
+	result, err := ps.db.Execute(ctx, func(ctx context.Context, tx storage.Tx) (any, error) {
+		jsonBytes, err := tx.Read(ctx, presentationRequestNamespace, id)
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading presentation request: %s", id)
+		}
+		if len(jsonBytes) == 0 {
+			return nil, errors.Errorf("presentation request not found with id: %s", id)
+		}
+		var stored prestorage.StoredRequest
+		if err = json.Unmarshal(jsonBytes, &stored); err != nil {
+			return nil, errors.Wrapf(err, "unmarshalling presentation request: %s", id)
+		}
+		if stored.Used {
+			return nil, prestorage.ErrRequestAlreadyUsed
+		}
+		stored.Used = true
+		stored.SubmissionID = submissionID
+		if jsonBytes, err = json.Marshal(stored); err != nil {
+			return nil, errors.Wrapf(err, "marshalling presentation request: %s", id)
+		}
+		if err = tx.Write(ctx, presentationRequestNamespace, id, jsonBytes); err != nil {
+			return nil, errors.Wrapf(err, "writing presentation request: %s", id)
+		}
+		return &stored, nil
+	}, watchKeys)
+	if err != nil {
+		return nil, err
+	}
+	stored, ok := result.(*prestorage.StoredRequest)
+	if !ok {
+		return nil, errors.New("casting to stored presentation request")
+	}
+	return stored, nil
+}
+
 func (ps *Storage) UpdateSubmission(ctx context.Context, id string, approved bool, reason string, opID string) (prestorage.StoredSubmission, opstorage.StoredOperation, error) {
 	m := map[string]any{
 		"status": opsubmission.StatusDenied,
@@ -61,10 +61,24 @@ type StoredRequest struct {
 	IssuerKID                 string   `json:"issuerKid"`
 	PresentationDefinitionID  string   `json:"presentationDefinitionId"`
 	PresentationDefinitionJWT string   `json:"presentationRequestJwt"`
+
+	// Nonce that a submission answering this request must be bound to.
+	Nonce string `json:"nonce,omitempty"`
+
+	// Set once a submission answered this request. Requests can only be answered once.
+	Used         bool   `json:"used,omitempty"`
+	SubmissionID string `json:"submissionId,omitempty"`
 }
 
 type RequestStorage interface {
 	StoreRequest(context.Context, StoredRequest) error
 	GetRequest(context.Context, string) (*StoredRequest, error)
 	DeleteRequest(context.Context, string) error
+	// UseRequest atomically marks the request as answered by the given submission. Returns ErrRequestAlreadyUsed
+	// when the request was already answered.
+	UseRequest(ctx context.Context, id string, submissionID string) (*StoredRequest, error)
 }
+
+var ErrRequestAlreadyUsed = errors.New("presentation request has already been used")
+
+var ErrRequestExpired = errors.New("presentation request has expired")
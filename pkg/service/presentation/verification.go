@@ -0,0 +1,199 @@
+package presentation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	credsdk "github.com/TBD54566975/ssi-sdk/credential"
+	"github.com/TBD54566975/ssi-sdk/credential/exchange"
+	"github.com/lestrrat-go/jwx/v2/jws"
+	"github.com/lestrrat-go/jwx/v2/jwt"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/tbd54566975/ssi-service/internal/credential"
+	didint "github.com/tbd54566975/ssi-service/internal/did"
+	"github.com/tbd54566975/ssi-service/internal/keyaccess"
+	"github.com/tbd54566975/ssi-service/pkg/service/presentation/model"
+)
+
+// nonceClaim carries the verifier's challenge in a presentation JWT.
+const nonceClaim = "nonce"
+
+// VerifyPresentation verifies a presentation without persisting it. The holder's signature is checked against the
+// holder's DID, each embedded credential is verified on its own, and, when a definition is given, the presentation
+// is checked to be a presentation submission that fulfills it. Failed checks are reported in the response; an error
+// is only returned when the presentation cannot be parsed.
+func (s Service) VerifyPresentation(ctx context.Context, request model.VerifyPresentationRequest) (*model.VerifyPresentationResponse, error) {
+	if !request.IsValid() {
+		return nil, errors.New("invalid verify presentation request: exactly one of a presentation or a presentation JWT must be provided")
+	}
+
+	var vp *credsdk.VerifiablePresentation
+	var holderErr error
+	if request.PresentationJWT != nil {
+		headers, token, parsed, err := keyaccess.ParseVerifiablePresentationFromJWT(*request.PresentationJWT)
+		if err != nil {
+			return nil, errors.Wrap(err, "parsing vp from jwt")
+		}
+		vp = parsed
+		if vp.Holder == "" {
+			vp.Holder = token.Issuer()
+		}
+		holderErr = s.verifyPresentationJWT(ctx, vp.Holder, headers, token, *request.PresentationJWT, request.Challenge, request.Domain)
+	} else {
+		vp = request.DataIntegrityPresentation
+		holderErr = s.verifyDataIntegrityPresentation(ctx, *vp, keyaccess.PresentationProofOptions{
+			Challenge: request.Challenge,
+			Domain:    request.Domain,
+		})
+	}
+
+	resp := model.VerifyPresentationResponse{
+		Verified:       true,
+		Holder:         vp.Holder,
+		HolderVerified: holderErr == nil,
+		Credentials:    make([]model.CredentialVerificationResult, 0, len(vp.VerifiableCredential)),
+	}
+	if holderErr != nil {
+		resp.Verified = false
+		resp.Reason = holderErr.Error()
+	}
+
+	for i, cred := range vp.VerifiableCredential {
+		result := s.verifyPresentedCredential(ctx, i, cred)
+		if !result.Verified && resp.Verified {
+			resp.Verified = false
+			resp.Reason = fmt.Sprintf("credential at index %d: %s", i, result.Reason)
+		}
+		resp.Credentials = append(resp.Credentials, result)
+	}
+
+	if request.PresentationDefinition != nil {
+		_, err := exchange.VerifyPresentationSubmissionVP(*request.PresentationDefinition, *vp)
+		definitionVerified := err == nil
+		resp.DefinitionVerified = &definitionVerified
+		if err != nil && resp.Verified {
+			resp.Verified = false
+			resp.Reason = errors.Wrap(err, "verifying presentation submission vp").Error()
+		}
+	}
+
+	logrus.Debugf("verified presentation from holder<%s>: %t", vp.Holder, resp.Verified)
+	return &resp, nil
+}
+
+// verifyPresentationJWT verifies the token's signature against the holder's DID, which must be the DID of the token's
+// issuer and kid. When set, the challenge must match the token's nonce claim and the domain must be one of its
+// audiences.
+func (s Service) verifyPresentationJWT(ctx context.Context, holder string, headers jws.Headers, token jwt.Token, presentationJWT keyaccess.JWT, challenge, domain string) error {
+	if holder == "" {
+		return errors.New("presentation has no holder")
+	}
+	issuer := token.Issuer()
+	if issuer != "" && issuer != holder {
+		return errors.Errorf("presentation holder<%s> does not match token issuer<%s>", holder, issuer)
+	}
+	// the holder of the parsed presentation is taken from the issuer, so the holder the presentation claims is checked
+	// on the claim itself
+	if claimed := claimedHolder(token); claimed != "" && claimed != holder {
+		return errors.Errorf("presentation holder<%s> does not match token issuer<%s>", claimed, holder)
+	}
+	gotKID, ok := headers.Get(jws.KeyIDKey)
+	if !ok {
+		return errors.New("kid not found in token headers")
+	}
+	kid, ok := gotKID.(string)
+	if !ok {
+		return errors.New("kid not a string")
+	}
+	if kidDID, _, found := strings.Cut(kid, "#"); found && kidDID != "" && kidDID != holder {
+		return errors.Errorf("presentation holder<%s> does not match the DID of kid<%s>", holder, kid)
+	}
+	if err := didint.VerifyTokenFromDID(ctx, s.resolver, holder, kid, presentationJWT); err != nil {
+		return errors.Wrapf(err, "verifying token from did<%s> with kid<%s>", holder, kid)
+	}
+	opts := []jwt.ValidateOption{}
+	if challenge != "" {
+		opts = append(opts, jwt.WithClaimValue(nonceClaim, challenge))
+	}
+	if domain != "" {
+		opts = append(opts, jwt.WithAudience(domain))
+	}
+	if err := jwt.Validate(token, opts...); err != nil {
+		return errors.Wrap(err, "validating presentation JWT claims")
+	}
+	return nil
+}
+
+// claimedHolder returns the holder set in the token's vp claim, if any.
+func claimedHolder(token jwt.Token) string {
+	vpClaim, ok := token.Get(credsdk.VPJWTProperty)
+	if !ok {
+		return ""
+	}
+	vp, ok := vpClaim.(map[string]any)
+	if !ok {
+		return ""
+	}
+	holder, _ := vp["holder"].(string)
+	return holder
+}
+
+// verifyDataIntegrityPresentation verifies the presentation's authentication proof against the holder's DID, and
+// that the proof is bound to the challenge and domain in opts.
+func (s Service) verifyDataIntegrityPresentation(ctx context.Context, vp credsdk.VerifiablePresentation, opts keyaccess.PresentationProofOptions) error {
+	if vp.Holder == "" {
+		return errors.New("presentation has no holder")
+	}
+	if vp.Proof == nil {
+		return errors.New("presentation has no proof")
+	}
+	proof, err := keyaccess.PresentationProofFromGenericProof(*vp.Proof)
+	if err != nil {
+		return err
+	}
+	if proof.VerificationMethod == "" {
+		return errors.New("proof has no verification method")
+	}
+
+	pubKey, err := didint.ResolveKeyForDID(ctx, s.resolver, vp.Holder, proof.VerificationMethod)
+	if err != nil {
+		return err
+	}
+	verifier, err := keyaccess.NewDataIntegrityKeyAccessVerifier(vp.Holder, proof.VerificationMethod, pubKey)
+	if err != nil {
+		return errors.Wrapf(err, "creating verifier for kid<%s>", proof.VerificationMethod)
+	}
+	return verifier.VerifyVerifiablePresentation(&vp, opts)
+}
+
+// verifyPresentedCredential verifies a single entry of a presentation's verifiableCredential property.
+func (s Service) verifyPresentedCredential(ctx context.Context, index int, cred any) model.CredentialVerificationResult {
+	result := model.CredentialVerificationResult{Index: index}
+	containers, err := credential.NewCredentialContainerFromArray([]any{cred})
+	if err != nil {
+		result.Reason = err.Error()
+		return result
+	}
+	container := containers[0]
+	result.ID = container.ID
+	if container.Credential != nil {
+		if issuer, ok := container.Credential.Issuer.(string); ok {
+			result.Issuer = issuer
+		}
+	}
+
+	if container.HasJWTCredential() {
+		err = s.verifier.VerifyJWTCredential(ctx, *container.CredentialJWT)
+	} else {
+		err = s.verifier.VerifyDataIntegrityCredential(ctx, *container.Credential)
+	}
+	if err != nil {
+		result.Reason = err.Error()
+		return result
+	}
+	result.Verified = true
+	return result
+}
@@ -6,7 +6,6 @@ import (
 	"time"
 
 	"github.com/TBD54566975/ssi-sdk/credential/schema"
-	didsdk "github.com/TBD54566975/ssi-sdk/did"
 	"github.com/TBD54566975/ssi-sdk/did/resolution"
 	schemalib "github.com/TBD54566975/ssi-sdk/schema"
 	sdkutil "github.com/TBD54566975/ssi-sdk/util"
@@ -18,6 +17,7 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/tbd54566975/ssi-service/config"
+	didint "github.com/tbd54566975/ssi-service/internal/did"
 	"github.com/tbd54566975/ssi-service/internal/keyaccess"
 	"github.com/tbd54566975/ssi-service/pkg/service/framework"
 	"github.com/tbd54566975/ssi-service/pkg/service/keystore"
@@ -143,7 +143,7 @@ func prepareJSONSchema(id, name string, s schema.JSONSchema) schema.JSONSchema {
 
 // signSchemaJWT signs a schema after the key associated with the provided author for the schema as a JWT
 func (s Service) signSchemaJWT(ctx context.Context, authorKID string, schema schema.VCJSONSchema) (*keyaccess.JWT, error) {
-	gotKey, err := s.keyStore.GetKey(ctx, keystore.GetKeyRequest{ID: authorKID})
+	gotKey, err := s.keyStore.GetSigningKey(ctx, keystore.GetKeyRequest{ID: authorKID})
 	if err != nil {
 		return nil, sdkutil.LoggingErrorMsgf(err, "could not get key for signing schema for authorKID<%s>", authorKID)
 	}
@@ -216,7 +216,7 @@ func (s Service) verifySchemaJWT(ctx context.Context, token keyaccess.JWT) (*sch
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to resolve schema author's did: %s", parsedSchema.Author)
 	}
-	pubKey, err := didsdk.GetKeyFromVerificationMethod(resolved.Document, kid)
+	pubKey, err := didint.KeyFromVerificationMethod(resolved.Document, kid)
 	if err != nil {
 		return nil, sdkutil.LoggingErrorMsg(err, "could not get verification information from schema")
 	}
@@ -15,6 +15,8 @@ import (
 	"github.com/tbd54566975/ssi-service/pkg/service/operation"
 	"github.com/tbd54566975/ssi-service/pkg/service/presentation"
 	"github.com/tbd54566975/ssi-service/pkg/service/schema"
+	"github.com/tbd54566975/ssi-service/pkg/service/trust"
+	"github.com/tbd54566975/ssi-service/pkg/service/wallet"
 	"github.com/tbd54566975/ssi-service/pkg/service/webhook"
 	"github.com/tbd54566975/ssi-service/pkg/storage"
 )
@@ -30,6 +32,8 @@ type SSIService struct {
 	Presentation *presentation.Service
 	Operation    *operation.Service
 	Webhook      *webhook.Service
+	Trust        *trust.Service
+	Wallet       *wallet.Service
 	storage      storage.ServiceStorage
 }
 
@@ -74,6 +78,12 @@ func validateServiceConfig(config config.ServicesConfig) error {
 	if config.WebhookConfig.IsEmpty() {
 		return fmt.Errorf("%s no config provided", framework.Webhook)
 	}
+	if config.TrustConfig.IsEmpty() {
+		return fmt.Errorf("%s no config provided", framework.Trust)
+	}
+	if config.WalletConfig.IsEmpty() {
+		return fmt.Errorf("%s no config provided", framework.Wallet)
+	}
 	return nil
 }
 
@@ -105,6 +115,11 @@ func instantiateServices(config config.ServicesConfig) (*SSIService, error) {
 		return nil, sdkutil.LoggingErrorMsg(err, "could not instantiate the schema service")
 	}
 
+	trustService, err := trust.NewTrustService(config.TrustConfig, storageProvider)
+	if err != nil {
+		return nil, sdkutil.LoggingErrorMsg(err, "could not instantiate the trust service")
+	}
+
 	issuanceService, err := issuance.NewIssuanceService(config.IssuanceServiceConfig, storageProvider)
 	if err != nil {
 		return nil, sdkutil.LoggingErrorMsg(err, "could not instantiate the issuance service")
@@ -125,7 +140,12 @@ func instantiateServices(config config.ServicesConfig) (*SSIService, error) {
 		return nil, sdkutil.LoggingErrorMsg(err, "could not instantiate the presentation service")
 	}
 
-	operationService, err := operation.NewOperationService(storageProvider)
+	walletService, err := wallet.NewWalletService(config.WalletConfig, storageProvider, keyStoreService, didResolver, schemaService)
+	if err != nil {
+		return nil, sdkutil.LoggingErrorMsg(err, "could not instantiate the wallet service")
+	}
+
+	operationService, err := operation.NewOperationService(storageProvider, didService)
 	if err != nil {
 		return nil, sdkutil.LoggingErrorMsg(err, "could not instantiate the operation service")
 	}
@@ -140,6 +160,8 @@ func instantiateServices(config config.ServicesConfig) (*SSIService, error) {
 		Presentation: presentationService,
 		Operation:    operationService,
 		Webhook:      webhookService,
+		Trust:        trustService,
+		Wallet:       walletService,
 		storage:      storageProvider,
 	}, nil
 }
@@ -156,6 +178,8 @@ func (s *SSIService) GetServices() []framework.Service {
 		s.Presentation,
 		s.Operation,
 		s.Webhook,
+		s.Trust,
+		s.Wallet,
 	}
 }
 
@@ -0,0 +1,132 @@
+package trust
+
+import (
+	"time"
+
+	"github.com/TBD54566975/ssi-sdk/credential"
+	"github.com/TBD54566975/ssi-sdk/util"
+	"github.com/pkg/errors"
+)
+
+// TrustedIssuer is an entry in the trust registry. It states that credentials issued by IssuerDID are trusted when
+// they match one of the listed schemas or credential types, for as long as the entry's validity window is open.
+type TrustedIssuer struct {
+	// ID of this entry.
+	// This is an output only field.
+	ID string `json:"id"`
+
+	// DID of the issuer that is trusted.
+	IssuerDID string `json:"issuerDid" validate:"required"`
+
+	// Human-readable name for the issuer.
+	Name string `json:"name,omitempty"`
+
+	// IDs of the credential schemas the issuer is trusted for. Matched against `credentialSchema.id`.
+	Schemas []string `json:"schemas,omitempty"`
+
+	// Credential types the issuer is trusted for. Matched against the credential's `type` values, ignoring the
+	// `VerifiableCredential` base type.
+	// When both Schemas and CredentialTypes are empty, the issuer is trusted for any credential.
+	CredentialTypes []string `json:"credentialTypes,omitempty"`
+
+	// Time from which the entry is in effect. When absent, the entry is in effect from its creation.
+	ValidFrom *time.Time `json:"validFrom,omitempty"`
+
+	// Time at which the entry stops being in effect. When absent, the entry does not expire.
+	ValidUntil *time.Time `json:"validUntil,omitempty"`
+}
+
+func (t TrustedIssuer) IsValid() error {
+	if err := util.IsValidStruct(t); err != nil {
+		return err
+	}
+	if t.ValidFrom != nil && t.ValidUntil != nil && !t.ValidUntil.After(*t.ValidFrom) {
+		return errors.New("validUntil must be after validFrom")
+	}
+	return nil
+}
+
+// IsActive returns whether the entry's validity window contains the given time.
+func (t TrustedIssuer) IsActive(at time.Time) bool {
+	if t.ValidFrom != nil && at.Before(*t.ValidFrom) {
+		return false
+	}
+	if t.ValidUntil != nil && !at.Before(*t.ValidUntil) {
+		return false
+	}
+	return true
+}
+
+// Covers returns whether the entry's scope includes a credential with the given schema and types.
+func (t TrustedIssuer) Covers(schemaID string, types []string) bool {
+	if len(t.Schemas) == 0 && len(t.CredentialTypes) == 0 {
+		return true
+	}
+	if schemaID != "" {
+		for _, s := range t.Schemas {
+			if s == schemaID {
+				return true
+			}
+		}
+	}
+	for _, credType := range types {
+		if credType == credential.VerifiableCredentialType {
+			continue
+		}
+		for _, ct := range t.CredentialTypes {
+			if ct == credType {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+type CreateTrustedIssuerRequest struct {
+	TrustedIssuer TrustedIssuer `json:"trustedIssuer" validate:"required"`
+}
+
+type CreateTrustedIssuerResponse struct {
+	TrustedIssuer TrustedIssuer `json:"trustedIssuer"`
+}
+
+type GetTrustedIssuerRequest struct {
+	ID string `json:"id" validate:"required"`
+}
+
+type GetTrustedIssuerResponse struct {
+	TrustedIssuer TrustedIssuer `json:"trustedIssuer"`
+}
+
+type UpdateTrustedIssuerRequest struct {
+	ID            string        `json:"id" validate:"required"`
+	TrustedIssuer TrustedIssuer `json:"trustedIssuer" validate:"required"`
+}
+
+type UpdateTrustedIssuerResponse struct {
+	TrustedIssuer TrustedIssuer `json:"trustedIssuer"`
+}
+
+type ListTrustedIssuersRequest struct {
+	// When present, only entries for this issuer DID are returned.
+	IssuerDID string `json:"issuerDid,omitempty"`
+}
+
+type ListTrustedIssuersResponse struct {
+	TrustedIssuers []TrustedIssuer `json:"trustedIssuers"`
+}
+
+type DeleteTrustedIssuerRequest struct {
+	ID string `json:"id" validate:"required"`
+}
+
+type VerifyIssuerTrustRequest struct {
+	IssuerDID       string   `json:"issuerDid" validate:"required"`
+	SchemaID        string   `json:"schemaId,omitempty"`
+	CredentialTypes []string `json:"credentialTypes,omitempty"`
+}
+
+type VerifyIssuerTrustResponse struct {
+	Trusted bool   `json:"trusted"`
+	Reason  string `json:"reason,omitempty"`
+}
@@ -0,0 +1,188 @@
+package trust
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/TBD54566975/ssi-sdk/credential"
+	sdkutil "github.com/TBD54566975/ssi-sdk/util"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/tbd54566975/ssi-service/config"
+	credint "github.com/tbd54566975/ssi-service/internal/credential"
+	"github.com/tbd54566975/ssi-service/pkg/service/framework"
+	"github.com/tbd54566975/ssi-service/pkg/storage"
+)
+
+type Service struct {
+	config  config.TrustServiceConfig
+	storage *Storage
+}
+
+func NewTrustService(config config.TrustServiceConfig, s storage.ServiceStorage) (*Service, error) {
+	trustStorage, err := NewTrustStorage(s)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating trust storage")
+	}
+	service := Service{
+		config:  config,
+		storage: trustStorage,
+	}
+	if !service.Status().IsReady() {
+		return nil, errors.New(service.Status().Message)
+	}
+	return &service, nil
+}
+
+func (s *Service) Type() framework.Type {
+	return framework.Trust
+}
+
+func (s *Service) Status() framework.Status {
+	ae := sdkutil.NewAppendError()
+	if s.storage == nil {
+		ae.AppendString("no storage configured")
+	}
+	if !ae.IsEmpty() {
+		return framework.Status{
+			Status:  framework.StatusNotReady,
+			Message: fmt.Sprintf("trust service is not ready: %s", ae.Error().Error()),
+		}
+	}
+	return framework.Status{Status: framework.StatusReady}
+}
+
+func (s *Service) Config() config.TrustServiceConfig {
+	return s.config
+}
+
+func (s *Service) CreateTrustedIssuer(ctx context.Context, request CreateTrustedIssuerRequest) (*CreateTrustedIssuerResponse, error) {
+	logrus.Debugf("creating trusted issuer: %+v", request)
+
+	if err := request.TrustedIssuer.IsValid(); err != nil {
+		return nil, errors.Wrap(err, "invalid trusted issuer")
+	}
+
+	stored := StoredTrustedIssuer{
+		TrustedIssuer: request.TrustedIssuer,
+		CreatedAt:     time.Now().Format(time.RFC3339),
+	}
+	stored.TrustedIssuer.ID = uuid.NewString()
+	if err := s.storage.StoreTrustedIssuer(ctx, stored); err != nil {
+		return nil, errors.Wrap(err, "storing trusted issuer")
+	}
+	return &CreateTrustedIssuerResponse{TrustedIssuer: stored.TrustedIssuer}, nil
+}
+
+func (s *Service) GetTrustedIssuer(ctx context.Context, request GetTrustedIssuerRequest) (*GetTrustedIssuerResponse, error) {
+	logrus.Debugf("getting trusted issuer: %s", request.ID)
+
+	stored, err := s.storage.GetTrustedIssuer(ctx, request.ID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting trusted issuer with id: %s", request.ID)
+	}
+	return &GetTrustedIssuerResponse{TrustedIssuer: stored.TrustedIssuer}, nil
+}
+
+// UpdateTrustedIssuer replaces the scope and validity window of an existing entry. The entry's ID cannot be changed.
+func (s *Service) UpdateTrustedIssuer(ctx context.Context, request UpdateTrustedIssuerRequest) (*UpdateTrustedIssuerResponse, error) {
+	logrus.Debugf("updating trusted issuer: %+v", request)
+
+	if err := request.TrustedIssuer.IsValid(); err != nil {
+		return nil, errors.Wrap(err, "invalid trusted issuer")
+	}
+
+	stored, err := s.storage.GetTrustedIssuer(ctx, request.ID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting trusted issuer with id: %s", request.ID)
+	}
+	stored.TrustedIssuer = request.TrustedIssuer
+	stored.TrustedIssuer.ID = request.ID
+	stored.UpdatedAt = time.Now().Format(time.RFC3339)
+	if err = s.storage.StoreTrustedIssuer(ctx, *stored); err != nil {
+		return nil, errors.Wrap(err, "storing trusted issuer")
+	}
+	return &UpdateTrustedIssuerResponse{TrustedIssuer: stored.TrustedIssuer}, nil
+}
+
+func (s *Service) ListTrustedIssuers(ctx context.Context, request ListTrustedIssuersRequest) (*ListTrustedIssuersResponse, error) {
+	logrus.Debug("listing trusted issuers")
+
+	var stored []StoredTrustedIssuer
+	var err error
+	if request.IssuerDID != "" {
+		stored, err = s.storage.GetTrustedIssuersByDID(ctx, request.IssuerDID)
+	} else {
+		stored, err = s.storage.ListTrustedIssuers(ctx)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "fetching trusted issuers from storage")
+	}
+
+	issuers := make([]TrustedIssuer, 0, len(stored))
+	for _, st := range stored {
+		issuers = append(issuers, st.TrustedIssuer)
+	}
+	return &ListTrustedIssuersResponse{TrustedIssuers: issuers}, nil
+}
+
+func (s *Service) DeleteTrustedIssuer(ctx context.Context, request DeleteTrustedIssuerRequest) error {
+	logrus.Debugf("deleting trusted issuer: %s", request.ID)
+
+	if err := s.storage.DeleteTrustedIssuer(ctx, request.ID); err != nil {
+		return errors.Wrapf(err, "deleting trusted issuer with id: %s", request.ID)
+	}
+	return nil
+}
+
+// VerifyIssuerTrust checks whether the registry currently trusts the given issuer for the given schema or types.
+func (s *Service) VerifyIssuerTrust(ctx context.Context, request VerifyIssuerTrustRequest) (*VerifyIssuerTrustResponse, error) {
+	if err := sdkutil.IsValidStruct(request); err != nil {
+		return nil, errors.Wrap(err, "invalid verify issuer trust request")
+	}
+	if err := verifyIssuerTrust(ctx, s.storage, request.IssuerDID, request.SchemaID, request.CredentialTypes); err != nil {
+		if errors.Is(err, ErrIssuerNotTrusted) {
+			return &VerifyIssuerTrustResponse{Trusted: false, Reason: err.Error()}, nil
+		}
+		return nil, err
+	}
+	return &VerifyIssuerTrustResponse{Trusted: true}, nil
+}
+
+// ErrIssuerNotTrusted is returned when no active registry entry covers a credential's issuer and schema.
+var ErrIssuerNotTrusted = errors.New("issuer is not trusted")
+
+// VerifyCredentialIssuer returns ErrIssuerNotTrusted (wrapped) unless the credential's issuer has an active entry in
+// the registry for the credential's schema or types. It is used by other services that read the registry directly
+// from storage.
+func VerifyCredentialIssuer(ctx context.Context, s *Storage, cred credential.VerifiableCredential) error {
+	issuerDID, err := credint.IssuerID(cred.Issuer)
+	if err != nil {
+		return err
+	}
+	var schemaID string
+	if cred.CredentialSchema != nil {
+		schemaID = cred.CredentialSchema.ID
+	}
+	return verifyIssuerTrust(ctx, s, issuerDID, schemaID, credint.Types(cred.Type))
+}
+
+func verifyIssuerTrust(ctx context.Context, s *Storage, issuerDID, schemaID string, types []string) error {
+	entries, err := s.GetTrustedIssuersByDID(ctx, issuerDID)
+	if err != nil {
+		return errors.Wrapf(err, "fetching trusted issuer entries for issuer<%s>", issuerDID)
+	}
+	now := time.Now()
+	for _, entry := range entries {
+		if entry.TrustedIssuer.IsActive(now) && entry.TrustedIssuer.Covers(schemaID, types) {
+			return nil
+		}
+	}
+	if schemaID != "" {
+		return errors.Wrapf(ErrIssuerNotTrusted, "issuer<%s> for schema<%s>", issuerDID, schemaID)
+	}
+	return errors.Wrapf(ErrIssuerNotTrusted, "issuer<%s> for types<%v>", issuerDID, types)
+}
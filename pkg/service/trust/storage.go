@@ -0,0 +1,101 @@
+package trust
+
+import (
+	"context"
+
+	"github.com/goccy/go-json"
+	"github.com/pkg/errors"
+
+	"github.com/tbd54566975/ssi-service/pkg/storage"
+)
+
+const namespace = "trusted_issuer"
+
+type Storage struct {
+	db storage.ServiceStorage
+}
+
+func NewTrustStorage(s storage.ServiceStorage) (*Storage, error) {
+	if s == nil {
+		return nil, errors.New("storage cannot be nil")
+	}
+	return &Storage{db: s}, nil
+}
+
+type StoredTrustedIssuer struct {
+	TrustedIssuer TrustedIssuer `json:"trustedIssuer"`
+	CreatedAt     string        `json:"createdAt"`
+	UpdatedAt     string        `json:"updatedAt,omitempty"`
+}
+
+func (s Storage) StoreTrustedIssuer(ctx context.Context, issuer StoredTrustedIssuer) error {
+	if issuer.TrustedIssuer.ID == "" {
+		return errors.New("cannot store trusted issuer without an ID")
+	}
+	data, err := json.Marshal(issuer)
+	if err != nil {
+		return errors.Wrap(err, "marshalling trusted issuer")
+	}
+	return s.db.Write(ctx, namespace, issuer.TrustedIssuer.ID, data)
+}
+
+func (s Storage) GetTrustedIssuer(ctx context.Context, id string) (*StoredTrustedIssuer, error) {
+	if id == "" {
+		return nil, errors.New("cannot fetch trusted issuer without an ID")
+	}
+	data, err := s.db.Read(ctx, namespace, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading from db")
+	}
+	if len(data) == 0 {
+		return nil, errors.Errorf("trusted issuer not found with id: %s", id)
+	}
+	var stored StoredTrustedIssuer
+	if err = json.Unmarshal(data, &stored); err != nil {
+		return nil, errors.Wrap(err, "unmarshalling trusted issuer")
+	}
+	return &stored, nil
+}
+
+func (s Storage) ListTrustedIssuers(ctx context.Context) ([]StoredTrustedIssuer, error) {
+	m, err := s.db.ReadAll(ctx, namespace)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading all")
+	}
+	stored := make([]StoredTrustedIssuer, 0, len(m))
+	for k, v := range m {
+		var next StoredTrustedIssuer
+		if err = json.Unmarshal(v, &next); err != nil {
+			return nil, errors.Wrapf(err, "unmarshalling trusted issuer with key <%s>", k)
+		}
+		stored = append(stored, next)
+	}
+	return stored, nil
+}
+
+func (s Storage) GetTrustedIssuersByDID(ctx context.Context, issuerDID string) ([]StoredTrustedIssuer, error) {
+	if issuerDID == "" {
+		return nil, errors.New("cannot find trusted issuers without an issuer DID")
+	}
+	all, err := s.ListTrustedIssuers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var matching []StoredTrustedIssuer
+	for _, stored := range all {
+		if stored.TrustedIssuer.IssuerDID == issuerDID {
+			matching = append(matching, stored)
+		}
+	}
+	return matching, nil
+}
+
+func (s Storage) DeleteTrustedIssuer(ctx context.Context, id string) error {
+	if id == "" {
+		return nil
+	}
+	if err := s.db.Delete(ctx, namespace, id); err != nil {
+		return errors.Wrap(err, "deleting from db")
+	}
+	return nil
+}
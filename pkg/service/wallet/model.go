@@ -0,0 +1,178 @@
+package wallet
+
+import (
+	"time"
+
+	"github.com/TBD54566975/ssi-sdk/credential"
+	"github.com/TBD54566975/ssi-sdk/credential/exchange"
+	"github.com/TBD54566975/ssi-sdk/util"
+	"github.com/pkg/errors"
+
+	credint "github.com/tbd54566975/ssi-service/internal/credential"
+	"github.com/tbd54566975/ssi-service/internal/keyaccess"
+)
+
+// HeldCredential is a credential held by a DID this service manages.
+type HeldCredential struct {
+	// ID of the credential in the wallet.
+	ID string `json:"id"`
+
+	// DID that holds the credential.
+	HolderDID string `json:"holderDid"`
+
+	// Issuer, schema and types of the credential, denormalized for querying.
+	Issuer string   `json:"issuer"`
+	Schema string   `json:"schema,omitempty"`
+	Types  []string `json:"types,omitempty"`
+
+	// only one of these fields will be present
+	Credential    *credential.VerifiableCredential `json:"credential,omitempty"`
+	CredentialJWT *keyaccess.JWT                   `json:"credentialJwt,omitempty"`
+
+	// ID of the credential response the credential was received in, if any.
+	ResponseID string    `json:"responseId,omitempty"`
+	ReceivedAt time.Time `json:"receivedAt"`
+}
+
+// Container returns the held credential as a credential container.
+func (h HeldCredential) Container() credint.Container {
+	c := credint.Container{
+		Credential:    h.Credential,
+		CredentialJWT: h.CredentialJWT,
+	}
+	if h.Credential != nil {
+		c.ID = h.Credential.ID
+	}
+	return c
+}
+
+type StoreCredentialRequest struct {
+	HolderDID string `json:"holderDid" validate:"required"`
+
+	// only one of these fields should be present
+	DataIntegrityCredential *credential.VerifiableCredential `json:"credential,omitempty"`
+	CredentialJWT           *keyaccess.JWT                   `json:"credentialJwt,omitempty"`
+}
+
+func (r StoreCredentialRequest) IsValid() error {
+	if err := util.IsValidStruct(r); err != nil {
+		return err
+	}
+	if r.DataIntegrityCredential == nil && r.CredentialJWT == nil {
+		return errors.New("either a credential or a credential JWT must be provided")
+	}
+	if r.DataIntegrityCredential != nil && r.CredentialJWT != nil {
+		return errors.New("only one of credential or credential JWT can be provided")
+	}
+	return nil
+}
+
+type StoreCredentialResponse struct {
+	HeldCredential
+}
+
+type GetCredentialRequest struct {
+	ID string `json:"id" validate:"required"`
+}
+
+type GetCredentialResponse struct {
+	HeldCredential
+}
+
+// ListCredentialsRequest filters the held credentials. Empty fields match any value.
+type ListCredentialsRequest struct {
+	HolderDID string `json:"holderDid,omitempty"`
+	Issuer    string `json:"issuer,omitempty"`
+	Schema    string `json:"schema,omitempty"`
+	Type      string `json:"type,omitempty"`
+}
+
+type ListCredentialsResponse struct {
+	Credentials []HeldCredential `json:"credentials"`
+}
+
+type DeleteCredentialRequest struct {
+	ID string `json:"id" validate:"required"`
+}
+
+// StoreCredentialResponseRequest accepts a credential response, as produced by an issuer's manifest service, on
+// behalf of HolderDID.
+type StoreCredentialResponseRequest struct {
+	HolderDID string `json:"holderDid" validate:"required"`
+
+	// A JWT signed by the issuer whose claims are a credential response and the credentials it fulfills.
+	ResponseJWT keyaccess.JWT `json:"responseJwt" validate:"required"`
+}
+
+type StoreCredentialResponseResponse struct {
+	ResponseID  string           `json:"responseId"`
+	Credentials []HeldCredential `json:"credentials"`
+}
+
+type CreatePresentationRequest struct {
+	HolderDID string `json:"holderDid" validate:"required"`
+
+	// ID of the key in the keystore used to sign the presentation. Its controller must be HolderDID.
+	HolderKID string `json:"holderKid" validate:"required"`
+
+	// IDs of the held credentials to include.
+	CredentialIDs []string `json:"credentialIds" validate:"required,min=1"`
+
+	// Audience of the presentation, set as the `aud` claim of the JWT.
+	Audience string `json:"audience,omitempty"`
+}
+
+type CreatePresentationResponse struct {
+	Presentation    credential.VerifiablePresentation `json:"presentation"`
+	PresentationJWT keyaccess.JWT                     `json:"presentationJwt"`
+}
+
+// CreatePresentationSubmissionRequest asks the wallet to fulfill a presentation definition with the holder's
+// credentials. Exactly one of PresentationDefinitionID or PresentationRequestJWT must be present.
+type CreatePresentationSubmissionRequest struct {
+	HolderDID string `json:"holderDid" validate:"required"`
+
+	// ID of the key in the keystore used to sign the presentation. Its controller must be HolderDID.
+	HolderKID string `json:"holderKid" validate:"required"`
+
+	// ID of a presentation definition stored in this service.
+	PresentationDefinitionID string `json:"presentationDefinitionId,omitempty"`
+
+	// A presentation request JWT received from a verifier. Its signature is checked against its issuer's DID.
+	PresentationRequestJWT *keyaccess.JWT `json:"presentationRequestJwt,omitempty"`
+
+	// Audience of the presentation. Defaults to the issuer of PresentationRequestJWT when one is given.
+	Audience string `json:"audience,omitempty"`
+
+	// When present, the signed submission is sent to this URL as the body of a create submission request.
+	SubmitURL string `json:"submitUrl,omitempty" validate:"omitempty,url"`
+}
+
+func (r CreatePresentationSubmissionRequest) IsValid() error {
+	if err := util.IsValidStruct(r); err != nil {
+		return err
+	}
+	if (r.PresentationDefinitionID == "") == (r.PresentationRequestJWT == nil) {
+		return errors.New("exactly one of presentationDefinitionId or presentationRequestJwt must be provided")
+	}
+	return nil
+}
+
+type CreatePresentationSubmissionResponse struct {
+	PresentationSubmission exchange.PresentationSubmission   `json:"presentationSubmission"`
+	Presentation           credential.VerifiablePresentation `json:"presentation"`
+	SubmissionJWT          keyaccess.JWT                     `json:"submissionJwt"`
+
+	// ID of the presentation request the submission answers, when it was created from one. Must be sent along with
+	// the submission.
+	PresentationRequestID string `json:"presentationRequestId,omitempty"`
+
+	// Set when the submission was sent to a SubmitURL.
+	SubmitResult *SubmitResult `json:"submitResult,omitempty"`
+}
+
+// SubmitResult is what the verifier answered when the submission was sent to it.
+type SubmitResult struct {
+	StatusCode int    `json:"statusCode"`
+	Body       string `json:"body,omitempty"`
+}
@@ -0,0 +1,402 @@
+package wallet
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/TBD54566975/ssi-sdk/credential"
+	"github.com/TBD54566975/ssi-sdk/credential/manifest"
+	"github.com/TBD54566975/ssi-sdk/did/resolution"
+	sdkutil "github.com/TBD54566975/ssi-sdk/util"
+	"github.com/goccy/go-json"
+	"github.com/google/uuid"
+	"github.com/lestrrat-go/jwx/jwt"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+
+	"github.com/tbd54566975/ssi-service/config"
+	credint "github.com/tbd54566975/ssi-service/internal/credential"
+	"github.com/tbd54566975/ssi-service/internal/keyaccess"
+	"github.com/tbd54566975/ssi-service/pkg/service/did"
+	"github.com/tbd54566975/ssi-service/pkg/service/framework"
+	"github.com/tbd54566975/ssi-service/pkg/service/keystore"
+	"github.com/tbd54566975/ssi-service/pkg/service/presentation"
+	presentationstorage "github.com/tbd54566975/ssi-service/pkg/service/presentation/storage"
+	"github.com/tbd54566975/ssi-service/pkg/service/schema"
+	"github.com/tbd54566975/ssi-service/pkg/storage"
+)
+
+const defaultSubmissionTimeout = 10 * time.Second
+
+// Service is the holder side of the SSI Service. It stores credentials issued by other parties to the DIDs this
+// service manages, and builds presentations from them.
+type Service struct {
+	config              config.WalletServiceConfig
+	storage             *Storage
+	didStorage          *did.Storage
+	presentationStorage presentationstorage.DefinitionStorage
+
+	// external dependencies
+	keyStore *keystore.Service
+	verifier *credint.Verifier
+
+	httpClient        *http.Client
+	submissionTimeout time.Duration
+}
+
+func (s *Service) Type() framework.Type {
+	return framework.Wallet
+}
+
+func (s *Service) Status() framework.Status {
+	ae := sdkutil.NewAppendError()
+	if s.storage == nil {
+		ae.AppendString("no storage configured")
+	}
+	if s.didStorage == nil {
+		ae.AppendString("no did storage configured")
+	}
+	if s.keyStore == nil {
+		ae.AppendString("no key store service configured")
+	}
+	if s.verifier == nil {
+		ae.AppendString("no credential verifier configured")
+	}
+	if !ae.IsEmpty() {
+		return framework.Status{
+			Status:  framework.StatusNotReady,
+			Message: fmt.Sprintf("wallet service is not ready: %s", ae.Error().Error()),
+		}
+	}
+	return framework.Status{Status: framework.StatusReady}
+}
+
+func (s *Service) Config() config.WalletServiceConfig {
+	return s.config
+}
+
+func NewWalletService(config config.WalletServiceConfig, s storage.ServiceStorage, keyStore *keystore.Service,
+	didResolver resolution.Resolver, schema *schema.Service) (*Service, error) {
+	walletStorage, err := NewWalletStorage(s)
+	if err != nil {
+		return nil, sdkutil.LoggingErrorMsg(err, "could not instantiate storage for the wallet service")
+	}
+	didStorage, err := did.NewDIDStorage(s)
+	if err != nil {
+		return nil, sdkutil.LoggingErrorMsg(err, "could not instantiate did storage for the wallet service")
+	}
+	presentationStorage, err := presentation.NewPresentationStorage(s)
+	if err != nil {
+		return nil, sdkutil.LoggingErrorMsg(err, "could not instantiate presentation storage for the wallet service")
+	}
+	verifier, err := credint.NewCredentialVerifier(didResolver, schema)
+	if err != nil {
+		return nil, sdkutil.LoggingErrorMsg(err, "could not instantiate verifier for the wallet service")
+	}
+	submissionTimeout := defaultSubmissionTimeout
+	if config.SubmissionTimeout != "" {
+		if submissionTimeout, err = time.ParseDuration(config.SubmissionTimeout); err != nil {
+			return nil, sdkutil.LoggingErrorMsg(err, "parsing submission timeout")
+		}
+	}
+	service := Service{
+		config:              config,
+		storage:             walletStorage,
+		didStorage:          didStorage,
+		presentationStorage: presentationStorage,
+		keyStore:            keyStore,
+		verifier:            verifier,
+		httpClient:          &http.Client{Transport: otelhttp.NewTransport(http.DefaultTransport)},
+		submissionTimeout:   submissionTimeout,
+	}
+	if !service.Status().IsReady() {
+		return nil, errors.New(service.Status().Message)
+	}
+	return &service, nil
+}
+
+// StoreCredential verifies a credential issued to a managed DID and stores it in that DID's wallet.
+func (s *Service) StoreCredential(ctx context.Context, request StoreCredentialRequest) (*StoreCredentialResponse, error) {
+	logrus.Debugf("storing credential for holder: %s", request.HolderDID)
+
+	if err := request.IsValid(); err != nil {
+		return nil, errors.Wrap(err, "invalid store credential request")
+	}
+	if err := s.checkManagedDID(ctx, request.HolderDID); err != nil {
+		return nil, err
+	}
+
+	container, err := s.verifyCredential(ctx, request.DataIntegrityCredential, request.CredentialJWT)
+	if err != nil {
+		return nil, err
+	}
+	held, err := s.storeContainer(ctx, request.HolderDID, "", *container)
+	if err != nil {
+		return nil, err
+	}
+	return &StoreCredentialResponse{HeldCredential: *held}, nil
+}
+
+func (s *Service) GetCredential(ctx context.Context, request GetCredentialRequest) (*GetCredentialResponse, error) {
+	logrus.Debugf("getting held credential: %s", request.ID)
+
+	stored, err := s.storage.GetHeldCredential(ctx, request.ID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting held credential with id: %s", request.ID)
+	}
+	return &GetCredentialResponse{HeldCredential: serviceModel(*stored)}, nil
+}
+
+func (s *Service) ListCredentials(ctx context.Context, request ListCredentialsRequest) (*ListCredentialsResponse, error) {
+	logrus.Debugf("listing held credentials: %+v", request)
+
+	stored, err := s.storage.ListHeldCredentials(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "fetching held credentials from storage")
+	}
+
+	creds := make([]HeldCredential, 0, len(stored))
+	for _, held := range stored {
+		if request.HolderDID != "" && held.HolderDID != request.HolderDID {
+			continue
+		}
+		if request.Issuer != "" && held.Issuer != request.Issuer {
+			continue
+		}
+		if request.Schema != "" && held.Schema != request.Schema {
+			continue
+		}
+		if request.Type != "" && !sdkutil.Contains(request.Type, held.Types) {
+			continue
+		}
+		creds = append(creds, serviceModel(held))
+	}
+	return &ListCredentialsResponse{Credentials: creds}, nil
+}
+
+func (s *Service) DeleteCredential(ctx context.Context, request DeleteCredentialRequest) error {
+	logrus.Debugf("deleting held credential: %s", request.ID)
+
+	if err := s.storage.DeleteHeldCredential(ctx, request.ID); err != nil {
+		return errors.Wrapf(err, "deleting held credential with id: %s", request.ID)
+	}
+	return nil
+}
+
+// StoreCredentialResponse accepts a credential response from an issuer. The response's signature is checked against
+// the issuer's DID, and each fulfilled credential is verified and stored for the holder. Denials are returned as
+// errors.
+func (s *Service) StoreCredentialResponse(ctx context.Context, request StoreCredentialResponseRequest) (*StoreCredentialResponseResponse, error) {
+	logrus.Debugf("storing credential response for holder: %s", request.HolderDID)
+
+	if err := sdkutil.IsValidStruct(request); err != nil {
+		return nil, errors.Wrap(err, "invalid store credential response request")
+	}
+	if err := s.checkManagedDID(ctx, request.HolderDID); err != nil {
+		return nil, err
+	}
+
+	token, err := jwt.Parse([]byte(request.ResponseJWT))
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing credential response JWT")
+	}
+	if err = s.verifier.VerifyJWT(ctx, token.Issuer(), request.ResponseJWT); err != nil {
+		return nil, errors.Wrapf(err, "verifying credential response signature from issuer<%s>", token.Issuer())
+	}
+	claims, err := token.AsMap(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting credential response claims")
+	}
+	claimsBytes, err := json.Marshal(claims)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshalling credential response claims")
+	}
+	var wrapper manifest.CredentialResponseWrapper
+	if err = json.Unmarshal(claimsBytes, &wrapper); err != nil {
+		return nil, errors.Wrap(err, "unmarshalling credential response")
+	}
+
+	response := wrapper.CredentialResponse
+	if err = response.IsValid(); err != nil {
+		return nil, errors.Wrap(err, "invalid credential response")
+	}
+	if response.Applicant != "" && response.Applicant != request.HolderDID {
+		return nil, errors.Errorf("credential response<%s> is for applicant<%s>, not holder<%s>", response.ID, response.Applicant, request.HolderDID)
+	}
+	if response.Denial != nil {
+		return nil, errors.Errorf("credential response<%s> is a denial: %s", response.ID, response.Denial.Reason)
+	}
+
+	containers, err := credint.NewCredentialContainerFromArray(wrapper.Credentials)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing credentials in credential response")
+	}
+
+	// verify everything before storing anything so that a response is accepted in full or not at all
+	verified := make([]credint.Container, 0, len(containers))
+	for _, container := range containers {
+		var verifiedContainer *credint.Container
+		if container.HasJWTCredential() {
+			verifiedContainer, err = s.verifyCredential(ctx, nil, container.CredentialJWT)
+		} else {
+			verifiedContainer, err = s.verifyCredential(ctx, container.Credential, nil)
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "credential response<%s>", response.ID)
+		}
+		verified = append(verified, *verifiedContainer)
+	}
+
+	held := make([]HeldCredential, 0, len(verified))
+	for _, container := range verified {
+		next, err := s.storeContainer(ctx, request.HolderDID, response.ID, container)
+		if err != nil {
+			return nil, err
+		}
+		held = append(held, *next)
+	}
+	return &StoreCredentialResponseResponse{ResponseID: response.ID, Credentials: held}, nil
+}
+
+// CreatePresentation builds a verifiable presentation from held credentials and signs it as a JWT with the holder's key.
+func (s *Service) CreatePresentation(ctx context.Context, request CreatePresentationRequest) (*CreatePresentationResponse, error) {
+	logrus.Debugf("creating presentation for holder: %s", request.HolderDID)
+
+	if err := sdkutil.IsValidStruct(request); err != nil {
+		return nil, errors.Wrap(err, "invalid create presentation request")
+	}
+
+	containers := make([]credint.Container, 0, len(request.CredentialIDs))
+	for _, id := range request.CredentialIDs {
+		stored, err := s.storage.GetHeldCredential(ctx, id)
+		if err != nil {
+			return nil, errors.Wrapf(err, "getting held credential with id: %s", id)
+		}
+		if stored.HolderDID != request.HolderDID {
+			return nil, errors.Errorf("credential<%s> is not held by holder<%s>", id, request.HolderDID)
+		}
+		containers = append(containers, serviceModel(*stored).Container())
+	}
+
+	builder := credential.NewVerifiablePresentationBuilder()
+	if err := builder.SetHolder(request.HolderDID); err != nil {
+		return nil, errors.Wrap(err, "setting holder")
+	}
+	if err := builder.AddVerifiableCredentials(credint.ContainersToInterface(containers)...); err != nil {
+		return nil, errors.Wrap(err, "adding credentials to presentation")
+	}
+	presentation, err := builder.Build()
+	if err != nil {
+		return nil, errors.Wrap(err, "building presentation")
+	}
+
+	presentationJWT, err := s.signPresentationJWT(ctx, request.HolderKID, request.Audience, *presentation)
+	if err != nil {
+		return nil, err
+	}
+	return &CreatePresentationResponse{Presentation: *presentation, PresentationJWT: *presentationJWT}, nil
+}
+
+func (s *Service) signPresentationJWT(ctx context.Context, holderKID, audience string, presentation credential.VerifiablePresentation) (*keyaccess.JWT, error) {
+	gotKey, err := s.keyStore.GetSigningKey(ctx, keystore.GetKeyRequest{ID: holderKID})
+	if err != nil {
+		return nil, sdkutil.LoggingErrorMsgf(err, "getting key for signing presentation<%s>", holderKID)
+	}
+	if gotKey.Controller != presentation.Holder {
+		return nil, sdkutil.LoggingNewErrorf("key controller<%s> does not match presentation holder<%s> for key<%s>", gotKey.Controller, presentation.Holder, holderKID)
+	}
+	keyAccess, err := keyaccess.NewJWKKeyAccess(gotKey.Controller, gotKey.ID, gotKey.Key)
+	if err != nil {
+		return nil, errors.Wrapf(err, "creating key access for signing presentation with key<%s>", gotKey.ID)
+	}
+	presentationToken, err := keyAccess.SignVerifiablePresentation(audience, presentation)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not sign presentation with key<%s>", gotKey.ID)
+	}
+	return presentationToken, nil
+}
+
+// checkManagedDID makes sure the given DID is one this service manages and that it has not been deleted.
+func (s *Service) checkManagedDID(ctx context.Context, holderDID string) error {
+	stored, err := s.didStorage.GetDIDDefault(ctx, holderDID)
+	if err != nil {
+		return errors.Wrapf(err, "holder<%s> is not a DID managed by this service", holderDID)
+	}
+	if stored.SoftDeleted {
+		return errors.Errorf("holder<%s> has been deleted", holderDID)
+	}
+	return nil
+}
+
+// verifyCredential verifies exactly one of a data integrity credential or a credential JWT, returning a container
+// with the parsed credential.
+func (s *Service) verifyCredential(ctx context.Context, diCred *credential.VerifiableCredential, credJWT *keyaccess.JWT) (*credint.Container, error) {
+	if credJWT != nil {
+		if err := s.verifier.VerifyJWTCredential(ctx, *credJWT); err != nil {
+			return nil, errors.Wrap(err, "verifying credential JWT")
+		}
+		container, err := credint.NewCredentialContainerFromJWT(credJWT.String())
+		if err != nil {
+			return nil, err
+		}
+		return container, nil
+	}
+	if err := s.verifier.VerifyDataIntegrityCredential(ctx, *diCred); err != nil {
+		return nil, errors.Wrap(err, "verifying data integrity credential")
+	}
+	return &credint.Container{ID: diCred.ID, Credential: diCred}, nil
+}
+
+func (s *Service) storeContainer(ctx context.Context, holderDID, responseID string, container credint.Container) (*HeldCredential, error) {
+	cred := container.Credential
+	if subject := cred.CredentialSubject.GetID(); subject != "" && subject != holderDID {
+		return nil, errors.Errorf("credential<%s> subject<%s> is not holder<%s>", cred.ID, subject, holderDID)
+	}
+	issuer, err := credint.IssuerID(cred.Issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	stored := StoredHeldCredential{
+		ID:           uuid.NewString(),
+		HolderDID:    holderDID,
+		CredentialID: cred.ID,
+		Issuer:       issuer,
+		Types:        credint.Types(cred.Type),
+		ResponseID:   responseID,
+		ReceivedAt:   time.Now().Format(time.RFC3339),
+	}
+	if cred.CredentialSchema != nil {
+		stored.Schema = cred.CredentialSchema.ID
+	}
+	if container.HasJWTCredential() {
+		stored.CredentialJWT = container.CredentialJWT
+	} else {
+		stored.Credential = cred
+	}
+	if err = s.storage.StoreHeldCredential(ctx, stored); err != nil {
+		return nil, errors.Wrap(err, "storing held credential")
+	}
+	held := serviceModel(stored)
+	return &held, nil
+}
+
+func serviceModel(stored StoredHeldCredential) HeldCredential {
+	held := HeldCredential{
+		ID:            stored.ID,
+		HolderDID:     stored.HolderDID,
+		Issuer:        stored.Issuer,
+		Schema:        stored.Schema,
+		Types:         stored.Types,
+		Credential:    stored.Credential,
+		CredentialJWT: stored.CredentialJWT,
+		ResponseID:    stored.ResponseID,
+	}
+	if receivedAt, err := time.Parse(time.RFC3339, stored.ReceivedAt); err == nil {
+		held.ReceivedAt = receivedAt
+	}
+	return held
+}
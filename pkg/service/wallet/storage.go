@@ -0,0 +1,101 @@
+package wallet
+
+import (
+	"context"
+
+	"github.com/TBD54566975/ssi-sdk/credential"
+	"github.com/goccy/go-json"
+	"github.com/pkg/errors"
+
+	"github.com/tbd54566975/ssi-service/internal/keyaccess"
+	"github.com/tbd54566975/ssi-service/pkg/storage"
+)
+
+const namespace = "wallet"
+
+type Storage struct {
+	db storage.ServiceStorage
+}
+
+func NewWalletStorage(s storage.ServiceStorage) (*Storage, error) {
+	if s == nil {
+		return nil, errors.New("storage cannot be nil")
+	}
+	return &Storage{db: s}, nil
+}
+
+// StoredHeldCredential is a credential issued by another party that is held by one of the DIDs this service manages.
+type StoredHeldCredential struct {
+	// This ID is generated by the wallet upon import and is unrelated to the credential's own ID.
+	ID        string `json:"id"`
+	HolderDID string `json:"holderDid"`
+
+	CredentialID string `json:"credentialId"`
+
+	// only one of these fields should be present
+	Credential    *credential.VerifiableCredential `json:"credential,omitempty"`
+	CredentialJWT *keyaccess.JWT                   `json:"token,omitempty"`
+
+	Issuer string   `json:"issuer"`
+	Schema string   `json:"schema,omitempty"`
+	Types  []string `json:"types,omitempty"`
+
+	// ID of the credential response the credential was received in, if any.
+	ResponseID string `json:"responseId,omitempty"`
+	ReceivedAt string `json:"receivedAt"`
+}
+
+func (s Storage) StoreHeldCredential(ctx context.Context, held StoredHeldCredential) error {
+	if held.ID == "" {
+		return errors.New("cannot store held credential without an ID")
+	}
+	data, err := json.Marshal(held)
+	if err != nil {
+		return errors.Wrap(err, "marshalling held credential")
+	}
+	return s.db.Write(ctx, namespace, held.ID, data)
+}
+
+func (s Storage) GetHeldCredential(ctx context.Context, id string) (*StoredHeldCredential, error) {
+	if id == "" {
+		return nil, errors.New("cannot fetch held credential without an ID")
+	}
+	data, err := s.db.Read(ctx, namespace, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading from db")
+	}
+	if len(data) == 0 {
+		return nil, errors.Errorf("held credential not found with id: %s", id)
+	}
+	var held StoredHeldCredential
+	if err = json.Unmarshal(data, &held); err != nil {
+		return nil, errors.Wrap(err, "unmarshalling held credential")
+	}
+	return &held, nil
+}
+
+func (s Storage) ListHeldCredentials(ctx context.Context) ([]StoredHeldCredential, error) {
+	m, err := s.db.ReadAll(ctx, namespace)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading all")
+	}
+	held := make([]StoredHeldCredential, 0, len(m))
+	for k, v := range m {
+		var next StoredHeldCredential
+		if err = json.Unmarshal(v, &next); err != nil {
+			return nil, errors.Wrapf(err, "unmarshalling held credential with key <%s>", k)
+		}
+		held = append(held, next)
+	}
+	return held, nil
+}
+
+func (s Storage) DeleteHeldCredential(ctx context.Context, id string) error {
+	if id == "" {
+		return nil
+	}
+	if err := s.db.Delete(ctx, namespace, id); err != nil {
+		return errors.Wrap(err, "deleting from db")
+	}
+	return nil
+}
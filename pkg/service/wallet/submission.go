@@ -0,0 +1,287 @@
+package wallet
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/TBD54566975/ssi-sdk/credential"
+	"github.com/TBD54566975/ssi-sdk/credential/exchange"
+	"github.com/goccy/go-json"
+	"github.com/lestrrat-go/jwx/jwt"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/tbd54566975/ssi-service/internal/keyaccess"
+	"github.com/tbd54566975/ssi-service/internal/util"
+	"github.com/tbd54566975/ssi-service/pkg/service/keystore"
+)
+
+const (
+	presentationDefinitionClaim = "presentation_definition"
+	nonceClaim                  = "nonce"
+)
+
+// presentationRequest is what the wallet needs from a verifier's presentation request to answer it.
+type presentationRequest struct {
+	ID         string
+	Requester  string
+	Nonce      string
+	Definition exchange.PresentationDefinition
+}
+
+// CreatePresentationSubmission selects credentials from the holder's wallet that fulfill a presentation definition,
+// builds a presentation submission from them, and signs it as a VP JWT with the holder's key. The definition is
+// either one stored in this service, or the one embedded in a verifier's presentation request. When a submit URL is
+// given, the signed submission is sent there.
+func (s *Service) CreatePresentationSubmission(ctx context.Context, request CreatePresentationSubmissionRequest) (*CreatePresentationSubmissionResponse, error) {
+	logrus.Debugf("creating presentation submission for holder: %s", request.HolderDID)
+
+	if err := request.IsValid(); err != nil {
+		return nil, errors.Wrap(err, "invalid create presentation submission request")
+	}
+
+	var definition *exchange.PresentationDefinition
+	var presRequest *presentationRequest
+	audience := request.Audience
+	if request.PresentationRequestJWT != nil {
+		parsed, err := s.parsePresentationRequest(ctx, *request.PresentationRequestJWT)
+		if err != nil {
+			return nil, err
+		}
+		presRequest = parsed
+		definition = &parsed.Definition
+		if audience == "" {
+			audience = parsed.Requester
+		}
+	} else {
+		storedDefinition, err := s.presentationStorage.GetDefinition(ctx, request.PresentationDefinitionID)
+		if err != nil {
+			return nil, errors.Wrapf(err, "getting presentation definition<%s>", request.PresentationDefinitionID)
+		}
+		definition = &storedDefinition.PresentationDefinition
+	}
+
+	claims, err := s.holderClaims(ctx, request.HolderDID)
+	if err != nil {
+		return nil, err
+	}
+	if len(claims) == 0 {
+		return nil, errors.Errorf("holder<%s> has no credentials", request.HolderDID)
+	}
+
+	presentation, err := exchange.BuildPresentationSubmissionVP(request.HolderDID, *definition, claims)
+	if err != nil {
+		return nil, errors.Wrap(err, "selecting credentials for presentation definition")
+	}
+	submission, ok := presentation.PresentationSubmission.(exchange.PresentationSubmission)
+	if !ok {
+		return nil, errors.New("presentation does not contain a presentation submission")
+	}
+
+	var nonce string
+	if presRequest != nil {
+		nonce = presRequest.Nonce
+	}
+	submissionJWT, err := s.signSubmission(ctx, request.HolderDID, request.HolderKID, audience, nonce, *presentation)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := CreatePresentationSubmissionResponse{
+		PresentationSubmission: submission,
+		Presentation:           *presentation,
+		SubmissionJWT:          *submissionJWT,
+	}
+	if presRequest != nil {
+		resp.PresentationRequestID = presRequest.ID
+	}
+	if request.SubmitURL != "" {
+		result, err := s.submit(ctx, request.SubmitURL, submitRequest{
+			SubmissionJWT:         *submissionJWT,
+			PresentationRequestID: resp.PresentationRequestID,
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "submitting presentation to %s", request.SubmitURL)
+		}
+		resp.SubmitResult = result
+	}
+	return &resp, nil
+}
+
+// parsePresentationRequest verifies a presentation request JWT against its issuer's DID, and returns the embedded
+// presentation definition along with the request's ID, issuer and nonce.
+func (s *Service) parsePresentationRequest(ctx context.Context, requestJWT keyaccess.JWT) (*presentationRequest, error) {
+	token, err := jwt.Parse([]byte(requestJWT), jwt.WithValidate(true))
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing presentation request JWT")
+	}
+	requester := token.Issuer()
+	if err = s.verifier.VerifyJWT(ctx, requester, requestJWT); err != nil {
+		return nil, errors.Wrapf(err, "verifying presentation request signature from requester<%s>", requester)
+	}
+
+	definitionClaim, ok := token.Get(presentationDefinitionClaim)
+	if !ok {
+		return nil, errors.Errorf("presentation request does not contain a %s claim", presentationDefinitionClaim)
+	}
+	definitionBytes, err := json.Marshal(definitionClaim)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshalling presentation definition")
+	}
+	var definition exchange.PresentationDefinition
+	if err = json.Unmarshal(definitionBytes, &definition); err != nil {
+		return nil, errors.Wrap(err, "unmarshalling presentation definition")
+	}
+	if err = definition.IsValid(); err != nil {
+		return nil, errors.Wrap(err, "invalid presentation definition in presentation request")
+	}
+
+	var nonce string
+	if nonceValue, ok := token.Get(nonceClaim); ok {
+		nonce, _ = nonceValue.(string)
+	}
+	return &presentationRequest{
+		ID:         token.JwtID(),
+		Requester:  requester,
+		Nonce:      nonce,
+		Definition: definition,
+	}, nil
+}
+
+// holderClaims returns every credential held by the holder as a claim that can be matched against input descriptors.
+func (s *Service) holderClaims(ctx context.Context, holderDID string) ([]exchange.NormalizedClaim, error) {
+	held, err := s.ListCredentials(ctx, ListCredentialsRequest{HolderDID: holderDID})
+	if err != nil {
+		return nil, errors.Wrap(err, "listing holder credentials")
+	}
+
+	claims := make([]exchange.NormalizedClaim, 0, len(held.Credentials))
+	for _, cred := range held.Credentials {
+		claim, err := toPresentationClaim(cred)
+		if err != nil {
+			logrus.WithError(err).Warnf("skipping held credential<%s>", cred.ID)
+			continue
+		}
+		data, err := claim.GetClaimJSON()
+		if err != nil {
+			logrus.WithError(err).Warnf("skipping held credential<%s>", cred.ID)
+			continue
+		}
+		format, err := claim.GetClaimFormat()
+		if err != nil {
+			return nil, err
+		}
+		// the wallet ID is used so that distinct credentials are never conflated
+		claims = append(claims, exchange.NormalizedClaim{
+			ID:             cred.ID,
+			Data:           data,
+			RawClaim:       claim.GetClaim(),
+			Format:         format,
+			AlgOrProofType: claim.SignatureAlgorithmOrProofType,
+		})
+	}
+	return claims, nil
+}
+
+func toPresentationClaim(cred HeldCredential) (*exchange.PresentationClaim, error) {
+	if cred.CredentialJWT != nil {
+		headers, err := keyaccess.GetJWTHeaders([]byte(*cred.CredentialJWT))
+		if err != nil {
+			return nil, errors.Wrap(err, "getting credential JWT headers")
+		}
+		token := cred.CredentialJWT.String()
+		return &exchange.PresentationClaim{
+			Token:                         &token,
+			JWTFormat:                     exchange.JWTVC.Ptr(),
+			SignatureAlgorithmOrProofType: headers.Algorithm().String(),
+		}, nil
+	}
+	if cred.Credential != nil {
+		var proofType string
+		if cred.Credential.Proof != nil {
+			if proof, ok := (*cred.Credential.Proof).(map[string]any); ok {
+				proofType, _ = proof["type"].(string)
+			}
+		}
+		return &exchange.PresentationClaim{
+			Credential:                    cred.Credential,
+			LDPFormat:                     exchange.LDPVC.Ptr(),
+			SignatureAlgorithmOrProofType: proofType,
+		}, nil
+	}
+	return nil, errors.New("held credential has no credential")
+}
+
+// signSubmission signs the presentation as a VP JWT via the keystore. The nonce, when present, binds the presentation
+// to the request it answers.
+func (s *Service) signSubmission(ctx context.Context, holderDID, holderKID, audience, nonce string, presentation credential.VerifiablePresentation) (*keyaccess.JWT, error) {
+	gotKey, err := s.keyStore.GetKeyDetails(ctx, keystore.GetKeyDetailsRequest{ID: holderKID})
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting key<%s>", holderKID)
+	}
+	if gotKey.Controller != holderDID {
+		return nil, errors.Errorf("key controller<%s> does not match holder<%s> for key<%s>", gotKey.Controller, holderDID, holderKID)
+	}
+
+	builder := jwt.NewBuilder().
+		Claim(credential.VPJWTProperty, presentation).
+		Issuer(holderDID).
+		IssuedAt(time.Now()).
+		NotBefore(time.Now()).
+		JwtID(presentation.ID)
+	if audience != "" {
+		builder = builder.Audience([]string{audience})
+	}
+	if nonce != "" {
+		builder = builder.Claim(nonceClaim, nonce)
+	}
+	token, err := builder.Build()
+	if err != nil {
+		return nil, errors.Wrap(err, "building presentation JWT")
+	}
+	signed, err := s.keyStore.Sign(ctx, holderKID, token)
+	if err != nil {
+		return nil, errors.Wrapf(err, "signing presentation with key<%s>", holderKID)
+	}
+	return signed, nil
+}
+
+type submitRequest struct {
+	SubmissionJWT         keyaccess.JWT `json:"submissionJwt"`
+	PresentationRequestID string        `json:"presentationRequestId,omitempty"`
+}
+
+// submit sends the signed submission to a verifier's create submission endpoint.
+func (s *Service) submit(ctx context.Context, url string, submission submitRequest) (*SubmitResult, error) {
+	body, err := json.Marshal(submission)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshalling submission")
+	}
+	timeoutCtx, cancel := context.WithTimeout(ctx, s.submissionTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(timeoutCtx, http.MethodPut, url, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, errors.Wrap(err, "building http req")
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "client http client")
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading response body")
+	}
+	if !util.Is2xxResponse(resp.StatusCode) {
+		return nil, fmt.Errorf("status code %v not in the 200s. body: %s", resp.StatusCode, string(respBody))
+	}
+	return &SubmitResult{StatusCode: resp.StatusCode, Body: string(respBody)}, nil
+}
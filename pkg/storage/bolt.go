@@ -116,6 +116,19 @@ func (btx *boltTx) Write(_ context.Context, namespace, key string, value []byte)
 	return writeFunc(namespace, key, value)(btx.tx)
 }
 
+func (btx *boltTx) Read(_ context.Context, namespace, key string) ([]byte, error) {
+	bucket := btx.tx.Bucket([]byte(namespace))
+	if bucket == nil {
+		return nil, nil
+	}
+	// values are only valid for the life of the transaction
+	value := bucket.Get([]byte(key))
+	if value == nil {
+		return nil, nil
+	}
+	return append([]byte(nil), value...), nil
+}
+
 // Execute runs the provided function within a transaction. Any failure during execution results in a rollback.
 // It is recommended to not open transactions within businessLogicFunc, as there are situation in which the interplay
 // between transactions may cause deadlocks.
@@ -2,8 +2,11 @@ package storage
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"sync"
+	"sync/atomic"
 	"testing"
 
 	"github.com/alicebob/miniredis/v2"
@@ -316,6 +319,56 @@ func TestDBExists_FalseAfterDeletingKey(t *testing.T) {
 	}
 }
 
+func TestDB_ExecuteReadsWithinTransaction(t *testing.T) {
+	for _, dbImpl := range getDBImplementations(t) {
+		db := dbImpl
+
+		namespace := "execute"
+		key := "nonce"
+		err := db.Write(context.Background(), namespace, key, []byte("unused"))
+		assert.NoError(t, err)
+
+		// only one of the concurrent transactions can see the value unused and mark it used
+		use := func(ctx context.Context, tx Tx) (any, error) {
+			value, err := tx.Read(ctx, namespace, key)
+			if err != nil {
+				return nil, err
+			}
+			if string(value) != "unused" {
+				return nil, errors.New("already used")
+			}
+			return nil, tx.Write(ctx, namespace, key, []byte("used"))
+		}
+		const attempts = 10
+		var wg sync.WaitGroup
+		var used atomic.Int32
+		for i := 0; i < attempts; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, err := db.Execute(context.Background(), use, []WatchKey{{Namespace: namespace, Key: key}}); err == nil {
+					used.Add(1)
+				}
+			}()
+		}
+		wg.Wait()
+		assert.Equal(t, int32(1), used.Load())
+
+		value, err := db.Read(context.Background(), namespace, key)
+		assert.NoError(t, err)
+		assert.Equal(t, "used", string(value))
+
+		// missing keys read as empty
+		_, err = db.Execute(context.Background(), func(ctx context.Context, tx Tx) (any, error) {
+			missing, err := tx.Read(ctx, "dnenamespace", "dnekey")
+			assert.NoError(t, err)
+			assert.Empty(t, missing)
+			return nil, nil
+		}, nil)
+		assert.NoError(t, err)
+	}
+}
+
 type testStruct struct {
 	Status int    `json:"status"`
 	Reason string `json:"reason"`
@@ -32,6 +32,7 @@ type RedisDB struct {
 }
 
 type redisTx struct {
+	tx   *goredislib.Tx
 	pipe goredislib.Pipeliner
 }
 
@@ -40,6 +41,17 @@ func (rtx *redisTx) Write(ctx context.Context, namespace, key string, value []by
 	return rtx.pipe.Set(ctx, nameSpaceKey, value, 0).Err()
 }
 
+// Read reads through the connection watching the keys of the transaction, since commands queued in the pipeline only
+// return once it is executed.
+func (rtx *redisTx) Read(ctx context.Context, namespace, key string) ([]byte, error) {
+	nameSpaceKey := getRedisKey(namespace, key)
+	res, err := rtx.tx.Get(ctx, nameSpaceKey).Bytes()
+	if errors.Is(err, goredislib.Nil) {
+		return res, nil
+	}
+	return res, err
+}
+
 func (b *RedisDB) Init(opts ...Option) error {
 	address, password, err := processRedisOptions(opts...)
 	if err != nil {
@@ -128,7 +140,7 @@ func (b *RedisDB) Execute(ctx context.Context, businessLogicFunc BusinessLogicFu
 	txf := func(tx *goredislib.Tx) error {
 		// Operation is commited only if the watched keys remain unchanged.
 		_, err := tx.TxPipelined(ctx, func(pipe goredislib.Pipeliner) error {
-			redisTx := redisTx{pipe}
+			redisTx := redisTx{tx: tx, pipe: pipe}
 			var err error
 
 			finalOutput, err = businessLogicFunc(ctx, &redisTx)
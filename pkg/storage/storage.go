@@ -19,6 +19,8 @@ type WatchKey struct {
 
 type Tx interface {
 	Write(ctx context.Context, namespace, key string, value []byte) error
+	// Read returns the value of a key as seen by the transaction, so that writes conditioned on it are atomic.
+	Read(ctx context.Context, namespace, key string) ([]byte, error)
 }
 
 const (